@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	databasev1alpha1 "github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespaceddatabasev1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
 	cockroachdbv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
 )
 
@@ -29,6 +30,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes,
 		cockroachdbv1alpha1.SchemeBuilder.AddToScheme,
 		databasev1alpha1.SchemeBuilder.AddToScheme,
+		namespaceddatabasev1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
 
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BackupJobParameters are the configurable fields of a BackupJob.
+type BackupJobParameters struct {
+	// ClusterRef references the Cluster that is backed up, over SQL as the
+	// user whose connection details the Cluster publishes. The Cluster
+	// cannot be deleted while the BackupJob exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster that is backed up, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database that is backed up. The whole cluster is backed up if neither
+	// database nor tables are set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database that is backed up, and sets
+	// database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database that is backed up, and sets
+	// databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Tables that are backed up, qualified with their database, e.g.
+	// app.public.users.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tables are immutable"
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+	// Storage the backup is written to.
+	// +kubebuilder:validation:XValidation:rule="self.uri == oldSelf.uri",message="storage uri is immutable"
+	Storage BackupStorage `json:"storage"`
+	// RevisionHistory backs up the revision history of the data, so that it
+	// can be restored as of any time covered by the backup.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="revisionHistory is immutable"
+	// +optional
+	RevisionHistory bool `json:"revisionHistory,omitempty"`
+}
+
+// BackupJobObservation are the observable fields of a BackupJob.
+type BackupJobObservation struct {
+	// Status of the backup job, e.g. running or succeeded.
+	Status string `json:"status,omitempty"`
+	// CompletionPercent is how much of the backup is done.
+	CompletionPercent int32 `json:"completionPercent,omitempty"`
+	// Message explaining why the backup failed.
+	Message string `json:"message,omitempty"`
+}
+
+// A BackupJobSpec defines the desired state of a BackupJob.
+type BackupJobSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       BackupJobParameters `json:"forProvider"`
+}
+
+// A BackupJobStatus represents the observed state of a BackupJob.
+type BackupJobStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          BackupJobObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A BackupJob takes a one-time backup of a cluster, a database or tables to
+// cloud storage, over SQL. It is ready once the backup succeeded. Its
+// external name is the ID of the backup job. Deleting it cancels the job if
+// it is still running, and leaves finished backups in place.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="PROGRESS",type="integer",JSONPath=".status.atProvider.completionPercent"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type BackupJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupJobSpec   `json:"spec"`
+	Status BackupJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupJobList contains a list of BackupJob
+type BackupJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupJob `json:"items"`
+}
+
+// BackupJob type metadata.
+var (
+	BackupJobKind                 = reflect.TypeOf(BackupJob{}).Name()
+	BackupJobGroupKind            = schema.GroupKind{Group: Group, Kind: BackupJobKind}.String()
+	BackupJobKindAPIVersion       = BackupJobKind + "." + SchemeGroupVersion.String()
+	BackupJobGroupVersionKind     = SchemeGroupVersion.WithKind(BackupJobKind)
+	BackupJobListGroupVersionKind = SchemeGroupVersion.WithKind(BackupJobKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&BackupJob{}, &BackupJobList{})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// FullBackupAlways takes a full backup on every run of a backup schedule.
+const FullBackupAlways = "ALWAYS"
+
+// BackupStorage is the cloud storage backups are written to or read from.
+type BackupStorage struct {
+	// URI of the storage, e.g. s3://bucket/path?AUTH=specified, without
+	// credentials.
+	URI string `json:"uri"`
+	// CredentialsSecretRef references a Secret whose keys and values are
+	// added to the URI as query parameters, e.g. AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY.
+	// +optional
+	CredentialsSecretRef *xpv1.SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// BackupScheduleParameters are the configurable fields of a BackupSchedule.
+type BackupScheduleParameters struct {
+	// ClusterRef references the Cluster that is backed up, over SQL as the
+	// user whose connection details the Cluster publishes. The Cluster
+	// cannot be deleted while the BackupSchedule exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster that is backed up, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database that is backed up. The whole cluster is backed up if neither
+	// database nor tables are set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database that is backed up, and sets
+	// database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database that is backed up, and sets
+	// databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Tables that are backed up, qualified with their database, e.g.
+	// app.public.users.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tables are immutable"
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+	// Storage the backups are written to.
+	// +kubebuilder:validation:XValidation:rule="self.uri == oldSelf.uri",message="storage uri is immutable"
+	Storage BackupStorage `json:"storage"`
+	// Recurrence is the cron expression backups are taken at. Backups are
+	// incremental unless fullBackup is ALWAYS.
+	Recurrence string `json:"recurrence"`
+	// FullBackup is the cron expression full backups are taken at, or
+	// ALWAYS to only take full backups. CockroachDB picks a cadence
+	// based on the recurrence if omitted.
+	// +optional
+	FullBackup string `json:"fullBackup,omitempty"`
+	// RevisionHistory backs up the revision history of the data, so that it
+	// can be restored as of any time covered by the backups.
+	// +optional
+	RevisionHistory bool `json:"revisionHistory,omitempty"`
+}
+
+// A BackupScheduleRun is one of the schedules CockroachDB created for a
+// BackupSchedule, for either its full or its incremental backups.
+type BackupScheduleRun struct {
+	// ID of the schedule.
+	ID string `json:"id"`
+	// Recurrence is the cron expression the schedule runs at.
+	Recurrence string `json:"recurrence,omitempty"`
+	// Status of the schedule, e.g. ACTIVE or PAUSED.
+	Status string `json:"status,omitempty"`
+	// NextRun is the time the schedule runs next.
+	NextRun *metav1.Time `json:"nextRun,omitempty"`
+}
+
+// BackupScheduleObservation are the observable fields of a BackupSchedule.
+type BackupScheduleObservation struct {
+	// Schedules CockroachDB created for the BackupSchedule.
+	Schedules []BackupScheduleRun `json:"schedules,omitempty"`
+	// SecretVersion is the resource version of the storage credentials
+	// Secret the schedules were created with.
+	SecretVersion string `json:"secretVersion,omitempty"`
+}
+
+// A BackupScheduleSpec defines the desired state of a BackupSchedule.
+type BackupScheduleSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       BackupScheduleParameters `json:"forProvider"`
+}
+
+// A BackupScheduleStatus represents the observed state of a BackupSchedule.
+type BackupScheduleStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          BackupScheduleObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A BackupSchedule schedules backups of a cluster, a database or tables to
+// cloud storage, over SQL. Its external name is the label of the schedules,
+// and defaults to the name of the BackupSchedule. The schedules are created
+// again when the cadence or the storage credentials change.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="RECURRENCE",type="string",JSONPath=".spec.forProvider.recurrence"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type BackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupScheduleSpec   `json:"spec"`
+	Status BackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupScheduleList contains a list of BackupSchedule
+type BackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupSchedule `json:"items"`
+}
+
+// BackupSchedule type metadata.
+var (
+	BackupScheduleKind                 = reflect.TypeOf(BackupSchedule{}).Name()
+	BackupScheduleGroupKind            = schema.GroupKind{Group: Group, Kind: BackupScheduleKind}.String()
+	BackupScheduleKindAPIVersion       = BackupScheduleKind + "." + SchemeGroupVersion.String()
+	BackupScheduleGroupVersionKind     = SchemeGroupVersion.WithKind(BackupScheduleKind)
+	BackupScheduleListGroupVersionKind = SchemeGroupVersion.WithKind(BackupScheduleKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&BackupSchedule{}, &BackupScheduleList{})
+}
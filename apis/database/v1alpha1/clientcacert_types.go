@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClientCACertParameters are the configurable fields of a ClientCACert.
+type ClientCACertParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud whose
+	// SQL clients authenticate with certificates.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster whose SQL clients authenticate with
+	// certificates, and sets clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster whose SQL clients authenticate with
+	// certificates, and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// X509PEMCert is the PEM encoded CA certificate that issues the client
+	// certificates of SQL users.
+	// +kubebuilder:validation:XValidation:rule="self.trim().startsWith('-----BEGIN CERTIFICATE-----')",message="x509PemCert must be a PEM encoded certificate"
+	X509PEMCert string `json:"x509PemCert"`
+}
+
+// ClientCACertObservation are the observable fields of a ClientCACert.
+type ClientCACertObservation struct {
+	// Status of the client CA certificate, e.g. IS_SET.
+	Status string `json:"status,omitempty"`
+}
+
+// A ClientCACertSpec defines the desired state of a ClientCACert.
+type ClientCACertSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ClientCACertParameters `json:"forProvider"`
+}
+
+// A ClientCACertStatus represents the observed state of a ClientCACert.
+type ClientCACertStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ClientCACertObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ClientCACert sets the CA certificate of a dedicated cluster that SQL
+// users may authenticate with client certificates issued by, instead of
+// passwords.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterId"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type ClientCACert struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClientCACertSpec   `json:"spec"`
+	Status ClientCACertStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClientCACertList contains a list of ClientCACert
+type ClientCACertList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClientCACert `json:"items"`
+}
+
+// ClientCACert type metadata.
+var (
+	ClientCACertKind             = reflect.TypeOf(ClientCACert{}).Name()
+	ClientCACertGroupKind        = schema.GroupKind{Group: Group, Kind: ClientCACertKind}.String()
+	ClientCACertKindAPIVersion   = ClientCACertKind + "." + SchemeGroupVersion.String()
+	ClientCACertGroupVersionKind = SchemeGroupVersion.WithKind(ClientCACertKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ClientCACert{}, &ClientCACertList{})
+}
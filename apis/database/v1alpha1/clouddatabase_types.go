@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CloudDatabaseParameters are the configurable fields of a CloudDatabase.
+type CloudDatabaseParameters struct {
+	// Name of the database. Defaults to the name of the CloudDatabase.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name,omitempty"`
+	// ClusterID is the ID of the cluster in CockroachDB Cloud the database
+	// belongs to.
+	// +optional
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster the database belongs to, and sets
+	// clusterId. The Cluster cannot be deleted while the CloudDatabase
+	// exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the database belongs to, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+}
+
+// CloudDatabaseObservation are the observable fields of a CloudDatabase.
+type CloudDatabaseObservation struct {
+	// ClusterID is the ID of the cluster the database was created in.
+	ClusterID string `json:"clusterId,omitempty"`
+	// TableCount is the number of tables of the database.
+	TableCount int64 `json:"tableCount,omitempty"`
+}
+
+// A CloudDatabaseSpec defines the desired state of a CloudDatabase.
+type CloudDatabaseSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       CloudDatabaseParameters `json:"forProvider"`
+}
+
+// A CloudDatabaseStatus represents the observed state of a CloudDatabase.
+type CloudDatabaseStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          CloudDatabaseObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A CloudDatabase is a database of a CockroachDB Cloud cluster, managed
+// through the Cloud API rather than over SQL. Deleting it drops the database.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type CloudDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudDatabaseSpec   `json:"spec"`
+	Status CloudDatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CloudDatabaseList contains a list of CloudDatabase
+type CloudDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudDatabase `json:"items"`
+}
+
+// CloudDatabase type metadata.
+var (
+	CloudDatabaseKind                 = reflect.TypeOf(CloudDatabase{}).Name()
+	CloudDatabaseGroupKind            = schema.GroupKind{Group: Group, Kind: CloudDatabaseKind}.String()
+	CloudDatabaseKindAPIVersion       = CloudDatabaseKind + "." + SchemeGroupVersion.String()
+	CloudDatabaseGroupVersionKind     = SchemeGroupVersion.WithKind(CloudDatabaseKind)
+	CloudDatabaseListGroupVersionKind = SchemeGroupVersion.WithKind(CloudDatabaseKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&CloudDatabase{}, &CloudDatabaseList{})
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"reflect"
+	"sort"
 
 	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -25,45 +26,531 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// AnnotationKeyProtected prevents a Cluster from being deleted while set to
+// "true". It is enforced by the validating webhook, independently of any
+// delete protection of the Cloud API.
+const AnnotationKeyProtected = "database.cockroachdb.crossplane.io/protected"
+
+// AnnotationKeyObserveOnly makes the provider only observe a Cluster while set
+// to "true". The cluster is never created or updated, and its status is kept
+// up to date.
+const AnnotationKeyObserveOnly = "database.cockroachdb.crossplane.io/observe-only"
+
+// AnnotationKeyOperationTimeout overrides how long each observe, create,
+// update or delete of a Cluster may take, as a duration such as "2m". It is
+// bounded by the one minute timeout of a whole reconcile.
+const AnnotationKeyOperationTimeout = "database.cockroachdb.crossplane.io/operation-timeout"
+
+// LabelKeyDiscovered is set to "true" on Clusters created by cluster
+// discovery.
+const LabelKeyDiscovered = "database.cockroachdb.crossplane.io/discovered"
+
+// A RoleOption is a CockroachDB role option.
+// +kubebuilder:validation:Enum=CREATEROLE;NOCREATEROLE;CREATELOGIN;NOCREATELOGIN;CREATEDB;NOCREATEDB;CONTROLJOB;NOCONTROLJOB;CONTROLCHANGEFEED;NOCONTROLCHANGEFEED;VIEWACTIVITY;NOVIEWACTIVITY;VIEWACTIVITYREDACTED;NOVIEWACTIVITYREDACTED;CANCELQUERY;NOCANCELQUERY;MODIFYCLUSTERSETTING;NOMODIFYCLUSTERSETTING;VIEWCLUSTERSETTING;NOVIEWCLUSTERSETTING
+type RoleOption string
+
+// A DatabasePrivilege is a privilege that can be granted on a database.
+// +kubebuilder:validation:Enum=ALL;CONNECT;CREATE;DROP;ZONECONFIG
+type DatabasePrivilege string
+
+// A DatabaseGrant grants privileges on a database.
+type DatabaseGrant struct {
+	// Database the privileges are granted on.
+	Database string `json:"database"`
+	// Privileges granted on the database.
+	// +kubebuilder:validation:MinItems=1
+	Privileges []DatabasePrivilege `json:"privileges"`
+}
+
 type Credentials struct {
-	// +immutable
+	// Username of the SQL user created for these credentials. Changing it
+	// creates a SQL user of the new username and republishes the connection
+	// details; the SQL user of the previous username is deleted once
+	// UsernameChangeGracePeriod ends.
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[a-z_][a-z0-9_.-]{0,62}$')",message="username must start with a lower case letter or underscore, contain only lower case letters, digits, underscores, periods and dashes, and be at most 63 characters long"
 	Username string `json:"username"`
 	// +immutable
 	// +optional
 	PasswordSecretRef *xpv1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// Admin determines whether the user is a member of the admin role. Users
+	// created through the Cloud API are admins. Admin membership, role
+	// options and grants are kept in sync by a short-lived admin SQL user,
+	// crossplane_role_admin, so they can be changed once the user is no
+	// longer an admin.
+	// +optional
+	// +kubebuilder:default=true
+	Admin *bool `json:"admin,omitempty"`
+	// RoleOptions of the user once the Cluster is available. Role options
+	// that are not listed are turned off.
+	// +optional
+	RoleOptions []RoleOption `json:"roleOptions,omitempty"`
+	// Grants of the user once the Cluster is available. Privileges on
+	// databases that are not listed are revoked.
+	// +optional
+	Grants []DatabaseGrant `json:"grants,omitempty"`
+	// UsernameChangeGracePeriod is how long the SQL user of the previous
+	// username is kept after the username changes, so that clients can
+	// switch over to the republished connection details. A change goes
+	// through the following steps, reported by the UsernameChanged
+	// condition:
+	//
+	// 1. CreatingUser: the SQL user of the new username is created and the
+	//    connection details are republished.
+	// 2. GracePeriod: both users exist until the grace period ends.
+	// 3. Completed: the SQL user of the previous username is deleted.
+	//
+	// The previous user is deleted without delay if unset.
+	// +optional
+	UsernameChangeGracePeriod *metav1.Duration `json:"usernameChangeGracePeriod,omitempty"`
+}
+
+// A ClusterSQLUser is a SQL user created by the Cluster controller in
+// addition to the user described by the Cluster credentials.
+type ClusterSQLUser struct {
+	// Name of the SQL user.
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[a-z_][a-z0-9_.-]{0,62}$')",message="name must start with a lower case letter or underscore, contain only lower case letters, digits, underscores, periods and dashes, and be at most 63 characters long"
+	Name string `json:"name"`
+	// PasswordSecretRef references the password of the SQL user. A random
+	// password is generated if omitted.
+	// +optional
+	PasswordSecretRef *xpv1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// WriteConnectionSecretToRef specifies the Secret to which the connection
+	// details of the SQL user, its own DSN and the CA certificate of the
+	// Cluster, are written. Defaults to the connection secret of the Cluster
+	// suffixed with the name of the user. The Secret is recreated if deleted,
+	// with a new password unless passwordSecretRef is set.
+	// +optional
+	WriteConnectionSecretToRef *xpv1.SecretReference `json:"writeConnectionSecretToRef,omitempty"`
+	// DefaultDatabase the SQL user connects to unless the client requests
+	// another one.
+	// +optional
+	DefaultDatabase string `json:"defaultDatabase,omitempty"`
+	// SessionDefaults are the defaults of session variables of the SQL user,
+	// e.g. search_path, applied with ALTER ROLE ... SET over the credentials
+	// of the Cluster. The session defaults of the SQL users of the Cluster
+	// are reset before they are applied, so defaults set by other means do
+	// not survive a change.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^[a-z_][a-z0-9_]*$'))",message="session variables must start with a lower case letter or underscore and contain only lower case letters, digits and underscores"
+	SessionDefaults map[string]string `json:"sessionDefaults,omitempty"`
+}
+
+// An AllowlistEntry allows a CIDR range to connect to a Cluster.
+type AllowlistEntry struct {
+	// CIDR range allowed to connect to the Cluster, e.g. 192.168.1.0/24.
+	// +kubebuilder:validation:Pattern=`^([0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}$`
+	CIDR string `json:"cidr"`
+	// Name of the entry.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// UI allows the CIDR range to access the DB Console.
+	// +optional
+	UI bool `json:"ui,omitempty"`
+	// SQL allows the CIDR range to open SQL connections.
+	// +optional
+	SQL bool `json:"sql,omitempty"`
+}
+
+// An AllowlistPolicy determines how the allowlist of a Cluster is synced.
+// +kubebuilder:validation:Enum=Additive;Exclusive
+type AllowlistPolicy string
+
+// Allowlist policies.
+const (
+	// AllowlistPolicyAdditive creates and updates the entries in the spec,
+	// leaving any other entry untouched.
+	AllowlistPolicyAdditive AllowlistPolicy = "Additive"
+	// AllowlistPolicyExclusive additionally deletes every entry that is not
+	// in the spec.
+	AllowlistPolicyExclusive AllowlistPolicy = "Exclusive"
+)
+
+// An UpdateStrategyType determines how disruptive changes to the nodes and
+// hardware of a dedicated Cluster are applied.
+// +kubebuilder:validation:Enum=InPlace;Surge
+type UpdateStrategyType string
+
+// Update strategy types.
+const (
+	// UpdateStrategyInPlace changes the nodes and hardware of a cluster in
+	// a single update.
+	UpdateStrategyInPlace UpdateStrategyType = "InPlace"
+	// UpdateStrategySurge first adds the nodes the spec requests, then
+	// changes the hardware, then removes the nodes the spec no longer
+	// requests. Each step is a separate update, made once the previous one
+	// settled, so the cluster has spare capacity while its nodes are
+	// replaced.
+	UpdateStrategySurge UpdateStrategyType = "Surge"
+)
+
+// A ClusterUpdateStrategy tunes how disruptive changes to the nodes and
+// hardware of a dedicated Cluster are applied. CockroachDB Cloud drains each
+// node before it is replaced or removed; how long it waits is not
+// configurable through the Cloud API.
+type ClusterUpdateStrategy struct {
+	// Type of the strategy.
+	// +optional
+	// +kubebuilder:default=InPlace
+	Type UpdateStrategyType `json:"type,omitempty"`
+	// MaxNodeChange is the most nodes added to or removed from a region in
+	// one update. Larger changes are made in steps, each once the previous
+	// one settled. Regions that are added or removed are not limited.
+	// Defaults to no limit.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxNodeChange int32 `json:"maxNodeChange,omitempty"`
 }
 
+// A ServerlessCluster scales with its usage, in one or more regions.
+// +kubebuilder:validation:XValidation:rule="size(self.regions) > 0",message="serverless clusters require at least one region"
 type ServerlessCluster struct {
 	// +immutable
 	// +kubebuilder:validation:Required
 	Regions []string `json:"regions"`
+	// SpendLimit is the most the cluster may cost per month, in US cents.
+	// It is the only limit of a serverless cluster: the version of the Cloud
+	// API this provider uses has no usage limits, so there is nothing it is
+	// mutually exclusive with yet.
 	// +optional
 	// +kubebuilder:default=0
 	SpendLimit *int32 `json:"spendLimit"`
 }
 
+// A DedicatedCluster runs on hardware dedicated to it, in one or more
+// regions. Its nodes and hardware are updated in place.
+// +kubebuilder:validation:XValidation:rule="has(self.machineType) != has(self.numVirtualCPUs)",message="exactly one of machineType or numVirtualCPUs must be set"
+type DedicatedCluster struct {
+	// RegionNodes are the regions of the cluster, e.g. us-east-1 on AWS or
+	// us-east1 on GCP, with the number of nodes in each. Multi-region
+	// clusters require at least three nodes in each of at least three
+	// regions. Changing the number of nodes scales the cluster.
+	// +kubebuilder:validation:MinProperties=1
+	// +kubebuilder:validation:XValidation:rule="self.all(r, self[r] > 0)",message="each region requires at least one node"
+	RegionNodes map[string]int32 `json:"regionNodes"`
+	// MachineType of the nodes, e.g. m5.xlarge on AWS or n2-standard-4 on
+	// GCP.
+	// +optional
+	MachineType string `json:"machineType,omitempty"`
+	// NumVirtualCPUs of each node. CockroachDB Cloud picks a machine type
+	// with that many virtual CPUs.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	NumVirtualCPUs int32 `json:"numVirtualCPUs,omitempty"`
+	// StorageGiB of each node. Defaults to the least storage available for
+	// the machine type. Storage can be increased but not decreased.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:XValidation:rule="self >= oldSelf",message="storageGiB cannot be decreased"
+	StorageGiB int32 `json:"storageGiB,omitempty"`
+	// DiskIOPS of each node. Defaults to the IOPS of the storage. Only
+	// configurable on AWS.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DiskIOPS int32 `json:"diskIOPS,omitempty"`
+}
+
 // ClusterParameters are the configurable fields of a Cluster.
+// +kubebuilder:validation:XValidation:rule="has(self.serverless) != has(self.dedicated)",message="exactly one of serverless or dedicated must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.dedicated) == has(oldSelf.dedicated)",message="the plan of a Cluster cannot be changed: create a new Cluster with the other plan, restore a backup into it and switch clients over"
+// +kubebuilder:validation:XValidation:rule="!has(self.allowlistPolicy) || self.allowlistPolicy != 'Exclusive' || has(self.allowlist)",message="an Exclusive allowlistPolicy requires an allowlist, or every existing entry would be deleted"
+// +kubebuilder:validation:XValidation:rule="has(self.sourceBackupId) == has(self.sourceClusterId)",message="sourceBackupId and sourceClusterId must be set together"
+// +kubebuilder:validation:XValidation:rule="!has(self.sourceBackupId) || has(self.dedicated)",message="only dedicated clusters can be created from a backup"
+// +kubebuilder:validation:XValidation:rule="!has(self.updateStrategy) || has(self.dedicated)",message="updateStrategy only applies to dedicated clusters"
 type ClusterParameters struct {
+	// Name of the cluster in CockroachDB Cloud. Defaults to the name of the
+	// Cluster, which must then meet the same constraints.
+	// +immutable
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9-]{4,18}[a-z0-9]$`
+	Name string `json:"name,omitempty"`
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=CLOUD_PROVIDER_UNSPECIFIED;GCP;AWS
+	// +kubebuilder:validation:Enum=CLOUD_PROVIDER_UNSPECIFIED;GCP;AWS;AZURE
 	Provider cockroachdb.ApiCloudProvider `json:"provider"`
-	// +kubebuilder:validation:Required
-	Serverless *ServerlessCluster `json:"serverless"`
-	// +kubebuilder:validation:Required
-	Credentials *Credentials `json:"credentials"`
+	// Serverless configures a serverless cluster.
+	// +optional
+	Serverless *ServerlessCluster `json:"serverless,omitempty"`
+	// Dedicated configures a dedicated cluster.
+	// +optional
+	Dedicated *DedicatedCluster `json:"dedicated,omitempty"`
+	// Credentials of the SQL user created along with the Cluster. Required
+	// unless the Cluster is observe-only.
+	// +optional
+	Credentials *Credentials `json:"credentials,omitempty"`
+	// SQLUsers are additional SQL users created on the Cluster. Users are only
+	// ever added: removing a user from this list does not delete it.
+	//
+	// Deprecated: Use SQLUser managed resources instead. SQL users managed by
+	// a SQLUser are ignored.
+	// +optional
+	SQLUsers []ClusterSQLUser `json:"sqlUsers,omitempty"`
+	// Allowlist of CIDR ranges allowed to connect to the Cluster.
+	// +optional
+	Allowlist []AllowlistEntry `json:"allowlist,omitempty"`
+	// AllowlistPolicy determines whether entries missing from the allowlist
+	// are left untouched (Additive) or deleted (Exclusive).
+	// +optional
+	// +kubebuilder:default=Additive
+	AllowlistPolicy AllowlistPolicy `json:"allowlistPolicy,omitempty"`
+	// Connection configures how connection details of the Cluster are
+	// composed.
+	// +optional
+	Connection *ClusterConnection `json:"connection,omitempty"`
+	// CascadeDeletion deletes the SQL users and allowlist entries in the spec
+	// before the Cluster itself when the Cluster is deleted.
+	// +optional
+	CascadeDeletion bool `json:"cascadeDeletion,omitempty"`
+	// UpdateStrategy tunes how changes to the nodes and hardware of a
+	// dedicated Cluster are applied. Defaults to changing them in place.
+	// +optional
+	UpdateStrategy *ClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+	// SourceClusterID is the ID of the cluster whose managed backup the
+	// Cluster is created from. The source cluster must run on the same
+	// provider, in the same regions. The Cluster is created with its
+	// CockroachDB version.
+	// +immutable
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="sourceClusterId is immutable"
+	SourceClusterID string `json:"sourceClusterId,omitempty"`
+	// SourceBackupID is the ID of the managed backup of the source cluster
+	// the Cluster is created from. The backup is restored once the cluster
+	// is created, and the Cluster only becomes available once it is.
+	// +immutable
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="sourceBackupId is immutable"
+	SourceBackupID string `json:"sourceBackupId,omitempty"`
+}
+
+// ClusterInitParameters are fields of a Cluster that are only honored when
+// the Cluster is created. Later changes to them, in the spec or in
+// CockroachDB Cloud, are ignored.
+type ClusterInitParameters struct {
+	// +optional
+	Serverless *ServerlessInitParameters `json:"serverless,omitempty"`
+}
+
+// ServerlessInitParameters are fields of a serverless Cluster that are only
+// honored when the Cluster is created.
+type ServerlessInitParameters struct {
+	// SpendLimit the Cluster is created with. Takes precedence over the spend
+	// limit in forProvider, which is then no longer enforced, e.g. when the
+	// limit is managed in the CockroachDB Cloud console.
+	// +optional
+	SpendLimit *int32 `json:"spendLimit,omitempty"`
+}
+
+// ClusterConnection configures how connection details of a Cluster are
+// composed.
+type ClusterConnection struct {
+	// CASecretRef references a Secret key holding the PEM encoded CA
+	// certificate of the Cluster. When set, the CA certificate is never
+	// fetched from CockroachDB Cloud, e.g. in air-gapped environments.
+	// +optional
+	CASecretRef *xpv1.SecretKeySelector `json:"caSecretRef,omitempty"`
+	// ClientCertificate authenticates the managed resources that are managed
+	// over SQL inside the Cluster, e.g. Databases and Grants, as the user of
+	// its connection details, for clusters that do not accept passwords.
+	// +optional
+	ClientCertificate *ClientCertificate `json:"clientCertificate,omitempty"`
+}
+
+// A ClientCertificate authenticates a SQL user with a TLS client
+// certificate. Its common name must be the name of the user.
+type ClientCertificate struct {
+	// CertSecretRef references a Secret key holding the PEM encoded client
+	// certificate.
+	CertSecretRef xpv1.SecretKeySelector `json:"certSecretRef"`
+	// KeySecretRef references a Secret key holding the PEM encoded private
+	// key of the client certificate.
+	KeySecretRef xpv1.SecretKeySelector `json:"keySecretRef"`
+}
+
+// ClusterNetworking is the observed network posture of a Cluster.
+type ClusterNetworking struct {
+	// Allowlist of CIDR ranges allowed to connect to the Cluster, including
+	// entries not managed by this resource.
+	Allowlist []AllowlistEntry `json:"allowlist,omitempty"`
+	// AllowlistPropagating is true while allowlist changes are being
+	// propagated to the Cluster.
+	AllowlistPropagating bool `json:"allowlistPropagating,omitempty"`
+	// NetworkVisibility of the Cluster, if reported by the Cloud API.
+	NetworkVisibility string `json:"networkVisibility,omitempty"`
+	// CloudProvider the Cluster runs on.
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	// CloudAccountID is the AWS account ID or GCP project ID the Cluster runs
+	// in, if reported by the Cloud API, e.g. to set up network peering or
+	// PrivateLink.
+	CloudAccountID string `json:"cloudAccountId,omitempty"`
+}
+
+// A ClusterNode is a node of a dedicated Cluster.
+type ClusterNode struct {
+	// Name of the node.
+	Name string `json:"name"`
+	// Region the node runs in.
+	Region string `json:"region"`
+	// Status of the node, e.g. LIVE or NOT_READY.
+	Status string `json:"status"`
+}
+
+// An OperationType is the type of a long-running Cloud operation.
+type OperationType string
+
+// Types of long-running Cloud operations.
+const (
+	OperationCreate         OperationType = "Create"
+	OperationEdit           OperationType = "Edit"
+	OperationScale          OperationType = "Scale"
+	OperationUpgrade        OperationType = "Upgrade"
+	OperationRollback       OperationType = "Rollback"
+	OperationPatch          OperationType = "Patch"
+	OperationMaintenance    OperationType = "Maintenance"
+	OperationInstanceUpdate OperationType = "InstanceUpdate"
+	OperationCMEK           OperationType = "CMEK"
+	OperationRestore        OperationType = "Restore"
+)
+
+// A PendingOperation is a long-running Cloud operation in flight on a
+// Cluster.
+type PendingOperation struct {
+	// Type of the operation, e.g. Create, Edit for region changes, or Upgrade.
+	Type OperationType `json:"type"`
+	// StartTime is when the operation started, or when it was first observed
+	// if the Cloud API does not report it.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// A PlannedAction is the kind of a planned change.
+type PlannedAction string
+
+// Planned actions.
+const (
+	PlannedActionCreate PlannedAction = "Create"
+	PlannedActionUpdate PlannedAction = "Update"
+	PlannedActionDelete PlannedAction = "Delete"
+)
+
+// A PlannedChange is a change the controller intends to make on the next
+// update of a Cluster.
+type PlannedChange struct {
+	// Field the change applies to, e.g. spec.forProvider.allowlist.
+	Field string `json:"field"`
+	// Action taken on the field.
+	Action PlannedAction `json:"action"`
+	// Target identifies the changed element of a list, e.g. a CIDR range or
+	// the name of a SQL user.
+	// +optional
+	Target string `json:"target,omitempty"`
+	// From is the observed value, if any.
+	// +optional
+	From string `json:"from,omitempty"`
+	// To is the desired value, if any.
+	// +optional
+	To string `json:"to,omitempty"`
 }
 
 // ClusterObservation are the observable fields of a Cluster.
 type ClusterObservation struct {
 	ID    string `json:"id"`
 	State string `json:"state"`
+	// SQLDNS is the DNS name of the SQL endpoint of the Cluster, suitable as
+	// the target of a CNAME record.
+	SQLDNS string `json:"sqlDns,omitempty"`
+	// RolesHash is the hash of the admin membership, role options and grants
+	// of the user of the Cluster when they were last in sync.
+	RolesHash string `json:"rolesHash,omitempty"`
+	// SQLUserDefaultsHash is the hash of the default databases and session
+	// defaults last applied to the SQL users of the Cluster.
+	SQLUserDefaultsHash string `json:"sqlUserDefaultsHash,omitempty"`
+	// Username of the SQL user created for spec.forProvider.credentials.
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PreviousUsername is the SQL user of the credentials before their
+	// username changed. It is deleted from the cluster at
+	// PreviousUserDeletionTime.
+	// +optional
+	PreviousUsername string `json:"previousUsername,omitempty"`
+	// PreviousUserDeletionTime is when the grace period of the previous
+	// username ends.
+	// +optional
+	PreviousUserDeletionTime *metav1.Time `json:"previousUserDeletionTime,omitempty"`
+	// Networking is the observed network posture of the Cluster.
+	Networking *ClusterNetworking `json:"networking,omitempty"`
+	// Nodes of the Cluster. Only dedicated clusters report nodes.
+	Nodes []ClusterNode `json:"nodes,omitempty"`
+	// PendingOperations are the long-running Cloud operations in flight on
+	// the Cluster. An empty list means no change is in flight.
+	PendingOperations []PendingOperation `json:"pendingOperations,omitempty"`
+	// PlannedChanges are the changes the controller intends to make on the
+	// next update of the Cluster. An empty list means the Cluster is up to
+	// date.
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+	// SourceRestore is the restore of spec.forProvider.sourceBackupId into
+	// the Cluster, once started.
+	// +optional
+	SourceRestore *ClusterRestore `json:"sourceRestore,omitempty"`
+}
+
+// A ClusterRestore is the restore of a managed backup into a Cluster.
+type ClusterRestore struct {
+	// ID of the restore in CockroachDB Cloud.
+	ID string `json:"id"`
+	// Status of the restore, e.g. PENDING or SUCCESS.
+	Status string `json:"status,omitempty"`
+	// Message explaining why the restore failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// A ConfigMapReference is a reference to a ConfigMap in an arbitrary
+// namespace.
+type ConfigMapReference struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+}
+
+// A DNSEndpointSpec configures an external-dns DNSEndpoint that publishes a
+// CNAME record pointing to the SQL endpoint of a Cluster.
+type DNSEndpointSpec struct {
+	// Name of the DNSEndpoint. Defaults to the name of the Cluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Namespace of the DNSEndpoint.
+	Namespace string `json:"namespace"`
+	// Hostname of the CNAME record.
+	Hostname string `json:"hostname"`
+	// RecordTTL of the CNAME record in seconds.
+	// +optional
+	RecordTTL *int64 `json:"recordTTL,omitempty"`
 }
 
 // A ClusterSpec defines the desired state of a Cluster.
 type ClusterSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       ClusterParameters `json:"forProvider"`
+	// InitProvider holds fields that are only honored when the Cluster is
+	// created, so that later changes to them do not make the Cluster out of
+	// date.
+	// +optional
+	InitProvider *ClusterInitParameters `json:"initProvider,omitempty"`
+	// WriteConnectionInfoToConfigMapRef specifies the ConfigMap to which the
+	// non-sensitive connection information of this Cluster is written, so
+	// that consumers that only need its endpoints do not need access to the
+	// connection secret.
+	// +optional
+	WriteConnectionInfoToConfigMapRef *ConfigMapReference `json:"writeConnectionInfoToConfigMapRef,omitempty"`
+	// DNSEndpoint optionally publishes a stable hostname for the SQL endpoint
+	// of this Cluster through external-dns. Requires the DNSEndpoint CRD to
+	// be installed and the provider to be allowed to manage DNSEndpoints.
+	// +optional
+	DNSEndpoint *DNSEndpointSpec `json:"dnsEndpoint,omitempty"`
+	// ConnectionSecretReplicaNamespaces are namespaces the connection secret
+	// of this Cluster is mirrored to, under the same name. Replicas are kept
+	// in sync with the connection secret, e.g. when credentials are rotated.
+	// +optional
+	ConnectionSecretReplicaNamespaces []string `json:"connectionSecretReplicaNamespaces,omitempty"`
 }
 
 // A ClusterStatus represents the observed state of a Cluster.
@@ -90,24 +577,181 @@ type Cluster struct {
 }
 
 func (c *Cluster) CreateClusterRequest() *cockroachdb.CreateClusterRequest {
+	if d := c.Spec.ForProvider.Dedicated; d != nil {
+		regionNodes := make(map[string]int32, len(d.RegionNodes))
+		for r, n := range d.RegionNodes {
+			regionNodes[r] = n
+		}
+		hw := cockroachdb.DedicatedHardwareCreateSpecification{
+			MachineSpec: d.machineSpec(),
+			StorageGib:  d.StorageGiB,
+		}
+		if d.DiskIOPS > 0 {
+			iops := d.DiskIOPS
+			hw.DiskIops = &iops
+		}
+		return &cockroachdb.CreateClusterRequest{
+			Name:     c.ClusterName(),
+			Provider: c.Spec.ForProvider.Provider,
+			Spec: cockroachdb.CreateClusterSpecification{
+				Dedicated: &cockroachdb.DedicatedClusterCreateSpecification{
+					RegionNodes: regionNodes,
+					Hardware:    hw,
+				},
+			},
+		}
+	}
+	spendLimit := *c.Spec.ForProvider.Serverless.SpendLimit
+	if l := c.InitSpendLimit(); l != nil {
+		spendLimit = *l
+	}
 	return &cockroachdb.CreateClusterRequest{
-		Name:     c.Name,
+		Name:     c.ClusterName(),
 		Provider: c.Spec.ForProvider.Provider,
 		Spec: cockroachdb.CreateClusterSpecification{
 			Serverless: &cockroachdb.ServerlessClusterCreateSpecification{
 				Regions:    c.Spec.ForProvider.Serverless.Regions,
-				SpendLimit: *c.Spec.ForProvider.Serverless.SpendLimit,
+				SpendLimit: spendLimit,
 			},
 		},
 	}
 }
 
-func (c *Cluster) UpdateClusterSpec() *cockroachdb.UpdateClusterSpecification {
-	return &cockroachdb.UpdateClusterSpecification{
-		Serverless: &cockroachdb.ServerlessClusterUpdateSpecification{
+// ClusterName returns the name of the cluster in CockroachDB Cloud.
+func (c *Cluster) ClusterName() string {
+	if c.Spec.ForProvider.Name != "" {
+		return c.Spec.ForProvider.Name
+	}
+	return c.Name
+}
+
+// InitSpendLimit returns the spend limit that is only honored when the
+// Cluster is created, if any.
+func (c *Cluster) InitSpendLimit() *int32 {
+	if ip := c.Spec.InitProvider; ip != nil && ip.Serverless != nil {
+		return ip.Serverless.SpendLimit
+	}
+	return nil
+}
+
+// ObserveOnly returns true if the Cluster should only be observed.
+func (c *Cluster) ObserveOnly() bool {
+	return c.GetAnnotations()[AnnotationKeyObserveOnly] == "true"
+}
+
+// Plan returns the plan requested by the Cluster.
+func (c *Cluster) Plan() cockroachdb.Plan {
+	if c.Spec.ForProvider.Dedicated != nil {
+		return cockroachdb.PLAN_DEDICATED
+	}
+	return cockroachdb.PLAN_SERVERLESS
+}
+
+// Regions returns the regions requested by the Cluster, in order.
+func (c *Cluster) Regions() []string {
+	p := c.Spec.ForProvider
+	switch {
+	case p.Dedicated != nil:
+		regions := make([]string, 0, len(p.Dedicated.RegionNodes))
+		for r := range p.Dedicated.RegionNodes {
+			regions = append(regions, r)
+		}
+		sort.Strings(regions)
+		return regions
+	case p.Serverless != nil:
+		return p.Serverless.Regions
+	}
+	return nil
+}
+
+// UpdateClusterSpec returns the update of the supplied cluster. Only the
+// fields that can be updated within the plan of the cluster are set, and
+// only the dedicated nodes and hardware that drifted from the spec are
+// updated.
+func (c *Cluster) UpdateClusterSpec(cluster *cockroachdb.Cluster) *cockroachdb.UpdateClusterSpecification {
+	spec := &cockroachdb.UpdateClusterSpecification{}
+	switch {
+	case cluster.Plan == cockroachdb.PLAN_SERVERLESS && c.Spec.ForProvider.Serverless != nil && c.InitSpendLimit() == nil:
+		spec.Serverless = &cockroachdb.ServerlessClusterUpdateSpecification{
 			SpendLimit: *c.Spec.ForProvider.Serverless.SpendLimit,
-		},
+		}
+	case cluster.Plan == cockroachdb.PLAN_DEDICATED && c.Spec.ForProvider.Dedicated != nil:
+		hw := c.HardwareUpdate(cluster.Config.Dedicated)
+		nodes := c.RegionNodesUpdate(cluster.Regions)
+		if hw != nil || nodes != nil {
+			spec.Dedicated = &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes}
+		}
+	}
+	return spec
+}
+
+// HardwareUpdate returns the update of the supplied dedicated hardware to
+// match the spec, or nil if it already does. Hardware left unset in the spec
+// is never updated.
+func (c *Cluster) HardwareUpdate(hw *cockroachdb.DedicatedHardwareConfig) *cockroachdb.DedicatedHardwareUpdateSpecification {
+	d := c.Spec.ForProvider.Dedicated
+	if d == nil {
+		return nil
+	}
+	if hw == nil {
+		hw = &cockroachdb.DedicatedHardwareConfig{}
+	}
+	u := &cockroachdb.DedicatedHardwareUpdateSpecification{}
+	changed := false
+	if (d.MachineType != "" && d.MachineType != hw.MachineType) || (d.NumVirtualCPUs > 0 && d.NumVirtualCPUs != hw.NumVirtualCpus) {
+		ms := d.machineSpec()
+		u.MachineSpec = &ms
+		changed = true
+	}
+	// Storage cannot be decreased, so a cluster with more storage than
+	// requested, e.g. one that was adopted, is left alone.
+	if d.StorageGiB > hw.StorageGib {
+		storage := d.StorageGiB
+		u.StorageGib = &storage
+		changed = true
+	}
+	if d.DiskIOPS > 0 && d.DiskIOPS != hw.DiskIops {
+		iops := d.DiskIOPS
+		u.DiskIops = &iops
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return u
+}
+
+// RegionNodesUpdate returns the number of nodes of each region the supplied
+// regions must be scaled to in order to match the spec, or nil if they
+// already do.
+func (c *Cluster) RegionNodesUpdate(regions []cockroachdb.Region) *map[string]int32 {
+	d := c.Spec.ForProvider.Dedicated
+	if d == nil {
+		return nil
+	}
+	observed := make(map[string]int32, len(regions))
+	for _, r := range regions {
+		observed[r.Name] = r.NodeCount
+	}
+	if reflect.DeepEqual(observed, d.RegionNodes) {
+		return nil
+	}
+	nodes := make(map[string]int32, len(d.RegionNodes))
+	for r, n := range d.RegionNodes {
+		nodes[r] = n
+	}
+	return &nodes
+}
+
+// machineSpec returns the machine of the nodes, either by machine type or by
+// number of virtual CPUs.
+func (d *DedicatedCluster) machineSpec() cockroachdb.DedicatedMachineTypeSpecification {
+	if d.MachineType != "" {
+		machineType := d.MachineType
+		return cockroachdb.DedicatedMachineTypeSpecification{MachineType: &machineType}
 	}
+	cpus := d.NumVirtualCPUs
+	return cockroachdb.DedicatedMachineTypeSpecification{NumVirtualCpus: &cpus}
 }
 
 func (c *Cluster) CreateSQLUserRequest(pwd string) *cockroachdb.CreateSQLUserRequest {
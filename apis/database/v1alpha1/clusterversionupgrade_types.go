@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Finalizations of a major version upgrade.
+const (
+	FinalizationPending  = "Pending"
+	FinalizationFinalize = "Finalize"
+	FinalizationRollback = "Rollback"
+)
+
+// ClusterVersionUpgradeParameters are the configurable fields of a
+// ClusterVersionUpgrade.
+type ClusterVersionUpgradeParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud to
+	// upgrade.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster to upgrade, and sets clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster to upgrade, and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Version is the major version to upgrade the cluster to, e.g. v23.1.
+	// +kubebuilder:validation:Pattern=`^v[0-9]+\.[0-9]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="version is immutable"
+	Version string `json:"version"`
+	// Finalization of the upgrade once the cluster runs the new version.
+	// Pending leaves the upgrade pending finalization, so that it can still
+	// be rolled back. Finalize completes the upgrade, and Rollback reverts
+	// the cluster to the major version it ran before.
+	// +kubebuilder:validation:Enum=Pending;Finalize;Rollback
+	// +kubebuilder:default=Pending
+	// +optional
+	Finalization string `json:"finalization,omitempty"`
+}
+
+// ClusterVersionUpgradeObservation are the observable fields of a
+// ClusterVersionUpgrade.
+type ClusterVersionUpgradeObservation struct {
+	// Phase of the upgrade, e.g. PendingFinalization.
+	Phase string `json:"phase,omitempty"`
+	// CockroachVersion the cluster runs.
+	CockroachVersion string `json:"cockroachVersion,omitempty"`
+	// UpgradeStatus of the cluster reported by the Cloud API.
+	UpgradeStatus string `json:"upgradeStatus,omitempty"`
+}
+
+// A ClusterVersionUpgradeSpec defines the desired state of a
+// ClusterVersionUpgrade.
+type ClusterVersionUpgradeSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ClusterVersionUpgradeParameters `json:"forProvider"`
+}
+
+// A ClusterVersionUpgradeStatus represents the observed state of a
+// ClusterVersionUpgrade.
+type ClusterVersionUpgradeStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ClusterVersionUpgradeObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ClusterVersionUpgrade upgrades a dedicated cluster to a major version,
+// then finalizes or rolls back the upgrade. Its Upgraded condition reports
+// the phase of the upgrade. Deleting it leaves the cluster as is.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.forProvider.version"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.atProvider.phase"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type ClusterVersionUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterVersionUpgradeSpec   `json:"spec"`
+	Status ClusterVersionUpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterVersionUpgradeList contains a list of ClusterVersionUpgrade
+type ClusterVersionUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterVersionUpgrade `json:"items"`
+}
+
+// ClusterVersionUpgrade type metadata.
+var (
+	ClusterVersionUpgradeKind             = reflect.TypeOf(ClusterVersionUpgrade{}).Name()
+	ClusterVersionUpgradeGroupKind        = schema.GroupKind{Group: Group, Kind: ClusterVersionUpgradeKind}.String()
+	ClusterVersionUpgradeKindAPIVersion   = ClusterVersionUpgradeKind + "." + SchemeGroupVersion.String()
+	ClusterVersionUpgradeGroupVersionKind = SchemeGroupVersion.WithKind(ClusterVersionUpgradeKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ClusterVersionUpgrade{}, &ClusterVersionUpgradeList{})
+}
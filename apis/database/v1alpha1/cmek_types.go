@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A CMEKKeyType is the kind of key management service a key is stored in.
+type CMEKKeyType string
+
+// Supported key types.
+const (
+	CMEKKeyTypeAWSKMS      CMEKKeyType = "AWS_KMS"
+	CMEKKeyTypeGCPCloudKMS CMEKKeyType = "GCP_CLOUD_KMS"
+)
+
+// A CMEKRegionSpec is the key encrypting the data of a region of the
+// cluster.
+type CMEKRegionSpec struct {
+	// Region of the cluster the key encrypts, e.g. us-east-1.
+	Region string `json:"region"`
+	// Type of the key management service the key is stored in.
+	// +kubebuilder:validation:Enum=AWS_KMS;GCP_CLOUD_KMS
+	Type CMEKKeyType `json:"type"`
+	// URI of the key, i.e. the ARN of an AWS KMS key or the resource name of
+	// a GCP Cloud KMS key.
+	URI string `json:"uri"`
+	// AuthPrincipal CockroachDB Cloud assumes to access the key, i.e. the
+	// ARN of an AWS IAM role or the email of a GCP service account.
+	AuthPrincipal string `json:"authPrincipal"`
+}
+
+// CMEKParameters are the configurable fields of a CMEK.
+type CMEKParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud whose
+	// data is encrypted.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster whose data is encrypted, and sets
+	// clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster whose data is encrypted, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// RegionSpecs are the keys of each region of the cluster. The Cloud API
+	// cannot change the keys once CMEK is enabled.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="regionSpecs are immutable"
+	RegionSpecs []CMEKRegionSpec `json:"regionSpecs"`
+}
+
+// A CMEKKeyObservation is the state of a key of a region of the cluster.
+type CMEKKeyObservation struct {
+	// Type of the key management service the key is stored in.
+	Type string `json:"type,omitempty"`
+	// URI of the key.
+	URI string `json:"uri,omitempty"`
+	// Status of the key, e.g. ENABLED or REVOKED.
+	Status string `json:"status,omitempty"`
+	// Message explaining the status of the key, e.g. why it could not be
+	// enabled.
+	Message string `json:"message,omitempty"`
+}
+
+// A CMEKRegionObservation is the state of customer-managed encryption of a
+// region of the cluster.
+type CMEKRegionObservation struct {
+	// Region of the cluster.
+	Region string `json:"region"`
+	// Keys encrypting the data of the region.
+	Keys []CMEKKeyObservation `json:"keys,omitempty"`
+}
+
+// CMEKObservation are the observable fields of a CMEK.
+type CMEKObservation struct {
+	// Status of customer-managed encryption of the cluster, e.g. ENABLED.
+	Status string `json:"status,omitempty"`
+	// Regions are the state of customer-managed encryption of each region
+	// of the cluster.
+	Regions []CMEKRegionObservation `json:"regions,omitempty"`
+}
+
+// A CMEKSpec defines the desired state of a CMEK.
+type CMEKSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       CMEKParameters `json:"forProvider"`
+}
+
+// A CMEKStatus represents the observed state of a CMEK.
+type CMEKStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          CMEKObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A CMEK enables customer-managed encryption keys for a dedicated cluster.
+// The Cloud API cannot disable customer-managed encryption, so it stays
+// enabled when the CMEK is deleted.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterId"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type CMEK struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CMEKSpec   `json:"spec"`
+	Status CMEKStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CMEKList contains a list of CMEK
+type CMEKList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CMEK `json:"items"`
+}
+
+// CMEK type metadata.
+var (
+	CMEKKind             = reflect.TypeOf(CMEK{}).Name()
+	CMEKGroupKind        = schema.GroupKind{Group: Group, Kind: CMEKKind}.String()
+	CMEKKindAPIVersion   = CMEKKind + "." + SchemeGroupVersion.String()
+	CMEKGroupVersionKind = SchemeGroupVersion.WithKind(CMEKKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&CMEK{}, &CMEKList{})
+}
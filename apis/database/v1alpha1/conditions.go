@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types.
+const (
+	// TypePlanMigration indicates whether the plan of a Cluster differs from
+	// the plan it was requested with.
+	TypePlanMigration xpv1.ConditionType = "PlanMigration"
+
+	// TypeQuotaExceeded indicates whether a Cluster cannot be created because
+	// a limit of the organization was reached.
+	TypeQuotaExceeded xpv1.ConditionType = "QuotaExceeded"
+
+	// TypeDependentsDeleted indicates whether the dependents of a Cluster
+	// with cascade deletion were deleted.
+	TypeDependentsDeleted xpv1.ConditionType = "DependentsDeleted"
+
+	// TypeReconcileTimeout indicates whether the last operation on a
+	// Cluster was cancelled because it did not complete in time.
+	TypeReconcileTimeout xpv1.ConditionType = "ReconcileTimeout"
+
+	// TypeUsernameChanged indicates whether the last username change of the
+	// credentials of a Cluster completed.
+	TypeUsernameChanged xpv1.ConditionType = "UsernameChanged"
+
+	// TypeCloudAPIError indicates whether the last operation on a Cluster
+	// failed with a well-known error of the CockroachDB Cloud API.
+	TypeCloudAPIError xpv1.ConditionType = "CloudAPIError"
+
+	// TypeUpgraded indicates whether the major version upgrade of a
+	// ClusterVersionUpgrade completed, i.e. was finalized or rolled back.
+	TypeUpgraded xpv1.ConditionType = "Upgraded"
+)
+
+// Condition reasons.
+const (
+	ReasonPlanMigrationUnsupported xpv1.ConditionReason = "MigrationUnsupported"
+	ReasonPlanMigrationNotRequired xpv1.ConditionReason = "NotRequired"
+
+	ReasonLimitReached xpv1.ConditionReason = "LimitReached"
+	ReasonWithinLimits xpv1.ConditionReason = "WithinLimits"
+
+	ReasonDeletingSQLUsers  xpv1.ConditionReason = "DeletingSQLUsers"
+	ReasonDeletingAllowlist xpv1.ConditionReason = "DeletingAllowlist"
+	ReasonDependentsDeleted xpv1.ConditionReason = "Deleted"
+
+	ReasonDeadlineExceeded xpv1.ConditionReason = "DeadlineExceeded"
+	ReasonWithinDeadline   xpv1.ConditionReason = "WithinDeadline"
+
+	ReasonCreatingUser            xpv1.ConditionReason = "CreatingUser"
+	ReasonUsernameGracePeriod     xpv1.ConditionReason = "GracePeriod"
+	ReasonUsernameChangeCompleted xpv1.ConditionReason = "Completed"
+
+	ReasonQuotaExceeded    xpv1.ConditionReason = "QuotaExceeded"
+	ReasonInvalidRegion    xpv1.ConditionReason = "InvalidRegion"
+	ReasonNameTaken        xpv1.ConditionReason = "NameTaken"
+	ReasonPermissionDenied xpv1.ConditionReason = "PermissionDenied"
+	ReasonNoCloudAPIError  xpv1.ConditionReason = "NoError"
+)
+
+// Phases of a major version upgrade, also used as reasons of the Upgraded
+// condition.
+const (
+	UpgradePhaseUpgrading           xpv1.ConditionReason = "Upgrading"
+	UpgradePhasePendingFinalization xpv1.ConditionReason = "PendingFinalization"
+	UpgradePhaseFinalized           xpv1.ConditionReason = "Finalized"
+	UpgradePhaseRollingBack         xpv1.ConditionReason = "RollingBack"
+	UpgradePhaseRolledBack          xpv1.ConditionReason = "RolledBack"
+	UpgradePhaseFailed              xpv1.ConditionReason = "Failed"
+)
+
+// PlanMigrationUnsupported returns a condition indicating that a Cluster
+// requested a plan it cannot be migrated to by the provider. The message
+// guides users through the manual migration.
+func PlanMigrationUnsupported(from, to cockroachdb.Plan) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlanMigration,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlanMigrationUnsupported,
+		Message: fmt.Sprintf("migrating from plan %s to %s is not supported by the CockroachDB Cloud API: "+
+			"create a new Cluster with the %s plan, restore a backup into it and switch clients over, "+
+			"then delete this Cluster, whose plan cannot be changed", from, to, to),
+	}
+}
+
+// PlanMigrationNotRequired returns a condition indicating that a Cluster runs
+// the plan it was requested with.
+func PlanMigrationNotRequired() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlanMigration,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlanMigrationNotRequired,
+	}
+}
+
+// QuotaExceeded returns a condition indicating that a Cluster cannot be
+// created because a limit of the organization was reached.
+func QuotaExceeded(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeQuotaExceeded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLimitReached,
+		Message:            msg,
+	}
+}
+
+// WithinQuota returns a condition indicating that a Cluster was created
+// within the limits of the organization.
+func WithinQuota() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeQuotaExceeded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWithinLimits,
+	}
+}
+
+// DeletingDependents returns a condition indicating that the dependents of a
+// Cluster are being deleted. The reason tells which kind of dependent.
+func DeletingDependents(r xpv1.ConditionReason) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependentsDeleted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+	}
+}
+
+// DependentsDeleted returns a condition indicating that the dependents of a
+// Cluster were deleted.
+func DependentsDeleted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependentsDeleted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDependentsDeleted,
+	}
+}
+
+// ReconcileTimedOut returns a condition indicating that the supplied operation
+// on a Cluster was cancelled after the supplied timeout.
+func ReconcileTimedOut(operation string, timeout time.Duration) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReconcileTimeout,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeadlineExceeded,
+		Message:            fmt.Sprintf("%s did not complete within %s", operation, timeout),
+	}
+}
+
+// ReconcileWithinTimeout returns a condition indicating that the last
+// operation on a Cluster completed in time.
+func ReconcileWithinTimeout() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReconcileTimeout,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWithinDeadline,
+	}
+}
+
+// CreatingUser returns a condition indicating that the SQL user of the new
+// username of a Cluster is being created.
+func CreatingUser(username string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUsernameChanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCreatingUser,
+		Message:            fmt.Sprintf("creating SQL user %q", username),
+	}
+}
+
+// UsernameGracePeriod returns a condition indicating that the connection
+// details of a Cluster were republished for its new username, and that the SQL
+// user of the previous username is deleted at the supplied time.
+func UsernameGracePeriod(previous string, until metav1.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUsernameChanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUsernameGracePeriod,
+		Message:            fmt.Sprintf("SQL user %q is deleted at %s", previous, until.UTC().Format(time.RFC3339)),
+	}
+}
+
+// UsernameChangeCompleted returns a condition indicating that the SQL user of
+// the previous username of a Cluster was deleted.
+func UsernameChangeCompleted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUsernameChanged,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUsernameChangeCompleted,
+	}
+}
+
+// CloudAPIError returns a condition indicating that the last operation on a
+// Cluster failed with the supplied well-known Cloud API error.
+func CloudAPIError(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCloudAPIError,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// NoCloudAPIError returns a condition indicating that the last operation on a
+// Cluster did not fail with a well-known Cloud API error.
+func NoCloudAPIError() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCloudAPIError,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoCloudAPIError,
+	}
+}
+
+// UpgradeProgress returns a condition indicating the supplied phase of a
+// major version upgrade. Only finalized and rolled back upgrades completed.
+func UpgradeProgress(phase xpv1.ConditionReason, msg string) xpv1.Condition {
+	status := corev1.ConditionFalse
+	if phase == UpgradePhaseFinalized || phase == UpgradePhaseRolledBack {
+		status = corev1.ConditionTrue
+	}
+	return xpv1.Condition{
+		Type:               TypeUpgraded,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             phase,
+		Message:            msg,
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A CostReportSpec selects the invoice a CostReport allocates, and the labels
+// of Clusters it is allocated by.
+type CostReportSpec struct {
+	// ProviderConfigReference specifies the ProviderConfig of the
+	// organization whose invoice is reported.
+	// +kubebuilder:default={"name": "default"}
+	ProviderConfigReference xpv1.Reference `json:"providerConfigRef,omitempty"`
+	// InvoiceID of the reported invoice. The invoice of the latest billing
+	// period is reported if omitted.
+	// +optional
+	InvoiceID string `json:"invoiceId,omitempty"`
+	// LabelKeys of Clusters their spend is allocated by, e.g. team.
+	// +kubebuilder:validation:MinItems=1
+	LabelKeys []string `json:"labelKeys"`
+}
+
+// A CostAllocation is the spend of the Clusters with the same value of a
+// label.
+type CostAllocation struct {
+	// LabelKey the spend is allocated by.
+	LabelKey string `json:"labelKey"`
+	// LabelValue of the Clusters. It is empty for Clusters without the
+	// label.
+	LabelValue string `json:"labelValue"`
+	// Amount billed for the Clusters, in the currency of the report.
+	Amount string `json:"amount"`
+	// Clusters is the number of Clusters the spend is allocated to.
+	Clusters int32 `json:"clusters"`
+}
+
+// A ClusterCost is the spend of a single Cluster.
+type ClusterCost struct {
+	// Name of the Cluster managing the cluster.
+	Name string `json:"name"`
+	// Namespace of the Cluster, if it is namespaced.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// ID of the cluster in CockroachDB Cloud.
+	ID string `json:"id"`
+	// Amount billed for the cluster, in the currency of the report.
+	Amount string `json:"amount"`
+}
+
+// An UnmanagedClusterCost is the spend of a single cluster no Cluster
+// manages.
+type UnmanagedClusterCost struct {
+	// Name of the cluster in CockroachDB Cloud.
+	Name string `json:"name"`
+	// ID of the cluster in CockroachDB Cloud.
+	ID string `json:"id"`
+	// Amount billed for the cluster, in the currency of the report.
+	Amount string `json:"amount"`
+}
+
+// A CostReportStatus is the spend of an invoice allocated to the labels of
+// the Clusters it was billed for. Amounts are decimal strings, so that they
+// can be exported by kube-state-metrics.
+type CostReportStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	// InvoiceID of the reported invoice.
+	// +optional
+	InvoiceID string `json:"invoiceId,omitempty"`
+	// PeriodStart is the start of the billing period of the invoice.
+	// +optional
+	PeriodStart *metav1.Time `json:"periodStart,omitempty"`
+	// PeriodEnd is the end of the billing period of the invoice.
+	// +optional
+	PeriodEnd *metav1.Time `json:"periodEnd,omitempty"`
+	// Currency of the amounts, e.g. USD.
+	// +optional
+	Currency string `json:"currency,omitempty"`
+	// Total amount of the invoice.
+	// +optional
+	Total string `json:"total,omitempty"`
+	// Unmanaged is the amount billed for clusters that no Cluster manages.
+	// +optional
+	Unmanaged string `json:"unmanaged,omitempty"`
+	// Allocations of the spend of managed clusters, by label.
+	// +optional
+	Allocations []CostAllocation `json:"allocations,omitempty"`
+	// Clusters are the spend of each managed cluster.
+	// +optional
+	Clusters []ClusterCost `json:"clusters,omitempty"`
+	// UnmanagedClusters are the spend of each cluster no Cluster manages.
+	// +optional
+	UnmanagedClusters []UnmanagedClusterCost `json:"unmanagedClusters,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A CostReport allocates the spend of an invoice of a CockroachDB Cloud
+// organization to the labels of the Clusters it was billed for, e.g. for
+// chargeback. It also reports the spend of every cluster of the invoice. It
+// only observes the Cloud API.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="INVOICE",type="string",JSONPath=".status.invoiceId"
+// +kubebuilder:printcolumn:name="TOTAL",type="string",JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="CURRENCY",type="string",JSONPath=".status.currency"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,cockroachdb}
+type CostReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CostReportSpec   `json:"spec"`
+	Status CostReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CostReportList contains a list of CostReport
+type CostReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CostReport `json:"items"`
+}
+
+// CostReport type metadata.
+var (
+	CostReportKind             = reflect.TypeOf(CostReport{}).Name()
+	CostReportGroupKind        = schema.GroupKind{Group: Group, Kind: CostReportKind}.String()
+	CostReportKindAPIVersion   = CostReportKind + "." + SchemeGroupVersion.String()
+	CostReportGroupVersionKind = SchemeGroupVersion.WithKind(CostReportKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&CostReport{}, &CostReportList{})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DatabaseParameters are the configurable fields of a Database.
+type DatabaseParameters struct {
+	// ClusterRef references the Cluster the database is created in, over SQL
+	// as the user whose connection details the Cluster publishes. The
+	// Cluster cannot be deleted while the Database exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the database is created in, and
+	// sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Owner is the role that owns the database. Defaults to the user the
+	// database is created as.
+	// +optional
+	Owner *string `json:"owner,omitempty"`
+	// Cascade drops the tables and other objects of the database along with
+	// it when the Database is deleted. Deleting a database that is not
+	// empty fails otherwise.
+	// +optional
+	Cascade bool `json:"cascade,omitempty"`
+}
+
+// DatabaseObservation are the observable fields of a Database.
+type DatabaseObservation struct {
+	// Owner is the role that owns the database.
+	Owner string `json:"owner,omitempty"`
+}
+
+// A DatabaseSpec defines the desired state of a Database.
+type DatabaseSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       DatabaseParameters `json:"forProvider"`
+}
+
+// A DatabaseStatus represents the observed state of a Database.
+type DatabaseStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          DatabaseObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Database is a database of a cluster, managed over SQL. Its external name
+// is the name of the database, and defaults to the name of the Database.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseSpec   `json:"spec"`
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Database `json:"items"`
+}
+
+// Database type metadata.
+var (
+	DatabaseKind                 = reflect.TypeOf(Database{}).Name()
+	DatabaseGroupKind            = schema.GroupKind{Group: Group, Kind: DatabaseKind}.String()
+	DatabaseKindAPIVersion       = DatabaseKind + "." + SchemeGroupVersion.String()
+	DatabaseGroupVersionKind     = SchemeGroupVersion.WithKind(DatabaseKind)
+	DatabaseListGroupVersionKind = SchemeGroupVersion.WithKind(DatabaseKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
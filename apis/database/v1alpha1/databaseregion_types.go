@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Survival goals of multi-region databases.
+const (
+	SurvivalGoalZone   = "ZONE"
+	SurvivalGoalRegion = "REGION"
+)
+
+// DatabaseRegionParameters are the configurable fields of a DatabaseRegion.
+type DatabaseRegionParameters struct {
+	// ClusterRef references the multi-region Cluster of the database, over
+	// SQL as the user whose connection details the Cluster publishes. The
+	// Cluster cannot be deleted while the DatabaseRegion exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster of the database, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database whose regions are managed.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database whose regions are managed, and
+	// sets database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database whose regions are managed, and
+	// sets databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// PrimaryRegion of the database, e.g. us-east1. It must be a region of
+	// the cluster.
+	PrimaryRegion string `json:"primaryRegion"`
+	// Regions of the database besides its primary region. Regions of the
+	// database that are not listed are dropped.
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+	// SurvivalGoal of the database. Surviving the failure of a REGION
+	// requires at least three regions. CockroachDB defaults to ZONE.
+	// +kubebuilder:validation:Enum=ZONE;REGION
+	// +optional
+	SurvivalGoal string `json:"survivalGoal,omitempty"`
+}
+
+// DatabaseRegionObservation are the observable fields of a DatabaseRegion.
+type DatabaseRegionObservation struct {
+	// PrimaryRegion of the database.
+	PrimaryRegion string `json:"primaryRegion,omitempty"`
+	// Regions of the database, including its primary region.
+	Regions []string `json:"regions,omitempty"`
+	// SurvivalGoal of the database.
+	SurvivalGoal string `json:"survivalGoal,omitempty"`
+}
+
+// A DatabaseRegionSpec defines the desired state of a DatabaseRegion.
+type DatabaseRegionSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       DatabaseRegionParameters `json:"forProvider"`
+}
+
+// A DatabaseRegionStatus represents the observed state of a DatabaseRegion.
+type DatabaseRegionStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          DatabaseRegionObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DatabaseRegion makes a database of a multi-region cluster a multi-region
+// database, and manages its regions and survival goal over SQL. Deleting it
+// drops the regions of the database, which makes it a single-region database
+// again.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DATABASE",type="string",JSONPath=".spec.forProvider.database"
+// +kubebuilder:printcolumn:name="PRIMARY",type="string",JSONPath=".status.atProvider.primaryRegion"
+// +kubebuilder:printcolumn:name="SURVIVAL",type="string",JSONPath=".status.atProvider.survivalGoal"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type DatabaseRegion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseRegionSpec   `json:"spec"`
+	Status DatabaseRegionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseRegionList contains a list of DatabaseRegion
+type DatabaseRegionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseRegion `json:"items"`
+}
+
+// DatabaseRegion type metadata.
+var (
+	DatabaseRegionKind                 = reflect.TypeOf(DatabaseRegion{}).Name()
+	DatabaseRegionGroupKind            = schema.GroupKind{Group: Group, Kind: DatabaseRegionKind}.String()
+	DatabaseRegionKindAPIVersion       = DatabaseRegionKind + "." + SchemeGroupVersion.String()
+	DatabaseRegionGroupVersionKind     = SchemeGroupVersion.WithKind(DatabaseRegionKind)
+	DatabaseRegionListGroupVersionKind = SchemeGroupVersion.WithKind(DatabaseRegionKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&DatabaseRegion{}, &DatabaseRegionList{})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultPrivilegesParameters are the configurable fields of a
+// DefaultPrivileges.
+type DefaultPrivilegesParameters struct {
+	// ClusterRef references the Cluster the default privileges are set in,
+	// over SQL as the user whose connection details the Cluster publishes.
+	// The Cluster cannot be deleted while the DefaultPrivileges exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the default privileges are set
+	// in, and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database the default privileges apply to objects created in.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database the default privileges apply to
+	// objects created in, and sets database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database the default privileges apply
+	// to objects created in, and sets databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Schema the default privileges apply to objects created in. They
+	// apply to objects created in any schema of the database if omitted.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="schema is immutable"
+	// +optional
+	Schema string `json:"schema,omitempty"`
+	// ForRole is the role whose new objects the default privileges apply
+	// to. Defaults to the user the default privileges are set as.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forRole is immutable"
+	// +optional
+	ForRole string `json:"forRole,omitempty"`
+	// ObjectType is the type of the objects the default privileges apply
+	// to.
+	// +kubebuilder:validation:Enum=TABLES;SEQUENCES;TYPES;SCHEMAS;FUNCTIONS
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="objectType is immutable"
+	// +kubebuilder:default=TABLES
+	// +optional
+	ObjectType string `json:"objectType,omitempty"`
+	// Role the privileges are granted to.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="role is immutable"
+	Role string `json:"role"`
+	// Privileges granted to the role on new objects, e.g. SELECT or ALL.
+	// Default privileges of the role on the same objects that are not
+	// listed are revoked.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=ALL;CREATE;DELETE;DROP;EXECUTE;INSERT;SELECT;UPDATE;USAGE
+	Privileges []string `json:"privileges"`
+	// WithGrantOption allows the role to grant the privileges to other
+	// roles.
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+}
+
+// A DefaultPrivilegesSpec defines the desired state of a DefaultPrivileges.
+type DefaultPrivilegesSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       DefaultPrivilegesParameters `json:"forProvider"`
+}
+
+// A DefaultPrivilegesStatus represents the observed state of a
+// DefaultPrivileges.
+type DefaultPrivilegesStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DefaultPrivileges grants privileges to a role on objects that are
+// created in a database in the future, over SQL. Objects that already exist
+// are not affected; use a Grant for those.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.forProvider.role"
+// +kubebuilder:printcolumn:name="DATABASE",type="string",JSONPath=".spec.forProvider.database"
+// +kubebuilder:printcolumn:name="OBJECTS",type="string",JSONPath=".spec.forProvider.objectType"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type DefaultPrivileges struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DefaultPrivilegesSpec   `json:"spec"`
+	Status DefaultPrivilegesStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DefaultPrivilegesList contains a list of DefaultPrivileges
+type DefaultPrivilegesList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DefaultPrivileges `json:"items"`
+}
+
+// DefaultPrivileges type metadata.
+var (
+	DefaultPrivilegesKind                 = reflect.TypeOf(DefaultPrivileges{}).Name()
+	DefaultPrivilegesGroupKind            = schema.GroupKind{Group: Group, Kind: DefaultPrivilegesKind}.String()
+	DefaultPrivilegesKindAPIVersion       = DefaultPrivilegesKind + "." + SchemeGroupVersion.String()
+	DefaultPrivilegesGroupVersionKind     = SchemeGroupVersion.WithKind(DefaultPrivilegesKind)
+	DefaultPrivilegesListGroupVersionKind = SchemeGroupVersion.WithKind(DefaultPrivilegesKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&DefaultPrivileges{}, &DefaultPrivilegesList{})
+}
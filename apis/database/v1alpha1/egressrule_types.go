@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EgressRuleParameters are the configurable fields of an EgressRule.
+type EgressRuleParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud whose
+	// egress traffic is restricted.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster whose egress traffic is restricted,
+	// and sets clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster whose egress traffic is restricted,
+	// and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Name of the rule, unique within the cluster.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name"`
+	// Type of the destination.
+	// +kubebuilder:validation:Enum=FQDN;CIDR
+	Type string `json:"type"`
+	// Destination the cluster may reach, i.e. a fully qualified domain name
+	// or a CIDR range.
+	Destination string `json:"destination"`
+	// Ports the cluster may reach the destination on. All ports if omitted.
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+	// Paths the cluster may request from an FQDN destination. All paths if
+	// omitted.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+	// Description of the rule.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// EgressRuleObservation are the observable fields of an EgressRule.
+type EgressRuleObservation struct {
+	// State of the rule, e.g. ACTIVE.
+	State string `json:"state,omitempty"`
+}
+
+// An EgressRuleSpec defines the desired state of an EgressRule.
+type EgressRuleSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       EgressRuleParameters `json:"forProvider"`
+}
+
+// An EgressRuleStatus represents the observed state of an EgressRule.
+type EgressRuleStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          EgressRuleObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An EgressRule allows a dedicated cluster with restricted egress traffic to
+// reach a destination. Its external name is the ID of the rule in
+// CockroachDB Cloud.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DESTINATION",type="string",JSONPath=".spec.forProvider.destination"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type EgressRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EgressRuleSpec   `json:"spec"`
+	Status EgressRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EgressRuleList contains a list of EgressRule
+type EgressRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EgressRule `json:"items"`
+}
+
+// EgressRule type metadata.
+var (
+	EgressRuleKind             = reflect.TypeOf(EgressRule{}).Name()
+	EgressRuleGroupKind        = schema.GroupKind{Group: Group, Kind: EgressRuleKind}.String()
+	EgressRuleKindAPIVersion   = EgressRuleKind + "." + SchemeGroupVersion.String()
+	EgressRuleGroupVersionKind = SchemeGroupVersion.WithKind(EgressRuleKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&EgressRule{}, &EgressRuleList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GrantParameters are the configurable fields of a Grant.
+type GrantParameters struct {
+	// ClusterRef references the Cluster the privileges are granted in, over
+	// SQL as the user whose connection details the Cluster publishes. The
+	// Cluster cannot be deleted while the Grant exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the privileges are granted in, and
+	// sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Role the privileges are granted to.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="role is immutable"
+	Role string `json:"role"`
+	// Database the privileges are granted on, or that contains the schema or
+	// tables they are granted on.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database the privileges are granted on, and
+	// sets database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database the privileges are granted on,
+	// and sets databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Schema the privileges are granted on, or that contains the tables they
+	// are granted on. Privileges are granted on the database if neither
+	// schema nor tables are set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="schema is immutable"
+	// +optional
+	Schema string `json:"schema,omitempty"`
+	// Tables the privileges are granted on. Tables are looked up in the
+	// public schema unless schema is set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tables are immutable"
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+	// Privileges granted to the role, e.g. CONNECT, SELECT or ALL.
+	// Privileges of the role on the same objects that are not listed are
+	// revoked.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=ALL;BACKUP;CHANGEFEED;CONNECT;CREATE;DELETE;DROP;EXECUTE;INSERT;RESTORE;SELECT;UPDATE;USAGE;ZONECONFIG
+	Privileges []string `json:"privileges"`
+	// WithGrantOption allows the role to grant the privileges to other
+	// roles.
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+}
+
+// A GrantSpec defines the desired state of a Grant.
+type GrantSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       GrantParameters `json:"forProvider"`
+}
+
+// A GrantStatus represents the observed state of a Grant.
+type GrantStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Grant grants privileges on a database, a schema or tables to a role,
+// over SQL. Missing privileges are granted again on each reconcile, and the
+// privileges are revoked when the Grant is deleted.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.forProvider.role"
+// +kubebuilder:printcolumn:name="DATABASE",type="string",JSONPath=".spec.forProvider.database"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type Grant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrantSpec   `json:"spec"`
+	Status GrantStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GrantList contains a list of Grant
+type GrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Grant `json:"items"`
+}
+
+// Grant type metadata.
+var (
+	GrantKind                 = reflect.TypeOf(Grant{}).Name()
+	GrantGroupKind            = schema.GroupKind{Group: Group, Kind: GrantKind}.String()
+	GrantKindAPIVersion       = GrantKind + "." + SchemeGroupVersion.String()
+	GrantGroupVersionKind     = SchemeGroupVersion.WithKind(GrantKind)
+	GrantListGroupVersionKind = SchemeGroupVersion.WithKind(GrantKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&Grant{}, &GrantList{})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ManagedBackupConfigParameters are the configurable fields of a
+// ManagedBackupConfig. Omitted fields are left as configured in CockroachDB
+// Cloud.
+type ManagedBackupConfigParameters struct {
+	// ClusterID is the ID of the cluster in CockroachDB Cloud whose managed
+	// backups are configured.
+	// +optional
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster whose managed backups are
+	// configured, and sets clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster whose managed backups are
+	// configured, and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Enabled turns managed backups of the cluster on or off.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// FrequencyMinutes is how often the cluster is backed up.
+	// +optional
+	// +kubebuilder:validation:Enum=5;10;15;30;60;240;1440
+	FrequencyMinutes *int32 `json:"frequencyMinutes,omitempty"`
+	// RetentionDays is how long backups are kept. CockroachDB Cloud only
+	// allows to change it once on some plans.
+	// +optional
+	// +kubebuilder:validation:Enum=2;7;30;90;365
+	RetentionDays *int32 `json:"retentionDays,omitempty"`
+}
+
+// ManagedBackupConfigObservation are the observable fields of a
+// ManagedBackupConfig.
+type ManagedBackupConfigObservation struct {
+	// Enabled is true if managed backups of the cluster are on.
+	Enabled bool `json:"enabled,omitempty"`
+	// FrequencyMinutes is how often the cluster is backed up.
+	FrequencyMinutes int32 `json:"frequencyMinutes,omitempty"`
+	// RetentionDays is how long backups are kept.
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+}
+
+// A ManagedBackupConfigSpec defines the desired state of a
+// ManagedBackupConfig.
+type ManagedBackupConfigSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ManagedBackupConfigParameters `json:"forProvider"`
+}
+
+// A ManagedBackupConfigStatus represents the observed state of a
+// ManagedBackupConfig.
+type ManagedBackupConfigStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ManagedBackupConfigObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ManagedBackupConfig configures the backups CockroachDB Cloud takes of a
+// cluster. Changes made in the console are reverted. Deleting it leaves the
+// configuration in place.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ENABLED",type="boolean",JSONPath=".status.atProvider.enabled"
+// +kubebuilder:printcolumn:name="FREQUENCY",type="integer",JSONPath=".status.atProvider.frequencyMinutes"
+// +kubebuilder:printcolumn:name="RETENTION",type="integer",JSONPath=".status.atProvider.retentionDays"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type ManagedBackupConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagedBackupConfigSpec   `json:"spec"`
+	Status ManagedBackupConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagedBackupConfigList contains a list of ManagedBackupConfig
+type ManagedBackupConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagedBackupConfig `json:"items"`
+}
+
+// ManagedBackupConfig type metadata.
+var (
+	ManagedBackupConfigKind             = reflect.TypeOf(ManagedBackupConfig{}).Name()
+	ManagedBackupConfigGroupKind        = schema.GroupKind{Group: Group, Kind: ManagedBackupConfigKind}.String()
+	ManagedBackupConfigKindAPIVersion   = ManagedBackupConfigKind + "." + SchemeGroupVersion.String()
+	ManagedBackupConfigGroupVersionKind = SchemeGroupVersion.WithKind(ManagedBackupConfigKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ManagedBackupConfig{}, &ManagedBackupConfigList{})
+}
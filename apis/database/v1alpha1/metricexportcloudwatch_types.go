@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MetricExportCloudWatchParameters are the configurable fields of a
+// MetricExportCloudWatch.
+type MetricExportCloudWatchParameters struct {
+	// ClusterID is the ID of the dedicated AWS cluster in CockroachDB Cloud
+	// whose metrics are exported.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster whose metrics are exported, and sets
+	// clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster whose metrics are exported, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// TargetRegion metrics are exported to. Defaults to the region of the
+	// cluster.
+	// +optional
+	TargetRegion string `json:"targetRegion,omitempty"`
+	// LogGroupName of the CloudWatch log group metrics are exported to.
+	// Defaults to a log group named after the cluster.
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+	// RoleARN of the IAM role CockroachDB Cloud assumes to write to
+	// CloudWatch.
+	RoleARN string `json:"roleArn"`
+}
+
+// A MetricExportCloudWatchSpec defines the desired state of a
+// MetricExportCloudWatch.
+type MetricExportCloudWatchSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       MetricExportCloudWatchParameters `json:"forProvider"`
+}
+
+// A MetricExportCloudWatchStatus represents the observed state of a
+// MetricExportCloudWatch.
+type MetricExportCloudWatchStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          MetricExportObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A MetricExportCloudWatch exports the metrics of a dedicated AWS cluster to
+// AWS CloudWatch.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterId"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type MetricExportCloudWatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MetricExportCloudWatchSpec   `json:"spec"`
+	Status MetricExportCloudWatchStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MetricExportCloudWatchList contains a list of MetricExportCloudWatch
+type MetricExportCloudWatchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MetricExportCloudWatch `json:"items"`
+}
+
+// MetricExportCloudWatch type metadata.
+var (
+	MetricExportCloudWatchKind             = reflect.TypeOf(MetricExportCloudWatch{}).Name()
+	MetricExportCloudWatchGroupKind        = schema.GroupKind{Group: Group, Kind: MetricExportCloudWatchKind}.String()
+	MetricExportCloudWatchKindAPIVersion   = MetricExportCloudWatchKind + "." + SchemeGroupVersion.String()
+	MetricExportCloudWatchGroupVersionKind = SchemeGroupVersion.WithKind(MetricExportCloudWatchKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&MetricExportCloudWatch{}, &MetricExportCloudWatchList{})
+}
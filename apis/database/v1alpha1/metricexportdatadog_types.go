@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MetricExportDatadogParameters are the configurable fields of a
+// MetricExportDatadog.
+type MetricExportDatadogParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud whose
+	// metrics are exported.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster whose metrics are exported, and sets
+	// clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster whose metrics are exported, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Site of the Datadog account metrics are exported to.
+	// +kubebuilder:validation:Enum=US1;US3;US5;US1_GOV;EU1;AP1
+	Site string `json:"site"`
+	// APIKeySecretRef references the Datadog API key metrics are exported
+	// with. The export is reconfigured when the referenced Secret changes.
+	APIKeySecretRef xpv1.SecretKeySelector `json:"apiKeySecretRef"`
+}
+
+// MetricExportObservation are the observable fields of a metric export.
+type MetricExportObservation struct {
+	// Status of the metric export, e.g. ENABLED.
+	Status string `json:"status,omitempty"`
+	// Message explaining the status of the metric export, e.g. why it
+	// failed.
+	Message string `json:"message,omitempty"`
+	// SecretVersion is the resource version of the credentials Secret last
+	// applied to the metric export, if it has any.
+	SecretVersion string `json:"secretVersion,omitempty"`
+}
+
+// A MetricExportDatadogSpec defines the desired state of a
+// MetricExportDatadog.
+type MetricExportDatadogSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       MetricExportDatadogParameters `json:"forProvider"`
+}
+
+// A MetricExportDatadogStatus represents the observed state of a
+// MetricExportDatadog.
+type MetricExportDatadogStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          MetricExportObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A MetricExportDatadog exports the metrics of a dedicated cluster to
+// Datadog.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterId"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type MetricExportDatadog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MetricExportDatadogSpec   `json:"spec"`
+	Status MetricExportDatadogStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MetricExportDatadogList contains a list of MetricExportDatadog
+type MetricExportDatadogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MetricExportDatadog `json:"items"`
+}
+
+// MetricExportDatadog type metadata.
+var (
+	MetricExportDatadogKind             = reflect.TypeOf(MetricExportDatadog{}).Name()
+	MetricExportDatadogGroupKind        = schema.GroupKind{Group: Group, Kind: MetricExportDatadogKind}.String()
+	MetricExportDatadogKindAPIVersion   = MetricExportDatadogKind + "." + SchemeGroupVersion.String()
+	MetricExportDatadogGroupVersionKind = SchemeGroupVersion.WithKind(MetricExportDatadogKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&MetricExportDatadog{}, &MetricExportDatadogList{})
+}
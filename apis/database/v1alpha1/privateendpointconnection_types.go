@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PrivateEndpointConnectionParameters are the configurable fields of a
+// PrivateEndpointConnection.
+type PrivateEndpointConnectionParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud the
+	// private endpoint connects to.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster the private endpoint connects to, and
+	// sets clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the private endpoint connects to,
+	// and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// EndpointID of the private endpoint to accept, i.e. the resource ID of
+	// an Azure private endpoint or the ID of an AWS VPC endpoint.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="endpointId is immutable"
+	EndpointID string `json:"endpointId"`
+}
+
+// PrivateEndpointConnectionObservation are the observable fields of a
+// PrivateEndpointConnection.
+type PrivateEndpointConnectionObservation struct {
+	// Region of the cluster the private endpoint connects to.
+	Region string `json:"region,omitempty"`
+	// CloudProvider the private endpoint runs in.
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	// Status of the connection, e.g. ENDPOINT_AVAILABLE.
+	Status string `json:"status,omitempty"`
+	// ServiceID of the endpoint service the private endpoint connects to.
+	ServiceID string `json:"serviceId,omitempty"`
+}
+
+// A PrivateEndpointConnectionSpec defines the desired state of a
+// PrivateEndpointConnection.
+type PrivateEndpointConnectionSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PrivateEndpointConnectionParameters `json:"forProvider"`
+}
+
+// A PrivateEndpointConnectionStatus represents the observed state of a
+// PrivateEndpointConnection.
+type PrivateEndpointConnectionStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PrivateEndpointConnectionObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PrivateEndpointConnection accepts the connection of a private endpoint,
+// e.g. an Azure private endpoint, to the endpoint service a
+// PrivateEndpointService created for a dedicated cluster.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".status.atProvider.cloudProvider"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type PrivateEndpointConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PrivateEndpointConnectionSpec   `json:"spec"`
+	Status PrivateEndpointConnectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PrivateEndpointConnectionList contains a list of PrivateEndpointConnection
+type PrivateEndpointConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PrivateEndpointConnection `json:"items"`
+}
+
+// PrivateEndpointConnection type metadata.
+var (
+	PrivateEndpointConnectionKind             = reflect.TypeOf(PrivateEndpointConnection{}).Name()
+	PrivateEndpointConnectionGroupKind        = schema.GroupKind{Group: Group, Kind: PrivateEndpointConnectionKind}.String()
+	PrivateEndpointConnectionKindAPIVersion   = PrivateEndpointConnectionKind + "." + SchemeGroupVersion.String()
+	PrivateEndpointConnectionGroupVersionKind = SchemeGroupVersion.WithKind(PrivateEndpointConnectionKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&PrivateEndpointConnection{}, &PrivateEndpointConnectionList{})
+}
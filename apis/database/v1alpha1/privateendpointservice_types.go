@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PrivateEndpointServiceParameters are the configurable fields of a
+// PrivateEndpointService.
+type PrivateEndpointServiceParameters struct {
+	// ClusterID is the ID of the dedicated cluster in CockroachDB Cloud the
+	// endpoint services are created for.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterId is immutable"
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster the endpoint services are created for,
+	// and sets clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the endpoint services are created
+	// for, and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+}
+
+// A PrivateEndpointServiceRegion is the endpoint service of a region of the
+// cluster.
+type PrivateEndpointServiceRegion struct {
+	// Region of the cluster the endpoint service exposes.
+	Region string `json:"region"`
+	// CloudProvider the endpoint service runs in.
+	CloudProvider string `json:"cloudProvider"`
+	// Status of the endpoint service, e.g.
+	// ENDPOINT_SERVICE_STATUS_AVAILABLE.
+	Status string `json:"status"`
+	// ServiceName of the AWS PrivateLink endpoint service VPC endpoints
+	// connect to.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// ServiceID of the AWS PrivateLink endpoint service, or the resource ID
+	// of the Azure Private Link service.
+	// +optional
+	ServiceID string `json:"serviceId,omitempty"`
+	// AvailabilityZoneIDs the AWS PrivateLink endpoint service is available
+	// in.
+	// +optional
+	AvailabilityZoneIDs []string `json:"availabilityZoneIds,omitempty"`
+	// AliasName of the Azure Private Link service private endpoints connect
+	// to.
+	// +optional
+	AliasName string `json:"aliasName,omitempty"`
+}
+
+// PrivateEndpointServiceObservation are the observable fields of a
+// PrivateEndpointService.
+type PrivateEndpointServiceObservation struct {
+	// Services are the endpoint services of the regions of the cluster.
+	Services []PrivateEndpointServiceRegion `json:"services,omitempty"`
+}
+
+// A PrivateEndpointServiceSpec defines the desired state of a
+// PrivateEndpointService.
+type PrivateEndpointServiceSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PrivateEndpointServiceParameters `json:"forProvider"`
+}
+
+// A PrivateEndpointServiceStatus represents the observed state of a
+// PrivateEndpointService.
+type PrivateEndpointServiceStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PrivateEndpointServiceObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PrivateEndpointService creates the AWS PrivateLink endpoint services of a
+// dedicated cluster, one per region. Their service names are reported in its
+// status, so that VPC endpoints can be wired up to them. The Cloud API
+// cannot delete endpoint services, so they are left in place when the
+// PrivateEndpointService is deleted and go with their cluster.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterId"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type PrivateEndpointService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PrivateEndpointServiceSpec   `json:"spec"`
+	Status PrivateEndpointServiceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PrivateEndpointServiceList contains a list of PrivateEndpointService
+type PrivateEndpointServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PrivateEndpointService `json:"items"`
+}
+
+// PrivateEndpointService type metadata.
+var (
+	PrivateEndpointServiceKind             = reflect.TypeOf(PrivateEndpointService{}).Name()
+	PrivateEndpointServiceGroupKind        = schema.GroupKind{Group: Group, Kind: PrivateEndpointServiceKind}.String()
+	PrivateEndpointServiceKindAPIVersion   = PrivateEndpointServiceKind + "." + SchemeGroupVersion.String()
+	PrivateEndpointServiceGroupVersionKind = SchemeGroupVersion.WithKind(PrivateEndpointServiceKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&PrivateEndpointService{}, &PrivateEndpointServiceList{})
+}
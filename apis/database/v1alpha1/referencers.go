@@ -0,0 +1,585 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterID extracts the ID in CockroachDB Cloud of a referenced Cluster. It
+// is empty until the cluster was created.
+func ClusterID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*Cluster)
+		if !ok {
+			return ""
+		}
+		return cr.Status.AtProvider.ID
+	}
+}
+
+// ClusterName extracts the name of a referenced Cluster, so that resources
+// managed over SQL can connect with the connection details it publishes.
+func ClusterName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		return mg.GetName()
+	}
+}
+
+// SQLUserName extracts the name of the SQL user of a referenced SQLUser, so
+// that it can be made the owner of objects managed over SQL.
+func SQLUserName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*SQLUser)
+		if !ok {
+			return ""
+		}
+		if n := cr.Spec.ForProvider.Name; n != "" {
+			return n
+		}
+		return cr.GetName()
+	}
+}
+
+// resolveClusterRef returns the reference to the Cluster the supplied
+// reference or selector of a resource managed over SQL resolves to.
+func resolveClusterRef(ctx context.Context, c client.Reader, mg resource.Managed, ref *xpv1.Reference, sel *xpv1.Selector) (*xpv1.Reference, error) {
+	current := ""
+	if ref != nil {
+		current = ref.Name
+	}
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: current,
+		Reference:    ref,
+		Selector:     sel,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterName(),
+	})
+	return rsp.ResolvedReference, errors.Wrap(err, "spec.forProvider.clusterRef")
+}
+
+// ResolveReferences of this SQLUser.
+func (mg *SQLUser) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this ManagedBackupConfig.
+func (mg *ManagedBackupConfig) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this RestoreJob.
+func (mg *RestoreJob) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this CloudDatabase.
+func (mg *CloudDatabase) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this CMEK.
+func (mg *CMEK) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this EgressRule.
+func (mg *EgressRule) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this ClientCACert.
+func (mg *ClientCACert) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this ClusterVersionUpgrade.
+func (mg *ClusterVersionUpgrade) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this MetricExportCloudWatch.
+func (mg *MetricExportCloudWatch) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this MetricExportDatadog.
+func (mg *MetricExportDatadog) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this PrivateEndpointConnection.
+func (mg *PrivateEndpointConnection) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this PrivateEndpointService.
+func (mg *PrivateEndpointService) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this Database.
+func (mg *Database) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+	return nil
+}
+
+// ResolveReferences of this Grant.
+func (mg *Grant) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this Schema.
+func (mg *Schema) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	orsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Owner),
+		Reference:    mg.Spec.ForProvider.OwnerRef,
+		Selector:     mg.Spec.ForProvider.OwnerSelector,
+		To:           reference.To{Managed: &SQLUser{}, List: &SQLUserList{}},
+		Extract:      SQLUserName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.owner")
+	}
+	mg.Spec.ForProvider.Owner = reference.ToPtrValue(orsp.ResolvedValue)
+	mg.Spec.ForProvider.OwnerRef = orsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this BackupSchedule.
+func (mg *BackupSchedule) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this BackupJob.
+func (mg *BackupJob) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this RestoreSQL.
+func (mg *RestoreSQL) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+	return nil
+}
+
+// ResolveReferences of this DefaultPrivileges.
+func (mg *DefaultPrivileges) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this TableTTLPolicy.
+func (mg *TableTTLPolicy) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this DatabaseRegion.
+func (mg *DatabaseRegion) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this SQLScript.
+func (mg *SQLScript) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	rsp, err := reference.NewAPIResolver(c, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this RoleDefaultSettings.
+func (mg *RoleDefaultSettings) ResolveReferences(ctx context.Context, c client.Reader) error {
+	ref, err := resolveClusterRef(ctx, c, mg, mg.Spec.ForProvider.ClusterRef, mg.Spec.ForProvider.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.ClusterRef = ref
+
+	r := reference.NewAPIResolver(c, mg)
+
+	rrsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Role,
+		Reference:    mg.Spec.ForProvider.RoleRef,
+		Selector:     mg.Spec.ForProvider.RoleSelector,
+		To:           reference.To{Managed: &SQLUser{}, List: &SQLUserList{}},
+		Extract:      SQLUserName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.role")
+	}
+	mg.Spec.ForProvider.Role = rrsp.ResolvedValue
+	mg.Spec.ForProvider.RoleRef = rrsp.ResolvedReference
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Database,
+		Reference:    mg.Spec.ForProvider.DatabaseRef,
+		Selector:     mg.Spec.ForProvider.DatabaseSelector,
+		To:           reference.To{Managed: &Database{}, List: &DatabaseList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.database")
+	}
+	mg.Spec.ForProvider.Database = rsp.ResolvedValue
+	mg.Spec.ForProvider.DatabaseRef = rsp.ResolvedReference
+
+	return nil
+}
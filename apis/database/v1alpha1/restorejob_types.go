@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A RestoreObject is a database or table to restore.
+type RestoreObject struct {
+	// Database to restore, or that contains the table to restore.
+	Database string `json:"database"`
+	// Schema that contains the table to restore. Defaults to public.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+	// Table to restore.
+	// +optional
+	Table string `json:"table,omitempty"`
+}
+
+// RestoreJobOptions change how objects are restored.
+type RestoreJobOptions struct {
+	// NewDatabaseName restores a database under a new name. Only applies
+	// to DATABASE restores of a single database.
+	// +optional
+	NewDatabaseName string `json:"newDatabaseName,omitempty"`
+	// IntoDatabase restores tables into another database. Only applies to
+	// TABLE restores.
+	// +optional
+	IntoDatabase string `json:"intoDatabase,omitempty"`
+	// SkipLocalitiesCheck restores even if the localities of the
+	// destination cluster do not match those of the backup.
+	// +optional
+	SkipLocalitiesCheck bool `json:"skipLocalitiesCheck,omitempty"`
+	// SkipMissingForeignKeys restores tables whose foreign keys reference
+	// tables that are not restored, removing those foreign keys.
+	// +optional
+	SkipMissingForeignKeys bool `json:"skipMissingForeignKeys,omitempty"`
+	// SkipMissingSequences restores tables whose columns reference sequences
+	// that are not restored.
+	// +optional
+	SkipMissingSequences bool `json:"skipMissingSequences,omitempty"`
+	// SchemaOnly restores the schema of the objects without their data.
+	// +optional
+	SchemaOnly bool `json:"schemaOnly,omitempty"`
+}
+
+// RestoreJobParameters are the configurable fields of a RestoreJob.
+type RestoreJobParameters struct {
+	// ClusterID is the ID of the cluster in CockroachDB Cloud to restore
+	// into.
+	// +optional
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster to restore into, and sets
+	// clusterId.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster to restore into, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// SourceClusterID is the ID of the cluster whose managed backup is
+	// restored. Defaults to the cluster restored into.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="sourceClusterId is immutable"
+	SourceClusterID string `json:"sourceClusterId,omitempty"`
+	// BackupID is the ID of the managed backup to restore. Defaults to the
+	// most recent backup of the source cluster.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backupId is immutable"
+	BackupID string `json:"backupId,omitempty"`
+	// Type of the restore. A CLUSTER restore replaces all data of the
+	// cluster restored into.
+	// +kubebuilder:validation:Enum=CLUSTER;DATABASE;TABLE
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="type is immutable"
+	Type string `json:"type"`
+	// Objects to restore. Required unless type is CLUSTER.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="objects are immutable"
+	Objects []RestoreObject `json:"objects,omitempty"`
+	// Options change how objects are restored.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="options are immutable"
+	Options *RestoreJobOptions `json:"options,omitempty"`
+}
+
+// RestoreJobObservation are the observable fields of a RestoreJob.
+type RestoreJobObservation struct {
+	// Status of the restore, e.g. PENDING or SUCCESS.
+	Status string `json:"status,omitempty"`
+	// CompletionPercent is how much of the restore is done.
+	CompletionPercent int32 `json:"completionPercent,omitempty"`
+	// BackupEndTime is the point in time the restored backup was taken at.
+	BackupEndTime string `json:"backupEndTime,omitempty"`
+	// JobID is the ID of the restore job in the cluster restored into.
+	JobID string `json:"jobId,omitempty"`
+	// Message explaining why the restore failed.
+	Message string `json:"message,omitempty"`
+}
+
+// A RestoreJobSpec defines the desired state of a RestoreJob.
+type RestoreJobSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RestoreJobParameters `json:"forProvider"`
+}
+
+// A RestoreJobStatus represents the observed state of a RestoreJob.
+type RestoreJobStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RestoreJobObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A RestoreJob restores a managed backup into a cluster, either the backed
+// up cluster or another one. It is ready once the restore succeeded. Its
+// external name is the ID of the restore in CockroachDB Cloud. Deleting it
+// does not undo the restore.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="PROGRESS",type="integer",JSONPath=".status.atProvider.completionPercent"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type RestoreJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RestoreJobSpec   `json:"spec"`
+	Status RestoreJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RestoreJobList contains a list of RestoreJob
+type RestoreJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RestoreJob `json:"items"`
+}
+
+// RestoreJob type metadata.
+var (
+	RestoreJobKind             = reflect.TypeOf(RestoreJob{}).Name()
+	RestoreJobGroupKind        = schema.GroupKind{Group: Group, Kind: RestoreJobKind}.String()
+	RestoreJobKindAPIVersion   = RestoreJobKind + "." + SchemeGroupVersion.String()
+	RestoreJobGroupVersionKind = SchemeGroupVersion.WithKind(RestoreJobKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&RestoreJob{}, &RestoreJobList{})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BackupLatest restores the most recent backup in a storage.
+const BackupLatest = "LATEST"
+
+// RestoreSQLParameters are the configurable fields of a RestoreSQL.
+type RestoreSQLParameters struct {
+	// ClusterRef references the Cluster to restore into, over SQL as the
+	// user whose connection details the Cluster publishes. The Cluster
+	// cannot be deleted while the RestoreSQL exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster to restore into, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database of the backup to restore. The whole cluster is restored if
+	// neither database nor tables are set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// Tables of the backup to restore, qualified with their database, e.g.
+	// app.public.users.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tables are immutable"
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+	// Storage the backup is read from.
+	// +kubebuilder:validation:XValidation:rule="self.uri == oldSelf.uri",message="storage uri is immutable"
+	Storage BackupStorage `json:"storage"`
+	// Backup is the subdirectory of the storage the backup to restore was
+	// written to, or LATEST to restore the most recent backup.
+	// +kubebuilder:default=LATEST
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backup is immutable"
+	// +optional
+	Backup string `json:"backup,omitempty"`
+	// Options change how objects are restored.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="options are immutable"
+	// +optional
+	Options *RestoreJobOptions `json:"options,omitempty"`
+}
+
+// RestoreSQLObservation are the observable fields of a RestoreSQL.
+type RestoreSQLObservation struct {
+	// Status of the restore job, e.g. running or succeeded.
+	Status string `json:"status,omitempty"`
+	// CompletionPercent is how much of the restore is done.
+	CompletionPercent int32 `json:"completionPercent,omitempty"`
+	// Message explaining why the restore failed.
+	Message string `json:"message,omitempty"`
+}
+
+// A RestoreSQLSpec defines the desired state of a RestoreSQL.
+type RestoreSQLSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RestoreSQLParameters `json:"forProvider"`
+}
+
+// A RestoreSQLStatus represents the observed state of a RestoreSQL.
+type RestoreSQLStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RestoreSQLObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A RestoreSQL restores a backup from cloud storage into a cluster, over
+// SQL. It is ready once the restore succeeded. Its external name is the ID
+// of the restore job. Deleting it cancels the job if it is still running,
+// and does not undo a finished restore.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="PROGRESS",type="integer",JSONPath=".status.atProvider.completionPercent"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type RestoreSQL struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RestoreSQLSpec   `json:"spec"`
+	Status RestoreSQLStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RestoreSQLList contains a list of RestoreSQL
+type RestoreSQLList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RestoreSQL `json:"items"`
+}
+
+// RestoreSQL type metadata.
+var (
+	RestoreSQLKind                 = reflect.TypeOf(RestoreSQL{}).Name()
+	RestoreSQLGroupKind            = schema.GroupKind{Group: Group, Kind: RestoreSQLKind}.String()
+	RestoreSQLKindAPIVersion       = RestoreSQLKind + "." + SchemeGroupVersion.String()
+	RestoreSQLGroupVersionKind     = SchemeGroupVersion.WithKind(RestoreSQLKind)
+	RestoreSQLListGroupVersionKind = SchemeGroupVersion.WithKind(RestoreSQLKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&RestoreSQL{}, &RestoreSQLList{})
+}
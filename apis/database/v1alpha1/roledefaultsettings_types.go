@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RoleDefaultSettingsParameters are the configurable fields of a
+// RoleDefaultSettings.
+type RoleDefaultSettingsParameters struct {
+	// ClusterRef references the Cluster of the role, over SQL as the user
+	// whose connection details the Cluster publishes. The Cluster cannot be
+	// deleted while the RoleDefaultSettings exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster of the role, and sets clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Role whose session defaults are managed.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="role is immutable"
+	// +optional
+	Role string `json:"role,omitempty"`
+	// RoleRef references the SQLUser whose session defaults are managed, and
+	// sets role.
+	// +optional
+	RoleRef *xpv1.Reference `json:"roleRef,omitempty"`
+	// RoleSelector selects the SQLUser whose session defaults are managed,
+	// and sets roleRef.
+	// +optional
+	RoleSelector *xpv1.Selector `json:"roleSelector,omitempty"`
+	// Database the session defaults apply in. They apply in all databases if
+	// omitted.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database the session defaults apply in, and
+	// sets database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database the session defaults apply in,
+	// and sets databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Settings are the session variables the role defaults to, e.g.
+	// search_path or default_transaction_quality_of_service. Session
+	// defaults of the role in the database that are not listed are reset.
+	// +kubebuilder:validation:MinProperties=1
+	Settings map[string]string `json:"settings"`
+}
+
+// RoleDefaultSettingsObservation are the observable fields of a
+// RoleDefaultSettings.
+type RoleDefaultSettingsObservation struct {
+	// Settings are the session variables the role defaults to in the
+	// database.
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// A RoleDefaultSettingsSpec defines the desired state of a
+// RoleDefaultSettings.
+type RoleDefaultSettingsSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RoleDefaultSettingsParameters `json:"forProvider"`
+}
+
+// A RoleDefaultSettingsStatus represents the observed state of a
+// RoleDefaultSettings.
+type RoleDefaultSettingsStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RoleDefaultSettingsObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A RoleDefaultSettings manages the session defaults of a role, optionally in
+// a single database, over SQL with ALTER ROLE ... SET. Deleting it resets the
+// session defaults of the role in the database.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.forProvider.role"
+// +kubebuilder:printcolumn:name="DATABASE",type="string",JSONPath=".spec.forProvider.database"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type RoleDefaultSettings struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RoleDefaultSettingsSpec   `json:"spec"`
+	Status RoleDefaultSettingsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RoleDefaultSettingsList contains a list of RoleDefaultSettings
+type RoleDefaultSettingsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RoleDefaultSettings `json:"items"`
+}
+
+// RoleDefaultSettings type metadata.
+var (
+	RoleDefaultSettingsKind                 = reflect.TypeOf(RoleDefaultSettings{}).Name()
+	RoleDefaultSettingsGroupKind            = schema.GroupKind{Group: Group, Kind: RoleDefaultSettingsKind}.String()
+	RoleDefaultSettingsKindAPIVersion       = RoleDefaultSettingsKind + "." + SchemeGroupVersion.String()
+	RoleDefaultSettingsGroupVersionKind     = SchemeGroupVersion.WithKind(RoleDefaultSettingsKind)
+	RoleDefaultSettingsListGroupVersionKind = SchemeGroupVersion.WithKind(RoleDefaultSettingsKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&RoleDefaultSettings{}, &RoleDefaultSettingsList{})
+}
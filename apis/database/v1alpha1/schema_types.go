@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemaParameters are the configurable fields of a Schema.
+type SchemaParameters struct {
+	// ClusterRef references the Cluster the schema is created in, over SQL
+	// as the user whose connection details the Cluster publishes. The
+	// Cluster cannot be deleted while the Schema exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the schema is created in, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database the schema is created in.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database the schema is created in, and sets
+	// database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database the schema is created in, and
+	// sets databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Owner is the role that owns the schema. Defaults to the user the
+	// schema is created as.
+	// +optional
+	Owner *string `json:"owner,omitempty"`
+	// OwnerRef references the SQLUser that owns the schema, and sets owner.
+	// +optional
+	OwnerRef *xpv1.Reference `json:"ownerRef,omitempty"`
+	// OwnerSelector selects the SQLUser that owns the schema, and sets
+	// ownerRef.
+	// +optional
+	OwnerSelector *xpv1.Selector `json:"ownerSelector,omitempty"`
+	// Cascade drops the tables and other objects of the schema along with it
+	// when the Schema is deleted. Deleting a schema that is not empty fails
+	// otherwise.
+	// +optional
+	Cascade bool `json:"cascade,omitempty"`
+}
+
+// SchemaObservation are the observable fields of a Schema.
+type SchemaObservation struct {
+	// Owner is the role that owns the schema.
+	Owner string `json:"owner,omitempty"`
+}
+
+// A SchemaSpec defines the desired state of a Schema.
+type SchemaSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SchemaParameters `json:"forProvider"`
+}
+
+// A SchemaStatus represents the observed state of a Schema.
+type SchemaStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SchemaObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Schema is a schema of a database, managed over SQL. Its external name is
+// the name of the schema, and defaults to the name of the Schema.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="DATABASE",type="string",JSONPath=".spec.forProvider.database"
+// +kubebuilder:printcolumn:name="OWNER",type="string",JSONPath=".status.atProvider.owner"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type Schema struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SchemaSpec   `json:"spec"`
+	Status SchemaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SchemaList contains a list of Schema
+type SchemaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Schema `json:"items"`
+}
+
+// Schema type metadata.
+var (
+	SchemaKind                 = reflect.TypeOf(Schema{}).Name()
+	SchemaGroupKind            = schema.GroupKind{Group: Group, Kind: SchemaKind}.String()
+	SchemaKindAPIVersion       = SchemaKind + "." + SchemeGroupVersion.String()
+	SchemaGroupVersionKind     = SchemeGroupVersion.WithKind(SchemaKind)
+	SchemaListGroupVersionKind = SchemeGroupVersion.WithKind(SchemaKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&Schema{}, &SchemaList{})
+}
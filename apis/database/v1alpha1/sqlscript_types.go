@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	ConfigMapReference `json:",inline"`
+	// Key of the ConfigMap.
+	Key string `json:"key"`
+}
+
+// SQLScriptParameters are the configurable fields of a SQLScript.
+// +kubebuilder:validation:XValidation:rule="[has(self.script), has(self.scriptConfigMapRef), has(self.scriptSecretRef)].filter(x, x).size() == 1",message="exactly one of script, scriptConfigMapRef and scriptSecretRef must be set"
+type SQLScriptParameters struct {
+	// ClusterRef references the Cluster the script runs against, over SQL
+	// as the user whose connection details the Cluster publishes. The
+	// Cluster cannot be deleted while the SQLScript exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the script runs against, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database the script runs in. Defaults to the database of the
+	// connection details of the Cluster.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database the script runs in, and sets
+	// database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database the script runs in, and sets
+	// databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Script of SQL statements. It may run more than once, so it should be
+	// idempotent, e.g. use CREATE TABLE IF NOT EXISTS.
+	// +optional
+	Script string `json:"script,omitempty"`
+	// ScriptConfigMapRef references a ConfigMap key that holds the script.
+	// +optional
+	ScriptConfigMapRef *ConfigMapKeySelector `json:"scriptConfigMapRef,omitempty"`
+	// ScriptSecretRef references a Secret key that holds the script.
+	// +optional
+	ScriptSecretRef *xpv1.SecretKeySelector `json:"scriptSecretRef,omitempty"`
+}
+
+// SQLScriptObservation are the observable fields of a SQLScript.
+type SQLScriptObservation struct {
+	// AppliedChecksums are the SHA-256 checksums of the scripts that were
+	// run, the most recent last. Only the most recent ones are kept.
+	AppliedChecksums []string `json:"appliedChecksums,omitempty"`
+	// LastAppliedTime is the time the script was last run.
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// A SQLScriptSpec defines the desired state of a SQLScript.
+type SQLScriptSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SQLScriptParameters `json:"forProvider"`
+}
+
+// A SQLScriptStatus represents the observed state of a SQLScript.
+type SQLScriptStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SQLScriptObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SQLScript runs a script of SQL statements against a cluster, e.g. to
+// bootstrap a schema. The script runs once for each checksum: it runs again
+// when it changes, but not when it changes back to a script that already
+// ran. Deleting it does not undo the script.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterRef.name"
+// +kubebuilder:printcolumn:name="LAST-APPLIED",type="date",JSONPath=".status.atProvider.lastAppliedTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type SQLScript struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SQLScriptSpec   `json:"spec"`
+	Status SQLScriptStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SQLScriptList contains a list of SQLScript
+type SQLScriptList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SQLScript `json:"items"`
+}
+
+// SQLScript type metadata.
+var (
+	SQLScriptKind                 = reflect.TypeOf(SQLScript{}).Name()
+	SQLScriptGroupKind            = schema.GroupKind{Group: Group, Kind: SQLScriptKind}.String()
+	SQLScriptKindAPIVersion       = SQLScriptKind + "." + SchemeGroupVersion.String()
+	SQLScriptGroupVersionKind     = SchemeGroupVersion.WithKind(SQLScriptKind)
+	SQLScriptListGroupVersionKind = SchemeGroupVersion.WithKind(SQLScriptKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&SQLScript{}, &SQLScriptList{})
+}
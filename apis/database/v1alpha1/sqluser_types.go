@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SQLUserParameters are the configurable fields of a SQLUser.
+type SQLUserParameters struct {
+	// Name of the SQL user. Defaults to the name of the SQLUser, which must
+	// then meet the same constraints.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[a-z_][a-z0-9_.-]{0,62}$')",message="name must start with a lower case letter or underscore, contain only lower case letters, digits, underscores, periods and dashes, and be at most 63 characters long"
+	Name string `json:"name,omitempty"`
+	// ClusterID is the ID of the cluster in CockroachDB Cloud the SQL user
+	// belongs to.
+	// +optional
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterRef references the Cluster the SQL user belongs to, and sets
+	// clusterId. The Cluster cannot be deleted while the SQLUser exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster the SQL user belongs to, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// PasswordSecretRef references the password of the SQL user. A random
+	// password is generated if omitted. The password is rotated when the
+	// referenced Secret changes.
+	// +optional
+	PasswordSecretRef *xpv1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+}
+
+// SQLUserObservation are the observable fields of a SQLUser.
+type SQLUserObservation struct {
+	// ClusterID is the ID of the cluster the SQL user was created in.
+	ClusterID string `json:"clusterId,omitempty"`
+	// PasswordSecretVersion is the resource version of the password Secret
+	// last applied to the SQL user.
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+}
+
+// A SQLUserSpec defines the desired state of a SQLUser.
+type SQLUserSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SQLUserParameters `json:"forProvider"`
+}
+
+// A SQLUserStatus represents the observed state of a SQLUser.
+type SQLUserStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SQLUserObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SQLUser is a SQL user of a CockroachDB Cloud cluster. Its connection
+// details, a DSN and the CA certificate of the cluster, are written to its
+// connection secret.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type SQLUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SQLUserSpec   `json:"spec"`
+	Status SQLUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SQLUserList contains a list of SQLUser
+type SQLUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SQLUser `json:"items"`
+}
+
+// SQLUser type metadata.
+var (
+	SQLUserKind                 = reflect.TypeOf(SQLUser{}).Name()
+	SQLUserGroupKind            = schema.GroupKind{Group: Group, Kind: SQLUserKind}.String()
+	SQLUserKindAPIVersion       = SQLUserKind + "." + SchemeGroupVersion.String()
+	SQLUserGroupVersionKind     = SchemeGroupVersion.WithKind(SQLUserKind)
+	SQLUserListGroupVersionKind = SchemeGroupVersion.WithKind(SQLUserKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&SQLUser{}, &SQLUserList{})
+}
+
+// SQLUserName returns the name of the SQL user.
+func (s *SQLUser) SQLUserName() string {
+	if s.Spec.ForProvider.Name != "" {
+		return s.Spec.ForProvider.Name
+	}
+	return s.Name
+}
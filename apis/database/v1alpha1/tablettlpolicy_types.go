@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TableTTLPolicyParameters are the configurable fields of a TableTTLPolicy.
+// +kubebuilder:validation:XValidation:rule="has(self.expireAfter) || has(self.expirationExpression)",message="either expireAfter or expirationExpression must be set"
+type TableTTLPolicyParameters struct {
+	// ClusterRef references the Cluster of the tables, over SQL as the user
+	// whose connection details the Cluster publishes. The Cluster cannot be
+	// deleted while the TableTTLPolicy exists.
+	// +optional
+	ClusterRef *xpv1.Reference `json:"clusterRef,omitempty"`
+	// ClusterSelector selects the Cluster of the tables, and sets
+	// clusterRef.
+	// +optional
+	ClusterSelector *xpv1.Selector `json:"clusterSelector,omitempty"`
+	// Database of the tables.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="database is immutable"
+	// +optional
+	Database string `json:"database,omitempty"`
+	// DatabaseRef references the Database of the tables, and sets database.
+	// +optional
+	DatabaseRef *xpv1.Reference `json:"databaseRef,omitempty"`
+	// DatabaseSelector selects the Database of the tables, and sets
+	// databaseRef.
+	// +optional
+	DatabaseSelector *xpv1.Selector `json:"databaseSelector,omitempty"`
+	// Schema of the tables. Defaults to public.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="schema is immutable"
+	// +optional
+	Schema string `json:"schema,omitempty"`
+	// Tables the TTL applies to.
+	// +kubebuilder:validation:MinItems=1
+	Tables []string `json:"tables"`
+	// ExpireAfter is the interval after which rows expire, counted from
+	// when they were last updated, e.g. 30 days.
+	// +optional
+	ExpireAfter string `json:"expireAfter,omitempty"`
+	// ExpirationExpression is a SQL expression of the time rows expire at,
+	// e.g. created_at + INTERVAL '30 days'. Takes precedence over
+	// expireAfter.
+	// +optional
+	ExpirationExpression string `json:"expirationExpression,omitempty"`
+	// JobCron is the cron expression of the job that deletes expired rows.
+	// CockroachDB runs it hourly if omitted.
+	// +optional
+	JobCron string `json:"jobCron,omitempty"`
+}
+
+// A TableTTLPolicySpec defines the desired state of a TableTTLPolicy.
+type TableTTLPolicySpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       TableTTLPolicyParameters `json:"forProvider"`
+}
+
+// A TableTTLPolicyStatus represents the observed state of a TableTTLPolicy.
+type TableTTLPolicyStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// A TableTTLPolicy applies row-level TTL to tables, over SQL, so that
+// expired rows are deleted. TTL settings that were changed outside of it
+// are corrected, and TTL is removed from the tables when it is deleted.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DATABASE",type="string",JSONPath=".spec.forProvider.database"
+// +kubebuilder:printcolumn:name="EXPIRE-AFTER",type="string",JSONPath=".spec.forProvider.expireAfter"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type TableTTLPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TableTTLPolicySpec   `json:"spec"`
+	Status TableTTLPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TableTTLPolicyList contains a list of TableTTLPolicy
+type TableTTLPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TableTTLPolicy `json:"items"`
+}
+
+// TableTTLPolicy type metadata.
+var (
+	TableTTLPolicyKind                 = reflect.TypeOf(TableTTLPolicy{}).Name()
+	TableTTLPolicyGroupKind            = schema.GroupKind{Group: Group, Kind: TableTTLPolicyKind}.String()
+	TableTTLPolicyKindAPIVersion       = TableTTLPolicyKind + "." + SchemeGroupVersion.String()
+	TableTTLPolicyGroupVersionKind     = SchemeGroupVersion.WithKind(TableTTLPolicyKind)
+	TableTTLPolicyListGroupVersionKind = SchemeGroupVersion.WithKind(TableTTLPolicyKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&TableTTLPolicy{}, &TableTTLPolicyList{})
+}
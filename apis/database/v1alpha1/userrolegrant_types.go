@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A RoleResource is the organization, folder or cluster a role is granted on.
+type RoleResource struct {
+	// Type of the resource.
+	// +kubebuilder:validation:Enum=ORGANIZATION;FOLDER;CLUSTER
+	Type string `json:"type"`
+	// ID of the organization, folder or cluster in CockroachDB Cloud.
+	ID string `json:"id"`
+}
+
+// UserRoleGrantParameters are the configurable fields of a UserRoleGrant. A
+// grant cannot be changed; create a new one to grant a different role.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forProvider is immutable"
+type UserRoleGrantParameters struct {
+	// UserID is the ID of the user or service account in CockroachDB Cloud
+	// the role is granted to.
+	UserID string `json:"userId"`
+	// Role is the built-in role to grant.
+	// +kubebuilder:validation:Enum=ORG_MEMBER;ORG_ADMIN;BILLING_COORDINATOR;FOLDER_ADMIN;FOLDER_MOVER;CLUSTER_CREATOR;CLUSTER_ADMIN;CLUSTER_OPERATOR_WRITER;CLUSTER_DEVELOPER
+	Role string `json:"role"`
+	// Resource the role is granted on.
+	Resource RoleResource `json:"resource"`
+}
+
+// A UserRoleGrantSpec defines the desired state of a UserRoleGrant.
+type UserRoleGrantSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       UserRoleGrantParameters `json:"forProvider"`
+}
+
+// A UserRoleGrantStatus represents the observed state of a UserRoleGrant.
+type UserRoleGrantStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// A UserRoleGrant grants a built-in CockroachDB Cloud role to a user or
+// service account of the organization, on the organization, a folder or a
+// cluster.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".spec.forProvider.userId"
+// +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.forProvider.role"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,cockroachdb}
+type UserRoleGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserRoleGrantSpec   `json:"spec"`
+	Status UserRoleGrantStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserRoleGrantList contains a list of UserRoleGrant
+type UserRoleGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UserRoleGrant `json:"items"`
+}
+
+// UserRoleGrant type metadata.
+var (
+	UserRoleGrantKind             = reflect.TypeOf(UserRoleGrant{}).Name()
+	UserRoleGrantGroupKind        = schema.GroupKind{Group: Group, Kind: UserRoleGrantKind}.String()
+	UserRoleGrantKindAPIVersion   = UserRoleGrantKind + "." + SchemeGroupVersion.String()
+	UserRoleGrantGroupVersionKind = SchemeGroupVersion.WithKind(UserRoleGrantKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&UserRoleGrant{}, &UserRoleGrantList{})
+}
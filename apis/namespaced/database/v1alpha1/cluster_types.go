@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	databasev1alpha1 "github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// A ClusterSpec defines the desired state of a Cluster.
+type ClusterSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       databasev1alpha1.ClusterParameters `json:"forProvider"`
+	// InitProvider holds fields that are only honored when the Cluster is
+	// created.
+	// +optional
+	InitProvider *databasev1alpha1.ClusterInitParameters `json:"initProvider,omitempty"`
+	// WriteConnectionInfoToConfigMapRef specifies the ConfigMap to which the
+	// non-sensitive connection information of this Cluster is written.
+	// +optional
+	WriteConnectionInfoToConfigMapRef *databasev1alpha1.ConfigMapReference `json:"writeConnectionInfoToConfigMapRef,omitempty"`
+	// DNSEndpoint optionally publishes a stable hostname for the SQL endpoint
+	// of this Cluster through external-dns. The DNSEndpoint is always created
+	// in the namespace of the Cluster.
+	// +optional
+	DNSEndpoint *databasev1alpha1.DNSEndpointSpec `json:"dnsEndpoint,omitempty"`
+}
+
+// A ClusterStatus represents the observed state of a Cluster.
+type ClusterStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          databasev1alpha1.ClusterObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Cluster is a namespaced CockroachDB Cloud cluster. Secrets referenced by
+// and written for a namespaced Cluster always live in its own namespace.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cockroachdb}
+type Cluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSpec   `json:"spec"`
+	Status ClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterList contains a list of Cluster
+type ClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cluster `json:"items"`
+}
+
+// Cluster type metadata.
+var (
+	ClusterKind             = reflect.TypeOf(Cluster{}).Name()
+	ClusterGroupKind        = schema.GroupKind{Group: Group, Kind: ClusterKind}.String()
+	ClusterKindAPIVersion   = ClusterKind + "." + SchemeGroupVersion.String()
+	ClusterGroupVersionKind = SchemeGroupVersion.WithKind(ClusterKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
+}
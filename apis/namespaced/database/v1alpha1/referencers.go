@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterID extracts the ID in CockroachDB Cloud of a referenced Cluster. It
+// is empty until the cluster was created.
+func ClusterID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*Cluster)
+		if !ok {
+			return ""
+		}
+		return cr.Status.AtProvider.ID
+	}
+}
+
+// ClusterName extracts the name of a referenced Cluster, so that resources
+// managed over SQL can connect with the connection details it publishes.
+func ClusterName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		return mg.GetName()
+	}
+}
+
+// A namespacedReader reads objects from a single namespace. References of
+// namespaced managed resources are resolved through it, so that they can
+// only reference resources in their own namespace.
+// +kubebuilder:object:generate=false
+type namespacedReader struct {
+	client.Reader
+	namespace string
+}
+
+func (r namespacedReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	key.Namespace = r.namespace
+	return r.Reader.Get(ctx, key, obj)
+}
+
+func (r namespacedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return r.Reader.List(ctx, list, append(opts, client.InNamespace(r.namespace))...)
+}
+
+// ResolveReferences of this SQLUser.
+func (mg *SQLUser) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(namespacedReader{Reader: c, namespace: mg.GetNamespace()}, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ClusterID,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterId")
+	}
+	mg.Spec.ForProvider.ClusterID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this Database.
+func (mg *Database) ResolveReferences(ctx context.Context, c client.Reader) error {
+	current := ""
+	if ref := mg.Spec.ForProvider.ClusterRef; ref != nil {
+		current = ref.Name
+	}
+	rsp, err := reference.NewAPIResolver(namespacedReader{Reader: c, namespace: mg.GetNamespace()}, mg).Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: current,
+		Reference:    mg.Spec.ForProvider.ClusterRef,
+		Selector:     mg.Spec.ForProvider.ClusterSelector,
+		To:           reference.To{Managed: &Cluster{}, List: &ClusterList{}},
+		Extract:      ClusterName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.clusterRef")
+	}
+	mg.Spec.ForProvider.ClusterRef = rsp.ResolvedReference
+
+	return nil
+}
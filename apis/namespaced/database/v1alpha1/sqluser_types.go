@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	databasev1alpha1 "github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// A SQLUserSpec defines the desired state of a SQLUser.
+type SQLUserSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       databasev1alpha1.SQLUserParameters `json:"forProvider"`
+}
+
+// A SQLUserStatus represents the observed state of a SQLUser.
+type SQLUserStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          databasev1alpha1.SQLUserObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SQLUser is a namespaced SQL user of a CockroachDB Cloud cluster. It
+// references and selects Clusters in its own namespace, and the Secrets it
+// references and writes always live in its own namespace.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.forProvider.clusterRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cockroachdb}
+type SQLUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SQLUserSpec   `json:"spec"`
+	Status SQLUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SQLUserList contains a list of SQLUser
+type SQLUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SQLUser `json:"items"`
+}
+
+// SQLUser type metadata.
+var (
+	SQLUserKind                 = reflect.TypeOf(SQLUser{}).Name()
+	SQLUserGroupKind            = schema.GroupKind{Group: Group, Kind: SQLUserKind}.String()
+	SQLUserKindAPIVersion       = SQLUserKind + "." + SchemeGroupVersion.String()
+	SQLUserGroupVersionKind     = SchemeGroupVersion.WithKind(SQLUserKind)
+	SQLUserListGroupVersionKind = SchemeGroupVersion.WithKind(SQLUserKind + "List")
+)
+
+func init() {
+	SchemeBuilder.Register(&SQLUser{}, &SQLUserList{})
+}
+
+// SQLUserName returns the name of the SQL user.
+func (s *SQLUser) SQLUserName() string {
+	if s.Spec.ForProvider.Name != "" {
+		return s.Spec.ForProvider.Name
+	}
+	return s.Name
+}
@@ -29,6 +29,125 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+	// Limits of the CockroachDB Cloud organization, checked before creating
+	// clusters.
+	// +optional
+	Limits *ProviderLimits `json:"limits,omitempty"`
+	// CAFingerprints are the SHA-256 fingerprints of the CA certificates
+	// clusters are expected to present, as hex optionally separated by
+	// colons. When set, CA certificates that match none of them are not
+	// published.
+	// +optional
+	CAFingerprints []string `json:"caFingerprints,omitempty"`
+	// TrustBundle maintains a ConfigMap holding the CA certificates of the
+	// Clusters that use this ProviderConfig.
+	// +optional
+	TrustBundle *TrustBundle `json:"trustBundle,omitempty"`
+	// Placement restricts the cloud providers and regions of the Clusters
+	// using this ProviderConfig, e.g. to enforce data residency. It is
+	// enforced on admission.
+	// +optional
+	Placement *PlacementPolicy `json:"placement,omitempty"`
+	// SelfHosted configures a self-hosted CockroachDB cluster that the
+	// managed resources managed over SQL, e.g. Databases and Grants, connect
+	// to when they reference no Cluster.
+	// +optional
+	SelfHosted *SelfHostedCluster `json:"selfHosted,omitempty"`
+	// Headers are added to every request to the CockroachDB Cloud API, e.g.
+	// to pin the API version or to tag traffic for an API gateway. The
+	// Authorization header cannot be overridden.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// AllowedNamespaces are the namespaces whose namespaced managed
+	// resources may use this ProviderConfig. Namespaced managed resources
+	// cannot use it unless their namespace is listed. Cluster scoped managed
+	// resources may always use it.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// A SelfHostedCluster is a CockroachDB cluster that is not managed through
+// CockroachDB Cloud, and is only connected to over SQL.
+type SelfHostedCluster struct {
+	// Host of the cluster, e.g. the address of a load balancer in front of
+	// its nodes.
+	Host string `json:"host"`
+	// Port the cluster accepts SQL connections on.
+	// +optional
+	// +kubebuilder:default=26257
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+	// Database to connect to, unless a managed resource requires another.
+	// +optional
+	// +kubebuilder:default=defaultdb
+	Database string `json:"database,omitempty"`
+	// CASecretRef references a Secret key holding the PEM encoded CA
+	// certificate the certificate of the cluster is verified with.
+	CASecretRef xpv1.SecretKeySelector `json:"caSecretRef"`
+	// Credentials of the admin user to connect as.
+	Credentials SelfHostedCredentials `json:"credentials"`
+}
+
+// SelfHostedCredentials of a user of a self-hosted cluster. The user
+// authenticates with a password, a client certificate, or both.
+// +kubebuilder:validation:XValidation:rule="has(self.passwordSecretRef) || has(self.certSecretRef)",message="either passwordSecretRef or certSecretRef must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.certSecretRef) == has(self.keySecretRef)",message="certSecretRef and keySecretRef must be set together"
+type SelfHostedCredentials struct {
+	// Username of the user.
+	Username string `json:"username"`
+	// PasswordSecretRef references the password of the user.
+	// +optional
+	PasswordSecretRef *xpv1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// CertSecretRef references a Secret key holding the PEM encoded client
+	// certificate of the user, whose common name must be its username.
+	// +optional
+	CertSecretRef *xpv1.SecretKeySelector `json:"certSecretRef,omitempty"`
+	// KeySecretRef references a Secret key holding the PEM encoded private
+	// key of the client certificate.
+	// +optional
+	KeySecretRef *xpv1.SecretKeySelector `json:"keySecretRef,omitempty"`
+}
+
+// A CloudProvider clusters can run on.
+// +kubebuilder:validation:Enum=GCP;AWS;AZURE
+type CloudProvider string
+
+// A PlacementPolicy restricts where Clusters may run.
+type PlacementPolicy struct {
+	// AllowedProviders are the cloud providers Clusters may run on. Any
+	// provider is allowed if empty.
+	// +optional
+	AllowedProviders []CloudProvider `json:"allowedProviders,omitempty"`
+	// AllowedRegions are the regions Clusters may run in, named after the
+	// convention of either AWS or GCP, e.g. us-east-1 or us-east1, or after
+	// their Azure name, e.g. eastus. Any region is allowed if empty.
+	// +optional
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+}
+
+// A TrustBundle is a ConfigMap holding the CA certificates of Clusters, so
+// that workloads can mount a single trust bundle instead of the connection
+// secret of each Cluster.
+type TrustBundle struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+	// Namespaces the ConfigMap is maintained in.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+	// Key of the ConfigMap holding the PEM encoded CA certificates.
+	// +optional
+	// +kubebuilder:default=ca.crt
+	Key string `json:"key,omitempty"`
+}
+
+// ProviderLimits are the limits of a CockroachDB Cloud organization.
+type ProviderLimits struct {
+	// MaxServerlessClusters is the maximum number of active serverless
+	// clusters of the organization.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxServerlessClusters *int32 `json:"maxServerlessClusters,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -60,6 +179,21 @@ type ProviderConfig struct {
 	Status ProviderConfigStatus `json:"status,omitempty"`
 }
 
+// AllowsNamespace returns true if managed resources in the supplied namespace
+// may use the ProviderConfig. Cluster scoped managed resources, which have no
+// namespace, may always use it.
+func (pc *ProviderConfig) AllowsNamespace(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	for _, ns := range pc.Spec.AllowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // +kubebuilder:object:root=true
 
 // ProviderConfigList contains a list of ProviderConfig.
@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command function is a Composition Function that renders app-specific
+// connection details, such as DATABASE_URL, from those of a composed Cluster.
+// It reads a FunctionIO from stdin and writes it to stdout.
+package main
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/provider-cockroachdb/internal/function"
+)
+
+func main() {
+	app := kingpin.New("function", "Render connection details of a composed CockroachDB Cluster.").DefaultEnvars()
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	in, err := io.ReadAll(os.Stdin)
+	kingpin.FatalIfError(err, "Cannot read FunctionIO")
+
+	fio := &function.FunctionIO{}
+	kingpin.FatalIfError(yaml.Unmarshal(in, fio), "Cannot parse FunctionIO")
+
+	function.Run(fio)
+
+	out, err := yaml.Marshal(fio)
+	kingpin.FatalIfError(err, "Cannot serialize FunctionIO")
+	_, err = os.Stdout.Write(out)
+	kingpin.FatalIfError(err, "Cannot write FunctionIO")
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -27,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -37,11 +40,22 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/provider-cockroachdb/apis"
+	clusterv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
 	"github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
 	cockroachdb "github.com/crossplane/provider-cockroachdb/internal/controller"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
 	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
+	"github.com/crossplane/provider-cockroachdb/internal/migration"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+	"github.com/crossplane/provider-cockroachdb/internal/tlsconfig"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
 )
 
+// shutdownMargin is how much longer than the shutdown grace period the
+// manager waits for its controllers to stop.
+const shutdownMargin = 5 * time.Second
+
 func main() {
 	var (
 		app            = kingpin.New(filepath.Base(os.Args[0]), "CockroachDB support for Crossplane.").DefaultEnvars()
@@ -55,15 +69,41 @@ func main() {
 		maxReconcileRate = app.Flag("max-reconcile-rate",
 			"The global maximum rate per second at which resources may checked for drift from the desired state.").
 			Default("10").Int()
-		namespace = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").
+		namespace = app.Flag("namespace", "Namespace used to set as default scope in default secret store config, and as default namespace of connection secrets.").
 				Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").
 						Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+		enableTracing = app.Flag("enable-tracing", "Export OpenTelemetry traces of reconciles via OTLP, configured through the OTEL_EXPORTER_OTLP_* environment variables.").
+				Default("false").Envar("ENABLE_TRACING").Bool()
+		webhookTLSCertDir = app.Flag("webhook-tls-cert-dir", "The directory of the TLS certificate used by the webhook server. Webhooks are disabled if unset.").
+					Envar("WEBHOOK_TLS_CERT_DIR").String()
+		enableLiveRegionValidation = app.Flag("enable-live-region-validation", "Reject Clusters whose regions are not offered by the Cloud API on admission.").
+						Default("false").Envar("ENABLE_LIVE_REGION_VALIDATION").Bool()
+		enableClusterDiscovery = app.Flag("enable-cluster-discovery", "Periodically create observe-only Clusters for unmanaged clusters of each ProviderConfig.").
+					Default("false").Envar("ENABLE_CLUSTER_DISCOVERY").Bool()
+		enablePreviewFeatures = app.Flag("enable-preview-feature", "Enable controllers and fields relying on a limited-access Cloud API capability: "+strings.Join(features.PreviewNames(), ", ")+". May be repeated.").
+					Envar("ENABLE_PREVIEW_FEATURES").Enums(features.PreviewNames()...)
+		migrateStorageVersions = app.Flag("migrate-storage-versions", "Rewrite stored objects of the provider's CRDs in their storage version on start, so that older API versions can be removed.").
+					Default("true").Envar("MIGRATE_STORAGE_VERSIONS").Bool()
+		tlsMinVersion = app.Flag("tls-min-version", "The minimum TLS version of outbound HTTPS and SQL connections, 1.2 or 1.3.").
+				Default("1.2").Envar("TLS_MIN_VERSION").String()
+		tlsCipherSuites = app.Flag("tls-cipher-suites", "The TLS 1.2 cipher suites offered by outbound HTTPS and SQL connections, by IANA name. Defaults to the secure cipher suites of Go.").
+				Envar("TLS_CIPHER_SUITES").Strings()
+		tlsFIPS = app.Flag("tls-fips", "Only offer FIPS approved cipher suites and curves over TLS 1.2 in outbound HTTPS and SQL connections.").
+			Default("false").Envar("TLS_FIPS").Bool()
+		operationTimeout = app.Flag("operation-timeout", "How long each observe, create, update or delete of a Cluster may take before it is cancelled. Overridden per Cluster by the "+clusterv1alpha1.AnnotationKeyOperationTimeout+" annotation.").
+					Default(cloud.DefaultOperationTimeout.String()).Duration()
+		shutdownGracePeriod = app.Flag("shutdown-grace-period", "How long in-flight reconciles may take to complete once the provider is stopping. Keep it below the termination grace period of the provider's pod.").
+					Default(cloud.DefaultShutdownGracePeriod.String()).Duration()
+		sqlMaxConns = app.Flag("sql-max-conns", "The maximum number of SQL connections to each cluster, shared by the resources managed over SQL inside it.").
+				Default(strconv.Itoa(sqlclient.DefaultMaxConns)).Int()
+		sqlIdleTimeout = app.Flag("sql-idle-timeout", "How long SQL connections to clusters are kept open while idle.").
+				Default(sqlclient.DefaultIdleTimeout.String()).Duration()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
 	zl := zap.New(zap.UseDevMode(*debug))
-	log := logging.NewLogrLogger(zl.WithName("provider-cockroachdb"))
+	log := redact.NewLogger(logging.NewLogrLogger(zl.WithName("provider-cockroachdb")))
 	if *debug {
 		// The controller-runtime runs with a no-op logger by default. It is
 		// *very* verbose even at info level, so we only provide it a real
@@ -71,6 +111,16 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
+	tlsConfig, err := tlsconfig.New(*tlsMinVersion, *tlsCipherSuites, *tlsFIPS)
+	kingpin.FatalIfError(err, "Cannot configure outbound TLS")
+
+	if *enableTracing {
+		shutdown, err := tracing.Setup(context.Background(), "provider-cockroachdb", tlsConfig)
+		kingpin.FatalIfError(err, "Cannot setup tracing")
+		defer shutdown(context.Background()) //nolint:errcheck
+		log.Info("Tracing enabled")
+	}
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -89,6 +139,15 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		// Leave room for in-flight reconciles to record their result
+		// before the manager returns, and hand leadership over as soon as
+		// they did.
+		GracefulShutdownTimeout:       func() *time.Duration { d := *shutdownGracePeriod + shutdownMargin; return &d }(),
+		LeaderElectionReleaseOnCancel: true,
+
+		Port:    9443,
+		CertDir: *webhookTLSCertDir,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add CockroachDB APIs to scheme")
@@ -120,6 +179,39 @@ func main() {
 		})), "cannot create default store config")
 	}
 
-	kingpin.FatalIfError(cockroachdb.Setup(mgr, o), "Cannot setup CockroachDB controllers")
+	if *enableLiveRegionValidation {
+		o.Features.Enable(features.EnableAlphaLiveRegionValidation)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaLiveRegionValidation)
+	}
+
+	if *enableClusterDiscovery {
+		o.Features.Enable(features.EnableAlphaClusterDiscovery)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaClusterDiscovery)
+	}
+
+	for _, name := range *enablePreviewFeatures {
+		o.Features.Enable(features.Preview[name])
+		log.Info("Preview feature enabled", "flag", features.Preview[name])
+	}
+
+	if *migrateStorageVersions {
+		kube, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		kingpin.FatalIfError(err, "Cannot create API server client")
+		kingpin.FatalIfError(mgr.Add(migration.NewMigrator(kube, log)), "Cannot add storage version migrator")
+	}
+
+	co := cloud.Options{
+		Options:             o,
+		OperationTimeout:    *operationTimeout,
+		ShutdownGracePeriod: *shutdownGracePeriod,
+		SQLPool:             sqlclient.NewPool(*sqlMaxConns, *sqlIdleTimeout, tlsConfig),
+		Transport:           tlsconfig.NewTransport(tlsConfig),
+		TLSConfig:           tlsConfig,
+		APIInfo:             cloud.NewAPIInfoReporter(log),
+	}
+	kingpin.FatalIfError(cockroachdb.Setup(mgr, co), "Cannot setup CockroachDB controllers")
+	if *webhookTLSCertDir != "" {
+		kingpin.FatalIfError(cockroachdb.SetupWebhooks(mgr, co, *namespace), "Cannot setup CockroachDB webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
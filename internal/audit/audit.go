@@ -0,0 +1,266 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the mutating calls the provider makes to the
+// CockroachDB Cloud API, so that changes to production databases can be
+// traced back to the managed resource that made them.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReasonCloudAPICall is the reason of the events recording mutating Cloud
+// API calls.
+const ReasonCloudAPICall event.Reason = "CloudAPICall"
+
+// Annotations of the events recording mutating Cloud API calls.
+const (
+	AnnotationKeyRequestID = "cockroachdb.crossplane.io/request-id"
+	AnnotationKeyTraceID   = "cockroachdb.crossplane.io/trace-id"
+)
+
+// requestIDHeaders are the response headers the Cloud API may return the ID
+// of a request in.
+var requestIDHeaders = []string{"X-Request-Id", "Grpc-Metadata-X-Request-Id"}
+
+// An Entry records a mutating Cloud API call.
+type Entry struct {
+	Method    string
+	Path      string
+	Status    int
+	RequestID string
+	TraceID   string
+	Time      time.Time
+	Err       error
+}
+
+// A Recorder records the mutating Cloud API calls made on behalf of a
+// managed resource as events of the resource and as structured logs.
+type Recorder struct {
+	events event.Recorder
+	log    logging.Logger
+}
+
+// NewRecorder returns a Recorder that records through the supplied event
+// recorder and logger. The logger should identify the kind of the managed
+// resources, e.g. through the name of their controller.
+func NewRecorder(e event.Recorder, l logging.Logger) *Recorder {
+	return &Recorder{events: e, log: l}
+}
+
+// Record the supplied entry for the supplied managed resource.
+func (r *Recorder) Record(mg resource.Managed, e Entry) {
+	r.log.Info("Cloud API call",
+		"namespace", mg.GetNamespace(),
+		"name", mg.GetName(),
+		"method", e.Method,
+		"path", e.Path,
+		"status", e.Status,
+		"requestID", e.RequestID,
+		"traceID", e.TraceID,
+		"time", e.Time.UTC().Format(time.RFC3339),
+		"error", e.Err,
+	)
+
+	kv := []string{AnnotationKeyRequestID, e.RequestID, AnnotationKeyTraceID, e.TraceID}
+	msg := fmt.Sprintf("%s %s: %s", e.Method, e.Path, statusText(e.Status))
+	if e.Err != nil || e.Status >= http.StatusBadRequest {
+		err := e.Err
+		if err == nil {
+			err = fmt.Errorf("%s", msg)
+		}
+		r.events.Event(mg, event.Warning(ReasonCloudAPICall, err, kv...))
+		return
+	}
+	r.events.Event(mg, event.Normal(ReasonCloudAPICall, msg, kv...))
+}
+
+func statusText(code int) string {
+	if code == 0 {
+		return "no response"
+	}
+	return strconv.Itoa(code) + " " + http.StatusText(code)
+}
+
+type subject struct {
+	mg       resource.Managed
+	recorder *Recorder
+	failure  *failure
+}
+
+// A failure holds the IDs of the last Cloud API call that failed.
+type failure struct {
+	mu        sync.Mutex
+	requestID string
+	traceID   string
+}
+
+func (f *failure) set(requestID, traceID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requestID, f.traceID = requestID, traceID
+}
+
+// wrap annotates the supplied error with the IDs of the last failed call, so
+// that they show up in the conditions and events reporting it, and can be
+// referenced in support tickets.
+func (f *failure) wrap(err error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil || f.requestID == "" && f.traceID == "" {
+		return err
+	}
+	return &callError{err: err, requestID: f.requestID, traceID: f.traceID}
+}
+
+// A callError is an error caused by a failed Cloud API call.
+type callError struct {
+	err       error
+	requestID string
+	traceID   string
+}
+
+func (e *callError) Error() string {
+	ids := []string{}
+	if e.requestID != "" {
+		ids = append(ids, "request ID "+e.requestID)
+	}
+	if e.traceID != "" {
+		ids = append(ids, "trace ID "+e.traceID)
+	}
+	return fmt.Sprintf("%s (%s)", e.err, strings.Join(ids, ", "))
+}
+
+func (e *callError) Unwrap() error {
+	return e.err
+}
+
+type contextKey struct{}
+
+// withSubject returns a context that attributes the Cloud API calls made
+// with it to the supplied managed resource, and the failure the IDs of
+// failed calls are recorded in.
+func withSubject(ctx context.Context, mg resource.Managed, r *Recorder, f *failure) context.Context {
+	return context.WithValue(ctx, contextKey{}, subject{mg: mg, recorder: r, failure: f})
+}
+
+// A Transport records the mutating requests made with a context attributed to
+// a managed resource.
+type Transport struct {
+	wrapped http.RoundTripper
+	now     func() time.Time
+}
+
+// NewTransport returns a Transport that sends requests through the supplied
+// RoundTripper.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{wrapped: base, now: time.Now}
+}
+
+// RoundTrip sends the supplied request, recording it if it is mutating. The
+// IDs of failed requests are recorded regardless of their method.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s, ok := req.Context().Value(contextKey{}).(subject)
+	if !ok {
+		return t.wrapped.RoundTrip(req)
+	}
+
+	e := Entry{Method: req.Method, Path: req.URL.Path, Time: t.now()}
+	if sc := trace.SpanContextFromContext(req.Context()); sc.HasTraceID() {
+		e.TraceID = sc.TraceID().String()
+	}
+	res, err := t.wrapped.RoundTrip(req)
+	e.Err = err
+	if res != nil {
+		e.Status = res.StatusCode
+		for _, h := range requestIDHeaders {
+			if id := res.Header.Get(h); id != "" {
+				e.RequestID = id
+				break
+			}
+		}
+	}
+	if s.failure != nil && (err != nil || e.Status >= http.StatusBadRequest) {
+		s.failure.set(e.RequestID, e.TraceID)
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		s.recorder.Record(s.mg, e)
+	}
+	return res, err
+}
+
+// A Connecter attributes the Cloud API calls made by the ExternalClients of
+// the wrapped ExternalConnecter to the managed resource they reconcile.
+type Connecter struct {
+	wrapped  managed.ExternalConnecter
+	recorder *Recorder
+}
+
+// NewConnecter wraps the supplied ExternalConnecter with auditing.
+func NewConnecter(c managed.ExternalConnecter, r *Recorder) *Connecter {
+	return &Connecter{wrapped: c, recorder: r}
+}
+
+// Connect to the external system.
+func (c *Connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	f := &failure{}
+	e, err := c.wrapped.Connect(withSubject(ctx, mg, c.recorder, f), mg)
+	if err != nil {
+		return nil, f.wrap(err)
+	}
+	return &external{wrapped: e, recorder: c.recorder}, nil
+}
+
+type external struct {
+	wrapped  managed.ExternalClient
+	recorder *Recorder
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	f := &failure{}
+	o, err := e.wrapped.Observe(withSubject(ctx, mg, e.recorder, f), mg)
+	return o, f.wrap(err)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	f := &failure{}
+	c, err := e.wrapped.Create(withSubject(ctx, mg, e.recorder, f), mg)
+	return c, f.wrap(err)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	f := &failure{}
+	u, err := e.wrapped.Update(withSubject(ctx, mg, e.recorder, f), mg)
+	return u, f.wrap(err)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	f := &failure{}
+	return f.wrap(e.wrapped.Delete(withSubject(ctx, mg, e.recorder, f), mg))
+}
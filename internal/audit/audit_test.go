@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type recordingEvents struct {
+	events []event.Event
+}
+
+func (r *recordingEvents) Event(_ runtime.Object, e event.Event)      { r.events = append(r.events, e) }
+func (r *recordingEvents) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := NewTransport(http.DefaultTransport)
+	tr.now = func() time.Time { return now }
+
+	cases := map[string]struct {
+		reason     string
+		attributed bool
+		method     string
+		path       string
+		want       []event.Event
+		wantFailed string
+	}{
+		"Unattributed": {
+			reason: "Calls not made on behalf of a managed resource should not be recorded.",
+			method: http.MethodPost,
+			path:   "/ok",
+		},
+		"Read": {
+			reason:     "Calls that do not mutate should not be recorded.",
+			attributed: true,
+			method:     http.MethodGet,
+			path:       "/ok",
+		},
+		"FailedRead": {
+			reason:     "Failed calls that do not mutate should not be recorded, but their request ID should be kept.",
+			attributed: true,
+			method:     http.MethodGet,
+			path:       "/fail",
+			wantFailed: "req-1",
+		},
+		"Mutating": {
+			reason:     "Mutating calls should be recorded with their request ID.",
+			attributed: true,
+			method:     http.MethodPatch,
+			path:       "/ok",
+			want: []event.Event{event.Normal(ReasonCloudAPICall, "PATCH /ok: 200 OK",
+				AnnotationKeyRequestID, "req-1", AnnotationKeyTraceID, "")},
+		},
+		"Failed": {
+			reason:     "Failed mutating calls should be recorded as warnings.",
+			attributed: true,
+			method:     http.MethodDelete,
+			path:       "/fail",
+			want: []event.Event{{
+				Type:        event.TypeWarning,
+				Reason:      ReasonCloudAPICall,
+				Message:     "DELETE /fail: 409 Conflict",
+				Annotations: map[string]string{AnnotationKeyRequestID: "req-1", AnnotationKeyTraceID: ""},
+			}},
+			wantFailed: "req-1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			events := &recordingEvents{}
+			f := &failure{}
+			ctx := context.Background()
+			if tc.attributed {
+				ctx = withSubject(ctx, &fake.Managed{}, NewRecorder(events, logging.NewNopLogger()), f)
+			}
+			req, _ := http.NewRequestWithContext(ctx, tc.method, srv.URL+tc.path, nil)
+			res, err := (&http.Client{Transport: tr}).Do(req)
+			if err != nil {
+				t.Fatalf("Do(...): %v", err)
+			}
+			res.Body.Close()
+			if diff := cmp.Diff(tc.want, events.events); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantFailed, f.requestID); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want failed request ID, +got failed request ID:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFailureWrap(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason  string
+		failure *failure
+		err     error
+		want    string
+	}{
+		"NoError": {
+			reason:  "No error should be returned if the operation succeeded.",
+			failure: &failure{requestID: "req-1"},
+		},
+		"NoFailedCall": {
+			reason:  "Errors not caused by a failed call should be returned unchanged.",
+			failure: &failure{},
+			err:     errBoom,
+			want:    "boom",
+		},
+		"RequestID": {
+			reason:  "Errors should reference the request ID of the failed call.",
+			failure: &failure{requestID: "req-1"},
+			err:     errBoom,
+			want:    "boom (request ID req-1)",
+		},
+		"RequestAndTraceID": {
+			reason:  "Errors should reference the request and trace IDs of the failed call.",
+			failure: &failure{requestID: "req-1", traceID: "abc"},
+			err:     errBoom,
+			want:    "boom (request ID req-1, trace ID abc)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.failure.wrap(tc.err)
+			got := ""
+			if err != nil {
+				got = err.Error()
+				if !errors.Is(err, tc.err) {
+					t.Errorf("\n%s\nwrap(...): must wrap the supplied error", tc.reason)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nwrap(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
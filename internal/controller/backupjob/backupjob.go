@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupjob
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/backupschedule"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotBackupJob     = "managed resource is not a BackupJob custom resource"
+	errGetBackupJob     = "cannot get backup job"
+	errStartBackupJob   = "cannot start backup job"
+	errCancelBackupJob  = "cannot cancel backup job"
+	errFmtBackupJobGone = "backup job %s is unknown to the cluster: remove the external name annotation to back up again"
+)
+
+// Setup adds a controller that reconciles BackupJob managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.BackupJobGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.BackupJobGroupVersionKind, &v1alpha1.BackupJob{}, func(kube client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{kube: kube, db: db}
+	}, managed.WithInitializers())
+}
+
+// An external reconciles BackupJobs over SQL.
+type external struct {
+	kube client.Client
+	db   *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.BackupJob)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBackupJob)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	jid, err := sqlresource.ParseJobID(id)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	j, err := sqlresource.GetJob(ctx, c.db, jid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		// Backing up again would take another backup, so a backup job that
+		// vanished is not started again.
+		return managed.ExternalObservation{}, errors.Errorf(errFmtBackupJobGone, id)
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetBackupJob)
+	}
+
+	cr.Status.AtProvider = v1alpha1.BackupJobObservation{
+		Status:            j.Status,
+		CompletionPercent: j.CompletionPercent(),
+		Message:           j.Error,
+	}
+	cr.Status.SetConditions(j.Condition())
+	if meta.WasDeleted(cr) && j.Finished() {
+		// Finished backups are left in place. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.BackupJob)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotBackupJob)
+	}
+	uri, _, err := backupschedule.StorageURI(ctx, c.kube, cr.Spec.ForProvider.Storage)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	stmt, err := backupStatement(cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	var id int64
+	if err := c.db.QueryRow(ctx, stmt, uri).Scan(&id); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errStartBackupJob)
+	}
+	meta.SetExternalName(cr, strconv.FormatInt(id, 10))
+	return managed.ExternalCreation{}, nil
+}
+
+// Update does nothing, as a backup cannot be changed once started.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete cancels the backup job. It is only called while the job is still
+// running.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.BackupJob)
+	if !ok {
+		return errors.New(errNotBackupJob)
+	}
+	jid, err := sqlresource.ParseJobID(meta.GetExternalName(cr))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(c.db.Exec(ctx, sqlresource.CancelJobStatement(jid)), errCancelBackupJob)
+}
+
+// backupStatement returns the SQL statement that starts a backup of the
+// supplied parameters in the background, and returns the ID of its job. The
+// storage URI is its only placeholder, so that its credentials are not part
+// of the statement.
+func backupStatement(p v1alpha1.BackupJobParameters) (string, error) {
+	targets, err := backupschedule.BackupTargets(p.Database, p.Tables)
+	if err != nil {
+		return "", err
+	}
+	stmt := "BACKUP "
+	if targets != "" {
+		stmt += targets + " "
+	}
+	stmt += "INTO $1 WITH detached"
+	if p.RevisionHistory {
+		stmt += ", revision_history"
+	}
+	return stmt, nil
+}
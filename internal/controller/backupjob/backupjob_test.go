@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupjob
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestBackupStatement(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.BackupJobParameters
+		want   string
+	}{
+		"Cluster": {
+			reason: "The whole cluster should be backed up in the background if neither database nor tables are set.",
+			want:   `BACKUP INTO $1 WITH detached`,
+		},
+		"DatabaseRevisionHistory": {
+			reason: "The revision history of a database should be backed up if requested.",
+			p:      v1alpha1.BackupJobParameters{Database: "app", RevisionHistory: true},
+			want:   `BACKUP DATABASE "app" INTO $1 WITH detached, revision_history`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := backupStatement(tc.p)
+			if err != nil {
+				t.Fatalf("\n%s\nbackupStatement(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nbackupStatement(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupschedule
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotBackupSchedule        = "managed resource is not a BackupSchedule custom resource"
+	errObserveBackupSchedule    = "cannot observe backup schedules"
+	errCreateBackupSchedule     = "cannot create backup schedule"
+	errDropBackupSchedule       = "cannot drop backup schedules"
+	errGetStorageCredentials    = "cannot get storage credentials"
+	errParseStorageURI          = "cannot parse storage URI"
+	errFmtInvalidQualifiedTable = "table %q is not qualified with its database"
+)
+
+// Setup adds a controller that reconciles BackupSchedule managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.BackupScheduleGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.BackupScheduleGroupVersionKind, &v1alpha1.BackupSchedule{}, func(kube client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{kube: kube, db: db}
+	})
+}
+
+// An external reconciles BackupSchedules over SQL.
+type external struct {
+	kube client.Client
+	db   *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.BackupSchedule)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBackupSchedule)
+	}
+
+	runs, err := c.observe(ctx, meta.GetExternalName(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveBackupSchedule)
+	}
+	if len(runs) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	cr.Status.AtProvider.Schedules = runs
+	cr.Status.SetConditions(xpv1.Available())
+
+	_, version, err := StorageURI(ctx, c.kube, cr.Spec.ForProvider.Storage)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: backupScheduleUpToDate(cr.Spec.ForProvider, runs) && version == cr.Status.AtProvider.SecretVersion,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.BackupSchedule)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotBackupSchedule)
+	}
+	return managed.ExternalCreation{}, c.create(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.BackupSchedule)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotBackupSchedule)
+	}
+	// Schedules cannot be altered in all supported versions of CockroachDB,
+	// so they are created again.
+	if err := c.db.Exec(ctx, dropBackupSchedulesStatement(meta.GetExternalName(cr))); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errDropBackupSchedule)
+	}
+	return managed.ExternalUpdate{}, c.create(ctx, cr)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.BackupSchedule)
+	if !ok {
+		return errors.New(errNotBackupSchedule)
+	}
+	return errors.Wrap(c.db.Exec(ctx, dropBackupSchedulesStatement(meta.GetExternalName(cr))), errDropBackupSchedule)
+}
+
+// observe returns the schedules with the supplied label.
+func (c *external) observe(ctx context.Context, label string) ([]v1alpha1.BackupScheduleRun, error) {
+	rows, err := c.db.Query(ctx, "SELECT id, schedule_status, next_run, COALESCE(recurrence, '') FROM [SHOW SCHEDULES] WHERE label = $1 ORDER BY id", label)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []v1alpha1.BackupScheduleRun{}
+	for rows.Next() {
+		var id int64
+		var next *time.Time
+		r := v1alpha1.BackupScheduleRun{}
+		if err := rows.Scan(&id, &r.Status, &next, &r.Recurrence); err != nil {
+			return nil, err
+		}
+		r.ID = strconv.FormatInt(id, 10)
+		if next != nil {
+			t := metav1.NewTime(*next)
+			r.NextRun = &t
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+func (c *external) create(ctx context.Context, cr *v1alpha1.BackupSchedule) error {
+	uri, version, err := StorageURI(ctx, c.kube, cr.Spec.ForProvider.Storage)
+	if err != nil {
+		return err
+	}
+	stmt, err := createBackupScheduleStatement(meta.GetExternalName(cr), cr.Spec.ForProvider)
+	if err != nil {
+		return err
+	}
+	if err := c.db.ExecArgs(ctx, stmt, uri); err != nil {
+		return errors.Wrap(err, errCreateBackupSchedule)
+	}
+	cr.Status.AtProvider.SecretVersion = version
+	return nil
+}
+
+// StorageURI returns the URI of the supplied storage with its credentials,
+// along with the resource version of the credentials Secret.
+func StorageURI(ctx context.Context, kube client.Client, s v1alpha1.BackupStorage) (string, string, error) {
+	u, err := url.Parse(s.URI)
+	if err != nil {
+		return "", "", errors.Wrap(err, errParseStorageURI)
+	}
+	ref := s.CredentialsSecretRef
+	if ref == nil {
+		return u.String(), "", nil
+	}
+	sec := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, sec); err != nil {
+		return "", "", errors.Wrap(err, errGetStorageCredentials)
+	}
+	q := u.Query()
+	for k, v := range sec.Data {
+		q.Set(k, string(v))
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), sec.GetResourceVersion(), nil
+}
+
+// BackupTargets returns the targets of a BACKUP or RESTORE statement for the
+// supplied database or tables, or an empty string for the whole cluster.
+func BackupTargets(database string, tables []string) (string, error) {
+	if len(tables) > 0 {
+		ts := make([]string, len(tables))
+		for i, t := range tables {
+			parts := strings.Split(t, ".")
+			if len(parts) < 2 {
+				return "", errors.Errorf(errFmtInvalidQualifiedTable, t)
+			}
+			ts[i] = pgx.Identifier(parts).Sanitize()
+		}
+		return "TABLE " + strings.Join(ts, ", "), nil
+	}
+	if database != "" {
+		return "DATABASE " + pgx.Identifier{database}.Sanitize(), nil
+	}
+	return "", nil
+}
+
+// createBackupScheduleStatement returns the SQL statement that creates the
+// schedules of the supplied parameters with the supplied label. The storage
+// URI is its only placeholder, so that its credentials are not part of the
+// statement.
+func createBackupScheduleStatement(label string, p v1alpha1.BackupScheduleParameters) (string, error) {
+	targets, err := BackupTargets(p.Database, p.Tables)
+	if err != nil {
+		return "", err
+	}
+	stmt := "CREATE SCHEDULE " + sqlresource.Literal(label) + " FOR BACKUP "
+	if targets != "" {
+		stmt += targets + " "
+	}
+	stmt += "INTO $1"
+	if p.RevisionHistory {
+		stmt += " WITH revision_history"
+	}
+	stmt += " RECURRING " + sqlresource.Literal(p.Recurrence)
+	switch {
+	case strings.EqualFold(p.FullBackup, v1alpha1.FullBackupAlways):
+		stmt += " FULL BACKUP ALWAYS"
+	case p.FullBackup != "":
+		stmt += " FULL BACKUP " + sqlresource.Literal(p.FullBackup)
+	}
+	return stmt, nil
+}
+
+// dropBackupSchedulesStatement returns the SQL statement that drops the
+// schedules with the supplied label.
+func dropBackupSchedulesStatement(label string) string {
+	return fmt.Sprintf("DROP SCHEDULES WITH s AS (SHOW SCHEDULES) SELECT id FROM s WHERE label = %s", sqlresource.Literal(label))
+}
+
+// backupScheduleUpToDate returns true if the supplied schedules run at the
+// cadence of the supplied parameters.
+func backupScheduleUpToDate(p v1alpha1.BackupScheduleParameters, runs []v1alpha1.BackupScheduleRun) bool {
+	got := make([]string, len(runs))
+	for i, r := range runs {
+		got[i] = r.Recurrence
+	}
+	sort.Strings(got)
+
+	switch {
+	case strings.EqualFold(p.FullBackup, v1alpha1.FullBackupAlways):
+		return len(got) == 1 && got[0] == p.Recurrence
+	case p.FullBackup != "":
+		want := []string{p.Recurrence, p.FullBackup}
+		sort.Strings(want)
+		return len(got) == 2 && got[0] == want[0] && got[1] == want[1]
+	}
+	// CockroachDB picks the cadence of full backups, so only that of
+	// incremental backups is known.
+	for _, r := range got {
+		if r == p.Recurrence {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupschedule
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestCreateBackupScheduleStatement(t *testing.T) {
+	type want struct {
+		stmt string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.BackupScheduleParameters
+		want   want
+	}{
+		"Cluster": {
+			reason: "The whole cluster should be backed up if neither database nor tables are set.",
+			p:      v1alpha1.BackupScheduleParameters{Recurrence: "@hourly"},
+			want:   want{stmt: `CREATE SCHEDULE 'nightly' FOR BACKUP INTO $1 RECURRING '@hourly'`},
+		},
+		"DatabaseFullBackups": {
+			reason: "Full backups of a database should be taken at their own cadence.",
+			p:      v1alpha1.BackupScheduleParameters{Database: "app", Recurrence: "@hourly", FullBackup: "@daily", RevisionHistory: true},
+			want:   want{stmt: `CREATE SCHEDULE 'nightly' FOR BACKUP DATABASE "app" INTO $1 WITH revision_history RECURRING '@hourly' FULL BACKUP '@daily'`},
+		},
+		"TablesAlwaysFull": {
+			reason: "Only full backups of tables should be taken if requested.",
+			p:      v1alpha1.BackupScheduleParameters{Tables: []string{"app.public.users", "app.orders"}, Recurrence: "@daily", FullBackup: "always"},
+			want:   want{stmt: `CREATE SCHEDULE 'nightly' FOR BACKUP TABLE "app"."public"."users", "app"."orders" INTO $1 RECURRING '@daily' FULL BACKUP ALWAYS`},
+		},
+		"UnqualifiedTable": {
+			reason: "Tables must be qualified with their database.",
+			p:      v1alpha1.BackupScheduleParameters{Tables: []string{"users"}, Recurrence: "@daily"},
+			want:   want{err: errors.Errorf(errFmtInvalidQualifiedTable, "users")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := createBackupScheduleStatement("nightly", tc.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncreateBackupScheduleStatement(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.stmt, got); diff != "" {
+				t.Errorf("\n%s\ncreateBackupScheduleStatement(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBackupScheduleUpToDate(t *testing.T) {
+	hourly := v1alpha1.BackupScheduleRun{ID: "1", Recurrence: "@hourly"}
+	daily := v1alpha1.BackupScheduleRun{ID: "2", Recurrence: "@daily"}
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.BackupScheduleParameters
+		runs   []v1alpha1.BackupScheduleRun
+		want   bool
+	}{
+		"IncrementalAndFull": {
+			reason: "Schedules running at the requested cadences should be up to date.",
+			p:      v1alpha1.BackupScheduleParameters{Recurrence: "@hourly", FullBackup: "@daily"},
+			runs:   []v1alpha1.BackupScheduleRun{daily, hourly},
+			want:   true,
+		},
+		"FullCadenceChanged": {
+			reason: "Schedules should be created again when the cadence of full backups changes.",
+			p:      v1alpha1.BackupScheduleParameters{Recurrence: "@hourly", FullBackup: "@weekly"},
+			runs:   []v1alpha1.BackupScheduleRun{daily, hourly},
+		},
+		"AlwaysFull": {
+			reason: "Incremental schedules should not remain when only full backups are requested.",
+			p:      v1alpha1.BackupScheduleParameters{Recurrence: "@hourly", FullBackup: v1alpha1.FullBackupAlways},
+			runs:   []v1alpha1.BackupScheduleRun{daily, hourly},
+		},
+		"DefaultFull": {
+			reason: "Only the cadence of incremental backups should be compared when CockroachDB picks that of full backups.",
+			p:      v1alpha1.BackupScheduleParameters{Recurrence: "@hourly"},
+			runs:   []v1alpha1.BackupScheduleRun{daily, hourly},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := backupScheduleUpToDate(tc.p, tc.runs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nbackupScheduleUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStorageURI(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+			s := o.(*corev1.Secret)
+			s.SetResourceVersion("42")
+			s.Data = map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("id"), "AWS_SECRET_ACCESS_KEY": []byte("s/cr+t")}
+			return nil
+		}),
+	}
+	s := v1alpha1.BackupStorage{
+		URI:                  "s3://backups/app?AUTH=specified",
+		CredentialsSecretRef: &xpv1.SecretReference{Name: "s3", Namespace: "crossplane-system"},
+	}
+
+	uri, version, err := StorageURI(context.Background(), kube, s)
+	if err != nil {
+		t.Fatalf("StorageURI(...): %v", err)
+	}
+	if diff := cmp.Diff("s3://backups/app?AUTH=specified&AWS_ACCESS_KEY_ID=id&AWS_SECRET_ACCESS_KEY=s%2Fcr%2Bt", uri); diff != "" {
+		t.Errorf("StorageURI(...): -want URI, +got URI:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("42", version); diff != "" {
+		t.Errorf("StorageURI(...): -want version, +got version:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcacert
+
+import (
+	"context"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotClientCACert    = "managed resource is not a ClientCACert custom resource"
+	errNoClusterID        = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetClientCACert    = "cannot get client CA certificate"
+	errSetClientCACert    = "cannot set client CA certificate"
+	errUpdateClientCACert = "cannot update client CA certificate"
+	errDeleteClientCACert = "cannot delete client CA certificate"
+)
+
+// Setup adds a controller that reconciles ClientCACert managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.ClientCACertGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.ClientCACertGroupVersionKind, &v1alpha1.ClientCACert{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles ClientCACerts.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ClientCACert)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotClientCACert)
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	cert, err := c.client.GetClientCACert(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetClientCACert)
+	}
+	if cert.Status == cockroachcloud.ClientCACertStatusNotSet {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider.Status = string(cert.Status)
+	switch cert.Status {
+	case cockroachcloud.ClientCACertStatusIsSet:
+		cr.Status.SetConditions(xpv1.Available())
+	case cockroachcloud.ClientCACertStatusPending:
+		cr.Status.SetConditions(xpv1.Creating())
+	default:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: samePEM(cert.X509PEMCert, cr.Spec.ForProvider.X509PEMCert),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.ClientCACert)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotClientCACert)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	_, err := c.client.SetClientCACert(ctx, cr.Spec.ForProvider.ClusterID, cr.Spec.ForProvider.X509PEMCert)
+	return managed.ExternalCreation{}, errors.Wrap(err, errSetClientCACert)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ClientCACert)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotClientCACert)
+	}
+
+	_, err := c.client.UpdateClientCACert(ctx, cr.Spec.ForProvider.ClusterID, cr.Spec.ForProvider.X509PEMCert)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateClientCACert)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.ClientCACert)
+	if !ok {
+		return errors.New(errNotClientCACert)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.DeleteClientCACert(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errDeleteClientCACert)
+}
+
+// samePEM returns true if the supplied PEM encoded certificates only differ
+// in surrounding whitespace or line endings.
+func samePEM(a, b string) bool {
+	norm := func(s string) string {
+		return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+	}
+	return norm(a) == norm(b)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcacert
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestClientCAObserve(t *testing.T) {
+	const pem = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"
+
+	type want struct {
+		o    managed.ExternalObservation
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   want
+	}{
+		"NotFound": {
+			reason: "A client CA certificate the Cloud API does not know should not exist.",
+		},
+		"NotSet": {
+			reason: "A client CA certificate that is not set should not exist.",
+			body:   `{"status":"NOT_SET"}`,
+		},
+		"UpToDate": {
+			reason: "A set certificate that only differs in line endings should be up to date.",
+			body:   `{"x509_pem_cert":"-----BEGIN CERTIFICATE-----\r\nMIIB\r\n-----END CERTIFICATE-----\r\n","status":"IS_SET"}`,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond: xpv1.Available(),
+			},
+		},
+		"Changed": {
+			reason: "A pending certificate that differs should be updated.",
+			body:   `{"x509_pem_cert":"-----BEGIN CERTIFICATE-----\nOLD\n-----END CERTIFICATE-----","status":"PENDING"}`,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				cond: xpv1.Creating(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body)}
+			cr := &v1alpha1.ClientCACert{Spec: v1alpha1.ClientCACertSpec{ForProvider: v1alpha1.ClientCACertParameters{ClusterID: testClusterID, X509PEMCert: pem}}}
+			o, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if got := cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/provider-cockroachdb/internal/metrics"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+// An APIInfoReporter logs and records the Cloud API version served to each
+// ProviderConfig the first time it is used.
+type APIInfoReporter struct {
+	log     logging.Logger
+	metrics *metrics.APIInfoRecorder
+
+	seen sync.Map
+}
+
+// NewAPIInfoReporter returns an APIInfoReporter that logs to the supplied
+// Logger.
+func NewAPIInfoReporter(log logging.Logger) *APIInfoReporter {
+	return &APIInfoReporter{log: log, metrics: metrics.NewAPIInfoRecorder()}
+}
+
+// Report discovers the Cloud API served to the named ProviderConfig, unless
+// it was already reported. Discovery failures are logged but not returned;
+// they must not prevent reconciliation.
+func (r *APIInfoReporter) Report(ctx context.Context, providerConfig string, c *cockroachcloud.Client) {
+	if r == nil || c == nil {
+		return
+	}
+	if _, seen := r.seen.LoadOrStore(providerConfig, true); seen {
+		return
+	}
+
+	info, err := c.APIInfo(ctx)
+	if err != nil {
+		r.log.Info("Cannot discover Cloud API version", "providerConfig", providerConfig, "error", err.Error())
+		return
+	}
+	r.log.Info("Discovered Cloud API", "providerConfig", providerConfig, "version", info.Version, "paths", len(info.Paths))
+	r.metrics.Record(providerConfig, info.Version)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+func TestAPIInfoReporter(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"info":{"version":"2022-09-20"},"paths":{}}`))
+	}))
+	defer srv.Close()
+	c, err := cockroachcloud.NewClient("key", cockroachcloud.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+
+	// Controllers share one reporter, so each ProviderConfig is reported
+	// once no matter how many controllers connect with it.
+	r := NewAPIInfoReporter(logging.NewNopLogger())
+	for _, pc := range []string{"default", "default", "other", "default"} {
+		r.Report(context.Background(), pc, c)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("r.Report(...): want 2 requests, got %d", got)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+)
+
+const (
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	errFmtNamespaceNotAllowed = "ProviderConfig %q cannot be used by managed resources in namespace %q: add the namespace to its allowedNamespaces"
+)
+
+// A Connector builds the Service of the ProviderConfig referenced by managed
+// resources.
+type Connector struct {
+	Kube         client.Client
+	Usage        resource.Tracker
+	APIInfo      *APIInfoReporter
+	NewServiceFn func(creds []byte, headers map[string]string) (*Service, error)
+}
+
+// Service builds a Service from the ProviderConfig referenced by the supplied
+// managed resource. Namespaced managed resources may only use ProviderConfigs
+// that allow their namespace.
+func (c *Connector) Service(ctx context.Context, mg resource.Managed) (*Service, error) {
+	pc, err := ProviderConfig(ctx, c.Kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	return c.serviceFor(ctx, pc)
+}
+
+// ServiceFor builds a Service from the named ProviderConfig.
+func (c *Connector) ServiceFor(ctx context.Context, providerConfig string) (*Service, error) {
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.Kube.Get(ctx, types.NamespacedName{Name: providerConfig}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	return c.serviceFor(ctx, pc)
+}
+
+// ProviderConfig returns the ProviderConfig referenced by the supplied managed
+// resource. It returns an error if the ProviderConfig does not allow the
+// namespace of the managed resource.
+func ProviderConfig(ctx context.Context, kube client.Client, mg resource.Managed) (*apisv1alpha1.ProviderConfig, error) {
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	if !pc.AllowsNamespace(mg.GetNamespace()) {
+		return nil, errors.Errorf(errFmtNamespaceNotAllowed, pc.GetName(), mg.GetNamespace())
+	}
+	return pc, nil
+}
+
+// serviceFor builds a Service from the supplied ProviderConfig.
+func (c *Connector) serviceFor(ctx context.Context, pc *apisv1alpha1.ProviderConfig) (*Service, error) {
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.Kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.NewServiceFn(data, pc.Spec.Headers)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	svc.Limits = pc.Spec.Limits
+	svc.CAFingerprints = pc.Spec.CAFingerprints
+	c.APIInfo.Report(ctx, pc.GetName(), svc.CloudClient)
+	return svc, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+)
+
+func TestProviderConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+	get := func(allowed ...string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o client.Object) error {
+			pc := o.(*apisv1alpha1.ProviderConfig)
+			pc.SetName("default")
+			pc.Spec.AllowedNamespaces = allowed
+			return nil
+		})
+	}
+	namespaced := func(ns string) resource.Managed {
+		cr := &namespacedv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool", Namespace: ns}}
+		cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+		return cr
+	}
+	clusterScoped := cluster(func(cr *v1alpha1.Cluster) {
+		cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+	})
+
+	type want struct {
+		pc  bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		mg     resource.Managed
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting the ProviderConfig should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			mg:     clusterScoped,
+			want:   want{err: errors.Wrap(errBoom, errGetPC)},
+		},
+		"ClusterScoped": {
+			reason: "Cluster scoped managed resources may use any ProviderConfig.",
+			kube:   &test.MockClient{MockGet: get()},
+			mg:     clusterScoped,
+			want:   want{pc: true},
+		},
+		"NamespaceAllowed": {
+			reason: "Namespaced managed resources may use ProviderConfigs that allow their namespace.",
+			kube:   &test.MockClient{MockGet: get("other", "team")},
+			mg:     namespaced("team"),
+			want:   want{pc: true},
+		},
+		"NamespaceNotAllowed": {
+			reason: "Namespaced managed resources may not use ProviderConfigs that do not allow their namespace.",
+			kube:   &test.MockClient{MockGet: get("other")},
+			mg:     namespaced("team"),
+			want:   want{err: errors.Errorf(errFmtNamespaceNotAllowed, "default", "team")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc, err := ProviderConfig(context.Background(), tc.kube, tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProviderConfig(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pc, pc != nil); diff != "" {
+				t.Errorf("\n%s\nProviderConfig(...): -want ProviderConfig, +got ProviderConfig:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
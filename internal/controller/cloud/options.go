@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	// DefaultOperationTimeout bounds each observe, create, update or delete
+	// of a managed resource by default.
+	DefaultOperationTimeout = 30 * time.Second
+
+	// DefaultShutdownGracePeriod is how long in-flight reconciles may take to
+	// complete by default once the provider is stopping.
+	DefaultShutdownGracePeriod = 20 * time.Second
+)
+
+// Options configure the controllers of the provider, on top of the options
+// of crossplane-runtime.
+type Options struct {
+	controller.Options
+
+	// OperationTimeout bounds each observe, create, update or delete of a
+	// managed resource that does not override it with the operation-timeout
+	// annotation.
+	OperationTimeout time.Duration
+
+	// ShutdownGracePeriod is how long in-flight reconciles may take to
+	// complete once the provider is stopping.
+	ShutdownGracePeriod time.Duration
+
+	// SQLPool shares SQL connections to each cluster between the operations
+	// on the resources managed over SQL inside it.
+	SQLPool *sqlclient.Pool
+
+	// Transport of the connections to the CockroachDB Cloud APIs. Defaults
+	// to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// TLSConfig of the SQL connections to clusters. Its root CAs, server
+	// name and certificates are set per cluster.
+	TLSConfig *tls.Config
+
+	// APIInfo reports the Cloud API served to each ProviderConfig. It is
+	// shared by all controllers, so that each ProviderConfig is reported
+	// once.
+	APIInfo *APIInfoReporter
+}
+
+// NewService returns a Service that authenticates with the supplied API key,
+// adds the supplied headers to its requests, and connects through the
+// Transport of the Options.
+func (o Options) NewService(creds []byte, headers map[string]string) (*Service, error) {
+	s, err := NewService(creds, headers, o.Transport)
+	if err != nil {
+		return nil, err
+	}
+	s.TLSConfig = o.TLSConfig
+	return s, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// A LocalConnectionPublisher publishes connection details of a namespaced
+// managed resource to the namespace of the managed resource, regardless of
+// the namespace set in its writeConnectionSecretToRef.
+type LocalConnectionPublisher struct {
+	publisher managed.ConnectionPublisher
+}
+
+// NewLocalConnectionPublisher returns a LocalConnectionPublisher that
+// publishes through the supplied publisher.
+func NewLocalConnectionPublisher(p managed.ConnectionPublisher) *LocalConnectionPublisher {
+	return &LocalConnectionPublisher{publisher: p}
+}
+
+// PublishConnection publishes the supplied connection details to the
+// namespace of the supplied owner.
+func (p *LocalConnectionPublisher) PublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	return p.publisher.PublishConnection(ctx, local(so), c)
+}
+
+// UnpublishConnection unpublishes the supplied connection details from the
+// namespace of the supplied owner.
+func (p *LocalConnectionPublisher) UnpublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+	return p.publisher.UnpublishConnection(ctx, local(so), c)
+}
+
+func local(so resource.ConnectionSecretOwner) resource.ConnectionSecretOwner {
+	ref := so.GetWriteConnectionSecretToReference()
+	if ref == nil || ref.Namespace == so.GetNamespace() {
+		return so
+	}
+	l := so.DeepCopyObject().(resource.ConnectionSecretOwner)
+	l.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: ref.Name, Namespace: so.GetNamespace()})
+	return l
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/usage"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+// SetupResource adds a controller that reconciles the supplied kind of
+// managed resource, which is only managed through the Cloud API, with the
+// ExternalClients produced by the supplied function.
+func SetupResource(mgr ctrl.Manager, o Options, name string, gvk schema.GroupVersionKind, obj resource.Managed, newExternal func(client.Client, *cockroachcloud.Client) managed.ExternalClient) error {
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(gvk),
+		managed.WithExternalConnecter(redact.NewConnecter(NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&resourceConnector{
+			Connector: &Connector{
+				Kube:         mgr.GetClient(),
+				Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+				APIInfo:      o.APIInfo,
+				NewServiceFn: o.NewService},
+			tracker:     usage.NewTracker(mgr.GetClient()),
+			newExternal: newExternal}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(obj).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return obj.DeepCopyObject().(resource.Managed) }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A resourceConnector produces an ExternalClient for managed resources
+// that are only managed through the Cloud API.
+type resourceConnector struct {
+	*Connector
+	tracker     *usage.Tracker
+	newExternal func(client.Client, *cockroachcloud.Client) managed.ExternalClient
+}
+
+func (c *resourceConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+	if ref := ClusterRef(mg); ref != nil {
+		if err := c.tracker.Track(ctx, mg, ref.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	svc, err := c.Service(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return c.newExternal(c.Kube, svc.CloudClient), nil
+}
+
+// MetricExportCondition returns the readiness of a metric export in the
+// supplied state.
+func MetricExportCondition(s cockroachcloud.MetricExportStatus) xpv1.Condition {
+	switch s {
+	case cockroachcloud.MetricExportStatusEnabled:
+		return xpv1.Available()
+	case cockroachcloud.MetricExportStatusEnabling:
+		return xpv1.Creating()
+	case cockroachcloud.MetricExportStatusDisabling:
+		return xpv1.Deleting()
+	default:
+		return xpv1.Unavailable()
+	}
+}
+
+// ClusterRef returns the Cluster the supplied managed resource depends on, if
+// it references one and must not outlive it.
+func ClusterRef(mg resource.Managed) *xpv1.Reference {
+	switch cr := mg.(type) {
+	case *v1alpha1.CloudDatabase:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.Database:
+		return cr.Spec.ForProvider.ClusterRef
+	case *namespacedv1alpha1.Database:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.Grant:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.Schema:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.BackupSchedule:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.BackupJob:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.RestoreSQL:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.DefaultPrivileges:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.TableTTLPolicy:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.DatabaseRegion:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.SQLScript:
+		return cr.Spec.ForProvider.ClusterRef
+	case *v1alpha1.RoleDefaultSettings:
+		return cr.Spec.ForProvider.ClusterRef
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetPassword returns the password stored in the selected Secret key, or a
+// random password if no Secret key is selected.
+func GetPassword(ctx context.Context, kube client.Client, secretKeySelector *xpv1.SecretKeySelector) ([]byte, error) {
+	if secretKeySelector == nil {
+		password, err := password.Generate(16, 4, 0, false, false)
+		if err != nil {
+			return nil, fmt.Errorf("error generating random password: %v", err)
+		}
+		return []byte(password), nil
+	}
+	return GetSecretKey(ctx, kube, secretKeySelector)
+}
+
+// GetSecretKey returns the value of the selected Secret key.
+func GetSecretKey(ctx context.Context, kube client.Client, secretKeySelector *xpv1.SecretKeySelector) ([]byte, error) {
+	nn := types.NamespacedName{
+		Name:      secretKeySelector.Name,
+		Namespace: secretKeySelector.Namespace,
+	}
+
+	var secret corev1.Secret
+	if err := kube.Get(ctx, nn, &secret); err != nil {
+		return nil, err
+	}
+
+	val, ok := secret.Data[secretKeySelector.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret key \"%s\" not found", secretKeySelector.Key)
+	}
+
+	return val, nil
+}
+
+// GetSecretKeyVersion returns the value of the selected Secret key along with
+// the resource version of the Secret, so that changes to it can be detected.
+func GetSecretKeyVersion(ctx context.Context, kube client.Client, sel xpv1.SecretKeySelector) ([]byte, string, error) {
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}, s); err != nil {
+		return nil, "", err
+	}
+	val, ok := s.Data[sel.Key]
+	if !ok {
+		return nil, "", fmt.Errorf("secret key \"%s\" not found", sel.Key)
+	}
+	return val, s.GetResourceVersion(), nil
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachca"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errVerifyClusterCA = "cannot verify cluster CA certificate"
+	errListSQLUsers    = "cannot list SQL users"
+
+	defaultCAURL = "https://cockroachlabs.cloud/"
+
+	// DefaultSQLPort is the SQL port of clusters in CockroachDB Cloud.
+	DefaultSQLPort = "26257"
+	// DefaultDatabase is the database clients connect to by default.
+	DefaultDatabase = "defaultdb"
+
+	// ConnectionDetailRegionDSNPrefix prefixes the name of each region of
+	// a multi-region cluster to key its DSN in the connection details.
+	ConnectionDetailRegionDSNPrefix = "dsn."
+)
+
+// A Service holds the clients of the CockroachDB Cloud APIs, configured from
+// a ProviderConfig.
+type Service struct {
+	CRDBClient  cockroachdb.Service
+	CAClient    *cockroachca.CAClient
+	CloudClient *cockroachcloud.Client
+	Limits      *apisv1alpha1.ProviderLimits
+
+	CAFingerprints []string
+
+	// TLSConfig of SQL connections to clusters, if any.
+	TLSConfig *tls.Config
+}
+
+// NewService returns a Service that authenticates with the supplied API key,
+// adds the supplied headers to its requests to the Cloud API, and connects
+// through the supplied transport. The transport defaults to
+// http.DefaultTransport.
+func NewService(creds []byte, headers map[string]string, t http.RoundTripper) (*Service, error) {
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	httpClient := &http.Client{Transport: audit.NewTransport(cockroachcloud.NewRetryTransport(tracing.NewTransport(t), cockroachcloud.DefaultRetryPolicy()))}
+	clientConfig := cockroachdb.NewConfiguration(string(creds))
+	clientConfig.HTTPClient = httpClient
+	addDefaultHeaders(clientConfig, headers)
+	cockroachclient := cockroachdb.NewClient(clientConfig)
+	service := cockroachdb.NewService(cockroachclient)
+
+	caClient, err := cockroachca.NewCAClient(
+		cockroachca.WithBaseURL(defaultCAURL),
+		cockroachca.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creatint CA client: %v", err)
+	}
+
+	cloudClient, err := cockroachcloud.NewClient(string(creds), cockroachcloud.WithHTTPClient(httpClient), cockroachcloud.WithHeaders(headers))
+	if err != nil {
+		return nil, fmt.Errorf("error creating Cloud API client: %v", err)
+	}
+
+	return &Service{
+		CRDBClient:  service,
+		CAClient:    caClient,
+		CloudClient: cloudClient,
+	}, nil
+}
+
+// addDefaultHeaders adds the supplied headers to the default headers of the
+// supplied configuration, replacing those with the same name. The
+// Authorization header is set by the client, and is never overridden.
+func addDefaultHeaders(cfg *cockroachdb.Configuration, headers map[string]string) {
+	for k, v := range headers {
+		k = http.CanonicalHeaderKey(k)
+		if k == "Authorization" {
+			continue
+		}
+		for existing := range cfg.DefaultHeader {
+			if http.CanonicalHeaderKey(existing) == k {
+				delete(cfg.DefaultHeader, existing)
+			}
+		}
+		cfg.AddDefaultHeader(k, v)
+	}
+}
+
+// ClusterCACert returns the CA certificate of the supplied cluster through the
+// authenticated Cloud API. The public certificate endpoint is only used as a
+// fallback, as it may not be reachable behind egress policies. The
+// certificate is verified against the fingerprints pinned in the
+// ProviderConfig, if any.
+func (s *Service) ClusterCACert(ctx context.Context, cluster *cockroachdb.Cluster) ([]byte, error) {
+	ca, err := s.downloadCACert(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := cockroachca.VerifyFingerprints(ca, s.CAFingerprints); err != nil {
+		return nil, errors.Wrap(err, errVerifyClusterCA)
+	}
+	return ca, nil
+}
+
+func (s *Service) downloadCACert(ctx context.Context, cluster *cockroachdb.Cluster) ([]byte, error) {
+	if s.CloudClient != nil {
+		if ca, err := s.CloudClient.ClusterCACert(ctx, cluster.Id); err == nil {
+			return ca, nil
+		}
+	}
+	return s.CAClient.ClusterCACert(ctx, cluster)
+}
+
+// ConnectionDetails returns the connection details of the supplied user.
+// Multi-region clusters additionally get a DSN per region, keyed
+// dsn.<region>, so that clients can connect to the region nearest to them.
+func (s *Service) ConnectionDetails(ctx context.Context, user string, cluster *cockroachdb.Cluster, ca, password []byte) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{
+		sqlclient.ConnectionDetailCA:  ca,
+		sqlclient.ConnectionDetailDSN: []byte(s.DSN(ctx, user, password, cluster)),
+	}
+	if len(cluster.Regions) > 1 {
+		for _, r := range cluster.Regions {
+			cd[ConnectionDetailRegionDSNPrefix+r.Name] = []byte(RegionDSN(user, password, cluster, r))
+		}
+	}
+	return cd
+}
+
+// DSN prefers the connection string composed by the Cloud API, so changes
+// to its format upstream are picked up, and falls back to composing it when
+// the API cannot be reached.
+func (s *Service) DSN(ctx context.Context, user string, password []byte, cluster *cockroachdb.Cluster) string {
+	if s.CloudClient != nil {
+		str, err := s.CloudClient.ConnectionString(ctx, cluster.Id, DefaultDatabase, user)
+		if u, perr := url.Parse(str); err == nil && perr == nil && u.Host != "" {
+			u.User = url.UserPassword(user, string(password))
+			return u.String()
+		}
+	}
+	return DSN(user, password, cluster)
+}
+
+// DSN composes the DSN of the first region of the supplied cluster.
+func DSN(user string, password []byte, cluster *cockroachdb.Cluster) string {
+	return RegionDSN(user, password, cluster, cluster.Regions[0])
+}
+
+// RegionDSN composes the DSN of the supplied region of a cluster. The user
+// and password are escaped, so they may contain any character.
+func RegionDSN(user string, password []byte, cluster *cockroachdb.Cluster, region cockroachdb.Region) string {
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(user, string(password)),
+		Host:     net.JoinHostPort(region.SqlDns, DefaultSQLPort),
+		Path:     "/" + DefaultDatabase,
+		RawQuery: "sslmode=verify-full",
+	}
+	if cluster.CloudProvider != cockroachcloud.CloudProviderAzure && cluster.Plan != cockroachdb.PLAN_DEDICATED {
+		// Dedicated clusters and clusters on Azure have a host name of
+		// their own rather than a shared one, so only the others need a
+		// routing option.
+		u.RawQuery += "&options=" + url.QueryEscape("--cluster="+cluster.Name)
+	}
+	return u.String()
+}
+
+// ListSQLUsers returns the names of the SQL users of the supplied cluster.
+func (s *Service) ListSQLUsers(ctx context.Context, clusterID string) (map[string]bool, error) {
+	users := map[string]bool{}
+	err := cockroachcloud.NewPager(func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		res, _, err := s.CRDBClient.ListSQLUsers(ctx, clusterID, &cockroachdb.ListSQLUsersOptions{PaginationStartKey: startKey})
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range res.Users {
+			users[u.Name] = true
+		}
+		return res.Pagination, nil
+	}).All(ctx)
+	return users, errors.Wrap(err, errListSQLUsers)
+}
+
+// IsNotFound returns true if the supplied response of the Cloud API is 404
+// Not Found. Responses are nil when requests fail before reaching the API,
+// e.g. because they timed out.
+func IsNotFound(res *http.Response) bool {
+	return res != nil && res.StatusCode == http.StatusNotFound
+}
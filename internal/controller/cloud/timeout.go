@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// A timeoutConnecter produces ExternalClients whose operations are cancelled
+// when they take longer than the timeout of the managed resource, so that a
+// hung Cloud API call cannot block a worker.
+type timeoutConnecter struct {
+	wrapped managed.ExternalConnecter
+	timeout time.Duration
+}
+
+// NewTimeoutConnecter returns an ExternalConnecter whose ExternalClients
+// cancel operations exceeding the supplied timeout, unless the managed
+// resource overrides it.
+func NewTimeoutConnecter(c managed.ExternalConnecter, timeout time.Duration) *timeoutConnecter {
+	return &timeoutConnecter{wrapped: c, timeout: timeout}
+}
+
+func (c *timeoutConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.wrapped.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutExternal{wrapped: e, timeout: c.timeout}, nil
+}
+
+type timeoutExternal struct {
+	wrapped managed.ExternalClient
+	timeout time.Duration
+}
+
+// timeoutFor returns the operation timeout of the supplied managed resource.
+// Invalid overrides are ignored.
+func (e *timeoutExternal) timeoutFor(mg resource.Managed) time.Duration {
+	if d, err := time.ParseDuration(mg.GetAnnotations()[v1alpha1.AnnotationKeyOperationTimeout]); err == nil && d > 0 {
+		return d
+	}
+	return e.timeout
+}
+
+// run calls fn with a context that is cancelled after the timeout of the
+// supplied managed resource, and reports whether the call timed out.
+func (e *timeoutExternal) run(ctx context.Context, mg resource.Managed, operation string, fn func(context.Context) error) error {
+	d := e.timeoutFor(mg)
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	err := fn(ctx)
+	switch {
+	case err != nil && ctx.Err() == context.DeadlineExceeded:
+		mg.SetConditions(v1alpha1.ReconcileTimedOut(operation, d))
+	case mg.GetCondition(v1alpha1.TypeReconcileTimeout).Status == corev1.ConditionTrue:
+		mg.SetConditions(v1alpha1.ReconcileWithinTimeout())
+	}
+	return err
+}
+
+func (e *timeoutExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	var o managed.ExternalObservation
+	err := e.run(ctx, mg, "Observe", func(ctx context.Context) error {
+		var err error
+		o, err = e.wrapped.Observe(ctx, mg)
+		return err
+	})
+	return o, err
+}
+
+func (e *timeoutExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	var c managed.ExternalCreation
+	err := e.run(ctx, mg, "Create", func(ctx context.Context) error {
+		var err error
+		c, err = e.wrapped.Create(ctx, mg)
+		return err
+	})
+	return c, err
+}
+
+func (e *timeoutExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	var u managed.ExternalUpdate
+	err := e.run(ctx, mg, "Update", func(ctx context.Context) error {
+		var err error
+		u, err = e.wrapped.Update(ctx, mg)
+		return err
+	})
+	return u, err
+}
+
+func (e *timeoutExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return e.run(ctx, mg, "Delete", func(ctx context.Context) error {
+		return e.wrapped.Delete(ctx, mg)
+	})
+}
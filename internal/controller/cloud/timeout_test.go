@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func cluster(m ...func(*v1alpha1.Cluster)) *v1alpha1.Cluster {
+	cr := &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func TestTimeoutExternal(t *testing.T) {
+	hang := func(ctx context.Context, _ resource.Managed) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	ok := func(context.Context, resource.Managed) error { return nil }
+
+	type want struct {
+		cr  *v1alpha1.Cluster
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		delete func(context.Context, resource.Managed) error
+		cr     *v1alpha1.Cluster
+		want   want
+	}{
+		"TimedOut": {
+			reason: "An operation exceeding the timeout of the Cluster should be cancelled and reported.",
+			delete: hang,
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyOperationTimeout: "1ms"})
+			}),
+			want: want{
+				cr: cluster(func(cr *v1alpha1.Cluster) {
+					meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyOperationTimeout: "1ms"})
+					cr.SetConditions(v1alpha1.ReconcileTimedOut("Delete", time.Millisecond))
+				}),
+				err: context.DeadlineExceeded,
+			},
+		},
+		"Recovered": {
+			reason: "An operation completing in time should clear a previous timeout.",
+			delete: ok,
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.SetConditions(v1alpha1.ReconcileTimedOut("Delete", time.Millisecond))
+			}),
+			want: want{
+				cr: cluster(func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.ReconcileWithinTimeout())
+				}),
+			},
+		},
+		"NeverTimedOut": {
+			reason: "An operation completing in time should not add a condition.",
+			delete: ok,
+			cr:     cluster(),
+			want:   want{cr: cluster()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &timeoutExternal{wrapped: &managed.ExternalClientFns{DeleteFn: tc.delete}, timeout: time.Minute}
+			err := e.Delete(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, cmpopts.IgnoreTypes(xpv1.Condition{}.LastTransitionTime)); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clouddatabase
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotCloudDatabase = "managed resource is not a CloudDatabase custom resource"
+	errListDatabases    = "cannot list databases"
+	errCreateDatabase   = "cannot create database"
+	errDeleteDatabase   = "cannot delete database"
+	errNoClusterID      = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+)
+
+// Setup adds a controller that reconciles CloudDatabase managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.CloudDatabaseGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.CloudDatabaseGroupVersionKind, &v1alpha1.CloudDatabase{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles CloudDatabases.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.CloudDatabase)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotCloudDatabase)
+	}
+	clusterID := cr.Spec.ForProvider.ClusterID
+	if clusterID == "" && meta.WasDeleted(cr) {
+		// References are not resolved while deleting, so a CloudDatabase
+		// whose Cluster never existed would otherwise never go.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if clusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	dbs, err := c.client.ListDatabases(ctx, clusterID)
+	if cockroachcloud.IsNotFound(err) {
+		// The databases of a cluster are deleted along with it.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListDatabases)
+	}
+	name := cloudDatabaseName(cr)
+	for _, db := range dbs {
+		if db.Name != name {
+			continue
+		}
+		cr.Status.AtProvider = v1alpha1.CloudDatabaseObservation{ClusterID: clusterID, TableCount: db.TableCount}
+		cr.Status.SetConditions(xpv1.Available())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	return managed.ExternalObservation{ResourceExists: false}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.CloudDatabase)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotCloudDatabase)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	name := cloudDatabaseName(cr)
+	if _, err := c.client.CreateDatabase(ctx, cr.Spec.ForProvider.ClusterID, name); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDatabase)
+	}
+	meta.SetExternalName(cr, name)
+	return managed.ExternalCreation{}, nil
+}
+
+// Update does nothing, as databases have no configurable fields besides
+// their immutable name.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.CloudDatabase)
+	if !ok {
+		return errors.New(errNotCloudDatabase)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.DeleteDatabase(ctx, cr.Spec.ForProvider.ClusterID, cloudDatabaseName(cr))
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errDeleteDatabase)
+}
+
+// cloudDatabaseName returns the name of the database of the supplied
+// CloudDatabase.
+func cloudDatabaseName(cr *v1alpha1.CloudDatabase) string {
+	if n := cr.Spec.ForProvider.Name; n != "" {
+		return n
+	}
+	return cr.GetName()
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clouddatabase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestCloudDatabaseObserve(t *testing.T) {
+	const body = `{"databases":[{"name":"defaultdb"},{"name":"app","table_count":3}]}`
+
+	type want struct {
+		o   managed.ExternalObservation
+		at  v1alpha1.CloudDatabaseObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		deleted   bool
+		clusterID string
+		name      string
+		body      string
+		want      want
+	}{
+		"DeletedWithoutCluster": {
+			reason:  "A deleted CloudDatabase whose Cluster never existed should not exist.",
+			deleted: true,
+		},
+		"NoClusterID": {
+			reason: "A CloudDatabase without a cluster ID cannot be observed.",
+			want:   want{err: errors.New(errNoClusterID)},
+		},
+		"ClusterGone": {
+			reason:    "The databases of a cluster the Cloud API does not know should not exist.",
+			clusterID: testClusterID,
+		},
+		"NotCreated": {
+			reason:    "A database that is not listed should not exist.",
+			clusterID: testClusterID,
+			name:      "other",
+			body:      body,
+		},
+		"Exists": {
+			reason:    "A listed database should exist and report its tables.",
+			clusterID: testClusterID,
+			body:      body,
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at: v1alpha1.CloudDatabaseObservation{ClusterID: testClusterID, TableCount: 3},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body)}
+			cr := &v1alpha1.CloudDatabase{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec:       v1alpha1.CloudDatabaseSpec{ForProvider: v1alpha1.CloudDatabaseParameters{ClusterID: tc.clusterID, Name: tc.name}},
+			}
+			if tc.deleted {
+				cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}
+			o, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.at, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want atProvider, +got atProvider:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
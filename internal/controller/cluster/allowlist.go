@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errListAllowlist     = "cannot list allowlist entries"
+	errAddAllowlistEntry = "cannot add allowlist entry"
+	errUpdAllowlistEntry = "cannot update allowlist entry"
+	errDelAllowlistEntry = "cannot delete allowlist entry"
+	errFmtParseCIDR      = "cannot parse allowlist CIDR %q"
+)
+
+// An allowlistDiff describes the changes required to sync the allowlist of a
+// cluster with the spec of a Cluster.
+type allowlistDiff struct {
+	add    []cockroachdb.AllowlistEntry
+	update []cockroachdb.AllowlistEntry
+	remove []cockroachdb.AllowlistEntry
+}
+
+func (d allowlistDiff) empty() bool {
+	return len(d.add) == 0 && len(d.update) == 0 && len(d.remove) == 0
+}
+
+// managesAllowlist returns true if the supplied Cluster manages the allowlist
+// of its cluster.
+func managesAllowlist(cr *v1alpha1.Cluster) bool {
+	return len(cr.Spec.ForProvider.Allowlist) > 0 || cr.Spec.ForProvider.AllowlistPolicy == v1alpha1.AllowlistPolicyExclusive
+}
+
+// listAllowlist returns the allowlist entries of the supplied cluster, and
+// whether changes to them are still propagating.
+func (c *external) listAllowlist(ctx context.Context, clusterID string) ([]cockroachdb.AllowlistEntry, bool, error) {
+	entries := []cockroachdb.AllowlistEntry{}
+	propagating := false
+	err := cockroachcloud.NewPager(func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		res, _, err := c.service.CRDBClient.ListAllowlistEntries(ctx, clusterID, &cockroachdb.ListAllowlistEntriesOptions{PaginationStartKey: startKey})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res.Allowlist...)
+		propagating = propagating || res.Propagating
+		return res.Pagination, nil
+	}).All(ctx)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errListAllowlist)
+	}
+	return entries, propagating, nil
+}
+
+// observeAllowlist returns the changes required to sync the allowlist of the
+// supplied cluster.
+func (c *external) observeAllowlist(ctx context.Context, cr *v1alpha1.Cluster, clusterID string) (allowlistDiff, error) {
+	if !managesAllowlist(cr) {
+		return allowlistDiff{}, nil
+	}
+	observed, _, err := c.listAllowlist(ctx, clusterID)
+	if err != nil {
+		return allowlistDiff{}, err
+	}
+	return diffAllowlist(cr.Spec.ForProvider.Allowlist, observed, cr.Spec.ForProvider.AllowlistPolicy)
+}
+
+// syncAllowlist applies the supplied changes to the allowlist of the supplied
+// cluster.
+func (c *external) syncAllowlist(ctx context.Context, clusterID string, d allowlistDiff) error {
+	for i := range d.add {
+		if _, _, err := c.service.CRDBClient.AddAllowlistEntry(ctx, clusterID, &d.add[i]); err != nil {
+			return errors.Wrap(err, errAddAllowlistEntry)
+		}
+	}
+	for i := range d.update {
+		e := &d.update[i]
+		if _, _, err := c.service.CRDBClient.UpdateAllowlistEntry(ctx, clusterID, e.CidrIp, e.CidrMask, e, &cockroachdb.UpdateAllowlistEntryOptions{}); err != nil {
+			return errors.Wrap(err, errUpdAllowlistEntry)
+		}
+	}
+	for _, e := range d.remove {
+		if _, _, err := c.service.CRDBClient.DeleteAllowlistEntry(ctx, clusterID, e.CidrIp, e.CidrMask); err != nil {
+			return errors.Wrap(err, errDelAllowlistEntry)
+		}
+	}
+	return nil
+}
+
+// diffAllowlist compares the desired allowlist with the observed one. Entries
+// are identified by their CIDR range. Observed entries that are not desired
+// are only removed by the Exclusive policy.
+func diffAllowlist(desired []v1alpha1.AllowlistEntry, observed []cockroachdb.AllowlistEntry, policy v1alpha1.AllowlistPolicy) (allowlistDiff, error) {
+	existing := map[string]cockroachdb.AllowlistEntry{}
+	for _, e := range observed {
+		existing[allowlistKey(e)] = e
+	}
+
+	d := allowlistDiff{}
+	wanted := map[string]bool{}
+	for _, de := range desired {
+		e, err := toAllowlistEntry(de)
+		if err != nil {
+			return allowlistDiff{}, err
+		}
+		k := allowlistKey(e)
+		wanted[k] = true
+		o, ok := existing[k]
+		switch {
+		case !ok:
+			d.add = append(d.add, e)
+		case o.Ui != e.Ui || o.Sql != e.Sql || (e.Name != nil && (o.Name == nil || *o.Name != *e.Name)):
+			d.update = append(d.update, e)
+		}
+	}
+
+	if policy != v1alpha1.AllowlistPolicyExclusive {
+		return d, nil
+	}
+	for _, o := range observed {
+		if !wanted[allowlistKey(o)] {
+			d.remove = append(d.remove, o)
+		}
+	}
+	return d, nil
+}
+
+func toAllowlistEntry(e v1alpha1.AllowlistEntry) (cockroachdb.AllowlistEntry, error) {
+	_, n, err := net.ParseCIDR(e.CIDR)
+	if err != nil {
+		return cockroachdb.AllowlistEntry{}, errors.Wrapf(err, errFmtParseCIDR, e.CIDR)
+	}
+	mask, _ := n.Mask.Size()
+	ae := cockroachdb.AllowlistEntry{CidrIp: n.IP.String(), CidrMask: int32(mask), Ui: e.UI, Sql: e.SQL}
+	if e.Name != "" {
+		ae.Name = &e.Name
+	}
+	return ae, nil
+}
+
+func fromAllowlistEntry(e cockroachdb.AllowlistEntry) v1alpha1.AllowlistEntry {
+	ae := v1alpha1.AllowlistEntry{CIDR: allowlistKey(e), UI: e.Ui, SQL: e.Sql}
+	if e.Name != nil {
+		ae.Name = *e.Name
+	}
+	return ae
+}
+
+func allowlistKey(e cockroachdb.AllowlistEntry) string {
+	return fmt.Sprintf("%s/%d", e.CidrIp, e.CidrMask)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestDiffAllowlist(t *testing.T) {
+	office := "office"
+	observed := []cockroachdb.AllowlistEntry{
+		{CidrIp: "10.0.0.0", CidrMask: 8, Sql: true},
+		{CidrIp: "192.168.1.0", CidrMask: 24, Ui: true, Sql: true, Name: &office},
+	}
+
+	type args struct {
+		desired []v1alpha1.AllowlistEntry
+		policy  v1alpha1.AllowlistPolicy
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   allowlistDiff
+	}{
+		"UpToDate": {
+			reason: "Desired entries that match the observed ones should not be changed.",
+			args: args{
+				desired: []v1alpha1.AllowlistEntry{{CIDR: "192.168.1.0/24", Name: "office", UI: true, SQL: true}},
+				policy:  v1alpha1.AllowlistPolicyAdditive,
+			},
+		},
+		"AddAndUpdate": {
+			reason: "Missing entries should be added and differing entries updated.",
+			args: args{
+				desired: []v1alpha1.AllowlistEntry{
+					{CIDR: "10.0.0.0/8", UI: true, SQL: true},
+					{CIDR: "172.16.0.1/12", SQL: true},
+				},
+				policy: v1alpha1.AllowlistPolicyAdditive,
+			},
+			want: allowlistDiff{
+				add:    []cockroachdb.AllowlistEntry{{CidrIp: "172.16.0.0", CidrMask: 12, Sql: true}},
+				update: []cockroachdb.AllowlistEntry{{CidrIp: "10.0.0.0", CidrMask: 8, Ui: true, Sql: true}},
+			},
+		},
+		"Exclusive": {
+			reason: "Entries that are not desired should be removed by the Exclusive policy.",
+			args: args{
+				desired: []v1alpha1.AllowlistEntry{{CIDR: "10.0.0.0/8", SQL: true}},
+				policy:  v1alpha1.AllowlistPolicyExclusive,
+			},
+			want: allowlistDiff{
+				remove: []cockroachdb.AllowlistEntry{observed[1]},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := diffAllowlist(tc.args.desired, observed, tc.args.policy)
+			if err != nil {
+				t.Fatalf("\n%s\ndiffAllowlist(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(allowlistDiff{})); diff != "" {
+				t.Errorf("\n%s\ndiffAllowlist(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
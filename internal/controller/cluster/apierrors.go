@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+)
+
+const (
+	msgFmtQuotaExceeded    = "an organization limit of CockroachDB Cloud was reached (%s); delete unused clusters or ask Cockroach Labs to raise the limit"
+	msgFmtInvalidRegion    = "CockroachDB Cloud does not offer a requested region (%s); check spec.forProvider.regions against the regions of the cloud provider"
+	msgFmtNameTaken        = "the name is already taken in the CockroachDB Cloud organization (%s); choose a name that is unique within the organization"
+	msgFmtPermissionDenied = "the API key of the ProviderConfig is not allowed to perform this operation (%s); check that the key was not revoked and that its service account has the required role"
+
+	// gRPC status codes of well-known Cloud API errors.
+	codeInvalidArgument  = 3
+	codeAlreadyExists    = 6
+	codePermissionDenied = 7
+	codeUnauthenticated  = 16
+)
+
+// A friendlyError replaces the message of a well-known Cloud API error with
+// one that tells users how to resolve it.
+type friendlyError struct {
+	reason xpv1.ConditionReason
+	msg    string
+	err    error
+}
+
+func (e *friendlyError) Error() string {
+	return e.msg
+}
+
+func (e *friendlyError) Unwrap() error {
+	return e.err
+}
+
+// friendly returns a friendlyError for the supplied error if it was caused by
+// a well-known Cloud API error, and nil otherwise.
+func friendly(err error) *friendlyError {
+	var apiErr cockroachdb.Error
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	code, msg := apiStatus(apiErr)
+	if msg == "" {
+		msg = apiErr.Error()
+	}
+	msg = redact.String(msg)
+	status := httpStatus(apiErr)
+
+	switch {
+	case code == codeResourceExhausted:
+		return &friendlyError{reason: v1alpha1.ReasonQuotaExceeded, msg: fmt.Sprintf(msgFmtQuotaExceeded, msg), err: err}
+	case (code == codeInvalidArgument || status == http.StatusBadRequest) && strings.Contains(strings.ToLower(msg), "region"):
+		return &friendlyError{reason: v1alpha1.ReasonInvalidRegion, msg: fmt.Sprintf(msgFmtInvalidRegion, msg), err: err}
+	case code == codeAlreadyExists || status == http.StatusConflict:
+		return &friendlyError{reason: v1alpha1.ReasonNameTaken, msg: fmt.Sprintf(msgFmtNameTaken, msg), err: err}
+	case code == codePermissionDenied || code == codeUnauthenticated || status == http.StatusForbidden || status == http.StatusUnauthorized:
+		return &friendlyError{reason: v1alpha1.ReasonPermissionDenied, msg: fmt.Sprintf(msgFmtPermissionDenied, msg), err: err}
+	}
+	return nil
+}
+
+// httpStatus returns the HTTP status code of the supplied Cloud API error.
+// The SDK only exposes it as the status line it uses as error string.
+func httpStatus(err cockroachdb.Error) int {
+	f := strings.Fields(err.Error())
+	if len(f) == 0 {
+		return 0
+	}
+	s, _ := strconv.Atoi(f[0])
+	return s
+}
+
+// mapAPIError translates the supplied error into a friendlyError if it was
+// caused by a well-known Cloud API error, and reflects it in the CloudAPIError
+// condition of the supplied Cluster.
+func mapAPIError(cr *v1alpha1.Cluster, err error) error {
+	if fe := friendly(err); fe != nil {
+		cr.Status.SetConditions(v1alpha1.CloudAPIError(fe.reason, fe.msg))
+		return fe
+	}
+	if cr.Status.GetCondition(v1alpha1.TypeCloudAPIError).Status == corev1.ConditionTrue {
+		cr.Status.SetConditions(v1alpha1.NoCloudAPIError())
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// cloudAPIError returns the error the SDK returns when the Cloud API responds
+// with the supplied status and gRPC status.
+func cloudAPIError(t *testing.T, status, code int, msg string) error {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"code":%d,"message":%q}`, code, msg)
+	}))
+	defer srv.Close()
+	cfg := cockroachdb.NewConfiguration("key")
+	cfg.ServerURL = srv.URL
+	_, _, err := cockroachdb.NewService(cockroachdb.NewClient(cfg)).GetCluster(context.Background(), testClusterID)
+	if err == nil {
+		t.Fatal("GetCluster(...): want error, got nil")
+	}
+	return err
+}
+
+func TestMapAPIError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		msg       string
+		condition xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.Cluster
+		err    error
+		want   want
+	}{
+		"NotCloudAPIError": {
+			reason: "Errors not returned by the Cloud API should be returned unchanged.",
+			cr:     cluster(),
+			err:    errBoom,
+			want:   want{msg: "boom"},
+		},
+		"UnknownCloudAPIError": {
+			reason: "Cloud API errors that are not well-known should be returned unchanged.",
+			cr:     cluster(),
+			err:    cloudAPIError(t, http.StatusBadRequest, codeInvalidArgument, "spend limit must be positive"),
+			want:   want{msg: "400 Bad Request"},
+		},
+		"QuotaExceeded": {
+			reason: "Exhausted resources should be reported as an exceeded quota.",
+			cr:     cluster(),
+			err:    errors.Wrap(cloudAPIError(t, http.StatusTooManyRequests, codeResourceExhausted, "too many clusters"), "cannot create cluster"),
+			want: want{
+				msg:       fmt.Sprintf(msgFmtQuotaExceeded, "too many clusters"),
+				condition: v1alpha1.CloudAPIError(v1alpha1.ReasonQuotaExceeded, fmt.Sprintf(msgFmtQuotaExceeded, "too many clusters")),
+			},
+		},
+		"RateLimited": {
+			reason: "Requests that are rate limited should not be reported as an exceeded quota.",
+			cr:     cluster(),
+			err:    cloudAPIError(t, http.StatusTooManyRequests, 0, "rate limit exceeded"),
+			want:   want{msg: "429 Too Many Requests"},
+		},
+		"InvalidRegion": {
+			reason: "Invalid arguments about regions should be reported as an invalid region.",
+			cr:     cluster(),
+			err:    cloudAPIError(t, http.StatusBadRequest, codeInvalidArgument, "unsupported region mars-1"),
+			want: want{
+				msg:       fmt.Sprintf(msgFmtInvalidRegion, "unsupported region mars-1"),
+				condition: v1alpha1.CloudAPIError(v1alpha1.ReasonInvalidRegion, fmt.Sprintf(msgFmtInvalidRegion, "unsupported region mars-1")),
+			},
+		},
+		"NameTaken": {
+			reason: "Conflicts should be reported as a taken name.",
+			cr:     cluster(),
+			err:    cloudAPIError(t, http.StatusConflict, codeAlreadyExists, "cluster cool already exists"),
+			want: want{
+				msg:       fmt.Sprintf(msgFmtNameTaken, "cluster cool already exists"),
+				condition: v1alpha1.CloudAPIError(v1alpha1.ReasonNameTaken, fmt.Sprintf(msgFmtNameTaken, "cluster cool already exists")),
+			},
+		},
+		"PermissionDenied": {
+			reason: "Forbidden requests should be reported as missing permissions.",
+			cr:     cluster(),
+			err:    cloudAPIError(t, http.StatusForbidden, codePermissionDenied, "forbidden"),
+			want: want{
+				msg:       fmt.Sprintf(msgFmtPermissionDenied, "forbidden"),
+				condition: v1alpha1.CloudAPIError(v1alpha1.ReasonPermissionDenied, fmt.Sprintf(msgFmtPermissionDenied, "forbidden")),
+			},
+		},
+		"Resolved": {
+			reason: "The CloudAPIError condition should be cleared once operations no longer fail with a well-known error.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Status.SetConditions(v1alpha1.CloudAPIError(v1alpha1.ReasonNameTaken, "taken"))
+			}),
+			want: want{condition: v1alpha1.NoCloudAPIError()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := mapAPIError(tc.cr, tc.err)
+			msg := ""
+			if err != nil {
+				msg = err.Error()
+			}
+			if diff := cmp.Diff(tc.want.msg, msg); diff != "" {
+				t.Errorf("\n%s\nmapAPIError(...): -want error message, +got error message:\n%s\n", tc.reason, diff)
+			}
+			got := tc.cr.Status.GetCondition(v1alpha1.TypeCloudAPIError)
+			if tc.want.condition.Type == "" {
+				tc.want.condition = xpv1.Condition{Type: v1alpha1.TypeCloudAPIError, Status: "Unknown"}
+			}
+			if diff := cmp.Diff(tc.want.condition, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nmapAPIError(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
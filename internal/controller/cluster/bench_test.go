@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/simulator"
+)
+
+// benchClusters is the size of the fleet driven by the benchmarks.
+const benchClusters = 1000
+
+// benchWorkers matches the default --max-reconcile-rate.
+const benchWorkers = 10
+
+// fleet returns benchClusters Clusters to reconcile against the simulator.
+func fleet() []*v1alpha1.Cluster {
+	crs := make([]*v1alpha1.Cluster, benchClusters)
+	for i := range crs {
+		crs[i] = simulatedCluster(fmt.Sprintf("cluster-%04d", i))
+	}
+	return crs
+}
+
+// drive calls fn for every supplied Cluster from benchWorkers goroutines,
+// and returns the latency of each call.
+func drive(b *testing.B, crs []*v1alpha1.Cluster, fn func(cr *v1alpha1.Cluster) error) []time.Duration {
+	b.Helper()
+	work := make(chan int)
+	latencies := make([]time.Duration, len(crs))
+	errs := make(chan error, len(crs))
+	wg := sync.WaitGroup{}
+	for w := 0; w < benchWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				start := time.Now()
+				if err := fn(crs[i]); err != nil {
+					errs <- err
+				}
+				latencies[i] = time.Since(start)
+			}
+		}()
+	}
+	for i := range crs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		b.Fatal(err)
+	}
+	return latencies
+}
+
+// report adds the latency percentiles and API calls per Cluster of a run to
+// the results of the supplied benchmark.
+func report(b *testing.B, latencies []time.Duration, sim *simulator.Server) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b.ReportMetric(float64(latencies[len(latencies)/2].Microseconds()), "p50-µs/cluster")
+	b.ReportMetric(float64(latencies[len(latencies)*99/100].Microseconds()), "p99-µs/cluster")
+	calls := 0
+	for _, n := range sim.Calls() {
+		calls += n
+	}
+	b.ReportMetric(float64(calls)/float64(len(latencies)), "calls/cluster")
+}
+
+// BenchmarkProvision measures provisioning a fleet of Clusters, from creation
+// until each is available.
+func BenchmarkProvision(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		sim := simulator.New(simulator.WithProvisioningPolls(2))
+		srv := httptest.NewServer(sim)
+		e := newSimulatedExternal(srv.URL)
+		crs := fleet()
+		b.StartTimer()
+
+		latencies := drive(b, crs, func(cr *v1alpha1.Cluster) error {
+			_, err := converge(context.Background(), e, cr, 20)
+			return err
+		})
+
+		b.StopTimer()
+		report(b, latencies, sim)
+		srv.Close()
+		b.StartTimer()
+	}
+}
+
+// BenchmarkObserve measures the periodic observe of a fleet of available
+// Clusters, which dominates the API calls of a steady fleet.
+func BenchmarkObserve(b *testing.B) {
+	sim := simulator.New()
+	srv := httptest.NewServer(sim)
+	defer srv.Close()
+	e := newSimulatedExternal(srv.URL)
+	crs := fleet()
+	drive(b, crs, func(cr *v1alpha1.Cluster) error {
+		_, err := converge(context.Background(), e, cr, 20)
+		return err
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		sim.ResetCalls()
+		b.StartTimer()
+
+		latencies := drive(b, crs, func(cr *v1alpha1.Cluster) error {
+			_, err := e.Observe(context.Background(), cr)
+			return err
+		})
+
+		b.StopTimer()
+		report(b, latencies, sim)
+		b.StartTimer()
+	}
+}
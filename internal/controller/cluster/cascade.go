@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+const (
+	errDeleteSQLUser = "cannot delete SQL user"
+)
+
+// deleteDependents deletes the SQL users and allowlist entries the provider
+// created for the supplied Cluster, in that order. Dependents that are
+// already gone are ignored, so that an interrupted deletion can be resumed.
+func (c *external) deleteDependents(ctx context.Context, cr *v1alpha1.Cluster, clusterID string) error {
+	p := cr.Spec.ForProvider
+
+	cr.SetConditions(v1alpha1.DeletingDependents(v1alpha1.ReasonDeletingSQLUsers))
+	users := make([]string, 0, len(p.SQLUsers)+1)
+	for _, u := range p.SQLUsers {
+		users = append(users, u.Name)
+	}
+	if p.Credentials != nil {
+		users = append(users, p.Credentials.Username)
+	}
+	if usernameChanged(cr) {
+		users = append(users, cr.Status.AtProvider.Username)
+	}
+	if prev := cr.Status.AtProvider.PreviousUsername; prev != "" {
+		users = append(users, prev)
+	}
+	for _, u := range users {
+		_, res, err := c.service.CRDBClient.DeleteSQLUser(ctx, clusterID, u)
+		if err != nil && !cloud.IsNotFound(res) {
+			return errors.Wrap(err, errDeleteSQLUser)
+		}
+	}
+
+	cr.SetConditions(v1alpha1.DeletingDependents(v1alpha1.ReasonDeletingAllowlist))
+	for _, e := range p.Allowlist {
+		ae, err := toAllowlistEntry(e)
+		if err != nil {
+			return err
+		}
+		_, res, err := c.service.CRDBClient.DeleteAllowlistEntry(ctx, clusterID, ae.CidrIp, ae.CidrMask)
+		if err != nil && !cloud.IsNotFound(res) {
+			return errors.Wrap(err, errDelAllowlistEntry)
+		}
+	}
+
+	cr.SetConditions(v1alpha1.DependentsDeleted())
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+func TestDeleteDependents(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	dependents := func(cr *v1alpha1.Cluster) {
+		cr.Spec.ForProvider.SQLUsers = []v1alpha1.ClusterSQLUser{{Name: "app"}, {Name: "reporting"}}
+		cr.Spec.ForProvider.Allowlist = []v1alpha1.AllowlistEntry{{CIDR: "10.0.0.0/8"}, {CIDR: "192.168.1.0/24"}}
+	}
+	renamed := func(cr *v1alpha1.Cluster) {
+		cr.Status.AtProvider.Username = "old"
+		cr.Status.AtProvider.PreviousUsername = "older"
+	}
+	deleteUser := func(deleted *[]string, code int, err error) func(context.Context, string, string) (*cockroachdb.SQLUser, *http.Response, error) {
+		return func(_ context.Context, _ string, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+			*deleted = append(*deleted, "user/"+name)
+			return nil, &http.Response{StatusCode: code}, err
+		}
+	}
+	deleteEntry := func(deleted *[]string, code int, err error) func(context.Context, string, string, int32) (*cockroachdb.AllowlistEntry, *http.Response, error) {
+		return func(_ context.Context, _ string, ip string, mask int32) (*cockroachdb.AllowlistEntry, *http.Response, error) {
+			*deleted = append(*deleted, fmt.Sprintf("allowlist/%s/%d", ip, mask))
+			return nil, &http.Response{StatusCode: code}, err
+		}
+	}
+
+	type want struct {
+		cr      *v1alpha1.Cluster
+		deleted []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service func(deleted *[]string) cockroachdb.Service
+		cr      *v1alpha1.Cluster
+		want    want
+	}{
+		"Order": {
+			reason: "SQL users, including those of a username change, should be deleted before allowlist entries.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteSQLUser:        deleteUser(deleted, http.StatusOK, nil),
+					MockDeleteAllowlistEntry: deleteEntry(deleted, http.StatusOK, nil),
+				}
+			},
+			cr: cluster(dependents, renamed),
+			want: want{
+				cr: cluster(dependents, renamed, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.DependentsDeleted())
+				}),
+				deleted: []string{"user/app", "user/reporting", "user/cool", "user/old", "user/older", "allowlist/10.0.0.0/8", "allowlist/192.168.1.0/24"},
+			},
+		},
+		"AlreadyDeleted": {
+			reason: "Dependents that are already gone should be ignored so that an interrupted deletion can be resumed.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteSQLUser:        deleteUser(deleted, http.StatusNotFound, errBoom),
+					MockDeleteAllowlistEntry: deleteEntry(deleted, http.StatusNotFound, errBoom),
+				}
+			},
+			cr: cluster(dependents),
+			want: want{
+				cr: cluster(dependents, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.DependentsDeleted())
+				}),
+				deleted: []string{"user/app", "user/reporting", "user/cool", "allowlist/10.0.0.0/8", "allowlist/192.168.1.0/24"},
+			},
+		},
+		"DeleteSQLUserError": {
+			reason: "Allowlist entries should not be deleted while a SQL user cannot be deleted.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteSQLUser: deleteUser(deleted, http.StatusInternalServerError, errBoom),
+				}
+			},
+			cr: cluster(dependents),
+			want: want{
+				cr: cluster(dependents, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.DeletingDependents(v1alpha1.ReasonDeletingSQLUsers))
+				}),
+				deleted: []string{"user/app"},
+				err:     errors.Wrap(errBoom, errDeleteSQLUser),
+			},
+		},
+		"DeleteAllowlistEntryError": {
+			reason: "Errors deleting allowlist entries should be returned once the SQL users are deleted.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteSQLUser:        deleteUser(deleted, http.StatusOK, nil),
+					MockDeleteAllowlistEntry: deleteEntry(deleted, http.StatusInternalServerError, errBoom),
+				}
+			},
+			cr: cluster(dependents),
+			want: want{
+				cr: cluster(dependents, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.DeletingDependents(v1alpha1.ReasonDeletingAllowlist))
+				}),
+				deleted: []string{"user/app", "user/reporting", "user/cool", "allowlist/10.0.0.0/8"},
+				err:     errors.Wrap(errBoom, errDelAllowlistEntry),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := []string{}
+			e := external{service: &cloud.Service{CRDBClient: tc.service(&deleted)}}
+			err := e.deleteDependents(context.Background(), tc.cr, testClusterID)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.deleteDependents(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\ne.deleteDependents(...): -want deleted, +got deleted:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.deleteDependents(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/simulator"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+// newSimulatedExternal returns an external client of the simulated Cloud API
+// served at the supplied URL. Requests are retried like in production, only
+// faster.
+func newSimulatedExternal(url string) *external {
+	policy := cockroachcloud.DefaultRetryPolicy()
+	policy.Backoff = time.Millisecond
+	cfg := cockroachdb.NewConfiguration("key")
+	cfg.ServerURL = url
+	cfg.HTTPClient = &http.Client{Transport: cockroachcloud.NewRetryTransport(http.DefaultTransport, policy)}
+	return &external{
+		service: &cloud.Service{CRDBClient: cockroachdb.NewService(cockroachdb.NewClient(cfg))},
+		kube: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				if s, ok := o.(*corev1.Secret); ok {
+					s.Data = map[string][]byte{"ca.crt": []byte("cert")}
+				}
+				return nil
+			}),
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		kind: v1alpha1.ClusterGroupVersionKind,
+	}
+}
+
+// simulatedCluster returns a Cluster that reads its CA certificate from a
+// Secret, so that it can be reconciled against the simulated Cloud API.
+func simulatedCluster(name string) *v1alpha1.Cluster {
+	return cluster(func(cr *v1alpha1.Cluster) {
+		cr.SetName(name)
+		cr.Spec.ForProvider.Connection = &v1alpha1.ClusterConnection{CASecretRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "ca", Namespace: "default"},
+			Key:             "ca.crt",
+		}}
+	})
+}
+
+// converge drives the supplied external client the way the managed
+// reconciler does, until the supplied Cluster is available and up to date.
+// It returns the number of reconciles it took.
+func converge(ctx context.Context, e managed.ExternalClient, cr *v1alpha1.Cluster, max int) (int, error) {
+	for i := 1; i <= max; i++ {
+		o, err := e.Observe(ctx, cr)
+		switch {
+		case err != nil:
+			continue
+		case !o.ResourceExists:
+			_, _ = e.Create(ctx, cr)
+		case !o.ResourceUpToDate:
+			_, _ = e.Update(ctx, cr)
+		case cr.GetCondition(xpv1.TypeReady).Reason == xpv1.ReasonAvailable:
+			return i, nil
+		}
+	}
+	return max, fmt.Errorf("cluster %q did not converge within %d reconciles", cr.GetName(), max)
+}
+
+func TestConvergeUnderFaults(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		faults simulator.Faults
+	}{
+		"NoFaults": {
+			reason: "Clusters should converge against a reliable API.",
+		},
+		"Errors": {
+			reason: "Clusters should converge despite failing requests.",
+			faults: simulator.Faults{ErrorRate: 0.2},
+		},
+		"Throttling": {
+			reason: "Clusters should converge despite bursts of throttled requests.",
+			faults: simulator.Faults{ThrottleEvery: 7, ThrottleBurst: 3},
+		},
+		"SlowResponses": {
+			reason: "Clusters should converge despite slow responses.",
+			faults: simulator.Faults{Latency: 2 * time.Millisecond},
+		},
+		"Everything": {
+			reason: "Clusters should converge despite all faults at once.",
+			faults: simulator.Faults{ErrorRate: 0.1, ThrottleEvery: 11, ThrottleBurst: 2, Latency: time.Millisecond},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sim := simulator.New(simulator.WithFaults(tc.faults), simulator.WithProvisioningPolls(2))
+			srv := httptest.NewServer(sim)
+			defer srv.Close()
+			e := newSimulatedExternal(srv.URL)
+
+			const clusters = 10
+			for i := 0; i < clusters; i++ {
+				cr := simulatedCluster(fmt.Sprintf("cluster-%02d", i))
+				if _, err := converge(context.Background(), e, cr, 100); err != nil {
+					t.Fatalf("\n%s\nconverge(...): %v", tc.reason, err)
+				}
+				if got := sim.SQLUsers(cr.Status.AtProvider.ID); len(got) != 1 || got[0] != "cool" {
+					t.Errorf("\n%s\nconverge(...): want SQL user %q of cluster %q, got %v", tc.reason, "cool", cr.GetName(), got)
+				}
+			}
+
+			if got := len(sim.Clusters()); got != clusters {
+				t.Errorf("\n%s\nsim.Clusters(): want %d clusters, got %d", tc.reason, clusters, got)
+			}
+		})
+	}
+}
+
+func TestCreationFailure(t *testing.T) {
+	sim := simulator.New(simulator.WithFaults(simulator.Faults{CreationFailureRate: 1}), simulator.WithProvisioningPolls(2))
+	srv := httptest.NewServer(sim)
+	defer srv.Close()
+	e := newSimulatedExternal(srv.URL)
+
+	cr := simulatedCluster("cluster")
+	if _, err := converge(context.Background(), e, cr, 20); err == nil {
+		t.Fatalf("converge(...): want a cluster that failed to be created not to converge")
+	}
+
+	want := xpv1.Unavailable().WithMessage(msgCreationFailed)
+	if got := cr.GetCondition(xpv1.TypeReady); !got.Equal(want) {
+		t.Errorf("cr.GetCondition(...): want %v, got %v", want, got)
+	}
+
+	// A cluster that failed to be created must be neither deleted nor
+	// created again behind the user's back.
+	if got := len(sim.Clusters()); got != 1 {
+		t.Errorf("sim.Clusters(): want %d clusters, got %d", 1, got)
+	}
+}
@@ -18,26 +18,35 @@ package cluster
 
 import (
 	"context"
-	"fmt"
-	"net/http"
+	"strings"
+	"time"
 
 	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
-	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
 	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
-	"github.com/crossplane/provider-cockroachdb/pkg/cockroachca"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/usage"
+	"github.com/crossplane/provider-cockroachdb/internal/metrics"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
-	"github.com/sethvargo/go-password/password"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,67 +60,68 @@ const (
 
 	errNewClient = "cannot create new Service"
 
-	defaultCAURL = "https://cockroachlabs.cloud/"
-)
-
-type CockroachdbService struct {
-	crdbClient cockroachdb.Service
-	caClient   *cockroachca.CAClient
-}
+	errCreateObserveOnly   = "cannot create an observe-only Cluster"
+	errNoCredentials       = "spec.forProvider.credentials is required to create a Cluster"
+	errPersistExternalName = "cannot persist external name of created cluster"
 
-var (
-	newCockroachdbService = func(creds []byte) (*CockroachdbService, error) {
-		clientConfig := cockroachdb.NewConfiguration(string(creds))
-		cockroachclient := cockroachdb.NewClient(clientConfig)
-		service := cockroachdb.NewService(cockroachclient)
+	errPublishConnectionInfo = "cannot publish connection info ConfigMap"
+	errPublishDNSEndpoint    = "cannot publish DNSEndpoint"
+	errGetCASecret           = "cannot get cluster CA certificate from caSecretRef"
 
-		caClient, err := cockroachca.NewCAClient(
-			cockroachca.WithBaseURL(defaultCAURL),
-			cockroachca.WithHTTPClient(http.DefaultClient),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error creatint CA client: %v", err)
-		}
+	msgCreationFailed = "cluster failed to be created; delete the Cluster to create it again"
+)
 
-		return &CockroachdbService{
-			crdbClient: service,
-			caClient:   caClient,
-		}, nil
+// clusterCACert returns the CA certificate of the supplied cluster. A CA
+// certificate supplied through the Cluster's caSecretRef takes precedence and
+// is not fetched from CockroachDB Cloud at all.
+func (c *external) clusterCACert(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) ([]byte, error) {
+	if conn := cr.Spec.ForProvider.Connection; conn != nil && conn.CASecretRef != nil {
+		ca, err := cloud.GetSecretKey(ctx, c.kube, conn.CASecretRef)
+		return ca, errors.Wrap(err, errGetCASecret)
 	}
-)
+	return c.service.ClusterCACert(ctx, cluster)
+}
 
 // Setup adds a controller that reconciles Cluster managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
 	name := managed.ControllerName(v1alpha1.ClusterGroupKind)
 
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	cps := []managed.ConnectionPublisher{
+		managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()),
+		&replicaPublisher{kube: mgr.GetClient()},
+	}
 	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ClusterGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newCockroachdbService}),
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&connector{
+			Connector: &cloud.Connector{
+				Kube:         mgr.GetClient(),
+				Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+				APIInfo:      o.APIInfo,
+				NewServiceFn: o.NewService},
+			protector: usage.NewProtector(mgr.GetClient(), v1alpha1.SQLUserListGroupVersionKind, v1alpha1.CloudDatabaseListGroupVersionKind, v1alpha1.DatabaseListGroupVersionKind, v1alpha1.GrantListGroupVersionKind, v1alpha1.SchemaListGroupVersionKind, v1alpha1.BackupScheduleListGroupVersionKind, v1alpha1.BackupJobListGroupVersionKind, v1alpha1.RestoreSQLListGroupVersionKind, v1alpha1.DefaultPrivilegesListGroupVersionKind, v1alpha1.TableTTLPolicyListGroupVersionKind, v1alpha1.DatabaseRegionListGroupVersionKind, v1alpha1.SQLScriptListGroupVersionKind, v1alpha1.RoleDefaultSettingsListGroupVersionKind),
+			metrics:   metrics.NewClusterStateRecorder()}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha1.Cluster{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return &v1alpha1.Cluster{} }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (*CockroachdbService, error)
+	*cloud.Connector
+	protector *usage.Protector
+	metrics   *metrics.ClusterStateRecorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -121,44 +131,40 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.New(errNotCluster)
 	}
 
-	if err := c.usage.Track(ctx, mg); err != nil {
+	if err := c.Usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
-	}
-
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
-	}
-
-	svc, err := c.newServiceFn(data)
+	svc, err := c.Service(ctx, cr)
 	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
+		return nil, err
 	}
 
 	return &external{
-		service: svc,
-		kube:    c.kube,
+		service:   svc,
+		kube:      c.Kube,
+		protector: c.protector,
+		metrics:   c.metrics,
+		kind:      v1alpha1.ClusterGroupVersionKind,
 	}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	service *CockroachdbService
-	kube    client.Client
+	service   *cloud.Service
+	kube      client.Client
+	protector *usage.Protector
+	metrics   *metrics.ClusterStateRecorder
+	kind      schema.GroupVersionKind
 }
 
-func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (_ managed.ExternalObservation, err error) {
 	cr, ok := mg.(*v1alpha1.Cluster)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotCluster)
 	}
+	defer func() { err = mapAPIError(cr, err) }()
 	externalName := meta.GetExternalName(cr)
 
 	// 'Status' is not updated in the Create method, so at this point 'Status.AtProvider.ID' will be empty.
@@ -169,9 +175,10 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		}, nil
 	}
 
-	cluster, res, err := c.service.crdbClient.GetCluster(ctx, externalName)
+	cluster, res, err := c.service.CRDBClient.GetCluster(ctx, externalName)
 	if err != nil {
-		if res.StatusCode == http.StatusNotFound {
+		if cloud.IsNotFound(res) {
+			c.forgetState(cr)
 			return managed.ExternalObservation{
 				ResourceExists: false,
 			}, nil
@@ -180,13 +187,54 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	fillAtProvider(cr, cluster)
+	c.recordState(cr, cluster)
+	if cr.Status.GetCondition(v1alpha1.TypeQuotaExceeded).Status == corev1.ConditionTrue {
+		cr.Status.SetConditions(v1alpha1.WithinQuota())
+	}
 
+	var allowlist []cockroachdb.AllowlistEntry
 	switch cluster.State {
 	case cockroachdb.CLUSTERSTATETYPE_CREATED:
+		// A cluster created from a backup is not available until the backup
+		// is restored, and nothing else is changed while it is restored.
+		restore, err := c.observeRestore(ctx, cr, cluster.Id)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if restore != restoreNone {
+			cr.Status.SetConditions(restoreCondition(restore))
+			return managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  restore != restoreNotStarted,
+				ConnectionDetails: managed.ConnectionDetails{},
+			}, nil
+		}
 		cr.Status.SetConditions(xpv1.Available())
+		if allowlist, err = c.observeNetworking(ctx, cr, cluster); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if err := c.observeNodes(ctx, cr, cluster); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if err := c.publishConnectionInfo(ctx, cr, cluster); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errPublishConnectionInfo)
+		}
+		if err := c.publishDNSEndpoint(ctx, cr, cluster); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errPublishDNSEndpoint)
+		}
 	case cockroachdb.CLUSTERSTATETYPE_CREATING:
 		cr.Status.SetConditions(xpv1.Creating())
+	case cockroachdb.CLUSTERSTATETYPE_CREATION_FAILED:
+		// A cluster that failed to be created never becomes available.
+		// Deleting it is left to users, who may want to inspect it first.
+		cr.Status.SetConditions(xpv1.Unavailable().WithMessage(msgCreationFailed))
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: managed.ConnectionDetails{},
+		}, nil
 	case cockroachdb.CLUSTERSTATETYPE_DELETED:
+		c.forgetState(cr)
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
@@ -194,73 +242,279 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.Status.SetConditions(xpv1.Unavailable())
 	}
 
+	if cr.ObserveOnly() {
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: managed.ConnectionDetails{},
+		}, nil
+	}
+
+	// The Cloud API cannot migrate clusters between plans, and the plan of a
+	// Cluster is immutable. A Cluster may still request another plan than its
+	// cluster runs, e.g. after importing an existing cluster. The plan is then
+	// left alone, and everything else is still kept in sync.
+	diff := diffSpec(cr, cluster)
+	if diff == specPlanChange {
+		cr.Status.SetConditions(v1alpha1.PlanMigrationUnsupported(cluster.Plan, cr.Plan()))
+	}
+	if diff != specPlanChange && cr.Status.GetCondition(v1alpha1.TypePlanMigration).Status == corev1.ConditionTrue {
+		cr.Status.SetConditions(v1alpha1.PlanMigrationNotRequired())
+	}
+
+	var users, missing, unpublished []v1alpha1.ClusterSQLUser
+	var d allowlistDiff
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED {
+		if err := c.observeCredentialsUser(ctx, cr, cluster.Id); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if users, err = c.sqlUsers(ctx, cr, cluster.Id); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if missing, err = c.missingSQLUsers(ctx, users, cluster.Id); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if unpublished, err = c.unpublishedSQLUsers(ctx, cr, users, missing); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if d, err = diffAllowlist(cr.Spec.ForProvider.Allowlist, allowlist, cr.Spec.ForProvider.AllowlistPolicy); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+	var roles []string
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED && managesRoles(cr) {
+		var err error
+		if roles, err = c.observeRoles(ctx, cr, cluster); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if len(roles) == 0 {
+			cr.Status.AtProvider.RolesHash = rolesHash(cr.Spec.ForProvider.Credentials)
+		}
+	}
+	rolesChanged := len(roles) > 0
+	defaultsChanged := cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED && sqlUserDefaultsHash(users) != cr.Status.AtProvider.SQLUserDefaultsHash
+	cr.Status.AtProvider.PlannedChanges = plannedChanges(cr, cluster, diff, missing, unpublished, d, rolesChanged, defaultsChanged)
+
+	upToDate := diff != specInPlan && len(missing) == 0 && len(unpublished) == 0 && d.empty() && !rolesChanged && !defaultsChanged &&
+		!(credentialsUserMissing(cr) && cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED) && !usernameChanged(cr) && !previousUserExpired(cr, time.Now())
+
 	return managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  isUpToDate(cr, cluster),
+		ResourceUpToDate:  upToDate,
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
-func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+func (c *external) Create(ctx context.Context, mg resource.Managed) (_ managed.ExternalCreation, err error) {
 	cr, ok := mg.(*v1alpha1.Cluster)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotCluster)
 	}
+	defer func() { err = mapAPIError(cr, err) }()
+	if cr.ObserveOnly() {
+		return managed.ExternalCreation{}, errors.New(errCreateObserveOnly)
+	}
+	if cr.Spec.ForProvider.Credentials == nil {
+		return managed.ExternalCreation{}, errors.New(errNoCredentials)
+	}
 
-	cluster, _, err := c.service.crdbClient.CreateCluster(ctx, cr.CreateClusterRequest())
+	msg, err := c.quotaExceeded(ctx, cr)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
+	if msg != "" {
+		cr.Status.SetConditions(v1alpha1.QuotaExceeded(msg))
+		return managed.ExternalCreation{}, errors.New(msg)
+	}
+
+	// Names derived from metadata.name, e.g. by generateName or a
+	// Composition, may not meet the constraints of CockroachDB Cloud or be
+	// taken already. Explicit names are used as is.
+	req := cr.CreateClusterRequest()
+	if cr.Spec.ForProvider.SourceBackupID != "" {
+		v, err := c.sourceVersion(ctx, cr)
+		if err != nil {
+			return managed.ExternalCreation{}, err
+		}
+		req.Spec.Dedicated.CockroachVersion = &v
+	}
+	generated := cr.Spec.ForProvider.Name == ""
+	if generated {
+		req.Name = cloudClusterName(req.Name, false)
+	}
+	cluster, res, err := c.service.CRDBClient.CreateCluster(ctx, req)
+	for i := 1; err != nil && generated && isNameCollision(res) && i < maxCreateAttempts; i++ {
+		req.Name = cloudClusterName(cr.GetName(), true)
+		cluster, res, err = c.service.CRDBClient.CreateCluster(ctx, req)
+	}
+	if err != nil {
+		if msg := quotaRejection(err); msg != "" {
+			cr.Status.SetConditions(v1alpha1.QuotaExceeded(msg))
+			return managed.ExternalCreation{}, errors.New(msg)
+		}
+		return managed.ExternalCreation{}, err
+	}
 	meta.SetExternalName(cr, cluster.Id)
 
-	pwd, err := getPassword(ctx, c.kube, cr.Spec.ForProvider.Credentials.PasswordSecretRef)
+	// Persist the external name right away, so that the cluster is not
+	// orphaned if the provider stops before the rest of Create completes.
+	if err := managed.NewRetryingCriticalAnnotationUpdater(c.kube).UpdateCriticalAnnotations(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errPersistExternalName)
+	}
+
+	pwd, err := cloud.GetPassword(ctx, c.kube, cr.Spec.ForProvider.Credentials.PasswordSecretRef)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
-	_, _, err = c.service.crdbClient.CreateSQLUser(ctx, cluster.Id, cr.CreateSQLUserRequest(string(pwd)))
+	_, _, err = c.service.CRDBClient.CreateSQLUser(ctx, cluster.Id, cr.CreateSQLUserRequest(string(pwd)))
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
-	ca, err := c.service.caClient.ClusterCACert(ctx, cluster)
+	ca, err := c.clusterCACert(ctx, cr, cluster)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
 	return managed.ExternalCreation{
-		ConnectionDetails: getConnectionDetails(cr, cluster, ca, pwd),
+		ConnectionDetails: c.service.ConnectionDetails(ctx, cr.Spec.ForProvider.Credentials.Username, cluster, ca, pwd),
 	}, nil
 }
 
-func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+func (c *external) Update(ctx context.Context, mg resource.Managed) (_ managed.ExternalUpdate, err error) {
 	cr, ok := mg.(*v1alpha1.Cluster)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotCluster)
 	}
+	defer func() { err = mapAPIError(cr, err) }()
 	externalName := meta.GetExternalName(cr)
 
-	_, _, err := c.service.crdbClient.UpdateCluster(ctx, externalName, cr.UpdateClusterSpec(), &cockroachdb.UpdateClusterOptions{})
+	cluster, _, err := c.service.CRDBClient.GetCluster(ctx, externalName)
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
 
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED && cr.Spec.ForProvider.SourceBackupID != "" && cr.Status.AtProvider.SourceRestore == nil {
+		return managed.ExternalUpdate{}, c.startRestore(ctx, cr, cluster)
+	}
+
+	switch diffSpec(cr, cluster) {
+	case specPlanChange:
+		cr.Status.SetConditions(v1alpha1.PlanMigrationUnsupported(cluster.Plan, cr.Plan()))
+	case specInPlan:
+		spec := cr.UpdateClusterSpec(cluster)
+		spec.Dedicated = nextDedicatedUpdate(cr.Spec.ForProvider.UpdateStrategy, spec.Dedicated, cluster.Regions)
+		cluster, _, err = c.service.CRDBClient.UpdateCluster(ctx, externalName, spec, &cockroachdb.UpdateClusterOptions{})
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		cr.Status.AtProvider.PendingOperations = pendingOperations(cr.Status.AtProvider.PendingOperations, cluster)
+		if spec.Dedicated != nil && spec.Dedicated.RegionNodes != nil {
+			cr.Status.AtProvider.PendingOperations = startOperation(cr.Status.AtProvider.PendingOperations, v1alpha1.OperationScale)
+		}
+	}
+
+	var users []v1alpha1.ClusterSQLUser
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED {
+		if users, err = c.sqlUsers(ctx, cr, externalName); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		missing, err := c.missingSQLUsers(ctx, users, externalName)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if err := c.createSQLUsers(ctx, cr, cluster, missing); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		unpublished, err := c.unpublishedSQLUsers(ctx, cr, users, missing)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if err := c.republishSQLUsers(ctx, cr, cluster, unpublished); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+
+		allowlist, err := c.observeAllowlist(ctx, cr, externalName)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if err := c.syncAllowlist(ctx, externalName, allowlist); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	cd := managed.ConnectionDetails{}
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED {
+		if cd, err = c.changeUsername(ctx, cr, cluster, time.Now()); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED && managesRoles(cr) {
+		roles, err := c.observeRoles(ctx, cr, cluster)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if len(roles) > 0 {
+			if err := c.applyRoles(ctx, cr, cluster, roles); err != nil {
+				return managed.ExternalUpdate{}, err
+			}
+		}
+		cr.Status.AtProvider.RolesHash = rolesHash(cr.Spec.ForProvider.Credentials)
+	}
+
+	if h := sqlUserDefaultsHash(users); h != cr.Status.AtProvider.SQLUserDefaultsHash && cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED {
+		if err := c.applySQLUserDefaults(ctx, cr, cluster, users); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		cr.Status.AtProvider.SQLUserDefaultsHash = h
+	}
+
 	return managed.ExternalUpdate{
-		ConnectionDetails: managed.ConnectionDetails{},
+		ConnectionDetails: cd,
 	}, nil
 }
 
-func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (err error) {
 	cr, ok := mg.(*v1alpha1.Cluster)
 	if !ok {
 		return errors.New(errNotCluster)
 	}
+	defer func() { err = mapAPIError(cr, err) }()
 	externalName := meta.GetExternalName(cr)
 
-	_, _, err := c.service.crdbClient.DeleteCluster(ctx, externalName)
+	if c.protector != nil {
+		if err := c.protector.Protect(ctx, cr); err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.ForProvider.CascadeDeletion {
+		if err := c.deleteDependents(ctx, cr, externalName); err != nil {
+			return err
+		}
+	}
+
+	_, _, err = c.service.CRDBClient.DeleteCluster(ctx, externalName)
 	return err
 }
 
+func (c *external) recordState(cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) {
+	if c.metrics == nil {
+		return
+	}
+	nn := types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}
+	c.metrics.Record(nn, string(cluster.State), string(cluster.Plan), string(cluster.CloudProvider))
+}
+
+func (c *external) forgetState(cr *v1alpha1.Cluster) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.Forget(types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()})
+}
+
 func isValidUUID(u string) bool {
 	_, err := uuid.Parse(u)
 	return err == nil
@@ -269,54 +523,136 @@ func isValidUUID(u string) bool {
 func fillAtProvider(cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) {
 	cr.Status.AtProvider.ID = cluster.Id
 	cr.Status.AtProvider.State = string(cluster.State)
+	if len(cluster.Regions) > 0 {
+		cr.Status.AtProvider.SQLDNS = cluster.Regions[0].SqlDns
+	}
+	cr.Status.AtProvider.PendingOperations = pendingOperations(cr.Status.AtProvider.PendingOperations, cluster)
 }
 
-func isUpToDate(cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) bool {
-	return *cr.Spec.ForProvider.Serverless.SpendLimit == cluster.Config.Serverless.SpendLimit
-}
+// A specDiff classifies how the spec of a Cluster differs from its cluster.
+type specDiff int
 
-func getPassword(ctx context.Context, kube client.Client, secretKeySelector *xpv1.SecretKeySelector) ([]byte, error) {
-	if secretKeySelector == nil {
-		password, err := password.Generate(16, 4, 0, false, false)
-		if err != nil {
-			return nil, fmt.Errorf("error generating random password: %v", err)
+const (
+	// specUpToDate means the cluster matches the spec.
+	specUpToDate specDiff = iota
+	// specInPlan means the cluster can be updated within its current plan.
+	specInPlan
+	// specPlanChange means the spec requests a different plan. The Cloud API
+	// cannot migrate clusters between plans.
+	specPlanChange
+)
+
+func diffSpec(cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) specDiff {
+	if cluster.Plan != cr.Plan() {
+		return specPlanChange
+	}
+	if cluster.Plan == cockroachdb.PLAN_SERVERLESS {
+		s := cluster.Config.Serverless
+		if s == nil || (cr.InitSpendLimit() == nil && *cr.Spec.ForProvider.Serverless.SpendLimit != s.SpendLimit) {
+			return specInPlan
+		}
+	}
+	if cluster.Plan == cockroachdb.PLAN_DEDICATED {
+		// The nodes and hardware of a dedicated cluster are only reported
+		// as they settle, so they are not compared while an operation is
+		// in flight. Updating them would be rejected anyway.
+		if _, running := runningOperations[cluster.OperationStatus]; running {
+			return specUpToDate
+		}
+		if cr.HardwareUpdate(cluster.Config.Dedicated) != nil || cr.RegionNodesUpdate(cluster.Regions) != nil {
+			return specInPlan
 		}
-		return []byte(password), nil
 	}
+	return specUpToDate
+}
 
-	nn := types.NamespacedName{
-		Name:      secretKeySelector.Name,
-		Namespace: secretKeySelector.Namespace,
+// publishConnectionInfo writes the non-sensitive connection information of
+// the supplied cluster to the ConfigMap referenced by the managed resource.
+func (c *external) publishConnectionInfo(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) error {
+	ref := cr.Spec.WriteConnectionInfoToConfigMapRef
+	if ref == nil || len(cluster.Regions) == 0 {
+		return nil
 	}
 
-	var secret corev1.Secret
-	if err := kube.Get(ctx, nn, &secret); err != nil {
-		return nil, err
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ref.Name,
+			Namespace:       ref.Namespace,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, c.kind))},
+		},
+		Data: getConnectionInfo(cluster),
 	}
+	err := resource.NewAPIPatchingApplicator(c.kube).Apply(ctx, cm,
+		resource.MustBeControllableBy(cr.GetUID()),
+		resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
+			return !cmp.Equal(current.(*corev1.ConfigMap).Data, desired.(*corev1.ConfigMap).Data)
+		}),
+	)
+	if resource.IsNotAllowed(err) {
+		return nil
+	}
+	return err
+}
 
-	val, ok := secret.Data[secretKeySelector.Key]
-	if !ok {
-		return nil, fmt.Errorf("secret key \"%s\" not found", secretKeySelector.Key)
+// dnsEndpointGroupVersionKind is the kind of the external-dns DNSEndpoint.
+var dnsEndpointGroupVersionKind = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// publishDNSEndpoint creates or updates an external-dns DNSEndpoint with a
+// CNAME record that points to the SQL endpoint of the supplied cluster.
+func (c *external) publishDNSEndpoint(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) error {
+	spec := cr.Spec.DNSEndpoint
+	if spec == nil || len(cluster.Regions) == 0 {
+		return nil
 	}
 
-	return val, nil
-}
+	ep := map[string]interface{}{
+		"dnsName":    spec.Hostname,
+		"recordType": "CNAME",
+		"targets":    []interface{}{cluster.Regions[0].SqlDns},
+	}
+	if spec.RecordTTL != nil {
+		ep["recordTTL"] = *spec.RecordTTL
+	}
 
-func getConnectionDetails(cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, ca, password []byte) managed.ConnectionDetails {
-	// TODO: Adapt this when supporting dedicated clusters, as they can run in multiple regions
-	host := cluster.Regions[0].SqlDns
-	user := cr.Spec.ForProvider.Credentials.Username
-	dsn := fmt.Sprintf(
-		"postgresql://%s:%s@%s:26257/defaultdb?sslmode=verify-full&options=--cluster%s%s",
-		user,
-		password,
-		host,
-		"%3D",
-		cluster.Name,
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(dnsEndpointGroupVersionKind)
+	u.SetName(spec.Name)
+	if u.GetName() == "" {
+		u.SetName(cr.GetName())
+	}
+	u.SetNamespace(spec.Namespace)
+	u.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, c.kind))})
+	u.Object["spec"] = map[string]interface{}{"endpoints": []interface{}{ep}}
+
+	err := resource.NewAPIPatchingApplicator(c.kube).Apply(ctx, u,
+		resource.MustBeControllableBy(cr.GetUID()),
+		resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
+			return !cmp.Equal(current.(*unstructured.Unstructured).Object["spec"], desired.(*unstructured.Unstructured).Object["spec"])
+		}),
 	)
+	if resource.IsNotAllowed(err) {
+		return nil
+	}
+	return err
+}
 
-	return managed.ConnectionDetails{
-		"ca.crt": ca,
-		"dsn":    []byte(dsn),
+func getConnectionInfo(cluster *cockroachdb.Cluster) map[string]string {
+	regions := make([]string, len(cluster.Regions))
+	for i, r := range cluster.Regions {
+		regions[i] = r.Name
+	}
+
+	info := map[string]string{
+		"host":     cluster.Regions[0].SqlDns,
+		"port":     cloud.DefaultSQLPort,
+		"database": cloud.DefaultDatabase,
+		"regions":  strings.Join(regions, ","),
+		"version":  cluster.CockroachVersion,
+	}
+	if len(cluster.Regions) > 1 {
+		for _, r := range cluster.Regions {
+			info["host."+r.Name] = r.SqlDns
+		}
 	}
+	return info
 }
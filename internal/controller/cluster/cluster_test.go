@@ -18,13 +18,34 @@ package cluster
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -35,9 +56,149 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+type mockService struct {
+	cockroachdb.Service
+
+	MockGetCluster           func(ctx context.Context, clusterId string) (*cockroachdb.Cluster, *http.Response, error)
+	MockListAllowlistEntries func(ctx context.Context, clusterId string, options *cockroachdb.ListAllowlistEntriesOptions) (*cockroachdb.ListAllowlistEntriesResponse, *http.Response, error)
+	MockListAvailableRegions func(ctx context.Context, options *cockroachdb.ListAvailableRegionsOptions) (*cockroachdb.ListAvailableRegionsResponse, *http.Response, error)
+	MockListClusters         func(ctx context.Context, options *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error)
+	MockCreateCluster        func(ctx context.Context, createClusterRequest *cockroachdb.CreateClusterRequest) (*cockroachdb.Cluster, *http.Response, error)
+	MockCreateSQLUser        func(ctx context.Context, clusterId string, createSQLUserRequest *cockroachdb.CreateSQLUserRequest) (*cockroachdb.SQLUser, *http.Response, error)
+	MockDeleteCluster        func(ctx context.Context, clusterId string) (*cockroachdb.Cluster, *http.Response, error)
+	MockDeleteSQLUser        func(ctx context.Context, clusterId string, name string) (*cockroachdb.SQLUser, *http.Response, error)
+	MockDeleteAllowlistEntry func(ctx context.Context, clusterId string, cidrIp string, cidrMask int32) (*cockroachdb.AllowlistEntry, *http.Response, error)
+	MockListSQLUsers         func(ctx context.Context, clusterId string, options *cockroachdb.ListSQLUsersOptions) (*cockroachdb.ListSQLUsersResponse, *http.Response, error)
+	MockEnableCMEK           func(ctx context.Context, clusterId string, cMEKClusterSpecification *cockroachdb.CMEKClusterSpecification) (*cockroachdb.CMEKClusterInfo, *http.Response, error)
+	MockGetCMEKClusterInfo   func(ctx context.Context, clusterId string) (*cockroachdb.CMEKClusterInfo, *http.Response, error)
+
+	MockUpdateSQLUserPassword func(ctx context.Context, clusterId string, name string, updateSQLUserPasswordRequest *cockroachdb.UpdateSQLUserPasswordRequest) (*cockroachdb.SQLUser, *http.Response, error)
+}
+
+func (m *mockService) GetCluster(ctx context.Context, clusterId string) (*cockroachdb.Cluster, *http.Response, error) {
+	return m.MockGetCluster(ctx, clusterId)
+}
+
+func (m *mockService) ListAllowlistEntries(ctx context.Context, clusterId string, options *cockroachdb.ListAllowlistEntriesOptions) (*cockroachdb.ListAllowlistEntriesResponse, *http.Response, error) {
+	return m.MockListAllowlistEntries(ctx, clusterId, options)
+}
+
+func (m *mockService) ListAvailableRegions(ctx context.Context, options *cockroachdb.ListAvailableRegionsOptions) (*cockroachdb.ListAvailableRegionsResponse, *http.Response, error) {
+	return m.MockListAvailableRegions(ctx, options)
+}
+
+func (m *mockService) ListClusters(ctx context.Context, options *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error) {
+	return m.MockListClusters(ctx, options)
+}
+
+func (m *mockService) CreateCluster(ctx context.Context, createClusterRequest *cockroachdb.CreateClusterRequest) (*cockroachdb.Cluster, *http.Response, error) {
+	return m.MockCreateCluster(ctx, createClusterRequest)
+}
+
+func (m *mockService) CreateSQLUser(ctx context.Context, clusterId string, createSQLUserRequest *cockroachdb.CreateSQLUserRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+	return m.MockCreateSQLUser(ctx, clusterId, createSQLUserRequest)
+}
+
+func (m *mockService) DeleteCluster(ctx context.Context, clusterId string) (*cockroachdb.Cluster, *http.Response, error) {
+	return m.MockDeleteCluster(ctx, clusterId)
+}
+
+func (m *mockService) DeleteSQLUser(ctx context.Context, clusterId string, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+	return m.MockDeleteSQLUser(ctx, clusterId, name)
+}
+
+func (m *mockService) DeleteAllowlistEntry(ctx context.Context, clusterId string, cidrIp string, cidrMask int32) (*cockroachdb.AllowlistEntry, *http.Response, error) {
+	return m.MockDeleteAllowlistEntry(ctx, clusterId, cidrIp, cidrMask)
+}
+
+func (m *mockService) ListSQLUsers(ctx context.Context, clusterId string, options *cockroachdb.ListSQLUsersOptions) (*cockroachdb.ListSQLUsersResponse, *http.Response, error) {
+	return m.MockListSQLUsers(ctx, clusterId, options)
+}
+
+func (m *mockService) EnableCMEK(ctx context.Context, clusterId string, cMEKClusterSpecification *cockroachdb.CMEKClusterSpecification) (*cockroachdb.CMEKClusterInfo, *http.Response, error) {
+	return m.MockEnableCMEK(ctx, clusterId, cMEKClusterSpecification)
+}
+
+func (m *mockService) GetCMEKClusterInfo(ctx context.Context, clusterId string) (*cockroachdb.CMEKClusterInfo, *http.Response, error) {
+	return m.MockGetCMEKClusterInfo(ctx, clusterId)
+}
+
+func (m *mockService) UpdateSQLUserPassword(ctx context.Context, clusterId string, name string, updateSQLUserPasswordRequest *cockroachdb.UpdateSQLUserPasswordRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+	return m.MockUpdateSQLUserPassword(ctx, clusterId, name, updateSQLUserPasswordRequest)
+}
+
+type clusterModifier func(*v1alpha1.Cluster)
+
+func withExternalName(n string) clusterModifier {
+	return func(cr *v1alpha1.Cluster) { meta.SetExternalName(cr, n) }
+}
+
+func cluster(m ...clusterModifier) *v1alpha1.Cluster {
+	spendLimit := int32(0)
+	cr := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec: v1alpha1.ClusterSpec{
+			ForProvider: v1alpha1.ClusterParameters{
+				Provider:    cockroachdb.APICLOUDPROVIDER_AWS,
+				Serverless:  &v1alpha1.ServerlessCluster{Regions: []string{"eu-west-1"}, SpendLimit: &spendLimit},
+				Credentials: &v1alpha1.Credentials{Username: "cool"},
+			},
+		},
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
 func TestObserve(t *testing.T) {
+	errNoResponse := errors.New("connection refused")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"nodes":[{"name":"node-1","region_name":"us-east-1","status":"LIVE"}]}`)
+	}))
+	defer srv.Close()
+	cloudClient, _ := cockroachcloud.NewClient("key", cockroachcloud.WithBaseURL(srv.URL))
+
+	created := &cockroachdb.Cluster{
+		Id:               testClusterID,
+		Plan:             cockroachdb.PLAN_SERVERLESS,
+		State:            cockroachdb.CLUSTERSTATETYPE_CREATED,
+		CockroachVersion: "v22.1.0",
+		Regions: []cockroachdb.Region{
+			{Name: "eu-west-1", SqlDns: "cool-eu.aws-eu-west-1.cockroachlabs.cloud"},
+			{Name: "us-east-1", SqlDns: "cool-us.aws-us-east-1.cockroachlabs.cloud"},
+		},
+		Config: cockroachdb.ClusterConfig{Serverless: &cockroachdb.ServerlessClusterConfig{}},
+	}
+	createdService := &cloud.Service{CRDBClient: &mockService{
+		MockGetCluster: func(_ context.Context, _ string) (*cockroachdb.Cluster, *http.Response, error) {
+			return created, &http.Response{StatusCode: http.StatusOK}, nil
+		},
+		MockListAllowlistEntries: func(_ context.Context, _ string, _ *cockroachdb.ListAllowlistEntriesOptions) (*cockroachdb.ListAllowlistEntriesResponse, *http.Response, error) {
+			return &cockroachdb.ListAllowlistEntriesResponse{}, &http.Response{StatusCode: http.StatusOK}, nil
+		},
+	}, CloudClient: cloudClient}
+	publisher := func(published *[]client.Object) client.Client {
+		return &test.MockClient{
+			MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+				*published = append(*published, obj)
+				return nil
+			},
+		}
+	}
+	owner := []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cluster(), v1alpha1.ClusterGroupVersionKind))}
+	synced := func(cr *v1alpha1.Cluster) {
+		meta.SetExternalName(cr, testClusterID)
+		cr.Spec.ForProvider.Serverless.Regions = []string{"eu-west-1", "us-east-1"}
+		cr.Status.AtProvider.Username = "cool"
+	}
+
 	type fields struct {
-		service *CockroachdbService
+		service *cloud.Service
+		kube    func(published *[]client.Object) client.Client
 	}
 
 	type args struct {
@@ -46,8 +207,9 @@ func TestObserve(t *testing.T) {
 	}
 
 	type want struct {
-		o   managed.ExternalObservation
-		err error
+		o         managed.ExternalObservation
+		published []client.Object
+		err       error
 	}
 
 	cases := map[string]struct {
@@ -56,12 +218,160 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NoExternalName": {
+			reason: "A Cluster without a valid external name should not exist.",
+			args: args{
+				mg: cluster(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A Cluster that is not found should not exist.",
+			fields: fields{
+				service: &cloud.Service{CRDBClient: &mockService{
+					MockGetCluster: func(_ context.Context, _ string) (*cockroachdb.Cluster, *http.Response, error) {
+						return nil, &http.Response{StatusCode: http.StatusNotFound}, cockroachdb.Error{}
+					},
+				}},
+			},
+			args: args{
+				mg: cluster(withExternalName(testClusterID)),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NoResponse": {
+			reason: "An error without an HTTP response should be returned rather than inspected.",
+			fields: fields{
+				service: &cloud.Service{CRDBClient: &mockService{
+					MockGetCluster: func(_ context.Context, _ string) (*cockroachdb.Cluster, *http.Response, error) {
+						return nil, nil, errNoResponse
+					},
+				}},
+			},
+			args: args{
+				mg: cluster(withExternalName(testClusterID)),
+			},
+			want: want{
+				err: errNoResponse,
+			},
+		},
+		"PlanMigrationUnsupported": {
+			reason: "A Cluster running a different plan should not be updated forever for it, while the rest of it is still observed.",
+			fields: fields{
+				service: &cloud.Service{CRDBClient: &mockService{
+					MockGetCluster: func(_ context.Context, _ string) (*cockroachdb.Cluster, *http.Response, error) {
+						return &cockroachdb.Cluster{
+							Id:    testClusterID,
+							Plan:  cockroachdb.PLAN_DEDICATED,
+							State: cockroachdb.CLUSTERSTATETYPE_CREATED,
+						}, &http.Response{StatusCode: http.StatusOK}, nil
+					},
+					MockListAllowlistEntries: func(_ context.Context, _ string, _ *cockroachdb.ListAllowlistEntriesOptions) (*cockroachdb.ListAllowlistEntriesResponse, *http.Response, error) {
+						return &cockroachdb.ListAllowlistEntriesResponse{}, &http.Response{StatusCode: http.StatusOK}, nil
+					},
+				}, CloudClient: cloudClient},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: cluster(withExternalName(testClusterID), func(cr *v1alpha1.Cluster) {
+					cr.Status.AtProvider.Username = "cool"
+				}),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"PublishConnectionInfo": {
+			reason: "The endpoints of a created Cluster, but none of its credentials, should be published to the referenced ConfigMap.",
+			fields: fields{
+				service: createdService,
+				kube:    publisher,
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: cluster(synced, func(cr *v1alpha1.Cluster) {
+					cr.Spec.WriteConnectionInfoToConfigMapRef = &v1alpha1.ConfigMapReference{Name: "cool-info", Namespace: "default"}
+				}),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+				published: []client.Object{&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-info", Namespace: "default", OwnerReferences: owner},
+					Data: map[string]string{
+						"host":           "cool-eu.aws-eu-west-1.cockroachlabs.cloud",
+						"host.eu-west-1": "cool-eu.aws-eu-west-1.cockroachlabs.cloud",
+						"host.us-east-1": "cool-us.aws-us-east-1.cockroachlabs.cloud",
+						"port":           cloud.DefaultSQLPort,
+						"database":       cloud.DefaultDatabase,
+						"regions":        "eu-west-1,us-east-1",
+						"version":        "v22.1.0",
+					},
+				}},
+			},
+		},
+		"PublishDNSEndpoint": {
+			reason: "A CNAME record pointing to the SQL endpoint of a created Cluster should be published as a DNSEndpoint.",
+			fields: fields{
+				service: createdService,
+				kube:    publisher,
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: cluster(synced, func(cr *v1alpha1.Cluster) {
+					cr.Spec.DNSEndpoint = &v1alpha1.DNSEndpointSpec{Namespace: "default", Hostname: "db.example.com"}
+				}),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+				published: []client.Object{func() client.Object {
+					u := &unstructured.Unstructured{}
+					u.SetGroupVersionKind(dnsEndpointGroupVersionKind)
+					u.SetName("cool")
+					u.SetNamespace("default")
+					u.SetOwnerReferences(owner)
+					u.Object["spec"] = map[string]interface{}{"endpoints": []interface{}{map[string]interface{}{
+						"dnsName":    "db.example.com",
+						"recordType": "CNAME",
+						"targets":    []interface{}{"cool-eu.aws-eu-west-1.cockroachlabs.cloud"},
+					}}}
+					return u
+				}()},
+			},
+		},
+	}
+
+	// Keys of the connection secret, none of which may leak into the
+	// connection info ConfigMap.
+	secret := map[string]bool{
+		sqlclient.ConnectionDetailCA:              true,
+		sqlclient.ConnectionDetailDSN:             true,
+		xpv1.ResourceCredentialsSecretUserKey:     true,
+		xpv1.ResourceCredentialsSecretPasswordKey: true,
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: tc.fields.service}
+			var published []client.Object
+			e := external{service: tc.fields.service, kind: v1alpha1.ClusterGroupVersionKind}
+			if tc.fields.kube != nil {
+				e.kube = tc.fields.kube(&published)
+			}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -69,6 +379,451 @@ func TestObserve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.published, published); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want published, +got published:\n%s\n", tc.reason, diff)
+			}
+			for _, o := range published {
+				cm, ok := o.(*corev1.ConfigMap)
+				if !ok {
+					continue
+				}
+				for k := range cm.Data {
+					if secret[k] || strings.HasPrefix(k, cloud.ConnectionDetailRegionDSNPrefix) {
+						t.Errorf("\n%s\ne.Observe(...): ConfigMap %s has secret field %q", tc.reason, cm.GetName(), k)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	maxClusters := int32(1)
+	errConflict := errors.New("cluster name already exists")
+	errBoom := errors.New("boom")
+	caRef := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "ca", Namespace: "default"}, Key: "ca.crt"}
+
+	type fields struct {
+		service *cloud.Service
+		kube    client.Client
+	}
+
+	type want struct {
+		cr  *v1alpha1.Cluster
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		cr     *v1alpha1.Cluster
+		want   want
+	}{
+		"ObserveOnly": {
+			reason: "An observe-only Cluster should never be created.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyObserveOnly: "true"})
+			}),
+			want: want{
+				cr: cluster(func(cr *v1alpha1.Cluster) {
+					meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyObserveOnly: "true"})
+				}),
+				err: errors.New(errCreateObserveOnly),
+			},
+		},
+		"QuotaExceeded": {
+			reason: "A Cluster that would exceed the limits of the organization should not be created.",
+			fields: fields{
+				service: &cloud.Service{
+					CRDBClient: &mockService{
+						MockListClusters: func(_ context.Context, _ *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error) {
+							return &cockroachdb.ListClustersResponse{Clusters: []cockroachdb.Cluster{
+								{Id: testClusterID, Plan: cockroachdb.PLAN_SERVERLESS},
+							}}, &http.Response{StatusCode: http.StatusOK}, nil
+						},
+					},
+					Limits: &apisv1alpha1.ProviderLimits{MaxServerlessClusters: &maxClusters},
+				},
+			},
+			cr: cluster(),
+			want: want{
+				cr: cluster(func(cr *v1alpha1.Cluster) {
+					cr.Status.SetConditions(v1alpha1.QuotaExceeded(fmt.Sprintf(errFmtMaxServerlessClusters, 1)))
+				}),
+				err: errors.Errorf(errFmtMaxServerlessClusters, 1),
+			},
+		},
+		"NameCollision": {
+			reason: "A Cluster whose name is taken in CockroachDB Cloud should be created under a generated name.",
+			fields: fields{
+				service: &cloud.Service{
+					CRDBClient: &mockService{
+						MockCreateCluster: func(_ context.Context, req *cockroachdb.CreateClusterRequest) (*cockroachdb.Cluster, *http.Response, error) {
+							if req.Name == "cool-cluster" {
+								return nil, &http.Response{StatusCode: http.StatusConflict}, errConflict
+							}
+							if !strings.HasPrefix(req.Name, "cool-cluster-") {
+								return nil, &http.Response{StatusCode: http.StatusBadRequest}, errors.Errorf("unexpected name %q", req.Name)
+							}
+							return &cockroachdb.Cluster{Id: testClusterID, Name: req.Name, Regions: []cockroachdb.Region{{SqlDns: "cool.crdb.io"}}}, &http.Response{StatusCode: http.StatusOK}, nil
+						},
+						MockCreateSQLUser: func(_ context.Context, _ string, _ *cockroachdb.CreateSQLUserRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+							return &cockroachdb.SQLUser{}, &http.Response{StatusCode: http.StatusOK}, nil
+						},
+					},
+				},
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						if s, ok := o.(*corev1.Secret); ok {
+							s.Data = map[string][]byte{"ca.crt": []byte("cert")}
+						}
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.SetName("cool-cluster")
+				cr.Spec.ForProvider.Connection = &v1alpha1.ClusterConnection{CASecretRef: caRef}
+			}),
+			want: want{
+				cr: cluster(withExternalName(testClusterID), func(cr *v1alpha1.Cluster) {
+					cr.SetName("cool-cluster")
+					cr.Spec.ForProvider.Connection = &v1alpha1.ClusterConnection{CASecretRef: caRef}
+				}),
+			},
+		},
+		"PersistExternalNameError": {
+			reason: "Errors persisting the external name of a created cluster should be returned before the rest of Create runs.",
+			fields: fields{
+				service: &cloud.Service{
+					CRDBClient: &mockService{
+						MockCreateCluster: func(_ context.Context, req *cockroachdb.CreateClusterRequest) (*cockroachdb.Cluster, *http.Response, error) {
+							return &cockroachdb.Cluster{Id: testClusterID, Name: req.Name}, &http.Response{StatusCode: http.StatusOK}, nil
+						},
+					},
+				},
+				kube: &test.MockClient{
+					MockGet:    test.NewMockGetFn(nil),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			cr: cluster(),
+			want: want{
+				cr:  cluster(withExternalName(testClusterID)),
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot update critical annotations"), errPersistExternalName),
+			},
+		},
+		"ExplicitNameCollision": {
+			reason: "A Cluster whose explicit name is taken in CockroachDB Cloud should not be created under another name.",
+			fields: fields{
+				service: &cloud.Service{
+					CRDBClient: &mockService{
+						MockCreateCluster: func(_ context.Context, _ *cockroachdb.CreateClusterRequest) (*cockroachdb.Cluster, *http.Response, error) {
+							return nil, &http.Response{StatusCode: http.StatusConflict}, errConflict
+						},
+					},
+				},
+			},
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.ForProvider.Name = "cool-cluster"
+			}),
+			want: want{
+				cr: cluster(func(cr *v1alpha1.Cluster) {
+					cr.Spec.ForProvider.Name = "cool-cluster"
+				}),
+				err: errConflict,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{service: tc.fields.service, kube: tc.fields.kube}
+			_, err := e.Create(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		cr      *v1alpha1.Cluster
+		deleted []string
+		err     error
+	}
+
+	cascade := func(cr *v1alpha1.Cluster) {
+		cr.Spec.ForProvider.CascadeDeletion = true
+		cr.Spec.ForProvider.SQLUsers = []v1alpha1.ClusterSQLUser{{Name: "app"}}
+		cr.Spec.ForProvider.Allowlist = []v1alpha1.AllowlistEntry{{CIDR: "10.0.0.0/8"}}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service func(deleted *[]string) cockroachdb.Service
+		cr      *v1alpha1.Cluster
+		want    want
+	}{
+		"NoCascade": {
+			reason: "Only the Cluster should be deleted when cascade deletion is disabled.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteCluster: func(_ context.Context, id string) (*cockroachdb.Cluster, *http.Response, error) {
+						*deleted = append(*deleted, "cluster/"+id)
+						return nil, &http.Response{StatusCode: http.StatusOK}, nil
+					},
+				}
+			},
+			cr: cluster(withExternalName(testClusterID)),
+			want: want{
+				cr:      cluster(withExternalName(testClusterID)),
+				deleted: []string{"cluster/" + testClusterID},
+			},
+		},
+		"Cascade": {
+			reason: "SQL users and allowlist entries should be deleted before the Cluster, ignoring those already gone.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteSQLUser: func(_ context.Context, _ string, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+						*deleted = append(*deleted, "user/"+name)
+						if name == "cool" {
+							return nil, &http.Response{StatusCode: http.StatusNotFound}, errBoom
+						}
+						return nil, &http.Response{StatusCode: http.StatusOK}, nil
+					},
+					MockDeleteAllowlistEntry: func(_ context.Context, _ string, ip string, mask int32) (*cockroachdb.AllowlistEntry, *http.Response, error) {
+						*deleted = append(*deleted, fmt.Sprintf("allowlist/%s/%d", ip, mask))
+						return nil, &http.Response{StatusCode: http.StatusOK}, nil
+					},
+					MockDeleteCluster: func(_ context.Context, id string) (*cockroachdb.Cluster, *http.Response, error) {
+						*deleted = append(*deleted, "cluster/"+id)
+						return nil, &http.Response{StatusCode: http.StatusOK}, nil
+					},
+				}
+			},
+			cr: cluster(withExternalName(testClusterID), cascade),
+			want: want{
+				cr: cluster(withExternalName(testClusterID), cascade, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.DependentsDeleted())
+				}),
+				deleted: []string{"user/app", "user/cool", "allowlist/10.0.0.0/8", "cluster/" + testClusterID},
+			},
+		},
+		"DeleteSQLUserError": {
+			reason: "The Cluster should not be deleted if one of its SQL users cannot be deleted.",
+			service: func(deleted *[]string) cockroachdb.Service {
+				return &mockService{
+					MockDeleteSQLUser: func(_ context.Context, _ string, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+						*deleted = append(*deleted, "user/"+name)
+						return nil, &http.Response{StatusCode: http.StatusInternalServerError}, errBoom
+					},
+				}
+			},
+			cr: cluster(withExternalName(testClusterID), cascade),
+			want: want{
+				cr: cluster(withExternalName(testClusterID), cascade, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.DeletingDependents(v1alpha1.ReasonDeletingSQLUsers))
+				}),
+				deleted: []string{"user/app"},
+				err:     errors.Wrap(errBoom, errDeleteSQLUser),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := []string{}
+			e := external{service: &cloud.Service{CRDBClient: tc.service(&deleted)}}
+			err := e.Delete(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want deleted, +got deleted:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffSpec(t *testing.T) {
+	initSpendLimit := int32(100)
+
+	cases := map[string]struct {
+		reason  string
+		cr      *v1alpha1.Cluster
+		cluster *cockroachdb.Cluster
+		want    specDiff
+	}{
+		"UpToDate": {
+			reason: "A cluster matching the spec should be up to date.",
+			cluster: &cockroachdb.Cluster{
+				Plan:   cockroachdb.PLAN_SERVERLESS,
+				Config: cockroachdb.ClusterConfig{Serverless: &cockroachdb.ServerlessClusterConfig{SpendLimit: 0}},
+			},
+			want: specUpToDate,
+		},
+		"InPlan": {
+			reason: "A spend limit change should be updated within the plan.",
+			cluster: &cockroachdb.Cluster{
+				Plan:   cockroachdb.PLAN_SERVERLESS,
+				Config: cockroachdb.ClusterConfig{Serverless: &cockroachdb.ServerlessClusterConfig{SpendLimit: 100}},
+			},
+			want: specInPlan,
+		},
+		"InitOnlySpendLimit": {
+			reason: "A spend limit only honored at creation should never make the cluster out of date.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.InitProvider = &v1alpha1.ClusterInitParameters{
+					Serverless: &v1alpha1.ServerlessInitParameters{SpendLimit: &initSpendLimit},
+				}
+			}),
+			cluster: &cockroachdb.Cluster{
+				Plan:   cockroachdb.PLAN_SERVERLESS,
+				Config: cockroachdb.ClusterConfig{Serverless: &cockroachdb.ServerlessClusterConfig{SpendLimit: 500}},
+			},
+			want: specUpToDate,
+		},
+		"PlanChange": {
+			reason: "A cluster running a different plan should require a plan change.",
+			cluster: &cockroachdb.Cluster{
+				Plan: cockroachdb.PLAN_DEDICATED,
+			},
+			want: specPlanChange,
+		},
+		"Dedicated": {
+			reason: "A dedicated cluster running the dedicated plan should be up to date.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.ForProvider.Serverless = nil
+				cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{
+					RegionNodes: map[string]int32{"eu-west-1": 3},
+					MachineType: "m5.large",
+				}
+			}),
+			cluster: &cockroachdb.Cluster{
+				Plan:    cockroachdb.PLAN_DEDICATED,
+				Config:  cockroachdb.ClusterConfig{Dedicated: &cockroachdb.DedicatedHardwareConfig{MachineType: "m5.large", StorageGib: 150}},
+				Regions: []cockroachdb.Region{{Name: "eu-west-1", NodeCount: 3}},
+			},
+			want: specUpToDate,
+		},
+		"HardwareDrift": {
+			reason: "A dedicated cluster whose hardware drifted from the spec should be updated within the plan.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.ForProvider.Serverless = nil
+				cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{
+					RegionNodes: map[string]int32{"eu-west-1": 3},
+					MachineType: "m5.large",
+					StorageGiB:  300,
+				}
+			}),
+			cluster: &cockroachdb.Cluster{
+				Plan:    cockroachdb.PLAN_DEDICATED,
+				Config:  cockroachdb.ClusterConfig{Dedicated: &cockroachdb.DedicatedHardwareConfig{MachineType: "m5.large", StorageGib: 150}},
+				Regions: []cockroachdb.Region{{Name: "eu-west-1", NodeCount: 3}},
+			},
+			want: specInPlan,
+		},
+		"RegionNodesDrift": {
+			reason: "A dedicated cluster running a different number of nodes should be scaled within the plan.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.ForProvider.Serverless = nil
+				cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{
+					RegionNodes: map[string]int32{"eu-west-1": 5},
+					MachineType: "m5.large",
+				}
+			}),
+			cluster: &cockroachdb.Cluster{
+				Plan:    cockroachdb.PLAN_DEDICATED,
+				Config:  cockroachdb.ClusterConfig{Dedicated: &cockroachdb.DedicatedHardwareConfig{MachineType: "m5.large"}},
+				Regions: []cockroachdb.Region{{Name: "eu-west-1", NodeCount: 3}},
+			},
+			want: specInPlan,
+		},
+		"Scaling": {
+			reason: "A dedicated cluster should not be updated again while it is scaling.",
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.ForProvider.Serverless = nil
+				cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{
+					RegionNodes: map[string]int32{"eu-west-1": 5},
+					MachineType: "m5.large",
+				}
+			}),
+			cluster: &cockroachdb.Cluster{
+				Plan:            cockroachdb.PLAN_DEDICATED,
+				OperationStatus: cockroachdb.CLUSTERSTATUSTYPE_CRDB_SCALE_RUNNING,
+				Config:          cockroachdb.ClusterConfig{Dedicated: &cockroachdb.DedicatedHardwareConfig{MachineType: "m5.large"}},
+				Regions:         []cockroachdb.Region{{Name: "eu-west-1", NodeCount: 3}},
+			},
+			want: specUpToDate,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tc.cr
+			if cr == nil {
+				cr = cluster()
+			}
+			got := diffSpec(cr, tc.cluster)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndiffSpec(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClusterCACert(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "ca", Namespace: "default"}, Key: "ca.crt"}
+
+	type want struct {
+		ca  []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"FromSecret": {
+			reason: "A CA certificate supplied through caSecretRef should be returned without fetching it.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				o.(*corev1.Secret).Data = map[string][]byte{"ca.crt": []byte("cert")}
+				return nil
+			})},
+			want: want{ca: []byte("cert")},
+		},
+		"SecretError": {
+			reason: "Errors getting the caSecretRef should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetCASecret)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := cluster()
+			cr.Spec.ForProvider.Connection = &v1alpha1.ClusterConnection{CASecretRef: ref}
+			e := &external{kube: tc.kube, service: &cloud.Service{}}
+			ca, err := e.clusterCACert(context.Background(), cr, &cockroachdb.Cluster{Id: testClusterID})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nclusterCACert(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ca, ca); diff != "" {
+				t.Errorf("\n%s\nclusterCACert(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	discoveryInterval = 10 * time.Minute
+	discoveryTimeout  = 2 * time.Minute
+
+	errListClusters        = "cannot list clusters"
+	errListManagedClusters = "cannot list managed Clusters"
+	errCreateDiscovered    = "cannot create discovered Cluster"
+
+	errFmtNameCollision = "cannot create a Cluster for discovered cluster %s: a Cluster named %s already exists"
+
+	// reasonNameCollision is the reason of the event recorded on a
+	// ProviderConfig when a discovered cluster cannot be observed because
+	// its name is taken by a Cluster of another cluster.
+	reasonNameCollision event.Reason = "DiscoveredClusterNameCollision"
+)
+
+// SetupDiscovery adds a controller that periodically creates observe-only
+// Clusters for the clusters of each ProviderConfig that are not managed yet.
+// It is only added if cluster discovery is enabled.
+func SetupDiscovery(mgr ctrl.Manager, o cloud.Options) error {
+	if !o.Features.Enabled(features.EnableAlphaClusterDiscovery) {
+		return nil
+	}
+	name := "discovery/" + strings.ToLower(v1alpha1.ClusterGroupKind)
+
+	c := &cloud.Connector{Kube: mgr.GetClient(), NewServiceFn: o.NewService}
+	r := &discoverer{
+		kube:    mgr.GetClient(),
+		service: c.ServiceFor,
+		log:     o.Logger.WithValues("controller", name),
+		record:  event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&apisv1alpha1.ProviderConfig{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A discoverer creates observe-only Clusters for the unmanaged clusters of a
+// ProviderConfig.
+type discoverer struct {
+	kube    client.Client
+	service func(ctx context.Context, providerConfig string) (*cloud.Service, error)
+	log     logging.Logger
+	record  event.Recorder
+}
+
+// Reconcile discovers the clusters of the requested ProviderConfig.
+func (d *discoverer) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := d.log.WithValues("request", req)
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	svc, err := d.service(ctx, req.Name)
+	if err != nil {
+		return reconcile.Result{}, resource.IgnoreNotFound(err)
+	}
+
+	managed, err := d.managedClusters(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	clusters, err := listClusters(ctx, svc.CRDBClient)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, cl := range clusters {
+		// Only serverless and dedicated clusters are discovered.
+		if managed[cl.Id] || (cl.Plan != cockroachdb.PLAN_SERVERLESS && cl.Plan != cockroachdb.PLAN_DEDICATED) {
+			continue
+		}
+		cr := discoveredCluster(req.Name, cl)
+		err := d.kube.Create(ctx, cr)
+		if kerrors.IsAlreadyExists(err) {
+			// The Cluster of that name manages another cluster, or it
+			// would have been listed as managed.
+			pc := &apisv1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: req.Name}}
+			d.record.Event(pc, event.Warning(reasonNameCollision, errors.Errorf(errFmtNameCollision, cl.Id, cr.GetName())))
+			continue
+		}
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errCreateDiscovered)
+		}
+		log.Debug("Discovered cluster", "id", cl.Id, "name", cr.GetName())
+	}
+
+	return reconcile.Result{RequeueAfter: discoveryInterval}, nil
+}
+
+// managedClusters returns the IDs of the clusters that are already managed by
+// a cluster scoped or namespaced Cluster.
+func (d *discoverer) managedClusters(ctx context.Context) (map[string]bool, error) {
+	ids := map[string]bool{}
+
+	l := &v1alpha1.ClusterList{}
+	if err := d.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListManagedClusters)
+	}
+	for i := range l.Items {
+		ids[meta.GetExternalName(&l.Items[i])] = true
+	}
+
+	nl := &namespacedv1alpha1.ClusterList{}
+	if err := d.kube.List(ctx, nl); err != nil {
+		return nil, errors.Wrap(err, errListManagedClusters)
+	}
+	for i := range nl.Items {
+		ids[meta.GetExternalName(&nl.Items[i])] = true
+	}
+
+	return ids, nil
+}
+
+// listClusters returns the active clusters of the organization.
+func listClusters(ctx context.Context, svc cockroachdb.Service) ([]cockroachdb.Cluster, error) {
+	clusters := []cockroachdb.Cluster{}
+	err := cockroachcloud.NewPager(func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		res, _, err := svc.ListClusters(ctx, &cockroachdb.ListClustersOptions{PaginationStartKey: startKey})
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, res.Clusters...)
+		return res.Pagination, nil
+	}).All(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListClusters)
+	}
+	return clusters, nil
+}
+
+// discoveredCluster returns an observe-only Cluster for the supplied cluster.
+// The cluster is orphaned when the Cluster is deleted.
+func discoveredCluster(providerConfig string, cl cockroachdb.Cluster) *v1alpha1.Cluster {
+	cr := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cl.Name,
+			Labels:      map[string]string{v1alpha1.LabelKeyDiscovered: "true"},
+			Annotations: map[string]string{v1alpha1.AnnotationKeyObserveOnly: "true"},
+		},
+		Spec: v1alpha1.ClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{Name: providerConfig},
+				DeletionPolicy:          xpv1.DeletionOrphan,
+			},
+			ForProvider: v1alpha1.ClusterParameters{
+				Provider: cl.CloudProvider,
+			},
+		},
+	}
+	if cl.Plan == cockroachdb.PLAN_DEDICATED {
+		cr.Spec.ForProvider.Dedicated = discoveredDedicated(cl)
+	} else {
+		cr.Spec.ForProvider.Serverless = discoveredServerless(cl)
+	}
+	meta.SetExternalName(cr, cl.Id)
+	return cr
+}
+
+// discoveredServerless returns the serverless configuration of the supplied
+// cluster.
+func discoveredServerless(cl cockroachdb.Cluster) *v1alpha1.ServerlessCluster {
+	regions := make([]string, len(cl.Regions))
+	for i, r := range cl.Regions {
+		regions[i] = r.Name
+	}
+	spendLimit := int32(0)
+	if cl.Config.Serverless != nil {
+		spendLimit = cl.Config.Serverless.SpendLimit
+	}
+	return &v1alpha1.ServerlessCluster{Regions: regions, SpendLimit: &spendLimit}
+}
+
+// discoveredDedicated returns the dedicated configuration of the supplied
+// cluster, i.e. the nodes of each of its regions and their hardware.
+func discoveredDedicated(cl cockroachdb.Cluster) *v1alpha1.DedicatedCluster {
+	d := &v1alpha1.DedicatedCluster{RegionNodes: make(map[string]int32, len(cl.Regions))}
+	for _, r := range cl.Regions {
+		d.RegionNodes[r.Name] = r.NodeCount
+	}
+	if hw := cl.Config.Dedicated; hw != nil {
+		// The Cloud API may omit the machine type of clusters created with
+		// a number of virtual CPUs, and exactly one of both must be set.
+		if hw.MachineType != "" {
+			d.MachineType = hw.MachineType
+		} else {
+			d.NumVirtualCPUs = hw.NumVirtualCpus
+		}
+		d.StorageGiB = hw.StorageGib
+		d.DiskIOPS = hw.DiskIops
+	}
+	return d
+}
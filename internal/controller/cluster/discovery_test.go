@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+type recordingEvents struct {
+	events []event.Event
+}
+
+func (r *recordingEvents) Event(_ runtime.Object, e event.Event)      { r.events = append(r.events, e) }
+func (r *recordingEvents) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestDiscover(t *testing.T) {
+	errBoom := errors.New("boom")
+	unmanagedID := "0b7e6f9c-2f4a-4c1e-9a53-6c2b1d9e8f70"
+
+	service := func(_ context.Context, _ string) (*cloud.Service, error) {
+		return &cloud.Service{CRDBClient: &mockService{
+			MockListClusters: func(_ context.Context, _ *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error) {
+				return &cockroachdb.ListClustersResponse{Clusters: []cockroachdb.Cluster{
+					{Id: testClusterID, Name: "cool", Plan: cockroachdb.PLAN_SERVERLESS},
+					{Id: unmanagedID, Name: "unmanaged", Plan: cockroachdb.PLAN_SERVERLESS, CloudProvider: cockroachdb.APICLOUDPROVIDER_GCP},
+					{Id: "dedicated", Name: "dedicated", Plan: cockroachdb.PLAN_DEDICATED},
+					{Id: "custom", Name: "custom", Plan: cockroachdb.PLAN_CUSTOM},
+				}}, &http.Response{StatusCode: http.StatusOK}, nil
+			},
+		}}, nil
+	}
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		if l, ok := obj.(*v1alpha1.ClusterList); ok {
+			l.Items = []v1alpha1.Cluster{*cluster(withExternalName(testClusterID))}
+		}
+		return nil
+	}
+
+	type want struct {
+		created []string
+		events  []event.Event
+		r       reconcile.Result
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   func(created *[]string) client.Client
+		want   want
+	}{
+		"CreateUnmanaged": {
+			reason: "Only unmanaged serverless and dedicated clusters should be discovered.",
+			kube: func(created *[]string) client.Client {
+				return &test.MockClient{
+					MockList: list,
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						cr := obj.(*v1alpha1.Cluster)
+						if !cr.ObserveOnly() {
+							t.Errorf("discovered Cluster %q is not observe-only", cr.GetName())
+						}
+						*created = append(*created, cr.GetName())
+						return nil
+					},
+				}
+			},
+			want: want{
+				created: []string{"unmanaged", "dedicated"},
+				r:       reconcile.Result{RequeueAfter: discoveryInterval},
+			},
+		},
+		"NameCollision": {
+			reason: "A discovered cluster whose name is taken should be reported and skipped.",
+			kube: func(created *[]string) client.Client {
+				return &test.MockClient{
+					MockList: list,
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						if obj.GetName() == "unmanaged" {
+							return kerrors.NewAlreadyExists(schema.GroupResource{}, obj.GetName())
+						}
+						*created = append(*created, obj.GetName())
+						return nil
+					},
+				}
+			},
+			want: want{
+				created: []string{"dedicated"},
+				events:  []event.Event{event.Warning(reasonNameCollision, errors.Errorf(errFmtNameCollision, unmanagedID, "unmanaged"))},
+				r:       reconcile.Result{RequeueAfter: discoveryInterval},
+			},
+		},
+		"CreateError": {
+			reason: "Errors creating discovered Clusters should be returned.",
+			kube: func(_ *[]string) client.Client {
+				return &test.MockClient{
+					MockList:   list,
+					MockCreate: test.NewMockCreateFn(errBoom),
+				}
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateDiscovered),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created []string
+			record := &recordingEvents{}
+			d := &discoverer{kube: tc.kube(&created), service: service, record: record, log: logging.NewNopLogger()}
+			got, err := d.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nd.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nd.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, created); diff != "" {
+				t.Errorf("\n%s\nd.Reconcile(...): -want created, +got created:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, record.events); diff != "" {
+				t.Errorf("\n%s\nd.Reconcile(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiscoveredCluster(t *testing.T) {
+	spendLimit := int32(100)
+
+	cases := map[string]struct {
+		reason string
+		cl     cockroachdb.Cluster
+		want   v1alpha1.ClusterParameters
+	}{
+		"Serverless": {
+			reason: "A serverless cluster should be discovered with its regions and spend limit.",
+			cl: cockroachdb.Cluster{
+				Plan:          cockroachdb.PLAN_SERVERLESS,
+				CloudProvider: cockroachdb.APICLOUDPROVIDER_GCP,
+				Regions:       []cockroachdb.Region{{Name: "us-east1"}},
+				Config:        cockroachdb.ClusterConfig{Serverless: &cockroachdb.ServerlessClusterConfig{SpendLimit: 100}},
+			},
+			want: v1alpha1.ClusterParameters{
+				Provider:   cockroachdb.APICLOUDPROVIDER_GCP,
+				Serverless: &v1alpha1.ServerlessCluster{Regions: []string{"us-east1"}, SpendLimit: &spendLimit},
+			},
+		},
+		"Dedicated": {
+			reason: "A dedicated cluster should be discovered with the nodes of each region and their hardware.",
+			cl: cockroachdb.Cluster{
+				Plan:          cockroachdb.PLAN_DEDICATED,
+				CloudProvider: cockroachdb.APICLOUDPROVIDER_AWS,
+				Regions:       []cockroachdb.Region{{Name: "us-east-1", NodeCount: 3}, {Name: "eu-west-1", NodeCount: 5}},
+				Config:        cockroachdb.ClusterConfig{Dedicated: &cockroachdb.DedicatedHardwareConfig{MachineType: "m5.large", NumVirtualCpus: 2, StorageGib: 150, DiskIops: 450}},
+			},
+			want: v1alpha1.ClusterParameters{
+				Provider: cockroachdb.APICLOUDPROVIDER_AWS,
+				Dedicated: &v1alpha1.DedicatedCluster{
+					RegionNodes: map[string]int32{"us-east-1": 3, "eu-west-1": 5},
+					MachineType: "m5.large",
+					StorageGiB:  150,
+					DiskIOPS:    450,
+				},
+			},
+		},
+		"DedicatedVirtualCPUs": {
+			reason: "A dedicated cluster without a machine type should be discovered with its number of virtual CPUs.",
+			cl: cockroachdb.Cluster{
+				Plan:          cockroachdb.PLAN_DEDICATED,
+				CloudProvider: cockroachdb.APICLOUDPROVIDER_GCP,
+				Regions:       []cockroachdb.Region{{Name: "us-east1", NodeCount: 3}},
+				Config:        cockroachdb.ClusterConfig{Dedicated: &cockroachdb.DedicatedHardwareConfig{NumVirtualCpus: 4, StorageGib: 150}},
+			},
+			want: v1alpha1.ClusterParameters{
+				Provider: cockroachdb.APICLOUDPROVIDER_GCP,
+				Dedicated: &v1alpha1.DedicatedCluster{
+					RegionNodes:    map[string]int32{"us-east1": 3},
+					NumVirtualCPUs: 4,
+					StorageGiB:     150,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := discoveredCluster("default", tc.cl)
+			if diff := cmp.Diff(tc.want, got.Spec.ForProvider); diff != "" {
+				t.Errorf("\n%s\ndiscoveredCluster(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/metrics"
+)
+
+// SetupInventory exports inventory metrics of the Clusters managed by the
+// provider.
+func SetupInventory(mgr ctrl.Manager, _ cloud.Options) error {
+	return crmetrics.Registry.Register(metrics.NewInventoryCollector(inventory(mgr.GetClient())))
+}
+
+// inventory returns an InventorySource that lists both cluster scoped and
+// namespaced Clusters.
+func inventory(kube client.Reader) metrics.InventorySource {
+	return func(ctx context.Context) ([]metrics.InventoryItem, error) {
+		items := []metrics.InventoryItem{}
+
+		l := &v1alpha1.ClusterList{}
+		if err := kube.List(ctx, l); err != nil {
+			return nil, errors.Wrap(err, errListManagedClusters)
+		}
+		for i := range l.Items {
+			items = append(items, inventoryItem(v1alpha1.ClusterGroupKind, &l.Items[i]))
+		}
+
+		nl := &namespacedv1alpha1.ClusterList{}
+		if err := kube.List(ctx, nl); err != nil {
+			return nil, errors.Wrap(err, errListManagedClusters)
+		}
+		for i := range nl.Items {
+			items = append(items, inventoryItem(namespacedv1alpha1.ClusterGroupKind, clusterFor(&nl.Items[i])))
+		}
+		return items, nil
+	}
+}
+
+func inventoryItem(kind string, cr *v1alpha1.Cluster) metrics.InventoryItem {
+	return metrics.InventoryItem{
+		Kind:     kind,
+		Plan:     string(cr.Plan()),
+		Provider: string(cr.Spec.ForProvider.Provider),
+		Ready:    cr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue,
+		Created:  cr.GetCreationTimestamp().Time,
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/metrics"
+)
+
+func TestInventory(t *testing.T) {
+	errBoom := errors.New("boom")
+	created := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ready := func(cr *v1alpha1.Cluster) {
+		cr.SetCreationTimestamp(metav1.NewTime(created))
+		cr.SetConditions(xpv1.Available())
+	}
+	dedicated := func(cr *v1alpha1.Cluster) {
+		cr.SetCreationTimestamp(metav1.NewTime(created))
+		cr.Spec.ForProvider.Provider = cockroachdb.APICLOUDPROVIDER_GCP
+		cr.Spec.ForProvider.Serverless = nil
+		cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{}
+		cr.SetConditions(xpv1.Creating())
+	}
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *v1alpha1.ClusterList:
+			l.Items = []v1alpha1.Cluster{*cluster(ready), *cluster(dedicated)}
+		case *namespacedv1alpha1.ClusterList:
+			l.Items = []namespacedv1alpha1.Cluster{{
+				ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+				Spec:       namespacedv1alpha1.ClusterSpec{ForProvider: v1alpha1.ClusterParameters{Provider: cockroachdb.APICLOUDPROVIDER_AWS}},
+			}}
+		}
+		return nil
+	}
+
+	type want struct {
+		items []metrics.InventoryItem
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		want   want
+	}{
+		"ClusterScopedAndNamespaced": {
+			reason: "Both cluster scoped and namespaced Clusters should be counted with their plan, provider and readiness.",
+			kube:   &test.MockClient{MockList: list},
+			want: want{
+				items: []metrics.InventoryItem{
+					{Kind: v1alpha1.ClusterGroupKind, Plan: "SERVERLESS", Provider: "AWS", Ready: true, Created: created},
+					{Kind: v1alpha1.ClusterGroupKind, Plan: "DEDICATED", Provider: "GCP", Ready: false, Created: created},
+					{Kind: namespacedv1alpha1.ClusterGroupKind, Plan: "SERVERLESS", Provider: "AWS", Ready: false, Created: created},
+				},
+			},
+		},
+		"ListError": {
+			reason: "Errors listing Clusters should be returned.",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListManagedClusters),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := inventory(tc.kube)(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninventory(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.items, got); diff != "" {
+				t.Errorf("\n%s\ninventory(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	t.Run("Collect", func(t *testing.T) {
+		want := `
+# HELP cockroachdb_managed_resources Number of managed resources by kind, plan, cloud provider and readiness.
+# TYPE cockroachdb_managed_resources gauge
+cockroachdb_managed_resources{kind="Cluster.database.cockroachdb.crossplane.io",plan="DEDICATED",provider="GCP",ready="false"} 1
+cockroachdb_managed_resources{kind="Cluster.database.cockroachdb.crossplane.io",plan="SERVERLESS",provider="AWS",ready="true"} 1
+cockroachdb_managed_resources{kind="Cluster.database.cockroachdb.m.crossplane.io",plan="SERVERLESS",provider="AWS",ready="false"} 1
+`
+		c := metrics.NewInventoryCollector(inventory(&test.MockClient{MockList: list}))
+		if err := testutil.CollectAndCompare(c, strings.NewReader(want), "cockroachdb_managed_resources"); err != nil {
+			t.Errorf("Collect(...): %v", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	clusterNameMaxLength = 20
+	clusterNameSuffixLen = 5
+
+	// maxCreateAttempts is the number of names tried when creating a
+	// cluster whose name was not set explicitly.
+	maxCreateAttempts = 3
+)
+
+var (
+	clusterNameRE        = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{4,18}[a-z0-9]$`)
+	clusterNameInvalidRE = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// cloudClusterName returns a name derived from the supplied one that meets
+// the length and charset constraints of CockroachDB Cloud. Compliant names
+// are returned as is unless a random suffix is requested, e.g. because the
+// name is already taken.
+func cloudClusterName(name string, suffix bool) string {
+	if !suffix && clusterNameRE.MatchString(name) {
+		return name
+	}
+	base := strings.Trim(clusterNameInvalidRE.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if max := clusterNameMaxLength - clusterNameSuffixLen - 1; len(base) > max {
+		base = strings.TrimRight(base[:max], "-")
+	}
+	if base == "" {
+		base = "cluster"
+	}
+	return base + "-" + rand.String(clusterNameSuffixLen)
+}
+
+// isNameCollision returns true if a cluster could not be created because its
+// name is already taken.
+func isNameCollision(res *http.Response) bool {
+	return res != nil && res.StatusCode == http.StatusConflict
+}
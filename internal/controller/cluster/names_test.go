@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCloudClusterName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		name   string
+		suffix bool
+		prefix string
+	}{
+		"Compliant": {
+			reason: "A compliant name should be returned as is.",
+			name:   "cool-cluster",
+			prefix: "cool-cluster",
+		},
+		"CompliantCollision": {
+			reason: "A random suffix should be appended to a compliant name on request.",
+			name:   "cool-cluster",
+			suffix: true,
+			prefix: "cool-cluster-",
+		},
+		"TooLong": {
+			reason: "Long names, e.g. of composed resources, should be truncated.",
+			name:   "my-app-database-xk2lp-8djw2",
+			prefix: "my-app-databas-",
+		},
+		"InvalidCharacters": {
+			reason: "Characters not allowed by CockroachDB Cloud should be replaced.",
+			name:   "My.App",
+			prefix: "my-app-",
+		},
+		"TooShort": {
+			reason: "Short names should be padded by the suffix.",
+			name:   "db",
+			prefix: "db-",
+		},
+		"Empty": {
+			reason: "A name without any usable character should fall back to a generic one.",
+			name:   "...",
+			prefix: "cluster-",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cloudClusterName(tc.name, tc.suffix)
+			if !clusterNameRE.MatchString(got) {
+				t.Errorf("\n%s\ncloudClusterName(...): %q does not meet the constraints of CockroachDB Cloud", tc.reason, got)
+			}
+			if !strings.HasPrefix(got, tc.prefix) {
+				t.Errorf("\n%s\ncloudClusterName(...): want prefix %q, got %q", tc.reason, tc.prefix, got)
+			}
+		})
+	}
+}
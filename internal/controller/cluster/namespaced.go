@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/usage"
+	"github.com/crossplane/provider-cockroachdb/internal/metrics"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+)
+
+const (
+	errNotNamespacedCluster = "managed resource is not a namespaced Cluster custom resource"
+)
+
+// SetupNamespaced adds a controller that reconciles namespaced Cluster managed
+// resources.
+func SetupNamespaced(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(namespacedv1alpha1.ClusterGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+	for i := range cps {
+		cps[i] = cloud.NewLocalConnectionPublisher(cps[i])
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(namespacedv1alpha1.ClusterGroupVersionKind),
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&namespacedConnector{connector: &connector{
+			Connector: &cloud.Connector{
+				Kube:         mgr.GetClient(),
+				Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+				APIInfo:      o.APIInfo,
+				NewServiceFn: o.NewService},
+			protector: usage.NewProtector(mgr.GetClient(), namespacedv1alpha1.SQLUserListGroupVersionKind, namespacedv1alpha1.DatabaseListGroupVersionKind),
+			metrics:   metrics.NewClusterStateRecorder()}}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&namespacedv1alpha1.Cluster{}).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return &namespacedv1alpha1.Cluster{} }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A namespacedConnector produces an ExternalClient for namespaced Clusters.
+type namespacedConnector struct {
+	*connector
+}
+
+func (c *namespacedConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Cluster)
+	if !ok {
+		return nil, errors.New(errNotNamespacedCluster)
+	}
+
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	svc, err := c.Service(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &namespacedExternal{external: &external{
+		service:   svc,
+		kube:      c.Kube,
+		protector: c.protector,
+		metrics:   c.metrics,
+		kind:      namespacedv1alpha1.ClusterGroupVersionKind,
+	}}, nil
+}
+
+// A namespacedExternal reconciles namespaced Clusters by delegating to the
+// cluster scoped external client.
+type namespacedExternal struct {
+	external *external
+}
+
+func (c *namespacedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Cluster)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotNamespacedCluster)
+	}
+	cl := clusterFor(cr)
+	o, err := c.external.Observe(ctx, cl)
+	setFromCluster(cr, cl)
+	return o, err
+}
+
+func (c *namespacedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Cluster)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotNamespacedCluster)
+	}
+	cl := clusterFor(cr)
+	cre, err := c.external.Create(ctx, cl)
+	setFromCluster(cr, cl)
+	return cre, err
+}
+
+func (c *namespacedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Cluster)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNamespacedCluster)
+	}
+	cl := clusterFor(cr)
+	u, err := c.external.Update(ctx, cl)
+	setFromCluster(cr, cl)
+	return u, err
+}
+
+func (c *namespacedExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*namespacedv1alpha1.Cluster)
+	if !ok {
+		return errors.New(errNotNamespacedCluster)
+	}
+	cl := clusterFor(cr)
+	err := c.external.Delete(ctx, cl)
+	setFromCluster(cr, cl)
+	return err
+}
+
+// clusterFor returns the cluster scoped equivalent of a namespaced Cluster.
+// Secret references are confined to the namespace of the namespaced Cluster.
+func clusterFor(cr *namespacedv1alpha1.Cluster) *v1alpha1.Cluster {
+	cl := &v1alpha1.Cluster{
+		ObjectMeta: *cr.ObjectMeta.DeepCopy(),
+		Spec: v1alpha1.ClusterSpec{
+			ResourceSpec: *cr.Spec.ResourceSpec.DeepCopy(),
+			ForProvider:  *cr.Spec.ForProvider.DeepCopy(),
+		},
+		Status: v1alpha1.ClusterStatus{
+			ResourceStatus: *cr.Status.ResourceStatus.DeepCopy(),
+			AtProvider:     *cr.Status.AtProvider.DeepCopy(),
+		},
+	}
+	if ip := cr.Spec.InitProvider; ip != nil {
+		cl.Spec.InitProvider = ip.DeepCopy()
+	}
+	if c := cl.Spec.ForProvider.Credentials; c != nil && c.PasswordSecretRef != nil {
+		c.PasswordSecretRef.Namespace = cr.GetNamespace()
+	}
+	if c := cl.Spec.ForProvider.Connection; c != nil && c.CASecretRef != nil {
+		c.CASecretRef.Namespace = cr.GetNamespace()
+	}
+	if c := cl.Spec.ForProvider.Connection; c != nil && c.ClientCertificate != nil {
+		c.ClientCertificate.CertSecretRef.Namespace = cr.GetNamespace()
+		c.ClientCertificate.KeySecretRef.Namespace = cr.GetNamespace()
+	}
+	if ref := cl.Spec.WriteConnectionSecretToReference; ref != nil {
+		ref.Namespace = cr.GetNamespace()
+	}
+	for i := range cl.Spec.ForProvider.SQLUsers {
+		u := &cl.Spec.ForProvider.SQLUsers[i]
+		if u.PasswordSecretRef != nil {
+			u.PasswordSecretRef.Namespace = cr.GetNamespace()
+		}
+		if u.WriteConnectionSecretToRef != nil {
+			u.WriteConnectionSecretToRef.Namespace = cr.GetNamespace()
+		}
+	}
+	if ref := cr.Spec.WriteConnectionInfoToConfigMapRef; ref != nil {
+		cl.Spec.WriteConnectionInfoToConfigMapRef = &v1alpha1.ConfigMapReference{Name: ref.Name, Namespace: cr.GetNamespace()}
+	}
+	if ep := cr.Spec.DNSEndpoint; ep != nil {
+		cl.Spec.DNSEndpoint = ep.DeepCopy()
+		cl.Spec.DNSEndpoint.Namespace = cr.GetNamespace()
+	}
+	return cl
+}
+
+// setFromCluster copies the state written by the external client back to the
+// namespaced Cluster.
+func setFromCluster(cr *namespacedv1alpha1.Cluster, cl *v1alpha1.Cluster) {
+	cr.SetAnnotations(cl.GetAnnotations())
+	cr.Status.ResourceStatus = cl.Status.ResourceStatus
+	cr.Status.AtProvider = cl.Status.AtProvider
+}
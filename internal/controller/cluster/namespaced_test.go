@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+)
+
+func TestClusterFor(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cr     *namespacedv1alpha1.Cluster
+		want   *v1alpha1.Cluster
+	}{
+		"SecretsConfinedToNamespace": {
+			reason: "Secret references of a namespaced Cluster should point to its own namespace.",
+			cr: &namespacedv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Namespace: "team"},
+				Spec: namespacedv1alpha1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{
+						WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "conn", Namespace: "other"},
+					},
+					ForProvider: v1alpha1.ClusterParameters{
+						Credentials: &v1alpha1.Credentials{
+							Username: "cool",
+							PasswordSecretRef: &xpv1.SecretKeySelector{
+								SecretReference: xpv1.SecretReference{Name: "pwd", Namespace: "other"},
+								Key:             "password",
+							},
+						},
+					},
+				},
+			},
+			want: &v1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Namespace: "team"},
+				Spec: v1alpha1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{
+						WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "conn", Namespace: "team"},
+					},
+					ForProvider: v1alpha1.ClusterParameters{
+						Credentials: &v1alpha1.Credentials{
+							Username: "cool",
+							PasswordSecretRef: &xpv1.SecretKeySelector{
+								SecretReference: xpv1.SecretReference{Name: "pwd", Namespace: "team"},
+								Key:             "password",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := clusterFor(tc.cr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nclusterFor(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.cr.Spec.WriteConnectionSecretToReference.Namespace != "other" {
+				t.Errorf("\n%s\nclusterFor(...): must not mutate the supplied Cluster", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// networkVisibilityKey is the field of a cluster that holds its network
+// visibility. It is not modelled by the SDK yet.
+const networkVisibilityKey = "network_visibility"
+
+const errListNodes = "cannot list cluster nodes"
+
+// observeNetworking fills the networking status of the supplied Cluster and
+// returns the observed allowlist entries of its cluster.
+func (c *external) observeNetworking(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) ([]cockroachdb.AllowlistEntry, error) {
+	entries, propagating, err := c.listAllowlist(ctx, cluster.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &v1alpha1.ClusterNetworking{AllowlistPropagating: propagating, CloudProvider: string(cluster.CloudProvider)}
+	if cluster.AccountId != nil {
+		n.CloudAccountID = *cluster.AccountId
+	}
+	for _, e := range entries {
+		n.Allowlist = append(n.Allowlist, fromAllowlistEntry(e))
+	}
+	if v, ok := cluster.AdditionalProperties[networkVisibilityKey].(string); ok {
+		n.NetworkVisibility = v
+	}
+	cr.Status.AtProvider.Networking = n
+	return entries, nil
+}
+
+// observeNodes fills the nodes status of the supplied Cluster. Only
+// dedicated clusters have nodes.
+func (c *external) observeNodes(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) error {
+	if cluster.Plan != cockroachdb.PLAN_DEDICATED {
+		cr.Status.AtProvider.Nodes = nil
+		return nil
+	}
+	nodes, err := c.service.CloudClient.ListClusterNodes(ctx, cluster.Id)
+	if err != nil {
+		return errors.Wrap(err, errListNodes)
+	}
+	cr.Status.AtProvider.Nodes = make([]v1alpha1.ClusterNode, len(nodes))
+	for i, n := range nodes {
+		cr.Status.AtProvider.Nodes[i] = v1alpha1.ClusterNode{Name: n.Name, Region: n.RegionName, Status: string(n.Status)}
+	}
+	return nil
+}
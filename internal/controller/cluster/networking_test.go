@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+func TestObserveNetworking(t *testing.T) {
+	account := "123456789012"
+	e := &external{service: &cloud.Service{CRDBClient: &mockService{
+		MockListAllowlistEntries: func(_ context.Context, _ string, _ *cockroachdb.ListAllowlistEntriesOptions) (*cockroachdb.ListAllowlistEntriesResponse, *http.Response, error) {
+			return &cockroachdb.ListAllowlistEntriesResponse{
+				Allowlist: []cockroachdb.AllowlistEntry{{CidrIp: "10.0.0.0", CidrMask: 8, Sql: true}},
+			}, &http.Response{StatusCode: http.StatusOK}, nil
+		},
+	}}}
+	cr := cluster()
+	_, err := e.observeNetworking(context.Background(), cr, &cockroachdb.Cluster{
+		Id:                   testClusterID,
+		CloudProvider:        cockroachdb.APICLOUDPROVIDER_AWS,
+		AccountId:            &account,
+		AdditionalProperties: map[string]interface{}{networkVisibilityKey: "PUBLIC"},
+	})
+	if err != nil {
+		t.Fatalf("e.observeNetworking(...): %v", err)
+	}
+
+	want := &v1alpha1.ClusterNetworking{
+		Allowlist:         []v1alpha1.AllowlistEntry{{CIDR: "10.0.0.0/8", SQL: true}},
+		NetworkVisibility: "PUBLIC",
+		CloudProvider:     "AWS",
+		CloudAccountID:    account,
+	}
+	if diff := cmp.Diff(want, cr.Status.AtProvider.Networking); diff != "" {
+		t.Errorf("e.observeNetworking(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// runningOperations maps the operation statuses of a cluster to the type of
+// operation that is in flight.
+var runningOperations = map[cockroachdb.ClusterStatusType]v1alpha1.OperationType{
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_EDIT_CLUSTER_RUNNING:    v1alpha1.OperationEdit,
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_SCALE_RUNNING:           v1alpha1.OperationScale,
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_UPGRADE_RUNNING:   v1alpha1.OperationUpgrade,
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_ROLLBACK_RUNNING:  v1alpha1.OperationRollback,
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_PATCH_RUNNING:           v1alpha1.OperationPatch,
+	cockroachdb.CLUSTERSTATUSTYPE_MAINTENANCE_RUNNING:          v1alpha1.OperationMaintenance,
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_INSTANCE_UPDATE_RUNNING: v1alpha1.OperationInstanceUpdate,
+	cockroachdb.CLUSTERSTATUSTYPE_CRDB_CMEK_OPERATION_RUNNING:  v1alpha1.OperationCMEK,
+	cockroachdb.CLUSTERSTATUSTYPE_TENANT_RESTORE_RUNNING:       v1alpha1.OperationRestore,
+}
+
+// pendingOperations returns the long-running operations in flight on the
+// supplied cluster. The start time of operations that were already pending
+// is preserved, so it does not move on every observation.
+func pendingOperations(previous []v1alpha1.PendingOperation, cluster *cockroachdb.Cluster) []v1alpha1.PendingOperation {
+	ops := []v1alpha1.PendingOperation{}
+	if cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATING {
+		ops = append(ops, pendingOperation(previous, v1alpha1.OperationCreate, cluster.CreatedAt))
+	}
+	if t, ok := runningOperations[cluster.OperationStatus]; ok {
+		ops = append(ops, pendingOperation(previous, t, cluster.UpdatedAt))
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	return ops
+}
+
+func pendingOperation(previous []v1alpha1.PendingOperation, t v1alpha1.OperationType, started *time.Time) v1alpha1.PendingOperation {
+	for _, op := range previous {
+		if op.Type == t {
+			return op
+		}
+	}
+	op := v1alpha1.PendingOperation{Type: t, StartTime: metav1.Now()}
+	if started != nil {
+		op.StartTime = metav1.NewTime(*started)
+	}
+	return op
+}
+
+// startOperation records that an operation of the supplied type was just
+// started, unless one is already pending. The Cloud API may not report an
+// operation right away, so this tracks it until the next observation.
+func startOperation(ops []v1alpha1.PendingOperation, t v1alpha1.OperationType) []v1alpha1.PendingOperation {
+	for _, op := range ops {
+		if op.Type == t {
+			return ops
+		}
+	}
+	return append(ops, v1alpha1.PendingOperation{Type: t, StartTime: metav1.Now()})
+}
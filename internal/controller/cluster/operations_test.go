@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestPendingOperations(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+	observed := metav1.NewTime(created.Add(time.Minute))
+
+	cases := map[string]struct {
+		reason   string
+		previous []v1alpha1.PendingOperation
+		cluster  *cockroachdb.Cluster
+		want     []v1alpha1.PendingOperation
+	}{
+		"Idle": {
+			reason:  "A created cluster without running operations should have no pending operations.",
+			cluster: &cockroachdb.Cluster{State: cockroachdb.CLUSTERSTATETYPE_CREATED, OperationStatus: cockroachdb.CLUSTERSTATUSTYPE_CLUSTER_STATUS_UNSPECIFIED},
+		},
+		"Creating": {
+			reason:  "A cluster being created should report a Create operation started at its creation time.",
+			cluster: &cockroachdb.Cluster{State: cockroachdb.CLUSTERSTATETYPE_CREATING, CreatedAt: &created},
+			want:    []v1alpha1.PendingOperation{{Type: v1alpha1.OperationCreate, StartTime: metav1.NewTime(created)}},
+		},
+		"Upgrading": {
+			reason:  "A running upgrade should be reported as started at the last update of the cluster.",
+			cluster: &cockroachdb.Cluster{State: cockroachdb.CLUSTERSTATETYPE_CREATED, OperationStatus: cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_UPGRADE_RUNNING, UpdatedAt: &updated},
+			want:    []v1alpha1.PendingOperation{{Type: v1alpha1.OperationUpgrade, StartTime: metav1.NewTime(updated)}},
+		},
+		"StillEditing": {
+			reason:   "The start time of an operation that was already pending should be preserved.",
+			previous: []v1alpha1.PendingOperation{{Type: v1alpha1.OperationEdit, StartTime: observed}},
+			cluster:  &cockroachdb.Cluster{State: cockroachdb.CLUSTERSTATETYPE_CREATED, OperationStatus: cockroachdb.CLUSTERSTATUSTYPE_CRDB_EDIT_CLUSTER_RUNNING, UpdatedAt: &updated},
+			want:     []v1alpha1.PendingOperation{{Type: v1alpha1.OperationEdit, StartTime: observed}},
+		},
+		"Failed": {
+			reason:   "A failed operation should no longer be pending.",
+			previous: []v1alpha1.PendingOperation{{Type: v1alpha1.OperationScale, StartTime: observed}},
+			cluster:  &cockroachdb.Cluster{State: cockroachdb.CLUSTERSTATETYPE_CREATED, OperationStatus: cockroachdb.CLUSTERSTATUSTYPE_CRDB_SCALE_FAILED},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := pendingOperations(tc.previous, tc.cluster)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npendingOperations(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
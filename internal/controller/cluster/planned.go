@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// plannedChanges returns the changes the next update of the supplied Cluster
+// intends to make, so that they can be reviewed before they are applied.
+func plannedChanges(cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, diff specDiff, missing, unpublished []v1alpha1.ClusterSQLUser, d allowlistDiff, rolesChanged, defaultsChanged bool) []v1alpha1.PlannedChange {
+	var changes []v1alpha1.PlannedChange
+
+	if diff == specInPlan && cr.Spec.ForProvider.Serverless != nil {
+		c := v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.serverless.spendLimit",
+			Action: v1alpha1.PlannedActionUpdate,
+			To:     strconv.Itoa(int(*cr.Spec.ForProvider.Serverless.SpendLimit)),
+		}
+		if s := cluster.Config.Serverless; s != nil {
+			c.From = strconv.Itoa(int(s.SpendLimit))
+		}
+		changes = append(changes, c)
+	}
+	if diff == specInPlan && cr.Spec.ForProvider.Dedicated != nil {
+		changes = append(changes, hardwareChanges(cr, cluster.Config.Dedicated)...)
+		changes = append(changes, regionNodeChanges(cr, cluster.Regions)...)
+	}
+
+	for _, u := range missing {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.sqlUsers", Action: v1alpha1.PlannedActionCreate, Target: u.Name})
+	}
+	for _, u := range unpublished {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.sqlUsers.writeConnectionSecretToRef", Action: v1alpha1.PlannedActionCreate, Target: u.Name})
+	}
+
+	for _, e := range d.add {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.allowlist", Action: v1alpha1.PlannedActionCreate, Target: allowlistKey(e)})
+	}
+	for _, e := range d.update {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.allowlist", Action: v1alpha1.PlannedActionUpdate, Target: allowlistKey(e)})
+	}
+	for _, e := range d.remove {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.allowlist", Action: v1alpha1.PlannedActionDelete, Target: allowlistKey(e)})
+	}
+
+	if credentialsUserMissing(cr) && cluster.State == cockroachdb.CLUSTERSTATETYPE_CREATED {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.credentials.username", Action: v1alpha1.PlannedActionCreate, Target: cr.Spec.ForProvider.Credentials.Username})
+	}
+	if usernameChanged(cr) {
+		changes = append(changes, v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.credentials.username",
+			Action: v1alpha1.PlannedActionUpdate,
+			From:   cr.Status.AtProvider.Username,
+			To:     cr.Spec.ForProvider.Credentials.Username,
+		})
+	}
+	if previousUserExpired(cr, time.Now()) {
+		changes = append(changes, v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.credentials.username",
+			Action: v1alpha1.PlannedActionDelete,
+			Target: cr.Status.AtProvider.PreviousUsername,
+		})
+	}
+
+	if rolesChanged && cr.Spec.ForProvider.Credentials != nil {
+		changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.credentials", Action: v1alpha1.PlannedActionUpdate, Target: cr.Spec.ForProvider.Credentials.Username})
+	}
+
+	if defaultsChanged {
+		for _, u := range cr.Spec.ForProvider.SQLUsers {
+			changes = append(changes, v1alpha1.PlannedChange{Field: "spec.forProvider.sqlUsers.sessionDefaults", Action: v1alpha1.PlannedActionUpdate, Target: u.Name})
+		}
+	}
+
+	return changes
+}
+
+// hardwareChanges returns the changes to the supplied dedicated hardware the
+// next update intends to make.
+func hardwareChanges(cr *v1alpha1.Cluster, hw *cockroachdb.DedicatedHardwareConfig) []v1alpha1.PlannedChange {
+	u := cr.HardwareUpdate(hw)
+	if u == nil {
+		return nil
+	}
+	if hw == nil {
+		hw = &cockroachdb.DedicatedHardwareConfig{}
+	}
+	var changes []v1alpha1.PlannedChange
+	if ms := u.MachineSpec; ms != nil && ms.MachineType != nil {
+		changes = append(changes, v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.dedicated.machineType",
+			Action: v1alpha1.PlannedActionUpdate,
+			From:   hw.MachineType,
+			To:     *ms.MachineType,
+		})
+	}
+	if ms := u.MachineSpec; ms != nil && ms.NumVirtualCpus != nil {
+		changes = append(changes, v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.dedicated.numVirtualCPUs",
+			Action: v1alpha1.PlannedActionUpdate,
+			From:   strconv.Itoa(int(hw.NumVirtualCpus)),
+			To:     strconv.Itoa(int(*ms.NumVirtualCpus)),
+		})
+	}
+	if u.StorageGib != nil {
+		changes = append(changes, v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.dedicated.storageGiB",
+			Action: v1alpha1.PlannedActionUpdate,
+			From:   strconv.Itoa(int(hw.StorageGib)),
+			To:     strconv.Itoa(int(*u.StorageGib)),
+		})
+	}
+	if u.DiskIops != nil {
+		changes = append(changes, v1alpha1.PlannedChange{
+			Field:  "spec.forProvider.dedicated.diskIOPS",
+			Action: v1alpha1.PlannedActionUpdate,
+			From:   strconv.Itoa(int(hw.DiskIops)),
+			To:     strconv.Itoa(int(*u.DiskIops)),
+		})
+	}
+	return changes
+}
+
+// regionNodeChanges returns the changes to the number of nodes of the
+// supplied regions the next update intends to make, in region order.
+func regionNodeChanges(cr *v1alpha1.Cluster, regions []cockroachdb.Region) []v1alpha1.PlannedChange {
+	u := cr.RegionNodesUpdate(regions)
+	if u == nil {
+		return nil
+	}
+	want := *u
+	have := make(map[string]int32, len(regions))
+	for _, r := range regions {
+		have[r.Name] = r.NodeCount
+	}
+	names := make([]string, 0, len(want)+len(have))
+	for r := range want {
+		names = append(names, r)
+	}
+	for r := range have {
+		if _, ok := want[r]; !ok {
+			names = append(names, r)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []v1alpha1.PlannedChange
+	for _, r := range names {
+		from, observed := have[r]
+		to, desired := want[r]
+		c := v1alpha1.PlannedChange{Field: "spec.forProvider.dedicated.regionNodes", Target: r}
+		switch {
+		case !observed:
+			c.Action = v1alpha1.PlannedActionCreate
+			c.To = strconv.Itoa(int(to))
+		case !desired:
+			c.Action = v1alpha1.PlannedActionDelete
+			c.From = strconv.Itoa(int(from))
+		case from != to:
+			c.Action = v1alpha1.PlannedActionUpdate
+			c.From = strconv.Itoa(int(from))
+			c.To = strconv.Itoa(int(to))
+		default:
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
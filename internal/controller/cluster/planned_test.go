@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestPlannedChanges(t *testing.T) {
+	observed := &cockroachdb.Cluster{Config: cockroachdb.ClusterConfig{Serverless: &cockroachdb.ServerlessClusterConfig{SpendLimit: 100}}}
+
+	type args struct {
+		diff         specDiff
+		missing      []v1alpha1.ClusterSQLUser
+		unpublished  []v1alpha1.ClusterSQLUser
+		d            allowlistDiff
+		rolesChanged bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []v1alpha1.PlannedChange
+	}{
+		"UpToDate": {
+			reason: "No changes should be planned for an up to date Cluster.",
+			args:   args{diff: specUpToDate},
+		},
+		"SpendLimit": {
+			reason: "A spend limit change should be planned from the observed to the desired value.",
+			args:   args{diff: specInPlan},
+			want: []v1alpha1.PlannedChange{
+				{Field: "spec.forProvider.serverless.spendLimit", Action: v1alpha1.PlannedActionUpdate, From: "100", To: "0"},
+			},
+		},
+		"Dependents": {
+			reason: "Missing SQL users, allowlist entries and role changes should each be planned.",
+			args: args{
+				diff:        specUpToDate,
+				missing:     []v1alpha1.ClusterSQLUser{{Name: "app"}},
+				unpublished: []v1alpha1.ClusterSQLUser{{Name: "reporting"}},
+				d: allowlistDiff{
+					add:    []cockroachdb.AllowlistEntry{{CidrIp: "10.0.0.0", CidrMask: 8}},
+					remove: []cockroachdb.AllowlistEntry{{CidrIp: "192.168.0.0", CidrMask: 16}},
+				},
+				rolesChanged: true,
+			},
+			want: []v1alpha1.PlannedChange{
+				{Field: "spec.forProvider.sqlUsers", Action: v1alpha1.PlannedActionCreate, Target: "app"},
+				{Field: "spec.forProvider.sqlUsers.writeConnectionSecretToRef", Action: v1alpha1.PlannedActionCreate, Target: "reporting"},
+				{Field: "spec.forProvider.allowlist", Action: v1alpha1.PlannedActionCreate, Target: "10.0.0.0/8"},
+				{Field: "spec.forProvider.allowlist", Action: v1alpha1.PlannedActionDelete, Target: "192.168.0.0/16"},
+				{Field: "spec.forProvider.credentials", Action: v1alpha1.PlannedActionUpdate, Target: "cool"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := plannedChanges(cluster(), observed, tc.args.diff, tc.args.missing, tc.args.unpublished, tc.args.d, tc.args.rolesChanged, false)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nplannedChanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHardwareChanges(t *testing.T) {
+	dedicated := func(d v1alpha1.DedicatedCluster) *v1alpha1.Cluster {
+		return cluster(func(cr *v1alpha1.Cluster) {
+			cr.Spec.ForProvider.Serverless = nil
+			d.RegionNodes = map[string]int32{"eu-west-1": 3}
+			cr.Spec.ForProvider.Dedicated = &d
+		})
+	}
+	observed := &cockroachdb.DedicatedHardwareConfig{MachineType: "m5.large", NumVirtualCpus: 2, StorageGib: 150, DiskIops: 450}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.Cluster
+		hw     *cockroachdb.DedicatedHardwareConfig
+		want   []v1alpha1.PlannedChange
+	}{
+		"UpToDate": {
+			reason: "No changes should be planned for hardware matching the spec.",
+			cr:     dedicated(v1alpha1.DedicatedCluster{MachineType: "m5.large", StorageGiB: 150}),
+			hw:     observed,
+		},
+		"MachineTypeAndStorage": {
+			reason: "Drifted machine type and storage should each be planned.",
+			cr:     dedicated(v1alpha1.DedicatedCluster{MachineType: "m5.xlarge", StorageGiB: 300}),
+			hw:     observed,
+			want: []v1alpha1.PlannedChange{
+				{Field: "spec.forProvider.dedicated.machineType", Action: v1alpha1.PlannedActionUpdate, From: "m5.large", To: "m5.xlarge"},
+				{Field: "spec.forProvider.dedicated.storageGiB", Action: v1alpha1.PlannedActionUpdate, From: "150", To: "300"},
+			},
+		},
+		"StorageDecrease": {
+			reason: "Storage cannot be decreased, so less storage than observed should not be planned.",
+			cr:     dedicated(v1alpha1.DedicatedCluster{MachineType: "m5.large", StorageGiB: 100}),
+			hw:     observed,
+		},
+		"VirtualCPUsAndIOPS": {
+			reason: "Drifted virtual CPUs and disk IOPS should each be planned.",
+			cr:     dedicated(v1alpha1.DedicatedCluster{NumVirtualCPUs: 4, DiskIOPS: 900}),
+			hw:     observed,
+			want: []v1alpha1.PlannedChange{
+				{Field: "spec.forProvider.dedicated.numVirtualCPUs", Action: v1alpha1.PlannedActionUpdate, From: "2", To: "4"},
+				{Field: "spec.forProvider.dedicated.diskIOPS", Action: v1alpha1.PlannedActionUpdate, From: "450", To: "900"},
+			},
+		},
+		"NotObserved": {
+			reason: "Hardware that is not reported yet should be planned from its zero value.",
+			cr:     dedicated(v1alpha1.DedicatedCluster{MachineType: "m5.large"}),
+			want: []v1alpha1.PlannedChange{
+				{Field: "spec.forProvider.dedicated.machineType", Action: v1alpha1.PlannedActionUpdate, To: "m5.large"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := hardwareChanges(tc.cr, tc.hw)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nhardwareChanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRegionNodeChanges(t *testing.T) {
+	cr := cluster(func(cr *v1alpha1.Cluster) {
+		cr.Spec.ForProvider.Serverless = nil
+		cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{
+			RegionNodes: map[string]int32{"eu-west-1": 5, "us-east-1": 3},
+			MachineType: "m5.large",
+		}
+	})
+
+	cases := map[string]struct {
+		reason  string
+		regions []cockroachdb.Region
+		want    []v1alpha1.PlannedChange
+	}{
+		"UpToDate": {
+			reason:  "No changes should be planned for regions running the desired number of nodes.",
+			regions: []cockroachdb.Region{{Name: "eu-west-1", NodeCount: 5}, {Name: "us-east-1", NodeCount: 3}},
+		},
+		"Scale": {
+			reason:  "Added, scaled and removed regions should each be planned in region order.",
+			regions: []cockroachdb.Region{{Name: "ap-south-1", NodeCount: 3}, {Name: "eu-west-1", NodeCount: 3}},
+			want: []v1alpha1.PlannedChange{
+				{Field: "spec.forProvider.dedicated.regionNodes", Action: v1alpha1.PlannedActionDelete, Target: "ap-south-1", From: "3"},
+				{Field: "spec.forProvider.dedicated.regionNodes", Action: v1alpha1.PlannedActionUpdate, Target: "eu-west-1", From: "3", To: "5"},
+				{Field: "spec.forProvider.dedicated.regionNodes", Action: v1alpha1.PlannedActionCreate, Target: "us-east-1", To: "3"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := regionNodeChanges(cr, tc.regions)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nregionNodeChanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+)
+
+const (
+	errFmtMaxServerlessClusters = "the organization already has %d active serverless clusters, the maximum allowed by the ProviderConfig"
+	errFmtQuotaRejected         = "the CockroachDB Cloud API rejected the cluster because an organization limit was reached: %s"
+
+	// codeResourceExhausted is the gRPC status code the Cloud API reports
+	// when a limit is reached.
+	codeResourceExhausted = 8
+)
+
+// quotaExceeded returns a message describing why creating the supplied
+// Cluster would exceed a limit of the organization, if it would.
+func (c *external) quotaExceeded(ctx context.Context, cr *v1alpha1.Cluster) (string, error) {
+	l := c.service.Limits
+	if l == nil || l.MaxServerlessClusters == nil || cr.Plan() != cockroachdb.PLAN_SERVERLESS {
+		return "", nil
+	}
+
+	clusters, err := listClusters(ctx, c.service.CRDBClient)
+	if err != nil {
+		return "", err
+	}
+	n := int32(0)
+	for _, cl := range clusters {
+		if cl.Plan == cockroachdb.PLAN_SERVERLESS {
+			n++
+		}
+	}
+	if n >= *l.MaxServerlessClusters {
+		return fmt.Sprintf(errFmtMaxServerlessClusters, n), nil
+	}
+	return "", nil
+}
+
+// quotaRejection returns a message describing the organization limit that
+// caused the Cloud API to reject a request, if any. Only exhausted resources
+// are limits of the organization: other responses with status 429 Too Many
+// Requests are rate limits, which are retried.
+func quotaRejection(err error) string {
+	var apiErr cockroachdb.Error
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	code, msg := apiStatus(apiErr)
+	if code != codeResourceExhausted {
+		return ""
+	}
+	if msg == "" {
+		msg = apiErr.Error()
+	}
+	// The message is surfaced in the QuotaExceeded condition.
+	return fmt.Sprintf(errFmtQuotaRejected, redact.String(msg))
+}
+
+// apiStatus returns the status code and message of the supplied Cloud API
+// error. Depending on the response status the SDK decodes the error either
+// as a Status or as a generic map.
+func apiStatus(err cockroachdb.Error) (int32, string) {
+	switch m := err.Model().(type) {
+	case cockroachdb.Status:
+		return m.GetCode(), m.GetMessage()
+	case map[string]interface{}:
+		code, _ := m["code"].(float64)
+		msg, _ := m["message"].(string)
+		return int32(code), msg
+	}
+	return 0, ""
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+func TestQuotaExceeded(t *testing.T) {
+	errBoom := errors.New("boom")
+	maxClusters := int32(2)
+
+	list := func(plans ...cockroachdb.Plan) func(context.Context, *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error) {
+		return func(_ context.Context, _ *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error) {
+			res := &cockroachdb.ListClustersResponse{}
+			for _, p := range plans {
+				res.Clusters = append(res.Clusters, cockroachdb.Cluster{Id: testClusterID, Plan: p})
+			}
+			return res, &http.Response{StatusCode: http.StatusOK}, nil
+		}
+	}
+
+	type want struct {
+		msg string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service *cloud.Service
+		cr      *v1alpha1.Cluster
+		want    want
+	}{
+		"NoLimits": {
+			reason:  "Clusters should not be listed if the ProviderConfig sets no limits.",
+			service: &cloud.Service{CRDBClient: &mockService{}},
+			cr:      cluster(),
+		},
+		"Dedicated": {
+			reason: "The limit of serverless clusters should not apply to dedicated clusters.",
+			service: &cloud.Service{
+				CRDBClient: &mockService{},
+				Limits:     &apisv1alpha1.ProviderLimits{MaxServerlessClusters: &maxClusters},
+			},
+			cr: cluster(func(cr *v1alpha1.Cluster) {
+				cr.Spec.ForProvider.Serverless = nil
+				cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{}
+			}),
+		},
+		"BelowLimit": {
+			reason: "A serverless cluster should be created if the organization has fewer serverless clusters than allowed. Dedicated clusters do not count.",
+			service: &cloud.Service{
+				CRDBClient: &mockService{MockListClusters: list(cockroachdb.PLAN_SERVERLESS, cockroachdb.PLAN_DEDICATED, cockroachdb.PLAN_DEDICATED)},
+				Limits:     &apisv1alpha1.ProviderLimits{MaxServerlessClusters: &maxClusters},
+			},
+			cr: cluster(),
+		},
+		"AtLimit": {
+			reason: "A serverless cluster should not be created if the organization has as many serverless clusters as allowed.",
+			service: &cloud.Service{
+				CRDBClient: &mockService{MockListClusters: list(cockroachdb.PLAN_SERVERLESS, cockroachdb.PLAN_SERVERLESS)},
+				Limits:     &apisv1alpha1.ProviderLimits{MaxServerlessClusters: &maxClusters},
+			},
+			cr:   cluster(),
+			want: want{msg: fmt.Sprintf(errFmtMaxServerlessClusters, 2)},
+		},
+		"ListError": {
+			reason: "Errors listing clusters should be returned.",
+			service: &cloud.Service{
+				CRDBClient: &mockService{
+					MockListClusters: func(_ context.Context, _ *cockroachdb.ListClustersOptions) (*cockroachdb.ListClustersResponse, *http.Response, error) {
+						return nil, nil, errBoom
+					},
+				},
+				Limits: &apisv1alpha1.ProviderLimits{MaxServerlessClusters: &maxClusters},
+			},
+			cr:   cluster(),
+			want: want{err: errors.Wrap(errBoom, errListClusters)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{service: tc.service}
+			msg, err := e.quotaExceeded(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.quotaExceeded(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msg, msg); diff != "" {
+				t.Errorf("\n%s\ne.quotaExceeded(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQuotaRejection(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"NotCloudAPIError": {
+			reason: "Errors not returned by the Cloud API should not be reported as a rejection.",
+			err:    errors.New("boom"),
+		},
+		"ResourceExhausted": {
+			reason: "Exhausted resources should be reported as a rejection.",
+			err:    errors.Wrap(cloudAPIError(t, http.StatusTooManyRequests, codeResourceExhausted, "too many clusters"), "cannot create cluster"),
+			want:   fmt.Sprintf(errFmtQuotaRejected, "too many clusters"),
+		},
+		"RateLimited": {
+			reason: "Requests that are rate limited should not be reported as a rejection.",
+			err:    cloudAPIError(t, http.StatusTooManyRequests, 0, "rate limit exceeded"),
+		},
+		"OtherError": {
+			reason: "Other Cloud API errors should not be reported as a rejection.",
+			err:    cloudAPIError(t, http.StatusBadRequest, codeInvalidArgument, "spend limit must be positive"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := quotaRejection(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nquotaRejection(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"regexp"
+	"strings"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+// regionName matches region names following either the AWS (us-east-1) or
+// the GCP (us-east1) naming convention.
+var regionName = regexp.MustCompile(`^([a-z]+)-([a-z]+)-?([0-9]+)$`)
+
+// regionGeographies maps the geography prefixes of one cloud provider's
+// region names to those of the supplied cloud provider, e.g. the AWS eu-west-1
+// convention to the GCP europe-west1 convention.
+var regionGeographies = map[cockroachdb.ApiCloudProvider]map[string]string{
+	cockroachdb.APICLOUDPROVIDER_AWS: {
+		"europe":       "eu",
+		"asia":         "ap",
+		"southamerica": "sa",
+	},
+	cockroachdb.APICLOUDPROVIDER_GCP: {
+		"eu": "europe",
+		"ap": "asia",
+		"sa": "southamerica",
+	},
+}
+
+// normalizeRegion returns the name the supplied cloud provider uses for the
+// supplied region, accepting the naming convention of the other provider,
+// e.g. us-east1 for us-east-1 on AWS. Regions that do not follow either
+// convention are returned in lower case. Azure region names share neither
+// convention, so only their display names are accepted, e.g. East US 2 for
+// eastus2.
+func normalizeRegion(provider cockroachdb.ApiCloudProvider, region string) string {
+	r := strings.ToLower(strings.TrimSpace(region))
+	if provider == cockroachcloud.CloudProviderAzure {
+		return strings.ReplaceAll(r, " ", "")
+	}
+	m := regionName.FindStringSubmatch(r)
+	if m == nil {
+		return r
+	}
+	geo, direction, n := m[1], m[2], m[3]
+	if g, ok := regionGeographies[provider][geo]; ok {
+		geo = g
+	}
+	switch provider {
+	case cockroachdb.APICLOUDPROVIDER_AWS:
+		return geo + "-" + direction + "-" + n
+	case cockroachdb.APICLOUDPROVIDER_GCP:
+		return geo + "-" + direction + n
+	default:
+		return r
+	}
+}
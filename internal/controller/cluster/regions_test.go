@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+func TestNormalizeRegion(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		provider cockroachdb.ApiCloudProvider
+		region   string
+		want     string
+	}{
+		"AWS": {
+			reason:   "AWS region names should be left untouched.",
+			provider: cockroachdb.APICLOUDPROVIDER_AWS,
+			region:   "us-east-1",
+			want:     "us-east-1",
+		},
+		"GCPNameOnAWS": {
+			reason:   "GCP style region names should be converted to the AWS convention.",
+			provider: cockroachdb.APICLOUDPROVIDER_AWS,
+			region:   "us-east1",
+			want:     "us-east-1",
+		},
+		"GCPGeographyOnAWS": {
+			reason:   "GCP geography prefixes should be converted to their AWS equivalent.",
+			provider: cockroachdb.APICLOUDPROVIDER_AWS,
+			region:   "europe-west1",
+			want:     "eu-west-1",
+		},
+		"GCP": {
+			reason:   "GCP region names should be left untouched.",
+			provider: cockroachdb.APICLOUDPROVIDER_GCP,
+			region:   "northamerica-northeast1",
+			want:     "northamerica-northeast1",
+		},
+		"AWSNameOnGCP": {
+			reason:   "AWS style region names should be converted to the GCP convention.",
+			provider: cockroachdb.APICLOUDPROVIDER_GCP,
+			region:   "us-central-1",
+			want:     "us-central1",
+		},
+		"AWSGeographyOnGCP": {
+			reason:   "AWS geography prefixes should be converted to their GCP equivalent.",
+			provider: cockroachdb.APICLOUDPROVIDER_GCP,
+			region:   "ap-southeast-1",
+			want:     "asia-southeast1",
+		},
+		"Azure": {
+			reason:   "Azure region names should be left untouched.",
+			provider: cockroachcloud.CloudProviderAzure,
+			region:   "eastus2",
+			want:     "eastus2",
+		},
+		"AzureDisplayName": {
+			reason:   "Azure display names should be converted to region names.",
+			provider: cockroachcloud.CloudProviderAzure,
+			region:   "West Europe",
+			want:     "westeurope",
+		},
+		"Unknown": {
+			reason:   "Region names following neither convention should only be lower cased.",
+			provider: cockroachdb.APICLOUDPROVIDER_GCP,
+			region:   " Local ",
+			want:     "local",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := normalizeRegion(tc.provider, tc.region)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nnormalizeRegion(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
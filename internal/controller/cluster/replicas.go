@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// LabelKeyReplicaOf is set on replicas of connection secrets. Its value is
+// the name of the Cluster whose connection secret is replicated.
+const LabelKeyReplicaOf = "database.cockroachdb.crossplane.io/replica-of"
+
+const (
+	errGetConnectionSecret = "cannot get connection secret"
+	errApplyReplica        = "cannot apply connection secret replica"
+	errListReplicas        = "cannot list connection secret replicas"
+	errDeleteReplica       = "cannot delete connection secret replica"
+)
+
+// A replicaPublisher mirrors the connection secret of a Cluster to the
+// namespaces listed in its connectionSecretReplicaNamespaces. It must be
+// called after the connection secret itself has been published, as it copies
+// its data rather than the connection details it is supplied, which are
+// incomplete when the Cluster is only observed.
+type replicaPublisher struct {
+	kube client.Client
+}
+
+func (p *replicaPublisher) PublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+	cr, ok := so.(*v1alpha1.Cluster)
+	if !ok {
+		return false, nil
+	}
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return false, p.prune(ctx, cr, nil)
+	}
+
+	src := &corev1.Secret{}
+	if err := p.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, src); err != nil {
+		return false, errors.Wrap(resource.IgnoreNotFound(err), errGetConnectionSecret)
+	}
+
+	published := false
+	want := map[string]bool{}
+	for _, ns := range cr.Spec.ConnectionSecretReplicaNamespaces {
+		if ns == ref.Namespace {
+			continue
+		}
+		want[ns] = true
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            ref.Name,
+				Namespace:       ns,
+				Labels:          map[string]string{LabelKeyReplicaOf: cr.GetName()},
+				OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.ClusterGroupVersionKind))},
+			},
+			Type: src.Type,
+			Data: src.Data,
+		}
+		err := resource.NewAPIPatchingApplicator(p.kube).Apply(ctx, s,
+			resource.MustBeControllableBy(cr.GetUID()),
+			resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
+				return !cmp.Equal(current.(*corev1.Secret).Data, desired.(*corev1.Secret).Data, cmpopts.EquateEmpty())
+			}),
+		)
+		if resource.IsNotAllowed(err) {
+			continue
+		}
+		if err != nil {
+			return false, errors.Wrap(err, errApplyReplica)
+		}
+		published = true
+	}
+	return published, p.prune(ctx, cr, want)
+}
+
+func (p *replicaPublisher) UnpublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, _ managed.ConnectionDetails) error {
+	cr, ok := so.(*v1alpha1.Cluster)
+	if !ok {
+		return nil
+	}
+	return p.prune(ctx, cr, nil)
+}
+
+// prune deletes the replicas of the connection secret of the supplied Cluster
+// that are not in one of the supplied namespaces.
+func (p *replicaPublisher) prune(ctx context.Context, cr *v1alpha1.Cluster, keep map[string]bool) error {
+	l := &corev1.SecretList{}
+	if err := p.kube.List(ctx, l, client.MatchingLabels{LabelKeyReplicaOf: cr.GetName()}); err != nil {
+		return errors.Wrap(err, errListReplicas)
+	}
+	for i := range l.Items {
+		s := &l.Items[i]
+		if keep[s.GetNamespace()] || !metav1.IsControlledBy(s, cr) {
+			continue
+		}
+		if err := p.kube.Delete(ctx, s); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteReplica)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestReplicaPublisher(t *testing.T) {
+	cr := cluster(func(cr *v1alpha1.Cluster) {
+		cr.SetUID("uid")
+		cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "conn", Namespace: "crossplane-system"})
+		cr.Spec.ConnectionSecretReplicaNamespaces = []string{"crossplane-system", "team-a"}
+	})
+	owner := []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.ClusterGroupVersionKind))}
+	data := map[string][]byte{"dsn": []byte("postgresql://")}
+
+	created := []string{}
+	deleted := []string{}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Namespace != "crossplane-system" {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+			}
+			obj.(*corev1.Secret).Data = data
+			return nil
+		},
+		MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			if diff := cmp.Diff(data, obj.(*corev1.Secret).Data); diff != "" {
+				t.Errorf("Create(...): -want data, +got data:\n%s\n", diff)
+			}
+			created = append(created, obj.GetNamespace())
+			return nil
+		},
+		MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*corev1.SecretList).Items = []corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "conn", Namespace: "team-a", OwnerReferences: owner}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "conn", Namespace: "team-b", OwnerReferences: owner}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "conn", Namespace: "team-c"}},
+			}
+			return nil
+		},
+		MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+			deleted = append(deleted, obj.GetNamespace())
+			return nil
+		},
+	}
+
+	p := &replicaPublisher{kube: kube}
+	published, err := p.PublishConnection(context.Background(), cr, nil)
+	if err != nil {
+		t.Fatalf("p.PublishConnection(...): %v", err)
+	}
+	if !published {
+		t.Errorf("p.PublishConnection(...): want published, got not published")
+	}
+	if diff := cmp.Diff([]string{"team-a"}, created); diff != "" {
+		t.Errorf("p.PublishConnection(...): -want replicas, +got replicas:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"team-b"}, deleted); diff != "" {
+		t.Errorf("p.PublishConnection(...): -want pruned replicas, +got pruned replicas:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errGetSourceCluster  = "cannot get source cluster of spec.forProvider.sourceBackupId"
+	errStartRestore      = "cannot start restore of spec.forProvider.sourceBackupId"
+	errGetRestore        = "cannot get restore of spec.forProvider.sourceBackupId"
+	errFmtSourceProvider = "source cluster %s runs on %s, not %s"
+	errFmtSourceRegions  = "source cluster %s runs in regions %s, not %s"
+	errFmtSourceVersion  = "source cluster %s runs CockroachDB %s, but the cluster runs %s"
+
+	msgRestorePending = "restoring spec.forProvider.sourceBackupId"
+	msgRestoreFailed  = "restore of spec.forProvider.sourceBackupId failed; delete the Cluster to create it again"
+)
+
+// A restoreState is the state of the restore of the source backup of a
+// Cluster.
+type restoreState int
+
+const (
+	// restoreNone means the Cluster has no source backup, or it was
+	// restored.
+	restoreNone restoreState = iota
+	// restoreNotStarted means the source backup is yet to be restored.
+	restoreNotStarted
+	// restoreRunning means the source backup is being restored.
+	restoreRunning
+	// restoreFailed means the source backup failed to be restored.
+	restoreFailed
+)
+
+// sourceVersion returns the major CockroachDB version of the source cluster
+// of the supplied Cluster, e.g. v22.1, which the Cluster is created with. It
+// returns an error if the source cluster cannot be restored into the
+// Cluster.
+func (c *external) sourceVersion(ctx context.Context, cr *v1alpha1.Cluster) (string, error) {
+	src, _, err := c.service.CRDBClient.GetCluster(ctx, cr.Spec.ForProvider.SourceClusterID)
+	if err != nil {
+		return "", errors.Wrap(err, errGetSourceCluster)
+	}
+	if err := sourceCompatible(cr, src); err != nil {
+		return "", err
+	}
+	return majorVersion(src.CockroachVersion), nil
+}
+
+// sourceCompatible returns an error unless the supplied source cluster runs
+// on the provider and in the regions of the supplied Cluster. A cluster
+// restore is rejected into a cluster with other localities.
+func sourceCompatible(cr *v1alpha1.Cluster, src *cockroachdb.Cluster) error {
+	p := cr.Spec.ForProvider
+	if src.CloudProvider != p.Provider {
+		return errors.Errorf(errFmtSourceProvider, src.Id, src.CloudProvider, p.Provider)
+	}
+	have := make([]string, len(src.Regions))
+	for i, r := range src.Regions {
+		have[i] = normalizeRegion(src.CloudProvider, r.Name)
+	}
+	want := make([]string, 0, len(cr.Regions()))
+	for _, r := range cr.Regions() {
+		want = append(want, normalizeRegion(p.Provider, r))
+	}
+	sort.Strings(have)
+	sort.Strings(want)
+	if strings.Join(have, ",") != strings.Join(want, ",") {
+		return errors.Errorf(errFmtSourceRegions, src.Id, strings.Join(have, ", "), strings.Join(want, ", "))
+	}
+	return nil
+}
+
+// majorVersion returns the major version of the supplied CockroachDB
+// version, e.g. v22.1 for v22.1.5.
+func majorVersion(v string) string {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// observeRestore fills the status of the restore of the source backup of the
+// supplied Cluster, and returns its state.
+func (c *external) observeRestore(ctx context.Context, cr *v1alpha1.Cluster, clusterID string) (restoreState, error) {
+	if cr.Spec.ForProvider.SourceBackupID == "" {
+		return restoreNone, nil
+	}
+	r := cr.Status.AtProvider.SourceRestore
+	if r == nil {
+		return restoreNotStarted, nil
+	}
+	switch cockroachcloud.RestoreStatus(r.Status) {
+	case cockroachcloud.RestoreStatusSuccess:
+		return restoreNone, nil
+	case cockroachcloud.RestoreStatusFailed, cockroachcloud.RestoreStatusCancelled:
+		return restoreFailed, nil
+	}
+
+	restore, err := c.service.CloudClient.GetRestore(ctx, clusterID, r.ID)
+	if err != nil {
+		return restoreRunning, errors.Wrap(err, errGetRestore)
+	}
+	cr.Status.AtProvider.SourceRestore = clusterRestore(restore)
+	switch restore.Status {
+	case cockroachcloud.RestoreStatusSuccess:
+		return restoreNone, nil
+	case cockroachcloud.RestoreStatusFailed, cockroachcloud.RestoreStatusCancelled:
+		return restoreFailed, nil
+	}
+	return restoreRunning, nil
+}
+
+// startRestore starts restoring the source backup of the supplied Cluster
+// into the supplied cluster. The restore is only started once: a cluster
+// restore is rejected by a cluster that holds data already.
+func (c *external) startRestore(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) error {
+	p := cr.Spec.ForProvider
+	src, _, err := c.service.CRDBClient.GetCluster(ctx, p.SourceClusterID)
+	if err != nil {
+		return errors.Wrap(err, errGetSourceCluster)
+	}
+	if have, want := majorVersion(cluster.CockroachVersion), majorVersion(src.CockroachVersion); have != want {
+		return errors.Errorf(errFmtSourceVersion, src.Id, src.CockroachVersion, cluster.CockroachVersion)
+	}
+	restore, err := c.service.CloudClient.CreateRestore(ctx, cluster.Id, cockroachcloud.RestoreRequest{
+		SourceClusterID: p.SourceClusterID,
+		BackupID:        p.SourceBackupID,
+		Type:            cockroachcloud.RestoreTypeCluster,
+	})
+	if err != nil {
+		return errors.Wrap(err, errStartRestore)
+	}
+	cr.Status.AtProvider.SourceRestore = clusterRestore(restore)
+	return nil
+}
+
+// restoreCondition returns the condition of a Cluster whose source backup is
+// in the supplied state. The reason a restore failed is reported in
+// status.atProvider.sourceRestore.
+func restoreCondition(s restoreState) xpv1.Condition {
+	if s == restoreFailed {
+		return xpv1.Unavailable().WithMessage(msgRestoreFailed)
+	}
+	return xpv1.Creating().WithMessage(msgRestorePending)
+}
+
+func clusterRestore(r *cockroachcloud.Restore) *v1alpha1.ClusterRestore {
+	return &v1alpha1.ClusterRestore{ID: r.ID, Status: string(r.Status), Message: r.ClientErrorMessage}
+}
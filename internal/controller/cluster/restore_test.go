@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+func withSourceBackup(cr *v1alpha1.Cluster) {
+	cr.Spec.ForProvider.Serverless = nil
+	cr.Spec.ForProvider.Dedicated = &v1alpha1.DedicatedCluster{RegionNodes: map[string]int32{"us-east-1": 3}}
+	cr.Spec.ForProvider.SourceClusterID = "source"
+	cr.Spec.ForProvider.SourceBackupID = "backup"
+}
+
+func TestSourceCompatible(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		src    *cockroachdb.Cluster
+		want   error
+	}{
+		"Compatible": {
+			reason: "A source cluster on the same provider, in the same regions, should be compatible, whatever their naming convention.",
+			src:    &cockroachdb.Cluster{Id: "source", CloudProvider: cockroachdb.APICLOUDPROVIDER_AWS, Regions: []cockroachdb.Region{{Name: "us-east1"}}},
+		},
+		"OtherProvider": {
+			reason: "A source cluster on another provider should not be compatible.",
+			src:    &cockroachdb.Cluster{Id: "source", CloudProvider: cockroachdb.APICLOUDPROVIDER_GCP, Regions: []cockroachdb.Region{{Name: "us-east1"}}},
+			want:   errors.New("source cluster source runs on GCP, not AWS"),
+		},
+		"OtherRegions": {
+			reason: "A source cluster in other regions should not be compatible.",
+			src:    &cockroachdb.Cluster{Id: "source", CloudProvider: cockroachdb.APICLOUDPROVIDER_AWS, Regions: []cockroachdb.Region{{Name: "us-east-1"}, {Name: "us-west-2"}}},
+			want:   errors.New("source cluster source runs in regions us-east-1, us-west-2, not us-east-1"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := sourceCompatible(cluster(withSourceBackup), tc.src)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nsourceCompatible(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMajorVersion(t *testing.T) {
+	cases := map[string]struct {
+		v    string
+		want string
+	}{
+		"Patch":   {v: "v22.1.5", want: "v22.1"},
+		"Major":   {v: "v22.1", want: "v22.1"},
+		"Unknown": {v: "latest", want: "latest"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := majorVersion(tc.v); got != tc.want {
+				t.Errorf("majorVersion(%q): want %q, got %q", tc.v, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestObserveRestore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"restore","status":"FAILED","client_error_message":"backup not found"}`))
+	}))
+	defer srv.Close()
+	cloudClient, _ := cockroachcloud.NewClient("key", cockroachcloud.WithBaseURL(srv.URL))
+
+	type want struct {
+		s       restoreState
+		restore *v1alpha1.ClusterRestore
+	}
+
+	cases := map[string]struct {
+		reason  string
+		m       []clusterModifier
+		restore *v1alpha1.ClusterRestore
+		want    want
+	}{
+		"NoSourceBackup": {
+			reason: "A Cluster without a source backup should have nothing to restore.",
+		},
+		"NotStarted": {
+			reason: "The source backup of a Cluster should be restored if no restore was started yet.",
+			m:      []clusterModifier{withSourceBackup},
+			want:   want{s: restoreNotStarted},
+		},
+		"Succeeded": {
+			reason:  "A restore that succeeded should not be observed again.",
+			m:       []clusterModifier{withSourceBackup},
+			restore: &v1alpha1.ClusterRestore{ID: "restore", Status: "SUCCESS"},
+			want:    want{s: restoreNone, restore: &v1alpha1.ClusterRestore{ID: "restore", Status: "SUCCESS"}},
+		},
+		"Failed": {
+			reason:  "A restore that failed should be reported with its reason.",
+			m:       []clusterModifier{withSourceBackup},
+			restore: &v1alpha1.ClusterRestore{ID: "restore", Status: "PENDING"},
+			want:    want{s: restoreFailed, restore: &v1alpha1.ClusterRestore{ID: "restore", Status: "FAILED", Message: "backup not found"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := cluster(tc.m...)
+			cr.Status.AtProvider.SourceRestore = tc.restore
+			e := external{service: &cloud.Service{CloudClient: cloudClient}}
+			s, err := e.observeRestore(context.Background(), cr, testClusterID)
+			if err != nil {
+				t.Fatalf("\n%s\ne.observeRestore(...): %v", tc.reason, err)
+			}
+			if s != tc.want.s {
+				t.Errorf("\n%s\ne.observeRestore(...): want state %d, got %d", tc.reason, tc.want.s, s)
+			}
+			if diff := cmp.Diff(tc.want.restore, cr.Status.AtProvider.SourceRestore); diff != "" {
+				t.Errorf("\n%s\ne.observeRestore(...): -want restore, +got restore:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/grant"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errApplyRoles       = "cannot apply roles of SQL user"
+	errObserveRoles     = "cannot observe roles of SQL user"
+	errCreateRoleAdmin  = "cannot create SQL user to apply roles with"
+	errDeleteRoleAdmin  = "cannot delete SQL user roles were applied with"
+	errGetUserPassword  = "cannot get password of SQL user"
+	errNoUserPassword   = "cannot determine password of SQL user: set passwordSecretRef or writeConnectionSecretToRef"
+	errGenerateRolePass = "cannot generate password of SQL user to apply roles with"
+)
+
+// roleAdminUser is the admin SQL user roles are applied with. It only exists
+// while roles are applied, so that no user ever changes its own roles.
+const roleAdminUser = "crossplane_role_admin"
+
+// roleOptions are the role options that can be requested for a SQL user, as
+// reported for users that have them. Each can be turned off by prefixing it
+// with NO.
+var roleOptions = []string{
+	"CANCELQUERY",
+	"CONTROLCHANGEFEED",
+	"CONTROLJOB",
+	"CREATEDB",
+	"CREATELOGIN",
+	"CREATEROLE",
+	"MODIFYCLUSTERSETTING",
+	"VIEWACTIVITY",
+	"VIEWACTIVITYREDACTED",
+	"VIEWCLUSTERSETTING",
+}
+
+// observedRoles are the admin membership, role options and database grants
+// of a SQL user.
+type observedRoles struct {
+	Admin bool
+	// Options that are turned on, e.g. CREATEDB.
+	Options map[string]bool
+	// Grants by database.
+	Grants map[string]grant.GrantedPrivileges
+}
+
+// execSQL runs the supplied statements against the cluster at the supplied
+// DSN, verifying its certificate with the supplied CA.
+func (c *external) execSQL(ctx context.Context, dsn string, ca []byte, stmts ...string) error {
+	return sqlclient.Exec(ctx, sqlclient.Config{DSN: dsn, CA: ca, TLS: c.service.TLSConfig}, stmts...)
+}
+
+// rolesHash returns a hash of the roles requested for the supplied
+// credentials, or an empty string if the defaults of the Cloud API apply.
+func rolesHash(c *v1alpha1.Credentials) string {
+	if c == nil || (c.Admin == nil || *c.Admin) && len(c.RoleOptions) == 0 && len(c.Grants) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(struct {
+		Admin       *bool
+		RoleOptions []v1alpha1.RoleOption
+		Grants      []v1alpha1.DatabaseGrant
+	}{c.Admin, c.RoleOptions, c.Grants})
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:8])
+}
+
+// managesRoles returns true if the roles of the user of the supplied Cluster
+// are kept in sync with its spec, i.e. if the spec requests roles other than
+// the defaults of the Cloud API, or did so when roles were last in sync.
+// Roles are left alone while the user of the credentials changes.
+func managesRoles(cr *v1alpha1.Cluster) bool {
+	c := cr.Spec.ForProvider.Credentials
+	if c == nil || credentialsUserMissing(cr) || usernameChanged(cr) {
+		return false
+	}
+	return rolesHash(c) != "" || cr.Status.AtProvider.RolesHash != ""
+}
+
+// rolesStatements returns the SQL statements that change the supplied
+// observed roles to those requested for the supplied credentials. Role
+// options and database privileges that are not requested are turned off and
+// revoked. Admin membership is changed last.
+func rolesStatements(c *v1alpha1.Credentials, o observedRoles) ([]string, error) {
+	user := pgx.Identifier{c.Username}.Sanitize()
+	stmts := []string{}
+	if opts := roleOptionChanges(c.RoleOptions, o.Options); len(opts) > 0 {
+		stmts = append(stmts, fmt.Sprintf("ALTER ROLE %s WITH %s", user, strings.Join(opts, " ")))
+	}
+
+	desired := map[string][]string{}
+	for _, g := range c.Grants {
+		for _, p := range g.Privileges {
+			desired[g.Database] = append(desired[g.Database], string(p))
+		}
+	}
+	dbs := make([]string, 0, len(desired)+len(o.Grants))
+	for db := range desired {
+		dbs = append(dbs, db)
+	}
+	for db := range o.Grants {
+		if _, ok := desired[db]; !ok {
+			dbs = append(dbs, db)
+		}
+	}
+	sort.Strings(dbs)
+	for _, db := range dbs {
+		s, err := grant.PrivilegeStatements(desired[db], false, o.Grants[db], "DATABASE "+pgx.Identifier{db}.Sanitize(), user)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s...)
+	}
+
+	admin := c.Admin == nil || *c.Admin
+	switch {
+	case admin && !o.Admin:
+		stmts = append(stmts, fmt.Sprintf("GRANT admin TO %s", user))
+	case !admin && o.Admin:
+		stmts = append(stmts, fmt.Sprintf("REVOKE admin FROM %s", user))
+	}
+	return stmts, nil
+}
+
+// roleOptionChanges returns the role options that change the supplied
+// observed options to the requested ones. Options that are not requested are
+// turned off.
+func roleOptionChanges(requested []v1alpha1.RoleOption, observed map[string]bool) []string {
+	desired := map[string]bool{}
+	for _, r := range requested {
+		o := string(r)
+		if strings.HasPrefix(o, "NO") {
+			desired[strings.TrimPrefix(o, "NO")] = false
+			continue
+		}
+		desired[o] = true
+	}
+	changes := []string{}
+	for _, o := range roleOptions {
+		switch {
+		case desired[o] && !observed[o]:
+			changes = append(changes, o)
+		case !desired[o] && observed[o]:
+			changes = append(changes, "NO"+o)
+		}
+	}
+	return changes
+}
+
+// parseRoleOptions returns the known role options in the supplied options
+// column of SHOW ROLES, which is either a comma separated list or an array,
+// e.g. {CREATEDB,VIEWACTIVITY}. Options with a value, e.g. VALID UNTIL, are
+// ignored.
+func parseRoleOptions(s string) map[string]bool {
+	known := map[string]bool{}
+	for _, o := range roleOptions {
+		known[o] = true
+	}
+	opts := map[string]bool{}
+	for _, o := range strings.Split(strings.Trim(s, "{}"), ",") {
+		o = strings.ToUpper(strings.Trim(strings.TrimSpace(o), `"`))
+		if known[o] {
+			opts[o] = true
+		}
+	}
+	return opts
+}
+
+// observeRoles returns the SQL statements that change the roles of the user
+// of the supplied Cluster to those requested in its spec. Roles are read as
+// the user itself.
+func (c *external) observeRoles(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) ([]string, error) {
+	creds := cr.Spec.ForProvider.Credentials
+	pwd, err := c.userPassword(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := c.clusterCACert(ctx, cr, cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClusterCA)
+	}
+	db, err := sqlclient.Connect(ctx, sqlclient.Config{DSN: cloud.DSN(creds.Username, pwd, cluster), CA: ca, TLS: c.service.TLSConfig})
+	if err != nil {
+		return nil, errors.Wrap(err, errObserveRoles)
+	}
+	defer db.Close(ctx) //nolint:errcheck
+
+	o, err := queryRoles(ctx, db, creds.Username)
+	if err != nil {
+		return nil, errors.Wrap(err, errObserveRoles)
+	}
+	return rolesStatements(creds, o)
+}
+
+// queryRoles returns the admin membership, role options and database grants
+// of the named SQL user.
+func queryRoles(ctx context.Context, db *sqlclient.DB, username string) (observedRoles, error) {
+	user := pgx.Identifier{username}.Sanitize()
+	o := observedRoles{Grants: map[string]grant.GrantedPrivileges{}}
+	if err := db.QueryRow(ctx, fmt.Sprintf("SELECT count(*) > 0 FROM [SHOW GRANTS ON ROLE admin FOR %s]", user)).Scan(&o.Admin); err != nil {
+		return observedRoles{}, err
+	}
+
+	var opts string
+	if err := db.QueryRow(ctx, "SELECT COALESCE(options::STRING, '') FROM [SHOW ROLES] WHERE username = $1", username).Scan(&opts); err != nil {
+		return observedRoles{}, err
+	}
+	o.Options = parseRoleOptions(opts)
+
+	rows, err := db.Query(ctx, fmt.Sprintf("SELECT database_name, privilege_type, is_grantable FROM [SHOW GRANTS FOR %s] WHERE schema_name IS NULL", user))
+	if err != nil {
+		return observedRoles{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var database, priv string
+		var grantable bool
+		if err := rows.Scan(&database, &priv, &grantable); err != nil {
+			return observedRoles{}, err
+		}
+		if o.Grants[database] == nil {
+			o.Grants[database] = grant.GrantedPrivileges{}
+		}
+		o.Grants[database][strings.ToUpper(priv)] = grantable
+	}
+	return o, rows.Err()
+}
+
+// applyRoles runs the supplied statements, which change the roles of the user
+// of the supplied Cluster, as the role admin. The user of the credentials
+// never changes its own roles, as it may not be allowed to do so, e.g. once
+// it is no longer an admin.
+func (c *external) applyRoles(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, stmts []string) error {
+	return errors.Wrap(c.execAsRoleAdmin(ctx, cr, cluster, stmts...), errApplyRoles)
+}
+
+// execAsRoleAdmin runs the supplied statements as a short-lived admin SQL
+// user, created through the Cloud API and deleted once they ran.
+func (c *external) execAsRoleAdmin(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, stmts ...string) (err error) {
+	ca, err := c.clusterCACert(ctx, cr, cluster)
+	if err != nil {
+		return errors.Wrap(err, errGetClusterCA)
+	}
+	pwd, err := cloud.GetPassword(ctx, c.kube, nil)
+	if err != nil {
+		return errors.Wrap(err, errGenerateRolePass)
+	}
+
+	// A role admin left behind by an earlier attempt gets a new password.
+	_, res, err := c.service.CRDBClient.CreateSQLUser(ctx, cluster.Id, &cockroachdb.CreateSQLUserRequest{Name: roleAdminUser, Password: string(pwd)})
+	if res != nil && res.StatusCode == http.StatusConflict {
+		_, _, err = c.service.CRDBClient.UpdateSQLUserPassword(ctx, cluster.Id, roleAdminUser, &cockroachdb.UpdateSQLUserPasswordRequest{Password: string(pwd)})
+	}
+	if err != nil {
+		return errors.Wrap(err, errCreateRoleAdmin)
+	}
+	defer func() {
+		_, res, derr := c.service.CRDBClient.DeleteSQLUser(ctx, cluster.Id, roleAdminUser)
+		if derr != nil && !cloud.IsNotFound(res) && err == nil {
+			err = errors.Wrap(derr, errDeleteRoleAdmin)
+		}
+	}()
+
+	return c.execSQL(ctx, cloud.DSN(roleAdminUser, pwd, cluster), ca, stmts...)
+}
+
+// userPassword returns the password of the user of the supplied Cluster,
+// either from its password secret or from its connection secret.
+func (c *external) userPassword(ctx context.Context, cr *v1alpha1.Cluster) ([]byte, error) {
+	if ref := cr.Spec.ForProvider.Credentials.PasswordSecretRef; ref != nil {
+		pwd, err := cloud.GetPassword(ctx, c.kube, ref)
+		return pwd, errors.Wrap(err, errGetUserPassword)
+	}
+
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return nil, errors.New(errNoUserPassword)
+	}
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(err, errGetUserPassword)
+	}
+	u, err := url.Parse(string(s.Data["dsn"]))
+	if err != nil {
+		return nil, errors.Wrap(err, errGetUserPassword)
+	}
+	pwd, ok := u.User.Password()
+	if !ok {
+		return nil, errors.New(errNoUserPassword)
+	}
+	return []byte(pwd), nil
+}
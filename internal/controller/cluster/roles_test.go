@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/grant"
+)
+
+func TestRolesStatements(t *testing.T) {
+	admin := false
+	defaults := observedRoles{Admin: true}
+
+	type want struct {
+		stmts []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		creds    *v1alpha1.Credentials
+		observed observedRoles
+		want     want
+	}{
+		"Defaults": {
+			reason:   "No statements should be run for the defaults of the Cloud API.",
+			creds:    &v1alpha1.Credentials{Username: "app"},
+			observed: defaults,
+			want:     want{stmts: []string{}},
+		},
+		"NonAdmin": {
+			reason: "Role options and grants should be applied before admin membership is revoked.",
+			creds: &v1alpha1.Credentials{
+				Username:    "app",
+				Admin:       &admin,
+				RoleOptions: []v1alpha1.RoleOption{"NOCREATEDB", "VIEWACTIVITY"},
+				Grants: []v1alpha1.DatabaseGrant{
+					{Database: "my-db", Privileges: []v1alpha1.DatabasePrivilege{"CONNECT", "CREATE"}},
+				},
+			},
+			observed: observedRoles{Admin: true, Options: map[string]bool{"CREATEDB": true}},
+			want: want{stmts: []string{
+				`ALTER ROLE "app" WITH NOCREATEDB VIEWACTIVITY`,
+				`GRANT CONNECT, CREATE ON DATABASE "my-db" TO "app"`,
+				`REVOKE admin FROM "app"`,
+			}},
+		},
+		"InSync": {
+			reason: "No statements should be run if the observed roles are those requested.",
+			creds: &v1alpha1.Credentials{
+				Username:    "app",
+				Admin:       &admin,
+				RoleOptions: []v1alpha1.RoleOption{"VIEWACTIVITY"},
+				Grants: []v1alpha1.DatabaseGrant{
+					{Database: "my-db", Privileges: []v1alpha1.DatabasePrivilege{"CONNECT"}},
+				},
+			},
+			observed: observedRoles{
+				Options: map[string]bool{"VIEWACTIVITY": true},
+				Grants:  map[string]grant.GrantedPrivileges{"my-db": {"CONNECT": false}},
+			},
+			want: want{stmts: []string{}},
+		},
+		"Revoke": {
+			reason: "Role options, privileges and databases that are no longer requested should be turned off and revoked.",
+			creds: &v1alpha1.Credentials{
+				Username: "app",
+				Admin:    &admin,
+				Grants: []v1alpha1.DatabaseGrant{
+					{Database: "my-db", Privileges: []v1alpha1.DatabasePrivilege{"CONNECT"}},
+				},
+			},
+			observed: observedRoles{
+				Options: map[string]bool{"CREATEDB": true, "VIEWACTIVITY": true},
+				Grants: map[string]grant.GrantedPrivileges{
+					"my-db":    {"CONNECT": false, "CREATE": false},
+					"other-db": {"CONNECT": false},
+				},
+			},
+			want: want{stmts: []string{
+				`ALTER ROLE "app" WITH NOCREATEDB NOVIEWACTIVITY`,
+				`REVOKE CREATE ON DATABASE "my-db" FROM "app"`,
+				`REVOKE CONNECT ON DATABASE "other-db" FROM "app"`,
+			}},
+		},
+		"RegrantAdmin": {
+			reason: "Admin membership that was revoked outside of the Cluster should be granted again.",
+			creds:  &v1alpha1.Credentials{Username: "app"},
+			want: want{stmts: []string{
+				`GRANT admin TO "app"`,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := rolesStatements(tc.creds, tc.observed)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrolesStatements(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.stmts, got); diff != "" {
+				t.Errorf("\n%s\nrolesStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if name == "Defaults" && rolesHash(tc.creds) != "" {
+				t.Errorf("\n%s\nrolesHash(...): want empty hash", tc.reason)
+			}
+		})
+	}
+}
+
+func TestParseRoleOptions(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		options string
+		want    map[string]bool
+	}{
+		"Empty": {
+			reason:  "A user without role options should have none.",
+			options: "",
+			want:    map[string]bool{},
+		},
+		"List": {
+			reason:  "Role options should be parsed from a comma separated list.",
+			options: "CREATEDB, VIEWACTIVITY",
+			want:    map[string]bool{"CREATEDB": true, "VIEWACTIVITY": true},
+		},
+		"Array": {
+			reason:  "Role options should be parsed from an array, ignoring those with a value.",
+			options: `{createdb,"VALID UNTIL=2030-01-01",CONTROLJOB}`,
+			want:    map[string]bool{"CREATEDB": true, "CONTROLJOB": true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := parseRoleOptions(tc.options)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nparseRoleOptions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
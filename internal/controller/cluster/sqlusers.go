@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+const (
+	errCreateSQLUser      = "cannot create SQL user"
+	errPublishSQLUserConn = "cannot publish connection details of SQL user"
+	errGetSQLUserPassword = "cannot get password of SQL user"
+	errGetClusterCA       = "cannot get cluster CA certificate"
+	errGetSQLUserConn     = "cannot get connection secret of SQL user"
+
+	errListSQLUserResources = "cannot list SQLUsers"
+)
+
+// sqlUsers returns the SQL users listed in the spec of the supplied Cluster,
+// except those a SQLUser of either scope manages. SQLUsers supersede the deprecated
+// spec.forProvider.sqlUsers, so a SQL user is never managed by both.
+func (c *external) sqlUsers(ctx context.Context, cr *v1alpha1.Cluster, clusterID string) ([]v1alpha1.ClusterSQLUser, error) {
+	if len(cr.Spec.ForProvider.SQLUsers) == 0 {
+		return nil, nil
+	}
+	l := &v1alpha1.SQLUserList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListSQLUserResources)
+	}
+	managed := map[string]bool{}
+	for i := range l.Items {
+		if l.Items[i].Spec.ForProvider.ClusterID == clusterID {
+			managed[l.Items[i].SQLUserName()] = true
+		}
+	}
+
+	nl := &namespacedv1alpha1.SQLUserList{}
+	if err := c.kube.List(ctx, nl); err != nil {
+		return nil, errors.Wrap(err, errListSQLUserResources)
+	}
+	for i := range nl.Items {
+		if nl.Items[i].Spec.ForProvider.ClusterID == clusterID {
+			managed[nl.Items[i].SQLUserName()] = true
+		}
+	}
+	users := make([]v1alpha1.ClusterSQLUser, 0, len(cr.Spec.ForProvider.SQLUsers))
+	for _, u := range cr.Spec.ForProvider.SQLUsers {
+		if !managed[u.Name] {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// missingSQLUsers returns the supplied SQL users that do not exist yet.
+func (c *external) missingSQLUsers(ctx context.Context, users []v1alpha1.ClusterSQLUser, clusterID string) ([]v1alpha1.ClusterSQLUser, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+	existing, err := c.service.ListSQLUsers(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	missing := []v1alpha1.ClusterSQLUser{}
+	for _, u := range users {
+		if !existing[u.Name] {
+			missing = append(missing, u)
+		}
+	}
+	return missing, nil
+}
+
+// createSQLUsers creates the supplied SQL users and publishes a connection
+// secret for each of them.
+func (c *external) createSQLUsers(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, users []v1alpha1.ClusterSQLUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ca, err := c.clusterCACert(ctx, cr, cluster)
+	if err != nil {
+		return errors.Wrap(err, errGetClusterCA)
+	}
+	for _, u := range users {
+		pwd, err := cloud.GetPassword(ctx, c.kube, u.PasswordSecretRef)
+		if err != nil {
+			return errors.Wrap(err, errGetSQLUserPassword)
+		}
+		if _, _, err := c.service.CRDBClient.CreateSQLUser(ctx, cluster.Id, &cockroachdb.CreateSQLUserRequest{Name: u.Name, Password: string(pwd)}); err != nil {
+			return errors.Wrap(err, errCreateSQLUser)
+		}
+		ref := sqlUserSecretRef(cr, u)
+		if ref == nil {
+			continue
+		}
+		if err := c.publishConnectionSecret(ctx, cr, ref, c.service.ConnectionDetails(ctx, u.Name, cluster, ca, pwd)); err != nil {
+			return errors.Wrap(err, errPublishSQLUserConn)
+		}
+	}
+	return nil
+}
+
+// sqlUserSecretRef returns the connection secret of the supplied SQL user, if
+// any.
+func sqlUserSecretRef(cr *v1alpha1.Cluster, u v1alpha1.ClusterSQLUser) *xpv1.SecretReference {
+	if u.WriteConnectionSecretToRef != nil {
+		return u.WriteConnectionSecretToRef
+	}
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return nil
+	}
+	name := strings.ToLower(strings.ReplaceAll(u.Name, "_", "-"))
+	return &xpv1.SecretReference{Name: ref.Name + "-" + name, Namespace: ref.Namespace}
+}
+
+// publishConnectionSecret writes the supplied connection details to a Secret
+// controlled by the supplied Cluster.
+func (c *external) publishConnectionSecret(ctx context.Context, cr *v1alpha1.Cluster, ref *xpv1.SecretReference, cd managed.ConnectionDetails) error {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ref.Name,
+			Namespace:       ref.Namespace,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, c.kind))},
+		},
+		Type: resource.SecretTypeConnection,
+		Data: cd,
+	}
+	return resource.NewAPIPatchingApplicator(c.kube).Apply(ctx, s, resource.ConnectionSecretMustBeControllableBy(cr.GetUID()))
+}
+
+// unpublishedSQLUsers returns the supplied SQL users of the supplied Cluster
+// whose connection secret does not exist, e.g. because it was deleted. Users
+// that are missing altogether are not returned.
+func (c *external) unpublishedSQLUsers(ctx context.Context, cr *v1alpha1.Cluster, users, missing []v1alpha1.ClusterSQLUser) ([]v1alpha1.ClusterSQLUser, error) {
+	skip := map[string]bool{}
+	for _, u := range missing {
+		skip[u.Name] = true
+	}
+	unpublished := []v1alpha1.ClusterSQLUser{}
+	for _, u := range users {
+		ref := sqlUserSecretRef(cr, u)
+		if skip[u.Name] || ref == nil {
+			continue
+		}
+		err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &corev1.Secret{})
+		if kerrors.IsNotFound(err) {
+			unpublished = append(unpublished, u)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetSQLUserConn)
+		}
+	}
+	return unpublished, nil
+}
+
+// republishSQLUsers publishes the connection secret of each of the supplied
+// existing SQL users. Generated passwords only live in the connection secret,
+// so users without a passwordSecretRef get a new one.
+func (c *external) republishSQLUsers(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, users []v1alpha1.ClusterSQLUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ca, err := c.clusterCACert(ctx, cr, cluster)
+	if err != nil {
+		return errors.Wrap(err, errGetClusterCA)
+	}
+	for _, u := range users {
+		pwd, err := cloud.GetPassword(ctx, c.kube, u.PasswordSecretRef)
+		if err != nil {
+			return errors.Wrap(err, errGetSQLUserPassword)
+		}
+		if u.PasswordSecretRef == nil {
+			req := &cockroachdb.UpdateSQLUserPasswordRequest{Password: string(pwd)}
+			if _, _, err := c.service.CRDBClient.UpdateSQLUserPassword(ctx, cluster.Id, u.Name, req); err != nil {
+				return errors.Wrap(err, errUpdateSQLUserPassword)
+			}
+		}
+		if err := c.publishConnectionSecret(ctx, cr, sqlUserSecretRef(cr, u), c.service.ConnectionDetails(ctx, u.Name, cluster, ca, pwd)); err != nil {
+			return errors.Wrap(err, errPublishSQLUserConn)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+)
+
+func TestSQLUsers(t *testing.T) {
+	errBoom := errors.New("boom")
+	users := func(cr *v1alpha1.Cluster) {
+		cr.Spec.ForProvider.SQLUsers = []v1alpha1.ClusterSQLUser{{Name: "app"}, {Name: "reporting"}, {Name: "ops"}}
+	}
+	list := func(other string) func(context.Context, client.ObjectList, ...client.ListOption) error {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *v1alpha1.SQLUserList:
+				u := v1alpha1.SQLUser{}
+				u.SetName("app")
+				u.Spec.ForProvider.ClusterID = testClusterID
+				l.Items = []v1alpha1.SQLUser{u}
+			case *namespacedv1alpha1.SQLUserList:
+				u := namespacedv1alpha1.SQLUser{}
+				u.SetName("reporting-user")
+				u.Spec.ForProvider.Name = "reporting"
+				u.Spec.ForProvider.ClusterID = testClusterID
+				o := namespacedv1alpha1.SQLUser{}
+				o.SetName("ops")
+				o.Spec.ForProvider.ClusterID = other
+				l.Items = []namespacedv1alpha1.SQLUser{u, o}
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		users []v1alpha1.ClusterSQLUser
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		cr     *v1alpha1.Cluster
+		want   want
+	}{
+		"NoSQLUsers": {
+			reason: "SQLUsers should not be listed if the Cluster lists no SQL users.",
+			kube:   &test.MockClient{},
+			cr:     cluster(),
+		},
+		"ManagedBySQLUsers": {
+			reason: "SQL users that a SQLUser of either scope manages on the same cluster should be excluded.",
+			kube:   &test.MockClient{MockList: list("other")},
+			cr:     cluster(users),
+			want:   want{users: []v1alpha1.ClusterSQLUser{{Name: "ops"}}},
+		},
+		"ListError": {
+			reason: "Errors listing SQLUsers should be returned.",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			cr:     cluster(users),
+			want:   want{err: errors.Wrap(errBoom, errListSQLUserResources)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube}
+			got, err := e.sqlUsers(context.Background(), tc.cr, testClusterID)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.sqlUsers(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.users, got); diff != "" {
+				t.Errorf("\n%s\ne.sqlUsers(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnpublishedSQLUsers(t *testing.T) {
+	errBoom := errors.New("boom")
+	users := func(cr *v1alpha1.Cluster) {
+		cr.Spec.WriteConnectionSecretToReference = &xpv1.SecretReference{Name: "conn", Namespace: "default"}
+		cr.Spec.ForProvider.SQLUsers = []v1alpha1.ClusterSQLUser{{Name: "app"}, {Name: "reporting"}, {Name: "new"}}
+	}
+	notFound := func(name string) error {
+		return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+
+	type want struct {
+		users []v1alpha1.ClusterSQLUser
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		kube    client.Client
+		missing []v1alpha1.ClusterSQLUser
+		want    want
+	}{
+		"AllPublished": {
+			reason: "No SQL user should be returned while all connection secrets exist.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			want:   want{users: []v1alpha1.ClusterSQLUser{}},
+		},
+		"SecretDeleted": {
+			reason: "SQL users whose connection secret was deleted should be returned, unless they are missing altogether.",
+			kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+				if key.Name == "conn-app" {
+					return nil
+				}
+				return notFound(key.Name)
+			}},
+			missing: []v1alpha1.ClusterSQLUser{{Name: "new"}},
+			want:    want{users: []v1alpha1.ClusterSQLUser{{Name: "reporting"}}},
+		},
+		"GetError": {
+			reason: "Errors getting a connection secret should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetSQLUserConn)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube}
+			cr := cluster(users)
+			got, err := e.unpublishedSQLUsers(context.Background(), cr, cr.Spec.ForProvider.SQLUsers, tc.missing)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.unpublishedSQLUsers(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.users, got); diff != "" {
+				t.Errorf("\n%s\ne.unpublishedSQLUsers(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+// nextDedicatedUpdate returns the part of the supplied update of a dedicated
+// cluster with the supplied regions that the supplied strategy applies next.
+// The rest is applied by later updates, once the cluster settled.
+func nextDedicatedUpdate(s *v1alpha1.ClusterUpdateStrategy, u *cockroachdb.DedicatedClusterUpdateSpecification, regions []cockroachdb.Region) *cockroachdb.DedicatedClusterUpdateSpecification {
+	if s == nil || u == nil {
+		return u
+	}
+	observed := make(map[string]int32, len(regions))
+	for _, r := range regions {
+		observed[r.Name] = r.NodeCount
+	}
+
+	next := &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: u.Hardware, RegionNodes: u.RegionNodes}
+	if next.RegionNodes != nil && s.MaxNodeChange > 0 {
+		nodes := limitNodeChange(observed, *next.RegionNodes, s.MaxNodeChange)
+		next.RegionNodes = &nodes
+	}
+	if s.Type != v1alpha1.UpdateStrategySurge || next.Hardware == nil || next.RegionNodes == nil {
+		return next
+	}
+	// Nodes are added before the hardware is changed, and removed after.
+	if up, ok := scaleUp(observed, *next.RegionNodes); ok {
+		return &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: &up}
+	}
+	return &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: next.Hardware}
+}
+
+// limitNodeChange returns the supplied nodes of each region, changed by at
+// most maxChange nodes from the observed ones. Added and removed regions are
+// not limited.
+func limitNodeChange(observed, nodes map[string]int32, maxChange int32) map[string]int32 {
+	limited := make(map[string]int32, len(nodes))
+	for r, n := range nodes {
+		o, ok := observed[r]
+		switch {
+		case !ok:
+			limited[r] = n
+		case n > o+maxChange:
+			limited[r] = o + maxChange
+		case n < o-maxChange:
+			limited[r] = o - maxChange
+		default:
+			limited[r] = n
+		}
+	}
+	return limited
+}
+
+// scaleUp returns the observed nodes of each region, with the nodes the
+// supplied ones add, and whether they add any. Nodes are not removed, nor are
+// regions.
+func scaleUp(observed, nodes map[string]int32) (map[string]int32, bool) {
+	up := make(map[string]int32, len(observed))
+	for r, o := range observed {
+		up[r] = o
+	}
+	added := false
+	for r, n := range nodes {
+		if n > up[r] {
+			up[r] = n
+			added = true
+		}
+	}
+	return up, added
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestNextDedicatedUpdate(t *testing.T) {
+	machineType := "m5.2xlarge"
+	hw := &cockroachdb.DedicatedHardwareUpdateSpecification{MachineSpec: &cockroachdb.DedicatedMachineTypeSpecification{MachineType: &machineType}}
+	regions := []cockroachdb.Region{{Name: "us-east-1", NodeCount: 3}, {Name: "us-west-2", NodeCount: 6}}
+	nodes := func(n map[string]int32) *map[string]int32 { return &n }
+
+	cases := map[string]struct {
+		reason string
+		s      *v1alpha1.ClusterUpdateStrategy
+		u      *cockroachdb.DedicatedClusterUpdateSpecification
+		want   *cockroachdb.DedicatedClusterUpdateSpecification
+	}{
+		"NoStrategy": {
+			reason: "Without a strategy, nodes and hardware should be changed in a single update.",
+			u:      &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 3})},
+			want:   &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 3})},
+		},
+		"InPlace": {
+			reason: "An InPlace strategy should change nodes and hardware in a single update.",
+			s:      &v1alpha1.ClusterUpdateStrategy{Type: v1alpha1.UpdateStrategyInPlace},
+			u:      &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 3})},
+			want:   &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 3})},
+		},
+		"MaxNodeChange": {
+			reason: "Nodes should be added to and removed from each region by at most maxNodeChange, except in added regions.",
+			s:      &v1alpha1.ClusterUpdateStrategy{MaxNodeChange: 1},
+			u:      &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 3, "eu-west-1": 3})},
+			want:   &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: nodes(map[string]int32{"us-east-1": 4, "us-west-2": 5, "eu-west-1": 3})},
+		},
+		"SurgeScaleUp": {
+			reason: "A Surge strategy should add nodes first, without removing any or changing the hardware.",
+			s:      &v1alpha1.ClusterUpdateStrategy{Type: v1alpha1.UpdateStrategySurge},
+			u:      &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 3})},
+			want:   &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: nodes(map[string]int32{"us-east-1": 5, "us-west-2": 6})},
+		},
+		"SurgeHardware": {
+			reason: "A Surge strategy should change the hardware once nodes were added, before removing any.",
+			s:      &v1alpha1.ClusterUpdateStrategy{Type: v1alpha1.UpdateStrategySurge},
+			u:      &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw, RegionNodes: nodes(map[string]int32{"us-east-1": 3, "us-west-2": 3})},
+			want:   &cockroachdb.DedicatedClusterUpdateSpecification{Hardware: hw},
+		},
+		"SurgeScaleDown": {
+			reason: "A Surge strategy should remove nodes once the hardware was changed.",
+			s:      &v1alpha1.ClusterUpdateStrategy{Type: v1alpha1.UpdateStrategySurge},
+			u:      &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: nodes(map[string]int32{"us-east-1": 3, "us-west-2": 3})},
+			want:   &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: nodes(map[string]int32{"us-east-1": 3, "us-west-2": 3})},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := nextDedicatedUpdate(tc.s, tc.u, regions)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nnextDedicatedUpdate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
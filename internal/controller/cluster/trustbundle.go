@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+const (
+	trustBundleInterval = 5 * time.Minute
+
+	// defaultProviderConfig is used by Clusters that do not reference a
+	// ProviderConfig.
+	defaultProviderConfig = "default"
+
+	errGetProviderConfig   = "cannot get ProviderConfig"
+	errGetCASecretOfBundle = "cannot get connection secret of Cluster"
+	errApplyTrustBundle    = "cannot apply trust bundle ConfigMap"
+)
+
+// SetupTrustBundle adds a controller that maintains the trust bundle
+// ConfigMaps of ProviderConfigs.
+func SetupTrustBundle(mgr ctrl.Manager, o cloud.Options) error {
+	name := "trustbundle/" + strings.ToLower(apisv1alpha1.ProviderConfigKind)
+
+	r := &trustBundler{
+		kube: mgr.GetClient(),
+		log:  o.Logger.WithValues("controller", name),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&apisv1alpha1.ProviderConfig{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A trustBundler publishes the CA certificates of the Clusters using a
+// ProviderConfig into the trust bundle ConfigMaps of the ProviderConfig.
+type trustBundler struct {
+	kube client.Client
+	log  logging.Logger
+}
+
+// Reconcile the trust bundle of the requested ProviderConfig.
+func (b *trustBundler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := b.log.WithValues("request", req)
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := b.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	tb := pc.Spec.TrustBundle
+	if tb == nil {
+		return reconcile.Result{}, nil
+	}
+
+	bundle, err := b.bundle(ctx, pc.GetName())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	key := tb.Key
+	if key == "" {
+		key = "ca.crt"
+	}
+	for _, ns := range tb.Namespaces {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            tb.Name,
+				Namespace:       ns,
+				OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(pc, apisv1alpha1.ProviderConfigGroupVersionKind))},
+			},
+			Data: map[string]string{key: bundle},
+		}
+		err := resource.NewAPIPatchingApplicator(b.kube).Apply(ctx, cm,
+			resource.MustBeControllableBy(pc.GetUID()),
+			resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
+				return !cmp.Equal(current.(*corev1.ConfigMap).Data, desired.(*corev1.ConfigMap).Data)
+			}),
+		)
+		if resource.IsNotAllowed(err) {
+			continue
+		}
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errApplyTrustBundle)
+		}
+		log.Debug("Applied trust bundle", "namespace", ns)
+	}
+
+	return reconcile.Result{RequeueAfter: trustBundleInterval}, nil
+}
+
+// bundle returns the PEM encoded CA certificates of the Clusters using the
+// named ProviderConfig, read from their connection secrets. Certificates are
+// deduplicated and sorted so the bundle only changes when they do.
+func (b *trustBundler) bundle(ctx context.Context, providerConfig string) (string, error) {
+	clusters := []*v1alpha1.Cluster{}
+
+	l := &v1alpha1.ClusterList{}
+	if err := b.kube.List(ctx, l); err != nil {
+		return "", errors.Wrap(err, errListManagedClusters)
+	}
+	for i := range l.Items {
+		clusters = append(clusters, &l.Items[i])
+	}
+
+	nl := &namespacedv1alpha1.ClusterList{}
+	if err := b.kube.List(ctx, nl); err != nil {
+		return "", errors.Wrap(err, errListManagedClusters)
+	}
+	for i := range nl.Items {
+		clusters = append(clusters, clusterFor(&nl.Items[i]))
+	}
+
+	certs := map[string]bool{}
+	for _, cr := range clusters {
+		ref := cr.GetWriteConnectionSecretToReference()
+		if ref == nil || providerConfigOf(cr) != providerConfig {
+			continue
+		}
+		s := &corev1.Secret{}
+		if err := b.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, s); err != nil {
+			if resource.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return "", errors.Wrap(err, errGetCASecretOfBundle)
+		}
+		if ca := bytes.TrimSpace(s.Data["ca.crt"]); len(ca) > 0 {
+			certs[string(ca)] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(certs))
+	for c := range certs {
+		sorted = append(sorted, c+"\n")
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ""), nil
+}
+
+func providerConfigOf(cr *v1alpha1.Cluster) string {
+	if ref := cr.GetProviderConfigReference(); ref != nil {
+		return ref.Name
+	}
+	return defaultProviderConfig
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+)
+
+func TestTrustBundle(t *testing.T) {
+	withSecret := func(name string) clusterModifier {
+		return func(cr *v1alpha1.Cluster) {
+			cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: name, Namespace: "crossplane-system"})
+		}
+	}
+	withProviderConfig := func(name string) clusterModifier {
+		return func(cr *v1alpha1.Cluster) { cr.SetProviderConfigReference(&xpv1.Reference{Name: name}) }
+	}
+	cas := map[string]string{
+		"crossplane-system/b":    "-----BEGIN CERTIFICATE-----\nb\n-----END CERTIFICATE-----\n",
+		"crossplane-system/a":    "-----BEGIN CERTIFICATE-----\na\n-----END CERTIFICATE-----",
+		"crossplane-system/dupe": "-----BEGIN CERTIFICATE-----\na\n-----END CERTIFICATE-----\n",
+		"crossplane-system/prod": "-----BEGIN CERTIFICATE-----\nprod\n-----END CERTIFICATE-----\n",
+		"team-a/conn":            "-----BEGIN CERTIFICATE-----\nteam\n-----END CERTIFICATE-----\n",
+	}
+
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *v1alpha1.ClusterList:
+				l.Items = []v1alpha1.Cluster{
+					*cluster(withSecret("b")),
+					*cluster(withSecret("a"), withProviderConfig(defaultProviderConfig)),
+					*cluster(withSecret("dupe")),
+					*cluster(withSecret("missing")),
+					*cluster(withSecret("prod"), withProviderConfig("prod")),
+					*cluster(),
+				}
+			case *namespacedv1alpha1.ClusterList:
+				l.Items = []namespacedv1alpha1.Cluster{{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool", Namespace: "team-a"},
+					Spec: namespacedv1alpha1.ClusterSpec{
+						ResourceSpec: xpv1.ResourceSpec{WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "conn"}},
+					},
+				}}
+			}
+			return nil
+		},
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			ca, ok := cas[key.Namespace+"/"+key.Name]
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+			}
+			obj.(*corev1.Secret).Data = map[string][]byte{"ca.crt": []byte(ca)}
+			return nil
+		},
+	}
+
+	b := &trustBundler{kube: kube}
+	got, err := b.bundle(context.Background(), defaultProviderConfig)
+	if err != nil {
+		t.Fatalf("b.bundle(...): %v", err)
+	}
+	want := "-----BEGIN CERTIFICATE-----\na\n-----END CERTIFICATE-----\n" +
+		"-----BEGIN CERTIFICATE-----\nb\n-----END CERTIFICATE-----\n" +
+		"-----BEGIN CERTIFICATE-----\nteam\n-----END CERTIFICATE-----\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("b.bundle(...): -want, +got:\n%s\n", diff)
+	}
+}
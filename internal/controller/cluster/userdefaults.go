@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+)
+
+const (
+	errApplySQLUserDefaults = "cannot apply defaults of SQL users"
+)
+
+// sqlUserDefaultsHash returns a hash of the default databases and session
+// defaults requested for the supplied SQL users, or an empty string if none
+// were requested.
+func sqlUserDefaultsHash(users []v1alpha1.ClusterSQLUser) string {
+	type defaults struct {
+		Name            string
+		DefaultDatabase string
+		SessionDefaults map[string]string
+	}
+	ds := []defaults{}
+	for _, u := range users {
+		if u.DefaultDatabase == "" && len(u.SessionDefaults) == 0 {
+			continue
+		}
+		ds = append(ds, defaults{Name: u.Name, DefaultDatabase: u.DefaultDatabase, SessionDefaults: u.SessionDefaults})
+	}
+	if len(ds) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(ds)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:8])
+}
+
+// sqlUserDefaultsStatements returns the SQL statements that apply the default
+// databases and session defaults requested for the supplied SQL users. The
+// defaults of each user are reset first, so that removed defaults do not
+// linger.
+func sqlUserDefaultsStatements(users []v1alpha1.ClusterSQLUser) []string {
+	stmts := []string{}
+	for _, u := range users {
+		user := pgx.Identifier{u.Name}.Sanitize()
+		stmts = append(stmts, fmt.Sprintf("ALTER ROLE %s RESET ALL", user))
+		if u.DefaultDatabase != "" {
+			stmts = append(stmts, fmt.Sprintf("ALTER ROLE %s SET database = %s", user, sqlresource.Literal(u.DefaultDatabase)))
+		}
+		vars := make([]string, 0, len(u.SessionDefaults))
+		for v := range u.SessionDefaults {
+			vars = append(vars, v)
+		}
+		sort.Strings(vars)
+		for _, v := range vars {
+			stmts = append(stmts, fmt.Sprintf("ALTER ROLE %s SET %s = %s", user, pgx.Identifier{v}.Sanitize(), sqlresource.Literal(u.SessionDefaults[v])))
+		}
+	}
+	return stmts
+}
+
+// applySQLUserDefaults applies the default databases and session defaults
+// requested for the supplied SQL users of the supplied Cluster over SQL, as
+// the role admin, since the user of its credentials may not be allowed to
+// alter other users.
+func (c *external) applySQLUserDefaults(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, users []v1alpha1.ClusterSQLUser) error {
+	return errors.Wrap(c.execAsRoleAdmin(ctx, cr, cluster, sqlUserDefaultsStatements(users)...), errApplySQLUserDefaults)
+}
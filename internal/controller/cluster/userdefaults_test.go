@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestSQLUserDefaultsStatements(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		users  []v1alpha1.ClusterSQLUser
+		want   []string
+	}{
+		"NoDefaults": {
+			reason: "The defaults of users without requested defaults should only be reset.",
+			users:  []v1alpha1.ClusterSQLUser{{Name: "app"}},
+			want:   []string{`ALTER ROLE "app" RESET ALL`},
+		},
+		"Defaults": {
+			reason: "The default database and session defaults should be set in order after the defaults were reset.",
+			users: []v1alpha1.ClusterSQLUser{{
+				Name:            "app",
+				DefaultDatabase: "my-db",
+				SessionDefaults: map[string]string{"timezone": "UTC", "search_path": "app's, public"},
+			}},
+			want: []string{
+				`ALTER ROLE "app" RESET ALL`,
+				`ALTER ROLE "app" SET database = 'my-db'`,
+				`ALTER ROLE "app" SET "search_path" = 'app''s, public'`,
+				`ALTER ROLE "app" SET "timezone" = 'UTC'`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := sqlUserDefaultsStatements(tc.users)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nsqlUserDefaultsStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if name == "NoDefaults" && sqlUserDefaultsHash(tc.users) != "" {
+				t.Errorf("\n%s\nsqlUserDefaultsHash(...): want empty hash", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+const (
+	errUpdateSQLUserPassword = "cannot update password of SQL user"
+	errDeletePreviousUser    = "cannot delete SQL user of previous username"
+)
+
+// observeCredentialsUser records the SQL user of the credentials of the
+// supplied Cluster in its status once it exists. It may be missing if the
+// Cluster was created but creating the user failed.
+func (c *external) observeCredentialsUser(ctx context.Context, cr *v1alpha1.Cluster, clusterID string) error {
+	creds := cr.Spec.ForProvider.Credentials
+	if creds == nil || cr.Status.AtProvider.Username != "" {
+		return nil
+	}
+	users, err := c.service.ListSQLUsers(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	if users[creds.Username] {
+		cr.Status.AtProvider.Username = creds.Username
+	}
+	return nil
+}
+
+// credentialsUserMissing returns true if the SQL user of the credentials of
+// the supplied Cluster was not observed.
+func credentialsUserMissing(cr *v1alpha1.Cluster) bool {
+	return cr.Spec.ForProvider.Credentials != nil && cr.Status.AtProvider.Username == ""
+}
+
+// usernameChanged returns true if the username of the credentials of the
+// supplied Cluster differs from the SQL user created for them.
+func usernameChanged(cr *v1alpha1.Cluster) bool {
+	c := cr.Spec.ForProvider.Credentials
+	return c != nil && cr.Status.AtProvider.Username != "" && cr.Status.AtProvider.Username != c.Username
+}
+
+// previousUserExpired returns true if the grace period of the previous
+// username of the supplied Cluster ended at the supplied time.
+func previousUserExpired(cr *v1alpha1.Cluster, now time.Time) bool {
+	o := cr.Status.AtProvider
+	return o.PreviousUsername != "" && (o.PreviousUserDeletionTime == nil || !now.Before(o.PreviousUserDeletionTime.Time))
+}
+
+// changeUsername moves a username change of the supplied Cluster forward, or
+// creates the SQL user of its credentials if that failed on creation. A
+// changed username gets its own SQL user, whose connection details are
+// returned so that they are republished. The SQL user of the previous
+// username is deleted once its grace period ends, so that no stale account
+// with a valid password is left behind.
+func (c *external) changeUsername(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster, now time.Time) (managed.ConnectionDetails, error) {
+	cd := managed.ConnectionDetails{}
+	if credentialsUserMissing(cr) {
+		var err error
+		if cd, err = c.createCredentialsUser(ctx, cr, cluster); err != nil {
+			return nil, err
+		}
+		cr.Status.AtProvider.Username = cr.Spec.ForProvider.Credentials.Username
+	}
+
+	if usernameChanged(cr) {
+		// A change superseding one still in its grace period cuts that
+		// grace period short.
+		if err := c.deletePreviousUser(ctx, cr, cluster.Id); err != nil {
+			return nil, err
+		}
+
+		cr.SetConditions(v1alpha1.CreatingUser(cr.Spec.ForProvider.Credentials.Username))
+		var err error
+		if cd, err = c.createCredentialsUser(ctx, cr, cluster); err != nil {
+			return nil, err
+		}
+
+		var grace time.Duration
+		if g := cr.Spec.ForProvider.Credentials.UsernameChangeGracePeriod; g != nil {
+			grace = g.Duration
+		}
+		until := metav1.NewTime(now.Add(grace))
+		cr.Status.AtProvider.PreviousUsername = cr.Status.AtProvider.Username
+		cr.Status.AtProvider.PreviousUserDeletionTime = &until
+		cr.Status.AtProvider.Username = cr.Spec.ForProvider.Credentials.Username
+		// Roles were applied to the previous user, and are applied to the
+		// new one from scratch.
+		cr.Status.AtProvider.RolesHash = ""
+		cr.SetConditions(v1alpha1.UsernameGracePeriod(cr.Status.AtProvider.PreviousUsername, until))
+	}
+
+	if previousUserExpired(cr, now) {
+		if err := c.deletePreviousUser(ctx, cr, cluster.Id); err != nil {
+			return nil, err
+		}
+		cr.SetConditions(v1alpha1.UsernameChangeCompleted())
+	}
+	return cd, nil
+}
+
+// createCredentialsUser creates the SQL user of the credentials of the
+// supplied Cluster under their current username, and returns its connection
+// details.
+func (c *external) createCredentialsUser(ctx context.Context, cr *v1alpha1.Cluster, cluster *cockroachdb.Cluster) (managed.ConnectionDetails, error) {
+	pwd, err := cloud.GetPassword(ctx, c.kube, cr.Spec.ForProvider.Credentials.PasswordSecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user may have been created by an earlier attempt whose password
+	// was generated and is lost, so its password is reset.
+	_, res, err := c.service.CRDBClient.CreateSQLUser(ctx, cluster.Id, cr.CreateSQLUserRequest(string(pwd)))
+	switch {
+	case isNameCollision(res):
+		req := &cockroachdb.UpdateSQLUserPasswordRequest{Password: string(pwd)}
+		if _, _, err := c.service.CRDBClient.UpdateSQLUserPassword(ctx, cluster.Id, cr.Spec.ForProvider.Credentials.Username, req); err != nil {
+			return nil, errors.Wrap(err, errUpdateSQLUserPassword)
+		}
+	case err != nil:
+		return nil, errors.Wrap(err, errCreateSQLUser)
+	}
+
+	ca, err := c.clusterCACert(ctx, cr, cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClusterCA)
+	}
+	return c.service.ConnectionDetails(ctx, cr.Spec.ForProvider.Credentials.Username, cluster, ca, pwd), nil
+}
+
+// deletePreviousUser deletes the SQL user of the previous username of the
+// supplied Cluster, if any.
+func (c *external) deletePreviousUser(ctx context.Context, cr *v1alpha1.Cluster, clusterID string) error {
+	p := cr.Status.AtProvider.PreviousUsername
+	if p == "" {
+		return nil
+	}
+	_, res, err := c.service.CRDBClient.DeleteSQLUser(ctx, clusterID, p)
+	if err != nil && !cloud.IsNotFound(res) {
+		return errors.Wrap(err, errDeletePreviousUser)
+	}
+	cr.Status.AtProvider.PreviousUsername = ""
+	cr.Status.AtProvider.PreviousUserDeletionTime = nil
+	return nil
+}
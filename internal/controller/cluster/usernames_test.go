@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+func TestChangeUsername(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := metav1.NewTime(now.Add(time.Hour))
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	caRef := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "ca", Namespace: "default"}, Key: "ca.crt"}
+	kube := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+		o.(*corev1.Secret).Data = map[string][]byte{"ca.crt": []byte("cert")}
+		return nil
+	})}
+	created := func(_ context.Context, _ string, _ *cockroachdb.CreateSQLUserRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+		return &cockroachdb.SQLUser{}, &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	deleted := func(_ context.Context, _ string, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+		if name != "old" {
+			return nil, &http.Response{StatusCode: http.StatusBadRequest}, errors.Errorf("unexpected user %q", name)
+		}
+		return &cockroachdb.SQLUser{}, &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	renamed := func(cr *v1alpha1.Cluster) {
+		cr.Spec.ForProvider.Connection = &v1alpha1.ClusterConnection{CASecretRef: caRef}
+		cr.Status.AtProvider.Username = "old"
+		cr.Status.AtProvider.RolesHash = "hash"
+	}
+	withGracePeriod := func(cr *v1alpha1.Cluster) {
+		cr.Spec.ForProvider.Credentials.UsernameChangeGracePeriod = &metav1.Duration{Duration: time.Hour}
+	}
+	switched := func(until metav1.Time) func(cr *v1alpha1.Cluster) {
+		return func(cr *v1alpha1.Cluster) {
+			cr.Status.AtProvider.Username = "cool"
+			cr.Status.AtProvider.RolesHash = ""
+			cr.Status.AtProvider.PreviousUsername = "old"
+			cr.Status.AtProvider.PreviousUserDeletionTime = &until
+		}
+	}
+	completed := func(cr *v1alpha1.Cluster) {
+		cr.Status.AtProvider.Username = "cool"
+		cr.Status.AtProvider.RolesHash = ""
+		cr.SetConditions(v1alpha1.UsernameChangeCompleted())
+	}
+
+	type want struct {
+		cr  *v1alpha1.Cluster
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		svc    *mockService
+		cr     *v1alpha1.Cluster
+		want   want
+	}{
+		"Unchanged": {
+			reason: "Nothing should be done while the username is unchanged.",
+			svc:    &mockService{},
+			cr:     cluster(func(cr *v1alpha1.Cluster) { cr.Status.AtProvider.Username = "cool" }),
+			want:   want{cr: cluster(func(cr *v1alpha1.Cluster) { cr.Status.AtProvider.Username = "cool" })},
+		},
+		"WithoutGracePeriod": {
+			reason: "The previous SQL user should be deleted right away without a grace period.",
+			svc:    &mockService{MockCreateSQLUser: created, MockDeleteSQLUser: deleted},
+			cr:     cluster(renamed),
+			want:   want{cr: cluster(renamed, completed)},
+		},
+		"GracePeriodStarted": {
+			reason: "The previous SQL user should be kept until the grace period ends.",
+			svc:    &mockService{MockCreateSQLUser: created},
+			cr:     cluster(renamed, withGracePeriod),
+			want: want{
+				cr: cluster(renamed, withGracePeriod, switched(later), func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.UsernameGracePeriod("old", later))
+				}),
+			},
+		},
+		"GracePeriodEnded": {
+			reason: "The previous SQL user should be deleted once the grace period ended.",
+			svc:    &mockService{MockDeleteSQLUser: deleted},
+			cr:     cluster(renamed, withGracePeriod, switched(earlier)),
+			want:   want{cr: cluster(renamed, withGracePeriod, completed)},
+		},
+		"AlreadyCreated": {
+			reason: "The password of a SQL user created by an earlier attempt should be reset.",
+			svc: &mockService{
+				MockCreateSQLUser: func(_ context.Context, _ string, _ *cockroachdb.CreateSQLUserRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+					return nil, &http.Response{StatusCode: http.StatusConflict}, errBoom
+				},
+				MockUpdateSQLUserPassword: func(_ context.Context, _ string, _ string, _ *cockroachdb.UpdateSQLUserPasswordRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+					return &cockroachdb.SQLUser{}, &http.Response{StatusCode: http.StatusOK}, nil
+				},
+				MockDeleteSQLUser: deleted,
+			},
+			cr:   cluster(renamed),
+			want: want{cr: cluster(renamed, completed)},
+		},
+		"CreateError": {
+			reason: "A username change should be reported while the new SQL user cannot be created.",
+			svc: &mockService{
+				MockCreateSQLUser: func(_ context.Context, _ string, _ *cockroachdb.CreateSQLUserRequest) (*cockroachdb.SQLUser, *http.Response, error) {
+					return nil, &http.Response{StatusCode: http.StatusInternalServerError}, errBoom
+				},
+			},
+			cr: cluster(renamed),
+			want: want{
+				cr: cluster(renamed, func(cr *v1alpha1.Cluster) {
+					cr.SetConditions(v1alpha1.CreatingUser("cool"))
+				}),
+				err: errors.Wrap(errBoom, errCreateSQLUser),
+			},
+		},
+		"DeleteError": {
+			reason: "The previous username should be kept while its SQL user cannot be deleted.",
+			svc: &mockService{
+				MockDeleteSQLUser: func(_ context.Context, _ string, _ string) (*cockroachdb.SQLUser, *http.Response, error) {
+					return nil, &http.Response{StatusCode: http.StatusInternalServerError}, errBoom
+				},
+			},
+			cr: cluster(renamed, switched(earlier)),
+			want: want{
+				cr:  cluster(renamed, switched(earlier)),
+				err: errors.Wrap(errBoom, errDeletePreviousUser),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{service: &cloud.Service{CRDBClient: tc.svc}, kube: kube}
+			_, err := e.changeUsername(context.Background(), tc.cr, &cockroachdb.Cluster{Id: testClusterID, Regions: []cockroachdb.Region{{SqlDns: "cool.crdb.io"}}}, now)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.changeUsername(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.changeUsername(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
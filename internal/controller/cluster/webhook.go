@@ -0,0 +1,327 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotAnyCluster        = "object is not a Cluster custom resource"
+	errListRegions          = "cannot list available regions"
+	errFmtRegionUnavailable = "region %q is not available for %s clusters on %s"
+	errFmtProtected         = "cannot delete Cluster while annotated with %s: \"true\""
+	errRenderSecretName     = "cannot render writeConnectionSecretToRef.name template"
+	errGetNamespace         = "cannot get writeConnectionSecretToRef namespace"
+	errFmtNoNamespace       = "writeConnectionSecretToRef namespace %q does not exist"
+	errFmtProviderDenied    = "provider %s is not allowed by ProviderConfig %q"
+	errFmtRegionDenied      = "region %q is not allowed by ProviderConfig %q"
+
+	// defaultConnectionSecretName is the template of the name of the
+	// connection secret of Clusters that do not specify one.
+	defaultConnectionSecretName = "{{ .Name }}-conn"
+)
+
+// SetupWebhook adds defaulting and validating webhooks for Cluster managed
+// resources. Connection secrets of cluster scoped Clusters default to the
+// supplied namespace.
+func SetupWebhook(mgr ctrl.Manager, o cloud.Options, namespace string) error {
+	d := &defaulter{namespace: namespace}
+	v := &validator{kube: mgr.GetAPIReader()}
+	if o.Features.Enabled(features.EnableAlphaLiveRegionValidation) {
+		c := &cloud.Connector{Kube: mgr.GetClient(), NewServiceFn: o.NewService}
+		v.service = c.Service
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.Cluster{}).WithDefaulter(d).WithValidator(v).Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).For(&namespacedv1alpha1.Cluster{}).WithDefaulter(d).WithValidator(v).Complete()
+}
+
+// A defaulter defaults the connection secret of Clusters on admission. An
+// omitted writeConnectionSecretToRef defaults to a secret named after the
+// Cluster, and a templated name such as {{ .Name }}-crdb is rendered with
+// the name and namespace of the Cluster. Region names are normalized to the
+// naming convention of the Cluster's cloud provider.
+type defaulter struct {
+	namespace string
+}
+
+// connectionSecretNameData is available to writeConnectionSecretToRef.name
+// templates.
+type connectionSecretNameData struct {
+	Name      string
+	Namespace string
+}
+
+func (d *defaulter) Default(_ context.Context, obj runtime.Object) error {
+	mg, ok := obj.(resource.Managed)
+	if !ok {
+		return errors.New(errNotAnyCluster)
+	}
+
+	ref := mg.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		ref = &xpv1.SecretReference{Name: defaultConnectionSecretName, Namespace: d.namespace}
+		if ns := mg.GetNamespace(); ns != "" {
+			ref.Namespace = ns
+		}
+	}
+	if strings.Contains(ref.Name, "{{") {
+		name, err := renderSecretName(ref.Name, connectionSecretNameData{Name: mg.GetName(), Namespace: mg.GetNamespace()})
+		if err != nil {
+			return errors.Wrap(err, errRenderSecretName)
+		}
+		ref = &xpv1.SecretReference{Name: name, Namespace: ref.Namespace}
+	}
+	mg.SetWriteConnectionSecretToReference(ref)
+
+	if p := forProvider(obj); p != nil && p.Serverless != nil {
+		for i, r := range p.Serverless.Regions {
+			p.Serverless.Regions[i] = normalizeRegion(p.Provider, r)
+		}
+	}
+	if p := forProvider(obj); p != nil && p.Dedicated != nil {
+		regionNodes := make(map[string]int32, len(p.Dedicated.RegionNodes))
+		for r, n := range p.Dedicated.RegionNodes {
+			regionNodes[normalizeRegion(p.Provider, r)] = n
+		}
+		p.Dedicated.RegionNodes = regionNodes
+	}
+	return nil
+}
+
+// forProvider returns the parameters of the supplied Cluster, if any.
+func forProvider(obj runtime.Object) *v1alpha1.ClusterParameters {
+	switch cr := obj.(type) {
+	case *v1alpha1.Cluster:
+		return &cr.Spec.ForProvider
+	case *namespacedv1alpha1.Cluster:
+		return &cr.Spec.ForProvider
+	default:
+		return nil
+	}
+}
+
+func renderSecretName(tmpl string, data connectionSecretNameData) (string, error) {
+	t, err := template.New("name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	b := &bytes.Buffer{}
+	if err := t.Execute(b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// A validator validates Clusters on admission. Regions are only validated
+// against the Cloud API when a service is configured. Protected Clusters
+// cannot be deleted.
+type validator struct {
+	kube    client.Reader
+	service func(ctx context.Context, mg resource.Managed) (*cloud.Service, error)
+}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	cr, err := asCluster(obj)
+	if err != nil {
+		return err
+	}
+	if err := v.validateSecretNamespace(ctx, cr); err != nil {
+		return err
+	}
+	if err := v.validatePlacement(ctx, cr); err != nil {
+		return err
+	}
+	return v.validateRegions(ctx, cr)
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	old, err := asCluster(oldObj)
+	if err != nil {
+		return err
+	}
+	cr, err := asCluster(newObj)
+	if err != nil {
+		return err
+	}
+	if !cmp.Equal(old.GetWriteConnectionSecretToReference(), cr.GetWriteConnectionSecretToReference()) {
+		if err := v.validateSecretNamespace(ctx, cr); err != nil {
+			return err
+		}
+	}
+	// Placements that were accepted once are not validated again, so that
+	// Clusters do not become impossible to update if a region is retired or
+	// a placement policy is tightened.
+	if cmp.Equal(old.Regions(), cr.Regions()) &&
+		old.Spec.ForProvider.Provider == cr.Spec.ForProvider.Provider &&
+		providerConfigOf(old) == providerConfigOf(cr) {
+		return nil
+	}
+	if err := v.validatePlacement(ctx, cr); err != nil {
+		return err
+	}
+	return v.validateRegions(ctx, cr)
+}
+
+func (v *validator) ValidateDelete(_ context.Context, obj runtime.Object) error {
+	cr, err := asCluster(obj)
+	if err != nil {
+		return err
+	}
+	if cr.GetAnnotations()[v1alpha1.AnnotationKeyProtected] == "true" {
+		return errors.Errorf(errFmtProtected, v1alpha1.AnnotationKeyProtected)
+	}
+	return nil
+}
+
+// validateSecretNamespace returns an error if the namespace of the connection
+// secret of the supplied Cluster does not exist.
+func (v *validator) validateSecretNamespace(ctx context.Context, cr *v1alpha1.Cluster) error {
+	ref := cr.GetWriteConnectionSecretToReference()
+	if v.kube == nil || ref == nil {
+		return nil
+	}
+	err := v.kube.Get(ctx, types.NamespacedName{Name: ref.Namespace}, &corev1.Namespace{})
+	if kerrors.IsNotFound(err) {
+		return errors.Errorf(errFmtNoNamespace, ref.Namespace)
+	}
+	return errors.Wrap(err, errGetNamespace)
+}
+
+// validatePlacement returns an error if the provider or any region of the
+// supplied Cluster is not allowed by the placement policy of its
+// ProviderConfig. Clusters whose ProviderConfig does not exist yet are
+// accepted.
+func (v *validator) validatePlacement(ctx context.Context, cr *v1alpha1.Cluster) error {
+	if v.kube == nil {
+		return nil
+	}
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := v.kube.Get(ctx, types.NamespacedName{Name: providerConfigOf(cr)}, pc); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetPC)
+	}
+	p := pc.Spec.Placement
+	if p == nil {
+		return nil
+	}
+
+	provider := cr.Spec.ForProvider.Provider
+	if len(p.AllowedProviders) > 0 {
+		allowed := false
+		for _, ap := range p.AllowedProviders {
+			allowed = allowed || string(ap) == string(provider)
+		}
+		if !allowed {
+			return errors.Errorf(errFmtProviderDenied, provider, pc.GetName())
+		}
+	}
+
+	if len(p.AllowedRegions) == 0 {
+		return nil
+	}
+	allowed := map[string]bool{}
+	for _, r := range p.AllowedRegions {
+		allowed[normalizeRegion(provider, r)] = true
+	}
+	for _, r := range cr.Regions() {
+		if !allowed[normalizeRegion(provider, r)] {
+			return errors.Errorf(errFmtRegionDenied, r, pc.GetName())
+		}
+	}
+	return nil
+}
+
+// validateRegions returns an error if any region of the supplied Cluster is
+// not offered by the Cloud API for its provider and plan.
+func (v *validator) validateRegions(ctx context.Context, cr *v1alpha1.Cluster) error {
+	if v.service == nil || len(cr.Regions()) == 0 {
+		return nil
+	}
+	svc, err := v.service(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	available, err := availableRegions(ctx, svc.CRDBClient, cr.Spec.ForProvider.Provider, cr.Plan() == cockroachdb.PLAN_SERVERLESS)
+	if err != nil {
+		return err
+	}
+	for _, r := range cr.Regions() {
+		if !available[r] {
+			return errors.Errorf(errFmtRegionUnavailable, r, cr.Plan(), cr.Spec.ForProvider.Provider)
+		}
+	}
+	return nil
+}
+
+// availableRegions returns the regions offered by the Cloud API for the
+// supplied provider.
+func availableRegions(ctx context.Context, svc cockroachdb.Service, provider cockroachdb.ApiCloudProvider, serverless bool) (map[string]bool, error) {
+	p := string(provider)
+	regions := map[string]bool{}
+	err := cockroachcloud.NewPager(func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		res, _, err := svc.ListAvailableRegions(ctx, &cockroachdb.ListAvailableRegionsOptions{Provider: &p, Serverless: &serverless, PaginationStartKey: startKey})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res.Regions {
+			regions[r.Name] = true
+		}
+		return res.Pagination, nil
+	}).All(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListRegions)
+	}
+	return regions, nil
+}
+
+// asCluster returns the cluster scoped equivalent of the supplied Cluster.
+func asCluster(obj runtime.Object) (*v1alpha1.Cluster, error) {
+	switch cr := obj.(type) {
+	case *v1alpha1.Cluster:
+		return cr, nil
+	case *namespacedv1alpha1.Cluster:
+		return clusterFor(cr), nil
+	default:
+		return nil, errors.New(errNotAnyCluster)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+func TestValidateCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	service := func(err error) func(ctx context.Context, mg resource.Managed) (*cloud.Service, error) {
+		return func(_ context.Context, _ resource.Managed) (*cloud.Service, error) {
+			return &cloud.Service{CRDBClient: &mockService{
+				MockListAvailableRegions: func(_ context.Context, _ *cockroachdb.ListAvailableRegionsOptions) (*cockroachdb.ListAvailableRegionsResponse, *http.Response, error) {
+					if err != nil {
+						return nil, &http.Response{StatusCode: http.StatusInternalServerError}, err
+					}
+					return &cockroachdb.ListAvailableRegionsResponse{
+						Regions: []cockroachdb.CloudProviderRegion{{Name: "us-east-1"}},
+					}, &http.Response{StatusCode: http.StatusOK}, nil
+				},
+			}}, nil
+		}
+	}
+
+	withSecretRef := func(cr *v1alpha1.Cluster) {
+		cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "conn", Namespace: "team"})
+	}
+
+	placement := func(p *apisv1alpha1.PlacementPolicy) client.Reader {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+			if pc, ok := o.(*apisv1alpha1.ProviderConfig); ok {
+				pc.SetName(defaultProviderConfig)
+				pc.Spec.Placement = p
+			}
+			return nil
+		})}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		kube    client.Reader
+		service func(ctx context.Context, mg resource.Managed) (*cloud.Service, error)
+		obj     runtime.Object
+		want    error
+	}{
+		"LiveValidationDisabled": {
+			reason: "Regions should not be validated unless live validation is enabled.",
+			obj:    cluster(),
+		},
+		"SecretNamespaceExists": {
+			reason: "A connection secret in an existing namespace should be accepted.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			obj:    cluster(withSecretRef),
+		},
+		"SecretNamespaceNotFound": {
+			reason: "A connection secret in a namespace that does not exist should be rejected.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "team"))},
+			obj:    cluster(withSecretRef),
+			want:   errors.Errorf(errFmtNoNamespace, "team"),
+		},
+		"GetNamespaceError": {
+			reason: "Errors getting the namespace of the connection secret should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			obj:    cluster(withSecretRef),
+			want:   errors.Wrap(errBoom, errGetNamespace),
+		},
+		"PlacementAllowed": {
+			reason: "Regions allowed by the ProviderConfig should be accepted, regardless of their naming convention.",
+			kube:   placement(&apisv1alpha1.PlacementPolicy{AllowedProviders: []apisv1alpha1.CloudProvider{"AWS"}, AllowedRegions: []string{"europe-west1"}}),
+			obj:    cluster(),
+		},
+		"ProviderDenied": {
+			reason: "Providers not allowed by the ProviderConfig should be rejected.",
+			kube:   placement(&apisv1alpha1.PlacementPolicy{AllowedProviders: []apisv1alpha1.CloudProvider{"GCP"}}),
+			obj:    cluster(),
+			want:   errors.Errorf(errFmtProviderDenied, cockroachdb.APICLOUDPROVIDER_AWS, defaultProviderConfig),
+		},
+		"RegionDenied": {
+			reason: "Regions not allowed by the ProviderConfig should be rejected.",
+			kube:   placement(&apisv1alpha1.PlacementPolicy{AllowedRegions: []string{"us-east-1"}}),
+			obj:    cluster(),
+			want:   errors.Errorf(errFmtRegionDenied, "eu-west-1", defaultProviderConfig),
+		},
+		"RegionUnavailable": {
+			reason:  "Regions that are not offered by the Cloud API should be rejected.",
+			service: service(nil),
+			obj:     cluster(),
+			want:    errors.Errorf(errFmtRegionUnavailable, "eu-west-1", cockroachdb.PLAN_SERVERLESS, cockroachdb.APICLOUDPROVIDER_AWS),
+		},
+		"ListRegionsError": {
+			reason:  "Errors listing available regions should be returned.",
+			service: service(errBoom),
+			obj:     cluster(),
+			want:    errors.Wrap(errBoom, errListRegions),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{kube: tc.kube, service: tc.service}
+			err := v.ValidateCreate(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateCreate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateDelete(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		want   error
+	}{
+		"Unprotected": {
+			reason: "A Cluster without the protected annotation should be deleted.",
+			obj:    cluster(),
+		},
+		"Protected": {
+			reason: "A Cluster with the protected annotation should not be deleted.",
+			obj: cluster(func(cr *v1alpha1.Cluster) {
+				meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyProtected: "true"})
+			}),
+			want: errors.Errorf(errFmtProtected, v1alpha1.AnnotationKeyProtected),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{}
+			err := v.ValidateDelete(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateDelete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		obj         resource.Managed
+		want        *xpv1.SecretReference
+		wantRegions []string
+	}{
+		"DefaultClusterScoped": {
+			reason: "The connection secret of a cluster scoped Cluster should default to the provider namespace.",
+			obj:    &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			want:   &xpv1.SecretReference{Name: "cool-conn", Namespace: "crossplane-system"},
+		},
+		"DefaultNamespaced": {
+			reason: "The connection secret of a namespaced Cluster should default to its namespace.",
+			obj:    &namespacedv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool", Namespace: "team"}},
+			want:   &xpv1.SecretReference{Name: "cool-conn", Namespace: "team"},
+		},
+		"Templated": {
+			reason: "A templated connection secret name should be rendered.",
+			obj: &v1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: v1alpha1.ClusterSpec{ResourceSpec: xpv1.ResourceSpec{
+					WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "{{ .Name }}-crdb", Namespace: "team"},
+				}},
+			},
+			want: &xpv1.SecretReference{Name: "cool-crdb", Namespace: "team"},
+		},
+		"NormalizedRegions": {
+			reason: "Regions should be normalized to the naming convention of the cloud provider.",
+			obj: &v1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: v1alpha1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{
+						WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "conn", Namespace: "team"},
+					},
+					ForProvider: v1alpha1.ClusterParameters{
+						Provider:   cockroachdb.APICLOUDPROVIDER_AWS,
+						Serverless: &v1alpha1.ServerlessCluster{Regions: []string{"us-east1"}},
+					},
+				},
+			},
+			want:        &xpv1.SecretReference{Name: "conn", Namespace: "team"},
+			wantRegions: []string{"us-east-1"},
+		},
+		"Untouched": {
+			reason: "A connection secret name without a template should be left untouched.",
+			obj: &v1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: v1alpha1.ClusterSpec{ResourceSpec: xpv1.ResourceSpec{
+					WriteConnectionSecretToReference: &xpv1.SecretReference{Name: "conn", Namespace: "team"},
+				}},
+			},
+			want: &xpv1.SecretReference{Name: "conn", Namespace: "team"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &defaulter{namespace: "crossplane-system"}
+			if err := d.Default(context.Background(), tc.obj); err != nil {
+				t.Fatalf("\n%s\nd.Default(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.obj.GetWriteConnectionSecretToReference()); diff != "" {
+				t.Errorf("\n%s\nd.Default(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if p := forProvider(tc.obj); p.Serverless != nil {
+				if diff := cmp.Diff(tc.wantRegions, p.Serverless.Regions); diff != "" {
+					t.Errorf("\n%s\nd.Default(...): -want regions, +got regions:\n%s\n", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
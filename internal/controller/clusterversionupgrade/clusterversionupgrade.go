@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterversionupgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotClusterVersionUpgrade = "managed resource is not a ClusterVersionUpgrade custom resource"
+	errNoClusterID              = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetUpgrade               = "cannot get major version upgrade of cluster"
+	errInitiateUpgrade          = "cannot initiate major version upgrade of cluster"
+	errFinalizeUpgrade          = "cannot finalize major version upgrade of cluster"
+	errRollbackUpgrade          = "cannot roll back major version upgrade of cluster"
+)
+
+// Setup adds a controller that reconciles ClusterVersionUpgrade managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.ClusterVersionUpgradeGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.ClusterVersionUpgradeGroupVersionKind, &v1alpha1.ClusterVersionUpgrade{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c.Upgrades()}
+	})
+}
+
+// An external reconciles ClusterVersionUpgrades. Its external
+// name is set to the ID of the cluster once the upgrade was initiated, so
+// that an upgrade that was rolled back is not initiated again.
+type external struct {
+	client *cockroachcloud.UpgradeClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ClusterVersionUpgrade)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotClusterVersionUpgrade)
+	}
+	// An upgrade cannot be undone by deleting it.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	u, err := c.client.Get(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetUpgrade)
+	}
+
+	phase := upgradePhase(cr.Spec.ForProvider.Version, u)
+	if phase == "" {
+		if meta.GetExternalName(cr) == "" {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		phase = v1alpha1.UpgradePhaseRolledBack
+	}
+
+	cr.Status.AtProvider = v1alpha1.ClusterVersionUpgradeObservation{
+		Phase:            string(phase),
+		CockroachVersion: u.CockroachVersion,
+		UpgradeStatus:    string(u.UpgradeStatus),
+	}
+	cr.Status.SetConditions(v1alpha1.UpgradeProgress(phase, fmt.Sprintf("cluster runs %s", u.CockroachVersion)))
+	switch phase {
+	case v1alpha1.UpgradePhaseUpgrading, v1alpha1.UpgradePhaseRollingBack:
+		cr.Status.SetConditions(xpv1.Creating())
+	case v1alpha1.UpgradePhaseFailed:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	default:
+		cr.Status.SetConditions(xpv1.Available())
+	}
+
+	f := cr.Spec.ForProvider.Finalization
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: phase != v1alpha1.UpgradePhasePendingFinalization || f == "" || f == v1alpha1.FinalizationPending,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.ClusterVersionUpgrade)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotClusterVersionUpgrade)
+	}
+	cr.Status.SetConditions(xpv1.Creating(), v1alpha1.UpgradeProgress(v1alpha1.UpgradePhaseUpgrading, ""))
+
+	if _, err := c.client.Initiate(ctx, cr.Spec.ForProvider.ClusterID, cr.Spec.ForProvider.Version); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errInitiateUpgrade)
+	}
+	meta.SetExternalName(cr, cr.Spec.ForProvider.ClusterID)
+	return managed.ExternalCreation{}, nil
+}
+
+// Update finalizes or rolls back an upgrade pending finalization.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ClusterVersionUpgrade)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotClusterVersionUpgrade)
+	}
+
+	switch cr.Spec.ForProvider.Finalization {
+	case v1alpha1.FinalizationFinalize:
+		_, err := c.client.Finalize(ctx, cr.Spec.ForProvider.ClusterID)
+		return managed.ExternalUpdate{}, errors.Wrap(err, errFinalizeUpgrade)
+	case v1alpha1.FinalizationRollback:
+		cr.Status.SetConditions(xpv1.Creating(), v1alpha1.UpgradeProgress(v1alpha1.UpgradePhaseRollingBack, ""))
+		_, err := c.client.Rollback(ctx, cr.Spec.ForProvider.ClusterID)
+		return managed.ExternalUpdate{}, errors.Wrap(err, errRollbackUpgrade)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete leaves the cluster as is, as deleting a ClusterVersionUpgrade
+// neither finalizes nor rolls back its upgrade.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	mg.SetConditions(xpv1.Deleting())
+	return nil
+}
+
+// upgradePhase returns the phase of the upgrade of a cluster to the supplied
+// major version, or an empty phase if the cluster neither runs nor is being
+// upgraded to it.
+func upgradePhase(version string, u *cockroachcloud.Upgrade) xpv1.ConditionReason {
+	switch u.OperationStatus {
+	case cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_UPGRADE_RUNNING:
+		return v1alpha1.UpgradePhaseUpgrading
+	case cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_ROLLBACK_RUNNING:
+		return v1alpha1.UpgradePhaseRollingBack
+	case cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_UPGRADE_FAILED, cockroachdb.CLUSTERSTATUSTYPE_CRDB_MAJOR_ROLLBACK_FAILED:
+		return v1alpha1.UpgradePhaseFailed
+	}
+	switch u.UpgradeStatus {
+	case cockroachcloud.UpgradeStatusMajorUpgradeRunning:
+		return v1alpha1.UpgradePhaseUpgrading
+	case cockroachcloud.UpgradeStatusRollbackRunning:
+		return v1alpha1.UpgradePhaseRollingBack
+	}
+	if u.CockroachVersion != version && !strings.HasPrefix(u.CockroachVersion, version+".") {
+		return ""
+	}
+	if u.UpgradeStatus == cockroachcloud.UpgradeStatusPendingFinalization {
+		return v1alpha1.UpgradePhasePendingFinalization
+	}
+	return v1alpha1.UpgradePhaseFinalized
+}
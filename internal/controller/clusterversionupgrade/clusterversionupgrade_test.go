@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterversionupgrade
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestVersionUpgradeObserve(t *testing.T) {
+	cluster := func(version, upgrade, operation string) string {
+		return `{"id":"` + testClusterID + `","cockroach_version":"` + version + `","upgrade_status":"` + upgrade + `","operation_status":"` + operation + `"}`
+	}
+
+	type want struct {
+		o     managed.ExternalObservation
+		phase string
+		cond  xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason       string
+		initiated    bool
+		finalization string
+		body         string
+		want         want
+	}{
+		"ClusterGone": {
+			reason: "An upgrade of a cluster the Cloud API does not know should not exist.",
+		},
+		"NotInitiated": {
+			reason: "An upgrade to a version the cluster does not run should not exist until it was initiated.",
+			body:   cluster("v22.2.9", "UPGRADE_AVAILABLE", "CLUSTER_STATUS_UNSPECIFIED"),
+		},
+		"Upgrading": {
+			reason:    "A running upgrade should be creating.",
+			initiated: true,
+			body:      cluster("v22.2.9", "MAJOR_UPGRADE_RUNNING", "CRDB_MAJOR_UPGRADE_RUNNING"),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				phase: "Upgrading",
+				cond:  xpv1.Creating(),
+			},
+		},
+		"PendingFinalization": {
+			reason:    "An upgrade pending finalization should be left pending by default.",
+			initiated: true,
+			body:      cluster("v23.1.11", "PENDING_FINALIZATION", "CLUSTER_STATUS_UNSPECIFIED"),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				phase: "PendingFinalization",
+				cond:  xpv1.Available(),
+			},
+		},
+		"Finalize": {
+			reason:       "An upgrade pending finalization should be finalized if requested.",
+			initiated:    true,
+			finalization: v1alpha1.FinalizationFinalize,
+			body:         cluster("v23.1.11", "PENDING_FINALIZATION", "CLUSTER_STATUS_UNSPECIFIED"),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				phase: "PendingFinalization",
+				cond:  xpv1.Available(),
+			},
+		},
+		"AlreadyFinalized": {
+			reason: "A cluster that runs the finalized version should not be upgraded again.",
+			body:   cluster("v23.1.11", "FINALIZED", "CLUSTER_STATUS_UNSPECIFIED"),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				phase: "Finalized",
+				cond:  xpv1.Available(),
+			},
+		},
+		"RolledBack": {
+			reason:       "An initiated upgrade of a cluster that runs its previous version again should be rolled back.",
+			initiated:    true,
+			finalization: v1alpha1.FinalizationRollback,
+			body:         cluster("v22.2.9", "UPGRADE_AVAILABLE", "CLUSTER_STATUS_UNSPECIFIED"),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				phase: "RolledBack",
+				cond:  xpv1.Available(),
+			},
+		},
+		"Failed": {
+			reason:    "A failed upgrade should be unavailable.",
+			initiated: true,
+			body:      cluster("v22.2.9", "UPGRADE_AVAILABLE", "CRDB_MAJOR_UPGRADE_FAILED"),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				phase: "Failed",
+				cond:  xpv1.Unavailable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body).Upgrades()}
+			cr := &v1alpha1.ClusterVersionUpgrade{
+				Spec: v1alpha1.ClusterVersionUpgradeSpec{ForProvider: v1alpha1.ClusterVersionUpgradeParameters{
+					ClusterID:    testClusterID,
+					Version:      "v23.1",
+					Finalization: tc.finalization,
+				}},
+			}
+			if tc.initiated {
+				meta.SetExternalName(cr, testClusterID)
+			}
+			o, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.phase, cr.Status.AtProvider.Phase); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want phase, +got phase:\n%s\n", tc.reason, diff)
+			}
+			if got := cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
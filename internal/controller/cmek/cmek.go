@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmek
+
+import (
+	"context"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+)
+
+const (
+	errNotCMEK      = "managed resource is not a CMEK custom resource"
+	errNoClusterID  = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetCMEKInfo  = "cannot get customer-managed encryption of cluster"
+	errEnableCMEK   = "cannot enable customer-managed encryption of cluster"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+)
+
+// Setup adds a controller that reconciles CMEK managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.CMEKGroupKind)
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.CMEKGroupVersionKind),
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&connector{Connector: &cloud.Connector{
+			Kube:         mgr.GetClient(),
+			Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			APIInfo:      o.APIInfo,
+			NewServiceFn: o.NewService}}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.CMEK{}).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return &v1alpha1.CMEK{} }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A connector produces an ExternalClient for CMEKs.
+type connector struct {
+	*cloud.Connector
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.CMEK); !ok {
+		return nil, errors.New(errNotCMEK)
+	}
+
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	svc, err := c.Service(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{client: svc.CRDBClient}, nil
+}
+
+// An external reconciles CMEKs.
+type external struct {
+	client cockroachdb.Service
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.CMEK)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotCMEK)
+	}
+	if meta.WasDeleted(cr) {
+		// Customer-managed encryption outlives its CMEK. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	info, res, err := c.client.GetCMEKClusterInfo(ctx, cr.Spec.ForProvider.ClusterID)
+	if cloud.IsNotFound(res) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetCMEKInfo)
+	}
+
+	status := info.GetStatus()
+	if status == cockroachdb.CMEKSTATUS_DISABLED || status == cockroachdb.CMEKSTATUS_UNKNOWN_STATUS {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider = cmekObservation(info)
+	switch status {
+	case cockroachdb.CMEKSTATUS_ENABLED:
+		cr.Status.SetConditions(xpv1.Available())
+	case cockroachdb.CMEKSTATUS_ENABLING:
+		cr.Status.SetConditions(xpv1.Creating())
+	default:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	}
+	// The keys cannot be changed once customer-managed encryption is
+	// enabled, so there is nothing to update.
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.CMEK)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotCMEK)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	_, _, err := c.client.EnableCMEK(ctx, cr.Spec.ForProvider.ClusterID, cmekSpecification(cr.Spec.ForProvider))
+	return managed.ExternalCreation{}, errors.Wrap(err, errEnableCMEK)
+}
+
+// Update does nothing, as the keys cannot be changed once customer-managed
+// encryption is enabled.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete leaves customer-managed encryption enabled, as the Cloud API cannot
+// disable it. Revoking the keys would make the data of the cluster
+// inaccessible instead.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	mg.SetConditions(xpv1.Deleting())
+	return nil
+}
+
+// cmekSpecification returns the Cloud API specification of the supplied
+// keys.
+func cmekSpecification(p v1alpha1.CMEKParameters) *cockroachdb.CMEKClusterSpecification {
+	spec := &cockroachdb.CMEKClusterSpecification{RegionSpecs: make([]cockroachdb.CMEKRegionSpecification, 0, len(p.RegionSpecs))}
+	for _, r := range p.RegionSpecs {
+		region, typ, uri, principal := r.Region, cockroachdb.CMEKKeyType(r.Type), r.URI, r.AuthPrincipal
+		spec.RegionSpecs = append(spec.RegionSpecs, cockroachdb.CMEKRegionSpecification{
+			Region:  &region,
+			KeySpec: &cockroachdb.CMEKKeySpecification{Type: &typ, Uri: &uri, AuthPrincipal: &principal},
+		})
+	}
+	return spec
+}
+
+// cmekObservation returns the observed state of the supplied customer-managed
+// encryption.
+func cmekObservation(info *cockroachdb.CMEKClusterInfo) v1alpha1.CMEKObservation {
+	o := v1alpha1.CMEKObservation{Status: string(info.GetStatus())}
+	for _, r := range info.GetRegionInfos() {
+		ro := v1alpha1.CMEKRegionObservation{Region: r.GetRegion()}
+		for _, k := range r.GetKeyInfos() {
+			spec := k.GetSpec()
+			ro.Keys = append(ro.Keys, v1alpha1.CMEKKeyObservation{
+				Type:    string(spec.GetType()),
+				URI:     spec.GetUri(),
+				Status:  string(k.GetStatus()),
+				Message: k.GetUserMessage(),
+			})
+		}
+		o.Regions = append(o.Regions, ro)
+	}
+	return o
+}
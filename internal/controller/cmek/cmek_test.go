@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmek
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+type mockService struct {
+	cockroachdb.Service
+
+	MockEnableCMEK         func(ctx context.Context, clusterId string, cMEKClusterSpecification *cockroachdb.CMEKClusterSpecification) (*cockroachdb.CMEKClusterInfo, *http.Response, error)
+	MockGetCMEKClusterInfo func(ctx context.Context, clusterId string) (*cockroachdb.CMEKClusterInfo, *http.Response, error)
+}
+
+func (m *mockService) EnableCMEK(ctx context.Context, clusterId string, cMEKClusterSpecification *cockroachdb.CMEKClusterSpecification) (*cockroachdb.CMEKClusterInfo, *http.Response, error) {
+	return m.MockEnableCMEK(ctx, clusterId, cMEKClusterSpecification)
+}
+
+func (m *mockService) GetCMEKClusterInfo(ctx context.Context, clusterId string) (*cockroachdb.CMEKClusterInfo, *http.Response, error) {
+	return m.MockGetCMEKClusterInfo(ctx, clusterId)
+}
+
+func TestCMEKObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	info := func(status cockroachdb.CMEKStatus) *cockroachdb.CMEKClusterInfo {
+		region, uri, msg := "us-east-1", "arn:aws:kms:us-east-1:123:key/abc", "key is not accessible"
+		typ := cockroachdb.CMEKKEYTYPE_AWS_KMS
+		return &cockroachdb.CMEKClusterInfo{
+			Status: &status,
+			RegionInfos: &[]cockroachdb.CMEKRegionInfo{{
+				Region: &region,
+				KeyInfos: &[]cockroachdb.CMEKKeyInfo{{
+					Status:      &status,
+					UserMessage: &msg,
+					Spec:        &cockroachdb.CMEKKeySpecification{Type: &typ, Uri: &uri},
+				}},
+			}},
+		}
+	}
+	observed := func(status string) v1alpha1.CMEKObservation {
+		return v1alpha1.CMEKObservation{
+			Status: status,
+			Regions: []v1alpha1.CMEKRegionObservation{{
+				Region: "us-east-1",
+				Keys: []v1alpha1.CMEKKeyObservation{{
+					Type:    "AWS_KMS",
+					URI:     "arn:aws:kms:us-east-1:123:key/abc",
+					Status:  status,
+					Message: "key is not accessible",
+				}},
+			}},
+		}
+	}
+
+	type want struct {
+		o    managed.ExternalObservation
+		at   v1alpha1.CMEKObservation
+		cond xpv1.Condition
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason      string
+		deleted     bool
+		noClusterID bool
+		info        *cockroachdb.CMEKClusterInfo
+		status      int
+		err         error
+		want        want
+	}{
+		"Deleted": {
+			reason:  "Customer-managed encryption should not exist once its CMEK is deleted, as it cannot be disabled.",
+			deleted: true,
+			info:    info(cockroachdb.CMEKSTATUS_ENABLED),
+			status:  http.StatusOK,
+		},
+		"NoClusterID": {
+			reason:      "Customer-managed encryption cannot be observed until the referenced Cluster was created.",
+			noClusterID: true,
+			want:        want{err: errors.New(errNoClusterID)},
+		},
+		"NotFound": {
+			reason: "Customer-managed encryption should not exist if the Cloud API does not know it.",
+			status: http.StatusNotFound,
+			err:    errBoom,
+		},
+		"GetError": {
+			reason: "Errors getting customer-managed encryption should be returned.",
+			status: http.StatusInternalServerError,
+			err:    errBoom,
+			want:   want{err: errors.Wrap(errBoom, errGetCMEKInfo)},
+		},
+		"Disabled": {
+			reason: "Disabled customer-managed encryption should not exist.",
+			info:   info(cockroachdb.CMEKSTATUS_DISABLED),
+			status: http.StatusOK,
+		},
+		"Enabling": {
+			reason: "Customer-managed encryption that is being enabled should be creating.",
+			info:   info(cockroachdb.CMEKSTATUS_ENABLING),
+			status: http.StatusOK,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   observed("ENABLING"),
+				cond: xpv1.Creating(),
+			},
+		},
+		"Enabled": {
+			reason: "Enabled customer-managed encryption should be available.",
+			info:   info(cockroachdb.CMEKSTATUS_ENABLED),
+			status: http.StatusOK,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   observed("ENABLED"),
+				cond: xpv1.Available(),
+			},
+		},
+		"Revoked": {
+			reason: "Customer-managed encryption whose keys were revoked should be unavailable.",
+			info:   info(cockroachdb.CMEKSTATUS_REVOKED),
+			status: http.StatusOK,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   observed("REVOKED"),
+				cond: xpv1.Unavailable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: &mockService{MockGetCMEKClusterInfo: func(context.Context, string) (*cockroachdb.CMEKClusterInfo, *http.Response, error) {
+				return tc.info, &http.Response{StatusCode: tc.status}, tc.err
+			}}}
+			cr := &v1alpha1.CMEK{Spec: v1alpha1.CMEKSpec{ForProvider: v1alpha1.CMEKParameters{ClusterID: testClusterID}}}
+			if tc.noClusterID {
+				cr.Spec.ForProvider.ClusterID = ""
+			}
+			if tc.deleted {
+				cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}
+			o, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.at, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want atProvider, +got atProvider:\n%s\n", tc.reason, diff)
+			}
+			if got := cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
+
+func TestCMEKCreate(t *testing.T) {
+	var got *cockroachdb.CMEKClusterSpecification
+	e := &external{client: &mockService{MockEnableCMEK: func(_ context.Context, _ string, spec *cockroachdb.CMEKClusterSpecification) (*cockroachdb.CMEKClusterInfo, *http.Response, error) {
+		got = spec
+		return nil, &http.Response{StatusCode: http.StatusOK}, nil
+	}}}
+	cr := &v1alpha1.CMEK{Spec: v1alpha1.CMEKSpec{ForProvider: v1alpha1.CMEKParameters{
+		ClusterID: testClusterID,
+		RegionSpecs: []v1alpha1.CMEKRegionSpec{{
+			Region:        "us-central1",
+			Type:          v1alpha1.CMEKKeyTypeGCPCloudKMS,
+			URI:           "projects/p/locations/us-central1/keyRings/r/cryptoKeys/k",
+			AuthPrincipal: "crl@p.iam.gserviceaccount.com",
+		}},
+	}}}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+
+	region, typ := "us-central1", cockroachdb.CMEKKEYTYPE_GCP_CLOUD_KMS
+	uri, principal := "projects/p/locations/us-central1/keyRings/r/cryptoKeys/k", "crl@p.iam.gserviceaccount.com"
+	want := &cockroachdb.CMEKClusterSpecification{RegionSpecs: []cockroachdb.CMEKRegionSpecification{{
+		Region:  &region,
+		KeySpec: &cockroachdb.CMEKKeySpecification{Type: &typ, Uri: &uri, AuthPrincipal: &principal},
+	}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Create(...): -want specification, +got specification:\n%s\n", diff)
+	}
+}
@@ -17,19 +17,75 @@ limitations under the License.
 package controller
 
 import (
-	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/crossplane/provider-cockroachdb/internal/controller/backupjob"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/backupschedule"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/clientcacert"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/clouddatabase"
 	"github.com/crossplane/provider-cockroachdb/internal/controller/cluster"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/clusterversionupgrade"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cmek"
 	"github.com/crossplane/provider-cockroachdb/internal/controller/config"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/costreport"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/database"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/databaseregion"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/defaultprivileges"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/egressrule"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/grant"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/managedbackupconfig"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/metricexportcloudwatch"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/metricexportdatadog"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/privateendpointconnection"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/privateendpointservice"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/restorejob"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/restoresql"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/roledefaultsettings"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/schema"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlscript"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqluser"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/tablettlpolicy"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/userrolegrant"
 )
 
 // Setup creates all CockroachDB controllers with the supplied logger and adds them to
 // the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	for _, setup := range []func(ctrl.Manager, cloud.Options) error{
 		config.Setup,
 		cluster.Setup,
+		cluster.SetupNamespaced,
+		sqluser.Setup,
+		sqluser.SetupNamespaced,
+		clouddatabase.Setup,
+		database.Setup,
+		database.SetupNamespaced,
+		grant.Setup,
+		schema.Setup,
+		defaultprivileges.Setup,
+		tablettlpolicy.Setup,
+		databaseregion.Setup,
+		sqlscript.Setup,
+		roledefaultsettings.Setup,
+		backupschedule.Setup,
+		backupjob.Setup,
+		restoresql.Setup,
+		privateendpointservice.Setup,
+		privateendpointconnection.Setup,
+		cmek.Setup,
+		metricexportdatadog.Setup,
+		metricexportcloudwatch.Setup,
+		clientcacert.Setup,
+		userrolegrant.Setup,
+		egressrule.Setup,
+		managedbackupconfig.Setup,
+		restorejob.Setup,
+		clusterversionupgrade.Setup,
+		cluster.SetupDiscovery,
+		cluster.SetupInventory,
+		cluster.SetupTrustBundle,
+		costreport.Setup,
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -37,3 +93,17 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	}
 	return nil
 }
+
+// SetupWebhooks adds all CockroachDB admission webhooks to the supplied
+// manager. Resources that are not namespaced default to the supplied
+// namespace.
+func SetupWebhooks(mgr ctrl.Manager, o cloud.Options, namespace string) error {
+	for _, setup := range []func(ctrl.Manager, cloud.Options, string) error{
+		cluster.SetupWebhook,
+	} {
+		if err := setup(mgr, o, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costreport
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	costReportInterval = time.Hour
+	costReportTimeout  = 2 * time.Minute
+
+	// defaultCurrency is assumed for invoices without totals.
+	defaultCurrency = "USD"
+
+	errGetCostReport       = "cannot get CostReport"
+	errUpdateCostReport    = "cannot update status of CostReport"
+	errListInvoices        = "cannot list invoices"
+	errGetInvoice          = "cannot get invoice"
+	errNoInvoices          = "the organization has no invoices yet"
+	errListManagedClusters = "cannot list managed Clusters"
+)
+
+// Setup adds a controller that allocates the spend of invoices to the labels
+// of the Clusters they were billed for.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := "costreport/" + strings.ToLower(v1alpha1.CostReportGroupKind)
+
+	c := &cloud.Connector{Kube: mgr.GetClient(), NewServiceFn: o.NewService}
+	r := &costReporter{
+		kube:    mgr.GetClient(),
+		service: c.ServiceFor,
+		log:     o.Logger.WithValues("controller", name),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.CostReport{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A costReporter reports the spend of an invoice of the organization of a
+// ProviderConfig.
+type costReporter struct {
+	kube    client.Client
+	service func(ctx context.Context, providerConfig string) (*cloud.Service, error)
+	log     logging.Logger
+}
+
+// Reconcile reports the invoice of the requested CostReport.
+func (r *costReporter) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	ctx, cancel := context.WithTimeout(ctx, costReportTimeout)
+	defer cancel()
+
+	cr := &v1alpha1.CostReport{}
+	if err := r.kube.Get(ctx, req.NamespacedName, cr); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetCostReport)
+	}
+
+	if err := r.report(ctx, cr); err != nil {
+		log.Debug("Cannot report invoice", "error", err)
+		cr.Status.SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.kube.Status().Update(ctx, cr), errUpdateCostReport)
+	}
+
+	cr.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: costReportInterval}, errors.Wrap(r.kube.Status().Update(ctx, cr), errUpdateCostReport)
+}
+
+// report sets the spend of the invoice selected by the supplied CostReport
+// in its status.
+func (r *costReporter) report(ctx context.Context, cr *v1alpha1.CostReport) error {
+	svc, err := r.service(ctx, cr.Spec.ProviderConfigReference.Name)
+	if err != nil {
+		return err
+	}
+	inv, err := invoice(ctx, svc.CloudClient, cr.Spec.InvoiceID)
+	if err != nil {
+		return err
+	}
+	managed, err := r.managedClusters(ctx)
+	if err != nil {
+		return err
+	}
+	allocateCosts(cr, inv, managed)
+	return nil
+}
+
+// invoice returns the supplied invoice, or that of the latest billing period
+// if none is supplied.
+func invoice(ctx context.Context, c *cockroachcloud.Client, id string) (*cockroachcloud.Invoice, error) {
+	if id == "" {
+		invs, err := c.ListInvoices(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, errListInvoices)
+		}
+		if len(invs) == 0 {
+			return nil, errors.New(errNoInvoices)
+		}
+		latest := invs[0]
+		for _, inv := range invs[1:] {
+			if inv.PeriodEnd.After(latest.PeriodEnd) {
+				latest = inv
+			}
+		}
+		id = latest.InvoiceID
+	}
+	inv, err := c.GetInvoice(ctx, id)
+	return inv, errors.Wrap(err, errGetInvoice)
+}
+
+// managedClusters returns the cluster scoped and namespaced Clusters, keyed
+// by the ID of the cluster they manage.
+func (r *costReporter) managedClusters(ctx context.Context) (map[string]metav1.Object, error) {
+	clusters := map[string]metav1.Object{}
+
+	l := &v1alpha1.ClusterList{}
+	if err := r.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListManagedClusters)
+	}
+	for i := range l.Items {
+		clusters[meta.GetExternalName(&l.Items[i])] = &l.Items[i]
+	}
+
+	nl := &namespacedv1alpha1.ClusterList{}
+	if err := r.kube.List(ctx, nl); err != nil {
+		return nil, errors.Wrap(err, errListManagedClusters)
+	}
+	for i := range nl.Items {
+		clusters[meta.GetExternalName(&nl.Items[i])] = &nl.Items[i]
+	}
+	return clusters, nil
+}
+
+// allocateCosts sets the spend of the supplied invoice in the status of the
+// supplied CostReport, allocated to the labels of the supplied Clusters. The
+// spend of clusters no Cluster manages is reported as unmanaged, in total and
+// by cluster.
+func allocateCosts(cr *v1alpha1.CostReport, inv *cockroachcloud.Invoice, managed map[string]metav1.Object) {
+	currency := defaultCurrency
+	if len(inv.Totals) > 0 {
+		currency = inv.Totals[0].Currency
+	}
+
+	type allocation struct {
+		amount   float64
+		clusters int32
+	}
+	allocs := map[[2]string]*allocation{}
+	unmanaged := 0.0
+	clusters := []v1alpha1.ClusterCost{}
+	unmanagedClusters := []v1alpha1.UnmanagedClusterCost{}
+	for _, item := range inv.InvoiceItems {
+		amount := total(item.Totals, currency)
+		cl, ok := managed[item.Cluster.ID]
+		if !ok {
+			unmanaged += amount
+			unmanagedClusters = append(unmanagedClusters, v1alpha1.UnmanagedClusterCost{Name: item.Cluster.Name, ID: item.Cluster.ID, Amount: formatAmount(amount)})
+			continue
+		}
+		clusters = append(clusters, v1alpha1.ClusterCost{Name: cl.GetName(), Namespace: cl.GetNamespace(), ID: item.Cluster.ID, Amount: formatAmount(amount)})
+		for _, k := range cr.Spec.LabelKeys {
+			key := [2]string{k, cl.GetLabels()[k]}
+			if allocs[key] == nil {
+				allocs[key] = &allocation{}
+			}
+			allocs[key].amount += amount
+			allocs[key].clusters++
+		}
+	}
+
+	cr.Status.Allocations = make([]v1alpha1.CostAllocation, 0, len(allocs))
+	for key, a := range allocs {
+		cr.Status.Allocations = append(cr.Status.Allocations, v1alpha1.CostAllocation{LabelKey: key[0], LabelValue: key[1], Amount: formatAmount(a.amount), Clusters: a.clusters})
+	}
+	sort.Slice(cr.Status.Allocations, func(i, j int) bool {
+		a, b := cr.Status.Allocations[i], cr.Status.Allocations[j]
+		if a.LabelKey != b.LabelKey {
+			return a.LabelKey < b.LabelKey
+		}
+		return a.LabelValue < b.LabelValue
+	})
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Namespace != clusters[j].Namespace {
+			return clusters[i].Namespace < clusters[j].Namespace
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+	sort.Slice(unmanagedClusters, func(i, j int) bool {
+		if unmanagedClusters[i].Name != unmanagedClusters[j].Name {
+			return unmanagedClusters[i].Name < unmanagedClusters[j].Name
+		}
+		return unmanagedClusters[i].ID < unmanagedClusters[j].ID
+	})
+
+	start, end := metav1.NewTime(inv.PeriodStart), metav1.NewTime(inv.PeriodEnd)
+	cr.Status.InvoiceID = inv.InvoiceID
+	cr.Status.PeriodStart = &start
+	cr.Status.PeriodEnd = &end
+	cr.Status.Currency = currency
+	cr.Status.Total = formatAmount(total(inv.Totals, currency))
+	cr.Status.Unmanaged = formatAmount(unmanaged)
+	cr.Status.Clusters = clusters
+	cr.Status.UnmanagedClusters = unmanagedClusters
+}
+
+// total returns the sum of the supplied amounts in the supplied currency.
+func total(amounts []cockroachcloud.CurrencyAmount, currency string) float64 {
+	sum := 0.0
+	for _, a := range amounts {
+		if a.Currency == currency {
+			sum += a.Amount
+		}
+	}
+	return sum
+}
+
+func formatAmount(a float64) string {
+	return strconv.FormatFloat(a, 'f', 2, 64)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+func TestAllocateCosts(t *testing.T) {
+	start := metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))
+	usd := func(a float64) []cockroachcloud.CurrencyAmount {
+		return []cockroachcloud.CurrencyAmount{{Amount: a, Currency: "USD"}}
+	}
+
+	inv := &cockroachcloud.Invoice{
+		InvoiceID:   "inv-1",
+		PeriodStart: start.Time,
+		PeriodEnd:   end.Time,
+		Totals:      usd(60),
+		InvoiceItems: []cockroachcloud.InvoiceItem{
+			{Cluster: cockroachcloud.InvoiceCluster{ID: "a"}, Totals: usd(10)},
+			{Cluster: cockroachcloud.InvoiceCluster{ID: "b"}, Totals: usd(20)},
+			{Cluster: cockroachcloud.InvoiceCluster{ID: "c"}, Totals: usd(25)},
+			{Cluster: cockroachcloud.InvoiceCluster{ID: "d", Name: "legacy"}, Totals: usd(5)},
+		},
+	}
+	managed := map[string]metav1.Object{
+		"a": &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"team": "payments"}}},
+		"b": &namespacedv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "search", Labels: map[string]string{"team": "search"}}},
+		"c": &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "c", Labels: map[string]string{"team": "payments"}}},
+	}
+	cr := &v1alpha1.CostReport{Spec: v1alpha1.CostReportSpec{LabelKeys: []string{"team", "env"}}}
+
+	allocateCosts(cr, inv, managed)
+
+	want := v1alpha1.CostReportStatus{
+		InvoiceID:   "inv-1",
+		PeriodStart: &start,
+		PeriodEnd:   &end,
+		Currency:    "USD",
+		Total:       "60.00",
+		Unmanaged:   "5.00",
+		Allocations: []v1alpha1.CostAllocation{
+			{LabelKey: "env", LabelValue: "", Amount: "55.00", Clusters: 3},
+			{LabelKey: "team", LabelValue: "payments", Amount: "35.00", Clusters: 2},
+			{LabelKey: "team", LabelValue: "search", Amount: "20.00", Clusters: 1},
+		},
+		Clusters: []v1alpha1.ClusterCost{
+			{Name: "a", ID: "a", Amount: "10.00"},
+			{Name: "c", ID: "c", Amount: "25.00"},
+			{Name: "b", Namespace: "search", ID: "b", Amount: "20.00"},
+		},
+		UnmanagedClusters: []v1alpha1.UnmanagedClusterCost{
+			{Name: "legacy", ID: "d", Amount: "5.00"},
+		},
+	}
+	if diff := cmp.Diff(want, cr.Status); diff != "" {
+		t.Errorf("\nThe spend of managed clusters should be allocated by label, and the rest reported as unmanaged.\nallocateCosts(...): -want, +got:\n%s\n", diff)
+	}
+}
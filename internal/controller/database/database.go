@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotDatabase     = "managed resource is not a Database custom resource"
+	errObserveDatabase = "cannot observe database"
+	errUpdateDatabase  = "cannot update database"
+	errDropDatabase    = "cannot drop database"
+	errCreateDatabase  = "cannot create database"
+)
+
+// Setup adds a controller that reconciles Database managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.DatabaseGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.DatabaseGroupVersionKind, &v1alpha1.Database{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles Databases over SQL.
+type external struct {
+	db *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Database)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDatabase)
+	}
+
+	var owner string
+	err := c.db.QueryRow(ctx, "SELECT owner FROM [SHOW DATABASES] WHERE database_name = $1", meta.GetExternalName(cr)).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveDatabase)
+	}
+
+	cr.Status.AtProvider.Owner = owner
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: ownerUpToDate(cr.Spec.ForProvider, owner),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Database)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDatabase)
+	}
+	stmts := createDatabaseStatements(meta.GetExternalName(cr), cr.Spec.ForProvider)
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmts...), errCreateDatabase)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Database)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotDatabase)
+	}
+	stmts := alterDatabaseStatements(meta.GetExternalName(cr), cr.Spec.ForProvider)
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmts...), errUpdateDatabase)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Database)
+	if !ok {
+		return errors.New(errNotDatabase)
+	}
+	stmt := dropDatabaseStatement(meta.GetExternalName(cr), cr.Spec.ForProvider.Cascade)
+	return errors.Wrap(c.db.Exec(ctx, stmt), errDropDatabase)
+}
+
+// createDatabaseStatements returns the SQL statements that create the named
+// database with the supplied parameters.
+func createDatabaseStatements(name string, p v1alpha1.DatabaseParameters) []string {
+	return append([]string{"CREATE DATABASE IF NOT EXISTS " + pgx.Identifier{name}.Sanitize()}, alterDatabaseStatements(name, p)...)
+}
+
+// alterDatabaseStatements returns the SQL statements that apply the supplied
+// parameters to the named database.
+func alterDatabaseStatements(name string, p v1alpha1.DatabaseParameters) []string {
+	stmts := []string{}
+	if p.Owner != nil {
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pgx.Identifier{name}.Sanitize(), pgx.Identifier{normalizeRoleName(*p.Owner)}.Sanitize()))
+	}
+	return stmts
+}
+
+// ownerUpToDate returns true if the supplied parameters set no owner, or the
+// supplied owner of the database.
+func ownerUpToDate(p v1alpha1.DatabaseParameters, owner string) bool {
+	return p.Owner == nil || normalizeRoleName(*p.Owner) == owner
+}
+
+// normalizeRoleName returns the supplied role name the way CockroachDB stores
+// it. Role names are case insensitive, and stored in lower case.
+func normalizeRoleName(name string) string {
+	return strings.ToLower(name)
+}
+
+// dropDatabaseStatement returns the SQL statement that drops the named
+// database, along with its objects if cascade is true.
+func dropDatabaseStatement(name string, cascade bool) string {
+	behavior := "RESTRICT"
+	if cascade {
+		behavior = "CASCADE"
+	}
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s %s", pgx.Identifier{name}.Sanitize(), behavior)
+}
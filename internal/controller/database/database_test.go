@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestCreateDatabaseStatements(t *testing.T) {
+	owner := "app-owner"
+	mixedCaseOwner := "App-Owner"
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.DatabaseParameters
+		want   []string
+	}{
+		"Defaults": {
+			reason: "A database without an owner should be owned by the user it is created as.",
+			want:   []string{`CREATE DATABASE IF NOT EXISTS "app"`},
+		},
+		"Owner": {
+			reason: "The owner of a database should be set once it was created.",
+			p:      v1alpha1.DatabaseParameters{Owner: &owner},
+			want: []string{
+				`CREATE DATABASE IF NOT EXISTS "app"`,
+				`ALTER DATABASE "app" OWNER TO "app-owner"`,
+			},
+		},
+		"MixedCaseOwner": {
+			reason: "The owner of a database should be normalized like CockroachDB normalizes role names.",
+			p:      v1alpha1.DatabaseParameters{Owner: &mixedCaseOwner},
+			want: []string{
+				`CREATE DATABASE IF NOT EXISTS "app"`,
+				`ALTER DATABASE "app" OWNER TO "app-owner"`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := createDatabaseStatements("app", tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncreateDatabaseStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDropDatabaseStatement(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		cascade bool
+		want    string
+	}{
+		"Restrict": {
+			reason: "Only empty databases should be dropped by default.",
+			want:   `DROP DATABASE IF EXISTS "app" RESTRICT`,
+		},
+		"Cascade": {
+			reason:  "Databases should be dropped along with their objects if requested.",
+			cascade: true,
+			want:    `DROP DATABASE IF EXISTS "app" CASCADE`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := dropDatabaseStatement("app", tc.cascade)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndropDatabaseStatement(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOwnerUpToDate(t *testing.T) {
+	owner := "App-Owner"
+	other := "other"
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.DatabaseParameters
+		owner  string
+		want   bool
+	}{
+		"NoOwner": {
+			reason: "A database whose owner is not managed should be up to date.",
+			owner:  "root",
+			want:   true,
+		},
+		"SameOwner": {
+			reason: "An owner that only differs in case should be up to date, as CockroachDB stores role names in lower case.",
+			p:      v1alpha1.DatabaseParameters{Owner: &owner},
+			owner:  "app-owner",
+			want:   true,
+		},
+		"OtherOwner": {
+			reason: "A database owned by another role should not be up to date.",
+			p:      v1alpha1.DatabaseParameters{Owner: &other},
+			owner:  "app-owner",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ownerUpToDate(tc.p, tc.owner); got != tc.want {
+				t.Errorf("\n%s\nownerUpToDate(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
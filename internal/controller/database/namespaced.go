@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotNamespacedDatabase = "managed resource is not a namespaced Database custom resource"
+)
+
+// SetupNamespaced adds a controller that reconciles namespaced Database
+// managed resources.
+func SetupNamespaced(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(namespacedv1alpha1.DatabaseGroupKind)
+	return sqlresource.Setup(mgr, o, name, namespacedv1alpha1.DatabaseGroupVersionKind, &namespacedv1alpha1.Database{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &namespacedExternal{external: &external{db: db}}
+	}, managed.WithConnectionPublishers(cloud.NewLocalConnectionPublisher(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()))))
+}
+
+// A namespacedExternal reconciles namespaced Databases by delegating to the
+// cluster scoped external client.
+type namespacedExternal struct {
+	external *external
+}
+
+func (c *namespacedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Database)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotNamespacedDatabase)
+	}
+	d := databaseFor(cr)
+	o, err := c.external.Observe(ctx, d)
+	setFromDatabase(cr, d)
+	return o, err
+}
+
+func (c *namespacedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Database)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotNamespacedDatabase)
+	}
+	d := databaseFor(cr)
+	cre, err := c.external.Create(ctx, d)
+	setFromDatabase(cr, d)
+	return cre, err
+}
+
+func (c *namespacedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*namespacedv1alpha1.Database)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNamespacedDatabase)
+	}
+	d := databaseFor(cr)
+	u, err := c.external.Update(ctx, d)
+	setFromDatabase(cr, d)
+	return u, err
+}
+
+func (c *namespacedExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*namespacedv1alpha1.Database)
+	if !ok {
+		return errors.New(errNotNamespacedDatabase)
+	}
+	d := databaseFor(cr)
+	err := c.external.Delete(ctx, d)
+	setFromDatabase(cr, d)
+	return err
+}
+
+// databaseFor returns the cluster scoped equivalent of a namespaced Database.
+func databaseFor(cr *namespacedv1alpha1.Database) *v1alpha1.Database {
+	return &v1alpha1.Database{
+		ObjectMeta: *cr.ObjectMeta.DeepCopy(),
+		Spec: v1alpha1.DatabaseSpec{
+			ResourceSpec: *cr.Spec.ResourceSpec.DeepCopy(),
+			ForProvider:  *cr.Spec.ForProvider.DeepCopy(),
+		},
+		Status: v1alpha1.DatabaseStatus{
+			ResourceStatus: *cr.Status.ResourceStatus.DeepCopy(),
+			AtProvider:     *cr.Status.AtProvider.DeepCopy(),
+		},
+	}
+}
+
+// setFromDatabase copies the state written by the external client back to the
+// namespaced Database.
+func setFromDatabase(cr *namespacedv1alpha1.Database, d *v1alpha1.Database) {
+	cr.SetAnnotations(d.GetAnnotations())
+	cr.Status.ResourceStatus = d.Status.ResourceStatus
+	cr.Status.AtProvider = d.Status.AtProvider
+}
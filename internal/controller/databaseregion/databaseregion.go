@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databaseregion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotDatabaseRegion        = "managed resource is not a DatabaseRegion custom resource"
+	errNoDatabaseRegionDatabase = "no database to manage the regions of: set database, databaseRef or databaseSelector"
+	errObserveDatabaseRegions   = "cannot observe regions of database"
+	errUpdateDatabaseRegions    = "cannot update regions of database"
+	errDropDatabaseRegions      = "cannot drop regions of database"
+)
+
+// Setup adds a controller that reconciles DatabaseRegion managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.DatabaseRegionGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.DatabaseRegionGroupVersionKind, &v1alpha1.DatabaseRegion{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles DatabaseRegions over SQL.
+type external struct {
+	db *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.DatabaseRegion)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDatabaseRegion)
+	}
+	if cr.Spec.ForProvider.Database == "" {
+		return managed.ExternalObservation{}, errors.New(errNoDatabaseRegionDatabase)
+	}
+
+	o, err := c.observe(ctx, cr.Spec.ForProvider.Database)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveDatabaseRegions)
+	}
+	if o.PrimaryRegion == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider = o
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(databaseRegionStatements(cr.Spec.ForProvider, o)) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.DatabaseRegion)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDatabaseRegion)
+	}
+	stmts := databaseRegionStatements(cr.Spec.ForProvider, v1alpha1.DatabaseRegionObservation{})
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmts...), errUpdateDatabaseRegions)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.DatabaseRegion)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotDatabaseRegion)
+	}
+	o, err := c.observe(ctx, cr.Spec.ForProvider.Database)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errObserveDatabaseRegions)
+	}
+	stmts := databaseRegionStatements(cr.Spec.ForProvider, o)
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmts...), errUpdateDatabaseRegions)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.DatabaseRegion)
+	if !ok {
+		return errors.New(errNotDatabaseRegion)
+	}
+	o, err := c.observe(ctx, cr.Spec.ForProvider.Database)
+	if err != nil {
+		return errors.Wrap(err, errObserveDatabaseRegions)
+	}
+	stmts := dropDatabaseRegionsStatements(cr.Spec.ForProvider.Database, o)
+	return errors.Wrap(c.db.Exec(ctx, stmts...), errDropDatabaseRegions)
+}
+
+// observe returns the regions and survival goal of the supplied database. A
+// database that does not exist has no regions.
+func (c *external) observe(ctx context.Context, db string) (v1alpha1.DatabaseRegionObservation, error) {
+	o := v1alpha1.DatabaseRegionObservation{}
+	err := c.db.QueryRow(ctx, "SELECT COALESCE(primary_region, ''), COALESCE(regions, ARRAY[]::STRING[]), COALESCE(survival_goal, '') FROM [SHOW DATABASES] WHERE database_name = $1", db).
+		Scan(&o.PrimaryRegion, &o.Regions, &o.SurvivalGoal)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return v1alpha1.DatabaseRegionObservation{}, nil
+	}
+	o.SurvivalGoal = strings.ToUpper(o.SurvivalGoal)
+	sort.Strings(o.Regions)
+	return o, err
+}
+
+// databaseRegionStatements returns the SQL statements that change the
+// supplied regions and survival goal of a database to those of the supplied
+// parameters. Regions are added before the primary region or the survival
+// goal change, and dropped after, as both may depend on them.
+func databaseRegionStatements(p v1alpha1.DatabaseRegionParameters, o v1alpha1.DatabaseRegionObservation) []string {
+	db := pgx.Identifier{p.Database}.Sanitize()
+	have := map[string]bool{}
+	for _, r := range o.Regions {
+		have[r] = true
+	}
+	want := map[string]bool{p.PrimaryRegion: true}
+	for _, r := range p.Regions {
+		want[r] = true
+	}
+
+	stmts := []string{}
+	if o.PrimaryRegion == "" {
+		// Setting the primary region makes the database a multi-region
+		// database, with the primary region as its only region.
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s SET PRIMARY REGION %s", db, pgx.Identifier{p.PrimaryRegion}.Sanitize()))
+		have[p.PrimaryRegion] = true
+	}
+	for _, r := range sortedRegions(want) {
+		if !have[r] {
+			stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s ADD REGION %s", db, pgx.Identifier{r}.Sanitize()))
+		}
+	}
+	if o.PrimaryRegion != "" && o.PrimaryRegion != p.PrimaryRegion {
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s SET PRIMARY REGION %s", db, pgx.Identifier{p.PrimaryRegion}.Sanitize()))
+	}
+	if p.SurvivalGoal != "" && !strings.EqualFold(p.SurvivalGoal, o.SurvivalGoal) {
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s SURVIVE %s FAILURE", db, strings.ToUpper(p.SurvivalGoal)))
+	}
+	for _, r := range sortedRegions(have) {
+		if !want[r] {
+			stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s DROP REGION %s", db, pgx.Identifier{r}.Sanitize()))
+		}
+	}
+	return stmts
+}
+
+// dropDatabaseRegionsStatements returns the SQL statements that drop the
+// supplied regions of a database, which makes it a single-region database.
+// The primary region can only be dropped last, and only while the database
+// survives zone failures.
+func dropDatabaseRegionsStatements(database string, o v1alpha1.DatabaseRegionObservation) []string {
+	db := pgx.Identifier{database}.Sanitize()
+	stmts := []string{}
+	if o.PrimaryRegion == "" {
+		return stmts
+	}
+	if strings.EqualFold(o.SurvivalGoal, v1alpha1.SurvivalGoalRegion) {
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s SURVIVE ZONE FAILURE", db))
+	}
+	for _, r := range o.Regions {
+		if r != o.PrimaryRegion {
+			stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s DROP REGION %s", db, pgx.Identifier{r}.Sanitize()))
+		}
+	}
+	return append(stmts, fmt.Sprintf("ALTER DATABASE %s DROP REGION %s", db, pgx.Identifier{o.PrimaryRegion}.Sanitize()))
+}
+
+// sortedRegions returns the supplied set of regions in order.
+func sortedRegions(regions map[string]bool) []string {
+	rs := make([]string, 0, len(regions))
+	for r := range regions {
+		rs = append(rs, r)
+	}
+	sort.Strings(rs)
+	return rs
+}
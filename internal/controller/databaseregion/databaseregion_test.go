@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databaseregion
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestDatabaseRegionStatements(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.DatabaseRegionParameters
+		o      v1alpha1.DatabaseRegionObservation
+		want   []string
+	}{
+		"SingleRegion": {
+			reason: "A single-region database should become a multi-region database, with regions added after the primary region.",
+			p:      v1alpha1.DatabaseRegionParameters{Database: "app", PrimaryRegion: "us-east1", Regions: []string{"us-west1", "europe-west1"}, SurvivalGoal: "REGION"},
+			want: []string{
+				`ALTER DATABASE "app" SET PRIMARY REGION "us-east1"`,
+				`ALTER DATABASE "app" ADD REGION "europe-west1"`,
+				`ALTER DATABASE "app" ADD REGION "us-west1"`,
+				`ALTER DATABASE "app" SURVIVE REGION FAILURE`,
+			},
+		},
+		"UpToDate": {
+			reason: "No statements should be needed when the database has the desired regions and survival goal.",
+			p:      v1alpha1.DatabaseRegionParameters{Database: "app", PrimaryRegion: "us-east1", Regions: []string{"us-west1"}},
+			o:      v1alpha1.DatabaseRegionObservation{PrimaryRegion: "us-east1", Regions: []string{"us-east1", "us-west1"}, SurvivalGoal: "ZONE"},
+			want:   []string{},
+		},
+		"MovePrimary": {
+			reason: "A new primary region should be added before it is made primary, and the old one dropped after.",
+			p:      v1alpha1.DatabaseRegionParameters{Database: "app", PrimaryRegion: "us-west1", SurvivalGoal: "ZONE"},
+			o:      v1alpha1.DatabaseRegionObservation{PrimaryRegion: "us-east1", Regions: []string{"us-east1"}, SurvivalGoal: "ZONE"},
+			want: []string{
+				`ALTER DATABASE "app" ADD REGION "us-west1"`,
+				`ALTER DATABASE "app" SET PRIMARY REGION "us-west1"`,
+				`ALTER DATABASE "app" DROP REGION "us-east1"`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := databaseRegionStatements(tc.p, tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndatabaseRegionStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDropDatabaseRegionsStatements(t *testing.T) {
+	o := v1alpha1.DatabaseRegionObservation{PrimaryRegion: "us-east1", Regions: []string{"europe-west1", "us-east1", "us-west1"}, SurvivalGoal: "REGION"}
+	want := []string{
+		`ALTER DATABASE "app" SURVIVE ZONE FAILURE`,
+		`ALTER DATABASE "app" DROP REGION "europe-west1"`,
+		`ALTER DATABASE "app" DROP REGION "us-west1"`,
+		`ALTER DATABASE "app" DROP REGION "us-east1"`,
+	}
+	if diff := cmp.Diff(want, dropDatabaseRegionsStatements("app", o)); diff != "" {
+		t.Errorf("dropDatabaseRegionsStatements(...): -want, +got:\n%s\n", diff)
+	}
+}
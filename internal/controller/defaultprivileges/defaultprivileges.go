@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultprivileges
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/grant"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotDefaultPrivileges        = "managed resource is not a DefaultPrivileges custom resource"
+	errNoDefaultPrivilegesDatabase = "no database to set default privileges in: set database, databaseRef or databaseSelector"
+	errObserveDefaultPrivileges    = "cannot observe default privileges of role"
+	errApplyDefaultPrivileges      = "cannot set default privileges of role"
+	errRevokeDefaultPrivileges     = "cannot revoke default privileges from role"
+
+	defaultObjectType = "TABLES"
+)
+
+// Setup adds a controller that reconciles DefaultPrivileges managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.DefaultPrivilegesGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.DefaultPrivilegesGroupVersionKind, &v1alpha1.DefaultPrivileges{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles DefaultPrivileges over SQL, while
+// connected to their database.
+type external struct {
+	db *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.DefaultPrivileges)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotDefaultPrivileges)
+	}
+	if cr.Spec.ForProvider.Database == "" {
+		return managed.ExternalObservation{}, errors.New(errNoDefaultPrivilegesDatabase)
+	}
+
+	have, err := c.observe(ctx, cr.Spec.ForProvider)
+	if sqlclient.IsNotFound(err) && meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveDefaultPrivileges)
+	}
+	if len(have) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	stmts, err := defaultPrivilegesStatements(cr.Spec.ForProvider, have)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(stmts) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.DefaultPrivileges)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotDefaultPrivileges)
+	}
+	stmts, err := defaultPrivilegesStatements(cr.Spec.ForProvider, grant.GrantedPrivileges{})
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmts...), errApplyDefaultPrivileges)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.DefaultPrivileges)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotDefaultPrivileges)
+	}
+	have, err := c.observe(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errObserveDefaultPrivileges)
+	}
+	stmts, err := defaultPrivilegesStatements(cr.Spec.ForProvider, have)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmts...), errApplyDefaultPrivileges)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.DefaultPrivileges)
+	if !ok {
+		return errors.New(errNotDefaultPrivileges)
+	}
+	p := cr.Spec.ForProvider
+	privs, err := grant.NormalizePrivileges(p.Privileges)
+	if err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf("ALTER DEFAULT PRIVILEGES%s REVOKE %s ON %s FROM %s", defaultPrivilegesScope(p), grant.PrivilegeList(privs), objectType(p), pgx.Identifier{p.Role}.Sanitize())
+	return errors.Wrap(c.db.Exec(ctx, stmt), errRevokeDefaultPrivileges)
+}
+
+// observe returns the default privileges of the role of the supplied
+// parameters.
+func (c *external) observe(ctx context.Context, p v1alpha1.DefaultPrivilegesParameters) (grant.GrantedPrivileges, error) {
+	rows, err := c.db.Query(ctx, fmt.Sprintf("SELECT privilege_type, is_grantable FROM [SHOW DEFAULT PRIVILEGES%s] WHERE grantee = $1 AND object_type = $2", defaultPrivilegesScope(p)), p.Role, strings.ToLower(objectType(p)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	have := grant.GrantedPrivileges{}
+	for rows.Next() {
+		var priv string
+		var grantable bool
+		if err := rows.Scan(&priv, &grantable); err != nil {
+			return nil, err
+		}
+		have[strings.ToUpper(priv)] = grantable
+	}
+	return have, rows.Err()
+}
+
+// defaultPrivilegesStatements returns the SQL statements that change the
+// supplied default privileges of the role to those of the supplied
+// parameters.
+func defaultPrivilegesStatements(p v1alpha1.DefaultPrivilegesParameters, have grant.GrantedPrivileges) ([]string, error) {
+	stmts, err := grant.PrivilegeStatements(p.Privileges, p.WithGrantOption, have, objectType(p), pgx.Identifier{p.Role}.Sanitize())
+	if err != nil {
+		return nil, err
+	}
+	for i := range stmts {
+		stmts[i] = "ALTER DEFAULT PRIVILEGES" + defaultPrivilegesScope(p) + " " + stmts[i]
+	}
+	return stmts, nil
+}
+
+// defaultPrivilegesScope returns the FOR ROLE and IN SCHEMA clauses of the
+// supplied parameters, if any.
+func defaultPrivilegesScope(p v1alpha1.DefaultPrivilegesParameters) string {
+	scope := ""
+	if p.ForRole != "" {
+		scope += " FOR ROLE " + pgx.Identifier{p.ForRole}.Sanitize()
+	}
+	if p.Schema != "" {
+		scope += " IN SCHEMA " + pgx.Identifier{p.Schema}.Sanitize()
+	}
+	return scope
+}
+
+// objectType returns the type of the objects the supplied parameters apply
+// to.
+func objectType(p v1alpha1.DefaultPrivilegesParameters) string {
+	if p.ObjectType == "" {
+		return defaultObjectType
+	}
+	return p.ObjectType
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultprivileges
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/grant"
+)
+
+func TestDefaultPrivilegesStatements(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.DefaultPrivilegesParameters
+		have   grant.GrantedPrivileges
+		want   []string
+		err    error
+	}{
+		"Tables": {
+			reason: "Default privileges should apply to tables created by the current user by default.",
+			p:      v1alpha1.DefaultPrivilegesParameters{Database: "app", Role: "reader", Privileges: []string{"select"}},
+			want:   []string{`ALTER DEFAULT PRIVILEGES GRANT SELECT ON TABLES TO "reader"`},
+		},
+		"ForRoleInSchema": {
+			reason: "Default privileges should apply to objects a role creates in a schema, if requested.",
+			p:      v1alpha1.DefaultPrivilegesParameters{Database: "app", Schema: "billing", ForRole: "migrator", ObjectType: "SEQUENCES", Role: "app", Privileges: []string{"USAGE"}, WithGrantOption: true},
+			want:   []string{`ALTER DEFAULT PRIVILEGES FOR ROLE "migrator" IN SCHEMA "billing" GRANT USAGE ON SEQUENCES TO "app" WITH GRANT OPTION`},
+		},
+		"Drift": {
+			reason: "Extra default privileges should be revoked, and missing ones granted.",
+			p:      v1alpha1.DefaultPrivilegesParameters{Database: "app", Role: "reader", Privileges: []string{"SELECT"}},
+			have:   grant.GrantedPrivileges{"DELETE": false},
+			want: []string{
+				`ALTER DEFAULT PRIVILEGES REVOKE DELETE ON TABLES FROM "reader"`,
+				`ALTER DEFAULT PRIVILEGES GRANT SELECT ON TABLES TO "reader"`,
+			},
+		},
+		"UpToDate": {
+			reason: "No statements should be needed when the role has exactly the desired default privileges.",
+			p:      v1alpha1.DefaultPrivilegesParameters{Database: "app", Role: "reader", Privileges: []string{"SELECT"}},
+			have:   grant.GrantedPrivileges{"SELECT": false},
+			want:   []string{},
+		},
+		"InvalidPrivilege": {
+			reason: "Privileges that are not privilege keywords should never be interpolated into statements.",
+			p:      v1alpha1.DefaultPrivilegesParameters{Database: "app", Role: "reader", Privileges: []string{"SELECT ON TABLES TO public; --"}},
+			err:    errors.New(`invalid privilege "SELECT ON TABLES TO public; --"`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := defaultPrivilegesStatements(tc.p, tc.have)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndefaultPrivilegesStatements(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndefaultPrivilegesStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egressrule
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotEgressRule    = "managed resource is not an EgressRule custom resource"
+	errNoClusterID      = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetEgressRule    = "cannot get egress rule"
+	errCreateEgressRule = "cannot create egress rule"
+	errUpdateEgressRule = "cannot update egress rule"
+	errDeleteEgressRule = "cannot delete egress rule"
+)
+
+// Setup adds a controller that reconciles EgressRule managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.EgressRuleGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.EgressRuleGroupVersionKind, &v1alpha1.EgressRule{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles EgressRules.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.EgressRule)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotEgressRule)
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	rule, err := c.client.GetEgressRule(ctx, cr.Spec.ForProvider.ClusterID, id)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetEgressRule)
+	}
+
+	cr.Status.AtProvider.State = string(rule.State)
+	switch rule.State {
+	case cockroachcloud.EgressRuleStateActive:
+		cr.Status.SetConditions(xpv1.Available())
+	case cockroachcloud.EgressRuleStatePendingCreation, cockroachcloud.EgressRuleStatePendingUpdate:
+		cr.Status.SetConditions(xpv1.Creating())
+	case cockroachcloud.EgressRuleStatePendingDeletion:
+		cr.Status.SetConditions(xpv1.Deleting())
+	default:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: egressRuleUpToDate(cr.Spec.ForProvider, rule),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.EgressRule)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotEgressRule)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	p := cr.Spec.ForProvider
+	rule, err := c.client.CreateEgressRule(ctx, p.ClusterID, cockroachcloud.EgressRule{
+		Name:        p.Name,
+		Type:        p.Type,
+		Destination: p.Destination,
+		Ports:       p.Ports,
+		Paths:       p.Paths,
+		Description: p.Description,
+	})
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateEgressRule)
+	}
+	meta.SetExternalName(cr, rule.ID)
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.EgressRule)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotEgressRule)
+	}
+
+	_, err := c.client.UpdateEgressRule(ctx, cr.Spec.ForProvider.ClusterID, meta.GetExternalName(cr), egressRuleUpdate(cr.Spec.ForProvider))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateEgressRule)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.EgressRule)
+	if !ok {
+		return errors.New(errNotEgressRule)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.DeleteEgressRule(ctx, cr.Spec.ForProvider.ClusterID, meta.GetExternalName(cr))
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errDeleteEgressRule)
+}
+
+// egressRuleUpdate returns the mutable fields of the supplied parameters.
+func egressRuleUpdate(p v1alpha1.EgressRuleParameters) cockroachcloud.EgressRuleUpdate {
+	return cockroachcloud.EgressRuleUpdate{
+		Type:        p.Type,
+		Destination: p.Destination,
+		Ports:       p.Ports,
+		Paths:       p.Paths,
+		Description: p.Description,
+	}
+}
+
+// egressRuleUpToDate returns true if the supplied egress rule has the
+// mutable fields of the supplied parameters.
+func egressRuleUpToDate(p v1alpha1.EgressRuleParameters, r *cockroachcloud.EgressRule) bool {
+	observed := cockroachcloud.EgressRuleUpdate{
+		Type:        r.Type,
+		Destination: r.Destination,
+		Ports:       r.Ports,
+		Paths:       r.Paths,
+		Description: r.Description,
+	}
+	return cmp.Equal(egressRuleUpdate(p), observed, cmpopts.EquateEmpty())
+}
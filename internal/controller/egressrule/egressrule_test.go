@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egressrule
+
+import (
+	"testing"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestEgressRuleUpToDate(t *testing.T) {
+	p := v1alpha1.EgressRuleParameters{
+		ClusterID:   testClusterID,
+		Name:        "s3",
+		Type:        "FQDN",
+		Destination: "s3.amazonaws.com",
+		Ports:       []int32{443},
+	}
+
+	cases := map[string]struct {
+		reason string
+		rule   *cockroachcloud.EgressRule
+		want   bool
+	}{
+		"UpToDate": {
+			reason: "A rule that only differs in empty and omitted paths should be up to date.",
+			rule:   &cockroachcloud.EgressRule{ID: "rule", Name: "s3", Type: "FQDN", Destination: "s3.amazonaws.com", Ports: []int32{443}, Paths: []string{}, State: cockroachcloud.EgressRuleStateActive},
+			want:   true,
+		},
+		"PortsChanged": {
+			reason: "A rule whose ports were changed in the console should be updated.",
+			rule:   &cockroachcloud.EgressRule{ID: "rule", Name: "s3", Type: "FQDN", Destination: "s3.amazonaws.com", Ports: []int32{80, 443}},
+		},
+		"DescriptionChanged": {
+			reason: "A rule whose description differs should be updated.",
+			rule:   &cockroachcloud.EgressRule{ID: "rule", Name: "s3", Type: "FQDN", Destination: "s3.amazonaws.com", Ports: []int32{443}, Description: "edited"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := egressRuleUpToDate(p, tc.rule); got != tc.want {
+				t.Errorf("\n%s\negressRuleUpToDate(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
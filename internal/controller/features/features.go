@@ -16,7 +16,11 @@
 
 package features
 
-import "github.com/crossplane/crossplane-runtime/pkg/feature"
+import (
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+)
 
 // Feature flags.
 const (
@@ -24,4 +28,44 @@ const (
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
+
+	// EnableAlphaLiveRegionValidation makes the Cluster validation webhook
+	// reject regions that the Cloud API does not offer for the selected
+	// provider and plan.
+	EnableAlphaLiveRegionValidation feature.Flag = "EnableAlphaLiveRegionValidation"
+
+	// EnableAlphaClusterDiscovery creates observe-only Clusters for the
+	// clusters of each ProviderConfig that are not managed yet.
+	EnableAlphaClusterDiscovery feature.Flag = "EnableAlphaClusterDiscovery"
 )
+
+// Preview feature flags. They gate controllers and fields that rely on
+// limited-access capabilities of the Cloud API, which must also be enabled
+// for the CockroachDB Cloud organization.
+const (
+	// EnablePreviewFolders enables organizing clusters in folders.
+	EnablePreviewFolders feature.Flag = "EnablePreviewFolders"
+
+	// EnablePreviewAzure enables clusters on Azure.
+	EnablePreviewAzure feature.Flag = "EnablePreviewAzure"
+
+	// EnablePreviewPhysicalReplication enables physical cluster replication.
+	EnablePreviewPhysicalReplication feature.Flag = "EnablePreviewPhysicalReplication"
+)
+
+// Preview maps the names preview features are enabled by to their flags.
+var Preview = map[string]feature.Flag{
+	"Folders":             EnablePreviewFolders,
+	"Azure":               EnablePreviewAzure,
+	"PhysicalReplication": EnablePreviewPhysicalReplication,
+}
+
+// PreviewNames returns the sorted names preview features are enabled by.
+func PreviewNames() []string {
+	names := make([]string, 0, len(Preview))
+	for n := range Preview {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotGrant        = "managed resource is not a Grant custom resource"
+	errNoGrantDatabase = "no database to grant privileges on: set database, databaseRef or databaseSelector"
+	errObserveGrant    = "cannot observe privileges of role"
+	errApplyGrant      = "cannot grant privileges to role"
+	errRevokeGrant     = "cannot revoke privileges from role"
+
+	errFmtInvalidPrivilege = "invalid privilege %q"
+
+	DefaultSchema = "public"
+)
+
+// privileges are the privileges that can be granted, on any object.
+// Privileges are checked against them before they are interpolated into
+// GRANT and REVOKE statements, which cannot take them as parameters.
+var privileges = map[string]bool{
+	"ALL":        true,
+	"BACKUP":     true,
+	"CHANGEFEED": true,
+	"CONNECT":    true,
+	"CREATE":     true,
+	"DELETE":     true,
+	"DROP":       true,
+	"EXECUTE":    true,
+	"INSERT":     true,
+	"RESTORE":    true,
+	"SELECT":     true,
+	"UPDATE":     true,
+	"USAGE":      true,
+	"ZONECONFIG": true,
+}
+
+// Setup adds a controller that reconciles Grant managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.GrantGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.GrantGroupVersionKind, &v1alpha1.Grant{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles Grants over SQL.
+type external struct {
+	db *sqlclient.DB
+}
+
+// GrantedPrivileges are the privileges of a role on an object, mapped to
+// whether the role may grant them to other roles.
+type GrantedPrivileges map[string]bool
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Grant)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotGrant)
+	}
+	if cr.Spec.ForProvider.Database == "" {
+		return managed.ExternalObservation{}, errors.New(errNoGrantDatabase)
+	}
+
+	observed, err := c.observe(ctx, cr.Spec.ForProvider)
+	if sqlclient.IsNotFound(err) && meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveGrant)
+	}
+
+	exists := false
+	for _, privs := range observed {
+		exists = exists || len(privs) > 0
+	}
+	if !exists {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	stmts, err := grantStatements(cr.Spec.ForProvider, observed)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(stmts) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Grant)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotGrant)
+	}
+	stmts, err := grantStatements(cr.Spec.ForProvider, map[string]GrantedPrivileges{})
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmts...), errApplyGrant)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Grant)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotGrant)
+	}
+	observed, err := c.observe(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errObserveGrant)
+	}
+	stmts, err := grantStatements(cr.Spec.ForProvider, observed)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmts...), errApplyGrant)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Grant)
+	if !ok {
+		return errors.New(errNotGrant)
+	}
+	stmts, err := revokeStatements(cr.Spec.ForProvider)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(c.db.Exec(ctx, stmts...), errRevokeGrant)
+}
+
+// observe returns the privileges of the role of the supplied parameters on
+// each of their objects.
+func (c *external) observe(ctx context.Context, p v1alpha1.GrantParameters) (map[string]GrantedPrivileges, error) {
+	role := pgx.Identifier{p.Role}.Sanitize()
+	observed := map[string]GrantedPrivileges{}
+	for _, obj := range grantObjects(p) {
+		rows, err := c.db.Query(ctx, fmt.Sprintf("SELECT privilege_type, is_grantable FROM [SHOW GRANTS ON %s FOR %s]", obj, role))
+		if err != nil {
+			return nil, err
+		}
+		privs := GrantedPrivileges{}
+		for rows.Next() {
+			var priv string
+			var grantable bool
+			if err := rows.Scan(&priv, &grantable); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			privs[strings.ToUpper(priv)] = grantable
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		observed[obj] = privs
+	}
+	return observed, nil
+}
+
+// grantObjects returns the objects the supplied parameters grant privileges
+// on, e.g. TABLE "db"."public"."users".
+func grantObjects(p v1alpha1.GrantParameters) []string {
+	switch {
+	case len(p.Tables) > 0:
+		schema := p.Schema
+		if schema == "" {
+			schema = DefaultSchema
+		}
+		objs := make([]string, len(p.Tables))
+		for i, t := range p.Tables {
+			objs[i] = "TABLE " + pgx.Identifier{p.Database, schema, t}.Sanitize()
+		}
+		return objs
+	case p.Schema != "":
+		return []string{"SCHEMA " + pgx.Identifier{p.Database, p.Schema}.Sanitize()}
+	default:
+		return []string{"DATABASE " + pgx.Identifier{p.Database}.Sanitize()}
+	}
+}
+
+// grantStatements returns the SQL statements that grant the privileges of
+// the supplied parameters the role is missing on each of their objects, and
+// revoke those it should not have.
+func grantStatements(p v1alpha1.GrantParameters, observed map[string]GrantedPrivileges) ([]string, error) {
+	stmts := []string{}
+	for _, obj := range grantObjects(p) {
+		s, err := PrivilegeStatements(p.Privileges, p.WithGrantOption, observed[obj], obj, pgx.Identifier{p.Role}.Sanitize())
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s...)
+	}
+	return stmts, nil
+}
+
+// PrivilegeStatements returns the GRANT and REVOKE statements that change
+// the supplied privileges a role has on an object to the supplied ones. It
+// returns an error if any of the supplied privileges is invalid.
+func PrivilegeStatements(privs []string, withGrantOption bool, have GrantedPrivileges, on, role string) ([]string, error) {
+	privs, err := NormalizePrivileges(privs)
+	if err != nil {
+		return nil, err
+	}
+	desired := map[string]bool{}
+	for _, priv := range privs {
+		desired[priv] = true
+	}
+
+	missing, extra, grantable := []string{}, []string{}, []string{}
+	for priv := range desired {
+		g, ok := have[priv]
+		if !ok || withGrantOption && !g {
+			missing = append(missing, priv)
+		}
+		if ok && g && !withGrantOption {
+			grantable = append(grantable, priv)
+		}
+	}
+	for priv := range have {
+		if !desired[priv] {
+			extra = append(extra, priv)
+		}
+	}
+
+	stmts := []string{}
+	if len(extra) > 0 {
+		stmts = append(stmts, fmt.Sprintf("REVOKE %s ON %s FROM %s", PrivilegeList(extra), on, role))
+	}
+	if len(grantable) > 0 {
+		stmts = append(stmts, fmt.Sprintf("REVOKE GRANT OPTION FOR %s ON %s FROM %s", PrivilegeList(grantable), on, role))
+	}
+	if len(missing) > 0 {
+		stmt := fmt.Sprintf("GRANT %s ON %s TO %s", PrivilegeList(missing), on, role)
+		if withGrantOption {
+			stmt += " WITH GRANT OPTION"
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
+}
+
+// revokeStatements returns the SQL statements that revoke the privileges of
+// the supplied parameters from the role.
+func revokeStatements(p v1alpha1.GrantParameters) ([]string, error) {
+	privs, err := NormalizePrivileges(p.Privileges)
+	if err != nil {
+		return nil, err
+	}
+	stmts := []string{}
+	for _, obj := range grantObjects(p) {
+		stmts = append(stmts, fmt.Sprintf("REVOKE %s ON %s FROM %s", PrivilegeList(privs), obj, pgx.Identifier{p.Role}.Sanitize()))
+	}
+	return stmts, nil
+}
+
+// NormalizePrivileges returns the supplied privileges in upper case. It
+// returns an error if any of them is not a privilege that can be granted.
+func NormalizePrivileges(privs []string) ([]string, error) {
+	normalized := make([]string, len(privs))
+	for i, priv := range privs {
+		normalized[i] = strings.ToUpper(priv)
+		if !privileges[normalized[i]] {
+			return nil, errors.Errorf(errFmtInvalidPrivilege, priv)
+		}
+	}
+	return normalized, nil
+}
+
+// PrivilegeList returns the supplied privileges as a sorted, comma separated
+// list. The supplied slice is not modified.
+func PrivilegeList(privs []string) string {
+	sorted := make([]string, len(privs))
+	copy(sorted, privs)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
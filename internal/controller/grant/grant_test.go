@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grant
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestGrantStatements(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		p        v1alpha1.GrantParameters
+		observed map[string]GrantedPrivileges
+		want     []string
+		err      error
+	}{
+		"Database": {
+			reason: "Privileges a role is missing on a database should be granted.",
+			p:      v1alpha1.GrantParameters{Role: "app", Database: "app", Privileges: []string{"create", "CONNECT"}},
+			want:   []string{`GRANT CONNECT, CREATE ON DATABASE "app" TO "app"`},
+		},
+		"Tables": {
+			reason: "Privileges should be granted on each table, in the public schema by default.",
+			p:      v1alpha1.GrantParameters{Role: "app", Database: "app", Tables: []string{"users", "orders"}, Privileges: []string{"SELECT"}, WithGrantOption: true},
+			want: []string{
+				`GRANT SELECT ON TABLE "app"."public"."users" TO "app" WITH GRANT OPTION`,
+				`GRANT SELECT ON TABLE "app"."public"."orders" TO "app" WITH GRANT OPTION`,
+			},
+		},
+		"UpToDate": {
+			reason: "No statements should be needed when the role has exactly the desired privileges.",
+			p:      v1alpha1.GrantParameters{Role: "app", Database: "app", Schema: "billing", Privileges: []string{"USAGE"}},
+			observed: map[string]GrantedPrivileges{
+				`SCHEMA "app"."billing"`: {"USAGE": false},
+			},
+			want: []string{},
+		},
+		"Drift": {
+			reason: "Extra privileges and grant options should be revoked, and missing privileges granted.",
+			p:      v1alpha1.GrantParameters{Role: "app", Database: "app", Privileges: []string{"CONNECT", "CREATE"}},
+			observed: map[string]GrantedPrivileges{
+				`DATABASE "app"`: {"CONNECT": true, "DROP": false},
+			},
+			want: []string{
+				`REVOKE DROP ON DATABASE "app" FROM "app"`,
+				`REVOKE GRANT OPTION FOR CONNECT ON DATABASE "app" FROM "app"`,
+				`GRANT CREATE ON DATABASE "app" TO "app"`,
+			},
+		},
+		"InvalidPrivilege": {
+			reason: "Privileges that are not privilege keywords should never be interpolated into statements.",
+			p:      v1alpha1.GrantParameters{Role: "app", Database: "app", Privileges: []string{"CONNECT ON DATABASE app TO app; DROP DATABASE app; --"}},
+			err:    errors.Errorf(errFmtInvalidPrivilege, "CONNECT ON DATABASE app TO app; DROP DATABASE app; --"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := grantStatements(tc.p, tc.observed)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngrantStatements(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ngrantStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrivilegeList(t *testing.T) {
+	privs := []string{"SELECT", "CONNECT"}
+	if got, want := PrivilegeList(privs), "CONNECT, SELECT"; got != want {
+		t.Errorf("PrivilegeList(...): want %q, got %q", want, got)
+	}
+	if diff := cmp.Diff([]string{"SELECT", "CONNECT"}, privs); diff != "" {
+		t.Errorf("PrivilegeList(...): the supplied privileges should not be modified: -want, +got:\n%s\n", diff)
+	}
+}
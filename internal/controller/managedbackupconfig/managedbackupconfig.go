@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedbackupconfig
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotManagedBackupConfig = "managed resource is not a ManagedBackupConfig custom resource"
+	errGetBackupConfig        = "cannot get managed backup configuration"
+	errUpdateBackupConfig     = "cannot update managed backup configuration"
+	errNoClusterID            = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+)
+
+// Setup adds a controller that reconciles ManagedBackupConfig managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.ManagedBackupConfigGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.ManagedBackupConfigGroupVersionKind, &v1alpha1.ManagedBackupConfig{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles ManagedBackupConfigs.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ManagedBackupConfig)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotManagedBackupConfig)
+	}
+	if meta.WasDeleted(cr) {
+		// The configuration outlives its ManagedBackupConfig. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	cfg, err := c.client.GetBackupConfiguration(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetBackupConfig)
+	}
+
+	cr.Status.AtProvider = v1alpha1.ManagedBackupConfigObservation{
+		Enabled:          cfg.Enabled,
+		FrequencyMinutes: cfg.FrequencyMinutes,
+		RetentionDays:    cfg.RetentionDays,
+	}
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: backupConfigUpToDate(cr.Spec.ForProvider, cfg),
+	}, nil
+}
+
+// Create applies the configuration, since every cluster has one.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ManagedBackupConfig)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotManagedBackupConfig)
+	}
+
+	p := cr.Spec.ForProvider
+	_, err := c.client.UpdateBackupConfiguration(ctx, p.ClusterID, cockroachcloud.BackupConfigurationUpdate{
+		Enabled:          p.Enabled,
+		FrequencyMinutes: p.FrequencyMinutes,
+		RetentionDays:    p.RetentionDays,
+	})
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateBackupConfig)
+}
+
+// Delete leaves the configuration in place, rather than turning off the
+// backups of the cluster.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	mg.SetConditions(xpv1.Deleting())
+	return nil
+}
+
+// backupConfigUpToDate returns true if the supplied configuration has the
+// fields set in the supplied parameters.
+func backupConfigUpToDate(p v1alpha1.ManagedBackupConfigParameters, cfg *cockroachcloud.BackupConfiguration) bool {
+	switch {
+	case p.Enabled != nil && *p.Enabled != cfg.Enabled:
+		return false
+	case p.FrequencyMinutes != nil && *p.FrequencyMinutes != cfg.FrequencyMinutes:
+		return false
+	case p.RetentionDays != nil && *p.RetentionDays != cfg.RetentionDays:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedbackupconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestBackupConfigObserve(t *testing.T) {
+	const body = `{"enabled":true,"frequency_minutes":60,"retention_days":30}`
+	enabled, frequency, retention := true, int32(60), int32(90)
+
+	type want struct {
+		o   managed.ExternalObservation
+		at  v1alpha1.ManagedBackupConfigObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		deleted bool
+		params  v1alpha1.ManagedBackupConfigParameters
+		body    string
+		want    want
+	}{
+		"Deleted": {
+			reason:  "The configuration should not exist once its ManagedBackupConfig is deleted, as it is left in place.",
+			deleted: true,
+			params:  v1alpha1.ManagedBackupConfigParameters{ClusterID: testClusterID},
+			body:    body,
+		},
+		"NoClusterID": {
+			reason: "A configuration without a cluster ID cannot be observed.",
+			body:   body,
+			want:   want{err: errors.New(errNoClusterID)},
+		},
+		"ClusterNotFound": {
+			reason: "The configuration of a cluster the Cloud API does not know should not exist.",
+			params: v1alpha1.ManagedBackupConfigParameters{ClusterID: testClusterID},
+		},
+		"UpToDate": {
+			reason: "Fields omitted from the spec should be left as configured.",
+			params: v1alpha1.ManagedBackupConfigParameters{ClusterID: testClusterID, FrequencyMinutes: &frequency},
+			body:   body,
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at: v1alpha1.ManagedBackupConfigObservation{Enabled: true, FrequencyMinutes: 60, RetentionDays: 30},
+			},
+		},
+		"ChangedInConsole": {
+			reason: "A configuration changed in the console should be reverted.",
+			params: v1alpha1.ManagedBackupConfigParameters{ClusterID: testClusterID, Enabled: &enabled, RetentionDays: &retention},
+			body:   body,
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				at: v1alpha1.ManagedBackupConfigObservation{Enabled: true, FrequencyMinutes: 60, RetentionDays: 30},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body)}
+			cr := &v1alpha1.ManagedBackupConfig{Spec: v1alpha1.ManagedBackupConfigSpec{ForProvider: tc.params}}
+			if tc.deleted {
+				cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}
+			o, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.at, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want atProvider, +got atProvider:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricexportcloudwatch
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotMetricExportCloudWatch    = "managed resource is not a MetricExportCloudWatch custom resource"
+	errNoClusterID                  = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetCloudWatchMetricExport    = "cannot get CloudWatch metric export"
+	errEnableCloudWatchMetricExport = "cannot enable CloudWatch metric export"
+	errDeleteCloudWatchMetricExport = "cannot delete CloudWatch metric export"
+)
+
+// Setup adds a controller that reconciles MetricExportCloudWatch managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.MetricExportCloudWatchGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.MetricExportCloudWatchGroupVersionKind, &v1alpha1.MetricExportCloudWatch{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles MetricExportCloudWatches.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.MetricExportCloudWatch)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMetricExportCloudWatch)
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	e, err := c.client.GetCloudWatchMetricExport(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetCloudWatchMetricExport)
+	}
+	if e.Status == cockroachcloud.MetricExportStatusNotDeployed {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider.Status = string(e.Status)
+	cr.Status.AtProvider.Message = e.UserMessage
+	cr.Status.SetConditions(cloud.MetricExportCondition(e.Status))
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate(cr.Spec.ForProvider, e)}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.MetricExportCloudWatch)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMetricExportCloudWatch)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+	return managed.ExternalCreation{}, c.enable(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.MetricExportCloudWatch)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMetricExportCloudWatch)
+	}
+	return managed.ExternalUpdate{}, c.enable(ctx, cr)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.MetricExportCloudWatch)
+	if !ok {
+		return errors.New(errNotMetricExportCloudWatch)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.DeleteCloudWatchMetricExport(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errDeleteCloudWatchMetricExport)
+}
+
+// enable configures the CloudWatch metric export of the supplied
+// MetricExportCloudWatch. Enabling an enabled export reconfigures it.
+func (c *external) enable(ctx context.Context, cr *v1alpha1.MetricExportCloudWatch) error {
+	p := cr.Spec.ForProvider
+	_, err := c.client.EnableCloudWatchMetricExport(ctx, p.ClusterID, p.RoleARN, p.TargetRegion, p.LogGroupName)
+	return errors.Wrap(err, errEnableCloudWatchMetricExport)
+}
+
+// upToDate returns true if the supplied export matches the supplied
+// parameters. The target region and log group are defaulted by the Cloud API,
+// so they are only compared if set.
+func upToDate(p v1alpha1.MetricExportCloudWatchParameters, e *cockroachcloud.CloudWatchMetricExport) bool {
+	switch {
+	case p.RoleARN != e.RoleARN:
+		return false
+	case p.TargetRegion != "" && p.TargetRegion != e.TargetRegion:
+		return false
+	case p.LogGroupName != "" && p.LogGroupName != e.LogGroupName:
+		return false
+	}
+	return true
+}
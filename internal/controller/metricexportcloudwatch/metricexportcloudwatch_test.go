@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricexportcloudwatch
+
+import (
+	"testing"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+func TestUpToDate(t *testing.T) {
+	exported := &cockroachcloud.CloudWatchMetricExport{RoleARN: "arn:aws:iam::1:role/r", TargetRegion: "us-east-1", LogGroupName: "crdb"}
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.MetricExportCloudWatchParameters
+		want   bool
+	}{
+		"Defaulted": {
+			reason: "A target region and log group defaulted by the Cloud API should be up to date.",
+			p:      v1alpha1.MetricExportCloudWatchParameters{RoleARN: "arn:aws:iam::1:role/r"},
+			want:   true,
+		},
+		"Same": {
+			reason: "An export matching every parameter should be up to date.",
+			p:      v1alpha1.MetricExportCloudWatchParameters{RoleARN: "arn:aws:iam::1:role/r", TargetRegion: "us-east-1", LogGroupName: "crdb"},
+			want:   true,
+		},
+		"RoleChanged": {
+			reason: "An export assuming another role should be reconfigured.",
+			p:      v1alpha1.MetricExportCloudWatchParameters{RoleARN: "arn:aws:iam::1:role/other"},
+		},
+		"LogGroupChanged": {
+			reason: "An export to another log group should be reconfigured.",
+			p:      v1alpha1.MetricExportCloudWatchParameters{RoleARN: "arn:aws:iam::1:role/r", LogGroupName: "other"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := upToDate(tc.p, exported); got != tc.want {
+				t.Errorf("\n%s\nupToDate(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
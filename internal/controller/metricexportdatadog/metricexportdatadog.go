@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricexportdatadog
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotMetricExportDatadog    = "managed resource is not a MetricExportDatadog custom resource"
+	errNoClusterID               = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetDatadogMetricExport    = "cannot get Datadog metric export"
+	errEnableDatadogMetricExport = "cannot enable Datadog metric export"
+	errDeleteDatadogMetricExport = "cannot delete Datadog metric export"
+	errGetDatadogAPIKey          = "cannot get Datadog API key"
+)
+
+// Setup adds a controller that reconciles MetricExportDatadog managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.MetricExportDatadogGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.MetricExportDatadogGroupVersionKind, &v1alpha1.MetricExportDatadog{}, func(kube client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{kube: kube, client: c}
+	})
+}
+
+// An external reconciles MetricExportDatadogs.
+type external struct {
+	kube   client.Client
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.MetricExportDatadog)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMetricExportDatadog)
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	e, err := c.client.GetDatadogMetricExport(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetDatadogMetricExport)
+	}
+	if e.Status == cockroachcloud.MetricExportStatusNotDeployed {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider.Status = string(e.Status)
+	cr.Status.AtProvider.Message = e.UserMessage
+	cr.Status.SetConditions(cloud.MetricExportCondition(e.Status))
+
+	_, version, err := cloud.GetSecretKeyVersion(ctx, c.kube, cr.Spec.ForProvider.APIKeySecretRef)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetDatadogAPIKey)
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: e.Site == cr.Spec.ForProvider.Site && version == cr.Status.AtProvider.SecretVersion,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.MetricExportDatadog)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMetricExportDatadog)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+	return managed.ExternalCreation{}, c.enable(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.MetricExportDatadog)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMetricExportDatadog)
+	}
+	return managed.ExternalUpdate{}, c.enable(ctx, cr)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.MetricExportDatadog)
+	if !ok {
+		return errors.New(errNotMetricExportDatadog)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.DeleteDatadogMetricExport(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errDeleteDatadogMetricExport)
+}
+
+// enable configures the Datadog metric export of the supplied
+// MetricExportDatadog. Enabling an enabled export reconfigures it.
+func (c *external) enable(ctx context.Context, cr *v1alpha1.MetricExportDatadog) error {
+	p := cr.Spec.ForProvider
+	key, version, err := cloud.GetSecretKeyVersion(ctx, c.kube, p.APIKeySecretRef)
+	if err != nil {
+		return errors.Wrap(err, errGetDatadogAPIKey)
+	}
+	if _, err := c.client.EnableDatadogMetricExport(ctx, p.ClusterID, p.Site, string(key)); err != nil {
+		return errors.Wrap(err, errEnableDatadogMetricExport)
+	}
+	cr.Status.AtProvider.SecretVersion = version
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricexportdatadog
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+// secretVersion returns a client that serves a Secret of the supplied
+// resource version.
+func secretVersion(version string) client.Client {
+	return &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		s := obj.(*corev1.Secret)
+		s.SetResourceVersion(version)
+		s.Data = map[string][]byte{"key": []byte("s3cr3t")}
+		return nil
+	}}
+}
+
+func TestObserve(t *testing.T) {
+	datadog := func(version string) *v1alpha1.MetricExportDatadog {
+		cr := &v1alpha1.MetricExportDatadog{Spec: v1alpha1.MetricExportDatadogSpec{ForProvider: v1alpha1.MetricExportDatadogParameters{
+			ClusterID:       testClusterID,
+			Site:            "EU1",
+			APIKeySecretRef: xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "datadog", Namespace: "default"}, Key: "key"},
+		}}}
+		cr.Status.AtProvider.SecretVersion = version
+		return cr
+	}
+
+	type want struct {
+		o    managed.ExternalObservation
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		kube   client.Client
+		cr     *v1alpha1.MetricExportDatadog
+		want   want
+	}{
+		"NotFound": {
+			reason: "A metric export the Cloud API does not know should not exist.",
+			cr:     datadog("1"),
+		},
+		"NotDeployed": {
+			reason: "A metric export that is not deployed should not exist.",
+			body:   `{"cluster_id":"c","site":"EU1","status":"NOT_DEPLOYED"}`,
+			cr:     datadog("1"),
+		},
+		"UpToDate": {
+			reason: "An enabled metric export of the same site and API key should be up to date.",
+			body:   `{"cluster_id":"c","site":"EU1","status":"ENABLED"}`,
+			kube:   secretVersion("1"),
+			cr:     datadog("1"),
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond: xpv1.Available(),
+			},
+		},
+		"SiteChanged": {
+			reason: "A metric export to another site should be reconfigured.",
+			body:   `{"cluster_id":"c","site":"US1","status":"ENABLED"}`,
+			kube:   secretVersion("1"),
+			cr:     datadog("1"),
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				cond: xpv1.Available(),
+			},
+		},
+		"APIKeyRotated": {
+			reason: "A metric export should be reconfigured once its API key Secret changed.",
+			body:   `{"cluster_id":"c","site":"EU1","status":"ERROR","user_message":"invalid API key"}`,
+			kube:   secretVersion("2"),
+			cr:     datadog("1"),
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				cond: xpv1.Unavailable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: cockroachcloudtest.NewClient(t, tc.body)}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if got := tc.cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpointconnection
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotPrivateEndpointConnection    = "managed resource is not a PrivateEndpointConnection custom resource"
+	errNoClusterID                     = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errListPrivateEndpointConnections  = "cannot list private endpoint connections"
+	errAddPrivateEndpointConnection    = "cannot add private endpoint connection"
+	errRemovePrivateEndpointConnection = "cannot remove private endpoint connection"
+)
+
+// Setup adds a controller that reconciles PrivateEndpointConnection managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.PrivateEndpointConnectionGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.PrivateEndpointConnectionGroupVersionKind, &v1alpha1.PrivateEndpointConnection{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles PrivateEndpointConnections.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.PrivateEndpointConnection)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPrivateEndpointConnection)
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	conns, err := c.client.ListPrivateEndpointConnections(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListPrivateEndpointConnections)
+	}
+
+	conn := privateEndpointConnection(conns, cr.Spec.ForProvider.EndpointID)
+	if conn == nil || conn.Status == cockroachcloud.PrivateEndpointConnectionStatusDeleted {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider = v1alpha1.PrivateEndpointConnectionObservation{
+		Region:        conn.RegionName,
+		CloudProvider: string(conn.CloudProvider),
+		Status:        string(conn.Status),
+		ServiceID:     conn.ServiceID,
+	}
+	switch conn.Status {
+	case cockroachcloud.PrivateEndpointConnectionStatusAvailable:
+		cr.Status.SetConditions(xpv1.Available())
+	case cockroachcloud.PrivateEndpointConnectionStatusPending, cockroachcloud.PrivateEndpointConnectionStatusPendingAcceptance:
+		cr.Status.SetConditions(xpv1.Creating())
+	case cockroachcloud.PrivateEndpointConnectionStatusDeleting:
+		cr.Status.SetConditions(xpv1.Deleting())
+	default:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	}
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.PrivateEndpointConnection)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPrivateEndpointConnection)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	_, err := c.client.AddPrivateEndpointConnection(ctx, cr.Spec.ForProvider.ClusterID, cr.Spec.ForProvider.EndpointID)
+	return managed.ExternalCreation{}, errors.Wrap(err, errAddPrivateEndpointConnection)
+}
+
+// Update is a no-op, as all fields of a PrivateEndpointConnection are
+// immutable.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.PrivateEndpointConnection)
+	if !ok {
+		return errors.New(errNotPrivateEndpointConnection)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.RemovePrivateEndpointConnection(ctx, cr.Spec.ForProvider.ClusterID, cr.Spec.ForProvider.EndpointID)
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errRemovePrivateEndpointConnection)
+}
+
+// privateEndpointConnection returns the connection of the supplied endpoint,
+// or nil if it is not connected.
+func privateEndpointConnection(conns []cockroachcloud.PrivateEndpointConnection, endpointID string) *cockroachcloud.PrivateEndpointConnection {
+	for i := range conns {
+		if conns[i].EndpointID == endpointID {
+			return &conns[i]
+		}
+	}
+	return nil
+}
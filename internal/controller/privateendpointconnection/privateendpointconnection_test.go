@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpointconnection
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestPrivateEndpointConnectionObserve(t *testing.T) {
+	const endpointID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/privateEndpoints/crdb"
+	body := func(status string) string {
+		return `{"connections":[{"region_name":"eastus2","cloud_provider":"AZURE","status":"` + status + `","endpoint_id":"` + endpointID + `","endpoint_service_id":"pls"}]}`
+	}
+	observation := func(status string) v1alpha1.PrivateEndpointConnectionObservation {
+		return v1alpha1.PrivateEndpointConnectionObservation{Region: "eastus2", CloudProvider: "AZURE", Status: status, ServiceID: "pls"}
+	}
+
+	type want struct {
+		o    managed.ExternalObservation
+		at   v1alpha1.PrivateEndpointConnectionObservation
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   want
+	}{
+		"ClusterGone": {
+			reason: "Connections to a cluster the Cloud API does not know should not exist.",
+		},
+		"NotConnected": {
+			reason: "An endpoint that is not listed should not be connected.",
+			body:   `{"connections":[]}`,
+		},
+		"Deleted": {
+			reason: "A deleted connection should not exist.",
+			body:   body("ENDPOINT_DELETED"),
+		},
+		"Pending": {
+			reason: "A pending connection should be creating.",
+			body:   body("ENDPOINT_PENDING"),
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   observation("ENDPOINT_PENDING"),
+				cond: xpv1.Creating(),
+			},
+		},
+		"Available": {
+			reason: "An available connection should be available.",
+			body:   body("ENDPOINT_AVAILABLE"),
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   observation("ENDPOINT_AVAILABLE"),
+				cond: xpv1.Available(),
+			},
+		},
+		"Rejected": {
+			reason: "A rejected connection should be unavailable.",
+			body:   body("ENDPOINT_REJECTED"),
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   observation("ENDPOINT_REJECTED"),
+				cond: xpv1.Unavailable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body)}
+			cr := &v1alpha1.PrivateEndpointConnection{
+				Spec: v1alpha1.PrivateEndpointConnectionSpec{ForProvider: v1alpha1.PrivateEndpointConnectionParameters{ClusterID: testClusterID, EndpointID: endpointID}},
+			}
+			o, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.at, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want atProvider, +got atProvider:\n%s\n", tc.reason, diff)
+			}
+			if got := cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
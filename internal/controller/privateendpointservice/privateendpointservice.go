@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpointservice
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotPrivateEndpointService    = "managed resource is not a PrivateEndpointService custom resource"
+	errNoClusterID                  = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errListPrivateEndpointServices  = "cannot list private endpoint services"
+	errCreatePrivateEndpointService = "cannot create private endpoint services"
+	errTrackPCUsage                 = "cannot track ProviderConfig usage"
+)
+
+// Setup adds a controller that reconciles PrivateEndpointService managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.PrivateEndpointServiceGroupKind)
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.PrivateEndpointServiceGroupVersionKind),
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&connector{Connector: &cloud.Connector{
+			Kube:         mgr.GetClient(),
+			Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			APIInfo:      o.APIInfo,
+			NewServiceFn: o.NewService}}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.PrivateEndpointService{}).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return &v1alpha1.PrivateEndpointService{} }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A connector produces an ExternalClient for
+// PrivateEndpointServices.
+type connector struct {
+	*cloud.Connector
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.PrivateEndpointService); !ok {
+		return nil, errors.New(errNotPrivateEndpointService)
+	}
+
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	svc, err := c.Service(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{client: svc.CloudClient}, nil
+}
+
+// An external reconciles PrivateEndpointServices.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.PrivateEndpointService)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPrivateEndpointService)
+	}
+	if meta.WasDeleted(cr) {
+		// Endpoint services outlive their PrivateEndpointService. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	svcs, err := c.client.ListPrivateEndpointServices(ctx, cr.Spec.ForProvider.ClusterID)
+	if cockroachcloud.IsNotFound(err) {
+		// Endpoint services go along with their cluster.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListPrivateEndpointServices)
+	}
+	if len(svcs) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider.Services = privateEndpointServiceRegions(svcs)
+	cr.Status.SetConditions(privateEndpointServicesCondition(svcs))
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.PrivateEndpointService)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPrivateEndpointService)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	_, err := c.client.CreatePrivateEndpointServices(ctx, cr.Spec.ForProvider.ClusterID)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreatePrivateEndpointService)
+}
+
+// Update does nothing, as endpoint services have no configurable fields.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete leaves the endpoint services in place, as the Cloud API cannot
+// delete them. They are deleted along with their cluster.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	mg.SetConditions(xpv1.Deleting())
+	return nil
+}
+
+// privateEndpointServiceRegions returns the status of the supplied endpoint
+// services.
+func privateEndpointServiceRegions(svcs []cockroachcloud.PrivateEndpointService) []v1alpha1.PrivateEndpointServiceRegion {
+	regions := make([]v1alpha1.PrivateEndpointServiceRegion, 0, len(svcs))
+	for _, s := range svcs {
+		r := v1alpha1.PrivateEndpointServiceRegion{
+			Region:        s.RegionName,
+			CloudProvider: string(s.CloudProvider),
+			Status:        string(s.Status),
+		}
+		if s.AWS != nil {
+			r.ServiceName = s.AWS.ServiceName
+			r.ServiceID = s.AWS.ServiceID
+			r.AvailabilityZoneIDs = s.AWS.AvailabilityZoneIDs
+		}
+		if s.Azure != nil {
+			r.AliasName = s.Azure.AliasName
+			r.ServiceID = s.Azure.ServiceID
+		}
+		regions = append(regions, r)
+	}
+	return regions
+}
+
+// privateEndpointServicesCondition returns the readiness of the supplied
+// endpoint services. They are only available once every region's service is.
+func privateEndpointServicesCondition(svcs []cockroachcloud.PrivateEndpointService) xpv1.Condition {
+	creating := false
+	for _, s := range svcs {
+		switch s.Status {
+		case cockroachcloud.PrivateEndpointServiceStatusAvailable:
+		case cockroachcloud.PrivateEndpointServiceStatusCreating:
+			creating = true
+		default:
+			return xpv1.Unavailable()
+		}
+	}
+	if creating {
+		return xpv1.Creating()
+	}
+	return xpv1.Available()
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpointservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestPrivateEndpointObserve(t *testing.T) {
+	endpoint := func(status string) string {
+		return `{"region_name":"us-east-1","cloud_provider":"AWS","status":"` + status + `","aws":{"service_name":"vpce-svc","service_id":"svc","availability_zone_ids":["use1-az1"]}}`
+	}
+	region := func(status string) v1alpha1.PrivateEndpointServiceRegion {
+		return v1alpha1.PrivateEndpointServiceRegion{
+			Region:              "us-east-1",
+			CloudProvider:       "AWS",
+			Status:              status,
+			ServiceName:         "vpce-svc",
+			ServiceID:           "svc",
+			AvailabilityZoneIDs: []string{"use1-az1"},
+		}
+	}
+
+	type want struct {
+		o        managed.ExternalObservation
+		services []v1alpha1.PrivateEndpointServiceRegion
+		cond     xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason  string
+		deleted bool
+		status  int
+		body    string
+		want    want
+	}{
+		"Deleted": {
+			reason:  "Endpoint services should not exist once their PrivateEndpointService is deleted, as they cannot be deleted.",
+			deleted: true,
+			status:  http.StatusOK,
+			body:    `{"services":[` + endpoint("ENDPOINT_SERVICE_STATUS_AVAILABLE") + `]}`,
+		},
+		"ClusterGone": {
+			reason: "Endpoint services should not exist once their cluster is gone.",
+			status: http.StatusNotFound,
+			body:   `{}`,
+		},
+		"NotCreated": {
+			reason: "Endpoint services should not exist until they were created.",
+			status: http.StatusOK,
+			body:   `{"services":[]}`,
+		},
+		"Creating": {
+			reason: "Endpoint services should be creating until every region's service is available.",
+			status: http.StatusOK,
+			body:   `{"services":[` + endpoint("ENDPOINT_SERVICE_STATUS_AVAILABLE") + `,` + endpoint("ENDPOINT_SERVICE_STATUS_CREATING") + `]}`,
+			want: want{
+				o:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				services: []v1alpha1.PrivateEndpointServiceRegion{region("ENDPOINT_SERVICE_STATUS_AVAILABLE"), region("ENDPOINT_SERVICE_STATUS_CREATING")},
+				cond:     xpv1.Creating(),
+			},
+		},
+		"Available": {
+			reason: "Available endpoint services should report their service names.",
+			status: http.StatusOK,
+			body:   `{"services":[` + endpoint("ENDPOINT_SERVICE_STATUS_AVAILABLE") + `]}`,
+			want: want{
+				o:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				services: []v1alpha1.PrivateEndpointServiceRegion{region("ENDPOINT_SERVICE_STATUS_AVAILABLE")},
+				cond:     xpv1.Available(),
+			},
+		},
+		"Azure": {
+			reason: "Available Azure endpoint services should report their aliases.",
+			status: http.StatusOK,
+			body:   `{"services":[{"region_name":"eastus2","cloud_provider":"AZURE","status":"ENDPOINT_SERVICE_STATUS_AVAILABLE","azure":{"alias_name":"crdb.guid.eastus2.azure.privatelinkservice","service_id":"pls"}}]}`,
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				services: []v1alpha1.PrivateEndpointServiceRegion{{
+					Region:        "eastus2",
+					CloudProvider: "AZURE",
+					Status:        "ENDPOINT_SERVICE_STATUS_AVAILABLE",
+					ServiceID:     "pls",
+					AliasName:     "crdb.guid.eastus2.azure.privatelinkservice",
+				}},
+				cond: xpv1.Available(),
+			},
+		},
+		"Failed": {
+			reason: "Endpoint services that failed to be created should be unavailable.",
+			status: http.StatusOK,
+			body:   `{"services":[` + endpoint("ENDPOINT_SERVICE_STATUS_CREATE_FAILED") + `]}`,
+			want: want{
+				o:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				services: []v1alpha1.PrivateEndpointServiceRegion{region("ENDPOINT_SERVICE_STATUS_CREATE_FAILED")},
+				cond:     xpv1.Unavailable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+			c, err := cockroachcloud.NewClient("key", cockroachcloud.WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatalf("NewClient(...): %v", err)
+			}
+
+			cr := &v1alpha1.PrivateEndpointService{Spec: v1alpha1.PrivateEndpointServiceSpec{ForProvider: v1alpha1.PrivateEndpointServiceParameters{ClusterID: testClusterID}}}
+			if tc.deleted {
+				cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}
+			e := &external{client: c}
+			o, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.services, cr.Status.AtProvider.Services); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want services, +got services:\n%s\n", tc.reason, diff)
+			}
+			if got := cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
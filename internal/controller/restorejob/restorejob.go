@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorejob
+
+import (
+	"context"
+	"math"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotRestoreJob  = "managed resource is not a RestoreJob custom resource"
+	errGetRestore     = "cannot get restore"
+	errCreateRestore  = "cannot start restore"
+	errFmtRestoreGone = "restore %s is unknown to the Cloud API: remove the external name annotation to restore again"
+	errNoClusterID    = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+)
+
+// Setup adds a controller that reconciles RestoreJob managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.RestoreJobGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.RestoreJobGroupVersionKind, &v1alpha1.RestoreJob{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles RestoreJobs.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.RestoreJob)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotRestoreJob)
+	}
+	if meta.WasDeleted(cr) {
+		// A restore cannot be undone. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if cr.Spec.ForProvider.ClusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	r, err := c.client.GetRestore(ctx, cr.Spec.ForProvider.ClusterID, id)
+	if cockroachcloud.IsNotFound(err) {
+		// Restoring again could overwrite data written since, so a restore
+		// that vanished is not started again.
+		return managed.ExternalObservation{}, errors.Errorf(errFmtRestoreGone, id)
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetRestore)
+	}
+
+	cr.Status.AtProvider = restoreJobObservation(r)
+	switch r.Status {
+	case cockroachcloud.RestoreStatusSuccess:
+		cr.Status.SetConditions(xpv1.Available())
+	case cockroachcloud.RestoreStatusPending:
+		cr.Status.SetConditions(xpv1.Creating())
+	default:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	}
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.RestoreJob)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotRestoreJob)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	r, err := c.client.CreateRestore(ctx, cr.Spec.ForProvider.ClusterID, restoreRequest(cr.Spec.ForProvider))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateRestore)
+	}
+	meta.SetExternalName(cr, r.ID)
+	return managed.ExternalCreation{}, nil
+}
+
+// Update does nothing, as a restore cannot be changed once started.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete leaves the restored data in place, as a restore cannot be undone.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	mg.SetConditions(xpv1.Deleting())
+	return nil
+}
+
+// restoreRequest returns the Cloud API request of the supplied parameters.
+func restoreRequest(p v1alpha1.RestoreJobParameters) cockroachcloud.RestoreRequest {
+	req := cockroachcloud.RestoreRequest{
+		SourceClusterID: p.SourceClusterID,
+		BackupID:        p.BackupID,
+		Type:            p.Type,
+	}
+	for _, o := range p.Objects {
+		req.Objects = append(req.Objects, cockroachcloud.RestoreItem{Database: o.Database, Schema: o.Schema, Table: o.Table})
+	}
+	if o := p.Options; o != nil {
+		req.RestoreOpts = &cockroachcloud.RestoreOptions{
+			NewDBName:              o.NewDatabaseName,
+			IntoDB:                 o.IntoDatabase,
+			SkipLocalitiesCheck:    o.SkipLocalitiesCheck,
+			SkipMissingForeignKeys: o.SkipMissingForeignKeys,
+			SkipMissingSequences:   o.SkipMissingSequences,
+			SchemaOnly:             o.SchemaOnly,
+		}
+	}
+	return req
+}
+
+// restoreJobObservation returns the status of the supplied restore.
+func restoreJobObservation(r *cockroachcloud.Restore) v1alpha1.RestoreJobObservation {
+	return v1alpha1.RestoreJobObservation{
+		Status:            string(r.Status),
+		CompletionPercent: int32(math.Floor(r.CompletionPercent * 100)),
+		BackupEndTime:     r.BackupEndTime,
+		JobID:             r.CRDBJobID,
+		Message:           r.ClientErrorMessage,
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorejob
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+func TestRestoreJobObserve(t *testing.T) {
+	type want struct {
+		o    managed.ExternalObservation
+		at   v1alpha1.RestoreJobObservation
+		cond xpv1.Condition
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		id     string
+		body   string
+		want   want
+	}{
+		"NotStarted": {
+			reason: "A restore without an external name should not exist.",
+		},
+		"Vanished": {
+			reason: "A started restore the Cloud API does not know should not be started again.",
+			id:     "restore",
+			want:   want{err: errors.Errorf(errFmtRestoreGone, "restore")},
+		},
+		"Pending": {
+			reason: "A pending restore should report its progress and not be ready.",
+			id:     "restore",
+			body:   `{"id":"restore","status":"PENDING","completion_percent":0.425,"crdb_job_id":"123"}`,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   v1alpha1.RestoreJobObservation{Status: "PENDING", CompletionPercent: 42, JobID: "123"},
+				cond: xpv1.Creating(),
+			},
+		},
+		"Succeeded": {
+			reason: "A restore should be ready once it succeeded.",
+			id:     "restore",
+			body:   `{"id":"restore","status":"SUCCESS","completion_percent":1,"backup_end_time":"2022-06-01T00:00:00Z"}`,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   v1alpha1.RestoreJobObservation{Status: "SUCCESS", CompletionPercent: 100, BackupEndTime: "2022-06-01T00:00:00Z"},
+				cond: xpv1.Available(),
+			},
+		},
+		"Failed": {
+			reason: "A failed restore should explain why and be unavailable.",
+			id:     "restore",
+			body:   `{"id":"restore","status":"FAILED","client_error_message":"database app already exists"}`,
+			want: want{
+				o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				at:   v1alpha1.RestoreJobObservation{Status: "FAILED", Message: "database app already exists"},
+				cond: xpv1.Unavailable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body)}
+			cr := &v1alpha1.RestoreJob{Spec: v1alpha1.RestoreJobSpec{ForProvider: v1alpha1.RestoreJobParameters{ClusterID: testClusterID, Type: "CLUSTER"}}}
+			meta.SetExternalName(cr, tc.id)
+			o, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.at, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want atProvider, +got atProvider:\n%s\n", tc.reason, diff)
+			}
+			if got := cr.Status.GetCondition(xpv1.TypeReady); tc.want.cond.Type != "" && !got.Equal(tc.want.cond) {
+				t.Errorf("\n%s\ne.Observe(...): want condition %v, got %v", tc.reason, tc.want.cond, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restoresql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/backupschedule"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotRestoreSQL     = "managed resource is not a RestoreSQL custom resource"
+	errGetRestoreSQL     = "cannot get restore job"
+	errStartRestoreSQL   = "cannot start restore job"
+	errCancelRestoreSQL  = "cannot cancel restore job"
+	errFmtRestoreSQLGone = "restore job %s is unknown to the cluster: remove the external name annotation to restore again"
+)
+
+// Setup adds a controller that reconciles RestoreSQL managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.RestoreSQLGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.RestoreSQLGroupVersionKind, &v1alpha1.RestoreSQL{}, func(kube client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{kube: kube, db: db}
+	}, managed.WithInitializers())
+}
+
+// An external reconciles RestoreSQLs over SQL.
+type external struct {
+	kube client.Client
+	db   *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.RestoreSQL)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotRestoreSQL)
+	}
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	jid, err := sqlresource.ParseJobID(id)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	j, err := sqlresource.GetJob(ctx, c.db, jid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		// Restoring again could overwrite data written since, so a restore
+		// job that vanished is not started again.
+		return managed.ExternalObservation{}, errors.Errorf(errFmtRestoreSQLGone, id)
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetRestoreSQL)
+	}
+
+	cr.Status.AtProvider = v1alpha1.RestoreSQLObservation{
+		Status:            j.Status,
+		CompletionPercent: j.CompletionPercent(),
+		Message:           j.Error,
+	}
+	cr.Status.SetConditions(j.Condition())
+	if meta.WasDeleted(cr) && j.Finished() {
+		// A finished restore cannot be undone. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.RestoreSQL)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotRestoreSQL)
+	}
+	uri, _, err := backupschedule.StorageURI(ctx, c.kube, cr.Spec.ForProvider.Storage)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	stmt, err := restoreStatement(cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	var id int64
+	if err := c.db.QueryRow(ctx, stmt, uri).Scan(&id); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errStartRestoreSQL)
+	}
+	meta.SetExternalName(cr, strconv.FormatInt(id, 10))
+	return managed.ExternalCreation{}, nil
+}
+
+// Update does nothing, as a restore cannot be changed once started.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete cancels the restore job. It is only called while the job is still
+// running.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.RestoreSQL)
+	if !ok {
+		return errors.New(errNotRestoreSQL)
+	}
+	jid, err := sqlresource.ParseJobID(meta.GetExternalName(cr))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(c.db.Exec(ctx, sqlresource.CancelJobStatement(jid)), errCancelRestoreSQL)
+}
+
+// restoreStatement returns the SQL statement that starts a restore of the
+// supplied parameters in the background, and returns the ID of its job. The
+// storage URI is its only placeholder, so that its credentials are not part
+// of the statement.
+func restoreStatement(p v1alpha1.RestoreSQLParameters) (string, error) {
+	targets, err := backupschedule.BackupTargets(p.Database, p.Tables)
+	if err != nil {
+		return "", err
+	}
+	stmt := "RESTORE "
+	if targets != "" {
+		stmt += targets + " "
+	}
+	backup := v1alpha1.BackupLatest
+	if p.Backup != "" && !strings.EqualFold(p.Backup, v1alpha1.BackupLatest) {
+		backup = sqlresource.Literal(p.Backup)
+	}
+	stmt += "FROM " + backup + " IN $1 WITH " + strings.Join(restoreOptions(p.Options), ", ")
+	return stmt, nil
+}
+
+// restoreOptions returns the options of a RESTORE statement for the supplied
+// options.
+func restoreOptions(o *v1alpha1.RestoreJobOptions) []string {
+	opts := []string{"detached"}
+	if o == nil {
+		return opts
+	}
+	if o.NewDatabaseName != "" {
+		opts = append(opts, "new_db_name = "+sqlresource.Literal(o.NewDatabaseName))
+	}
+	if o.IntoDatabase != "" {
+		opts = append(opts, "into_db = "+sqlresource.Literal(o.IntoDatabase))
+	}
+	if o.SkipLocalitiesCheck {
+		opts = append(opts, "skip_localities_check")
+	}
+	if o.SkipMissingForeignKeys {
+		opts = append(opts, "skip_missing_foreign_keys")
+	}
+	if o.SkipMissingSequences {
+		opts = append(opts, "skip_missing_sequences")
+	}
+	if o.SchemaOnly {
+		opts = append(opts, "schema_only")
+	}
+	return opts
+}
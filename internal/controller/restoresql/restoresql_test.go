@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restoresql
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestRestoreStatement(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.RestoreSQLParameters
+		want   string
+	}{
+		"LatestCluster": {
+			reason: "The most recent backup of the whole cluster should be restored in the background by default.",
+			want:   `RESTORE FROM LATEST IN $1 WITH detached`,
+		},
+		"DatabaseNewName": {
+			reason: "A database of a given backup should be restorable under a new name.",
+			p: v1alpha1.RestoreSQLParameters{
+				Database: "app",
+				Backup:   "2022/10/01-120000.00",
+				Options:  &v1alpha1.RestoreJobOptions{NewDatabaseName: "app_restored"},
+			},
+			want: `RESTORE DATABASE "app" FROM '2022/10/01-120000.00' IN $1 WITH detached, new_db_name = 'app_restored'`,
+		},
+		"TablesIntoDatabase": {
+			reason: "Tables should be restorable into another database, skipping what is missing.",
+			p: v1alpha1.RestoreSQLParameters{
+				Tables:  []string{"app.public.users"},
+				Backup:  v1alpha1.BackupLatest,
+				Options: &v1alpha1.RestoreJobOptions{IntoDatabase: "staging", SkipMissingForeignKeys: true, SkipMissingSequences: true},
+			},
+			want: `RESTORE TABLE "app"."public"."users" FROM LATEST IN $1 WITH detached, into_db = 'staging', skip_missing_foreign_keys, skip_missing_sequences`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := restoreStatement(tc.p)
+			if err != nil {
+				t.Fatalf("\n%s\nrestoreStatement(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrestoreStatement(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
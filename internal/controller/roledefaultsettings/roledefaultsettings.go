@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roledefaultsettings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotRoleDefaultSettings     = "managed resource is not a RoleDefaultSettings custom resource"
+	errNoRoleDefaultSettingsRole  = "no role to manage the session defaults of: set role, roleRef or roleSelector"
+	errObserveRoleDefaultSettings = "cannot observe session defaults of role"
+	errUpdateRoleDefaultSettings  = "cannot update session defaults of role"
+	errResetRoleDefaultSettings   = "cannot reset session defaults of role"
+)
+
+// Setup adds a controller that reconciles RoleDefaultSettings managed
+// resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.RoleDefaultSettingsGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.RoleDefaultSettingsGroupVersionKind, &v1alpha1.RoleDefaultSettings{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles RoleDefaultSettings over SQL.
+type external struct {
+	db *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.RoleDefaultSettings)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotRoleDefaultSettings)
+	}
+	if cr.Spec.ForProvider.Role == "" {
+		return managed.ExternalObservation{}, errors.New(errNoRoleDefaultSettingsRole)
+	}
+
+	have, err := c.observe(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveRoleDefaultSettings)
+	}
+	if len(have) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider.Settings = have
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(roleDefaultSettingsStatements(cr.Spec.ForProvider, have)) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.RoleDefaultSettings)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotRoleDefaultSettings)
+	}
+	stmts := roleDefaultSettingsStatements(cr.Spec.ForProvider, nil)
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmts...), errUpdateRoleDefaultSettings)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.RoleDefaultSettings)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotRoleDefaultSettings)
+	}
+	have, err := c.observe(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errObserveRoleDefaultSettings)
+	}
+	stmts := roleDefaultSettingsStatements(cr.Spec.ForProvider, have)
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmts...), errUpdateRoleDefaultSettings)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.RoleDefaultSettings)
+	if !ok {
+		return errors.New(errNotRoleDefaultSettings)
+	}
+	stmt := fmt.Sprintf("ALTER ROLE %s RESET ALL", roleSettingsScope(cr.Spec.ForProvider))
+	return errors.Wrap(c.db.Exec(ctx, stmt), errResetRoleDefaultSettings)
+}
+
+// observe returns the session defaults of the role of the supplied
+// parameters in its database, or in all databases if it has none. A role that
+// does not exist has no session defaults.
+func (c *external) observe(ctx context.Context, p v1alpha1.RoleDefaultSettingsParameters) (map[string]string, error) {
+	var config []string
+	err := c.db.QueryRow(ctx, `SELECT s.setconfig FROM pg_catalog.pg_db_role_setting s
+JOIN pg_catalog.pg_roles r ON r.oid = s.setrole
+LEFT JOIN pg_catalog.pg_database d ON d.oid = s.setdatabase
+WHERE r.rolname = $1 AND COALESCE(d.datname, '') = $2`, p.Role, p.Database).Scan(&config)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseRoleSettings(config), nil
+}
+
+// parseRoleSettings returns the supplied var=value session defaults as a map.
+func parseRoleSettings(config []string) map[string]string {
+	settings := map[string]string{}
+	for _, c := range config {
+		kv := strings.SplitN(c, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		settings[kv[0]] = kv[1]
+	}
+	return settings
+}
+
+// roleDefaultSettingsStatements returns the SQL statements that change the
+// supplied session defaults of a role to those of the supplied parameters.
+func roleDefaultSettingsStatements(p v1alpha1.RoleDefaultSettingsParameters, have map[string]string) []string {
+	scope := roleSettingsScope(p)
+	stmts := []string{}
+	for _, v := range sortedSettings(p.Settings) {
+		if cur, ok := have[v]; !ok || cur != p.Settings[v] {
+			stmts = append(stmts, fmt.Sprintf("ALTER ROLE %s SET %s = %s", scope, pgx.Identifier{v}.Sanitize(), sqlresource.Literal(p.Settings[v])))
+		}
+	}
+	for _, v := range sortedSettings(have) {
+		if _, ok := p.Settings[v]; !ok {
+			stmts = append(stmts, fmt.Sprintf("ALTER ROLE %s RESET %s", scope, pgx.Identifier{v}.Sanitize()))
+		}
+	}
+	return stmts
+}
+
+// roleSettingsScope returns the role, and the database if any, that the
+// session defaults of the supplied parameters apply to.
+func roleSettingsScope(p v1alpha1.RoleDefaultSettingsParameters) string {
+	scope := pgx.Identifier{p.Role}.Sanitize()
+	if p.Database != "" {
+		scope += " IN DATABASE " + pgx.Identifier{p.Database}.Sanitize()
+	}
+	return scope
+}
+
+// sortedSettings returns the session variables of the supplied settings in
+// order.
+func sortedSettings(settings map[string]string) []string {
+	vs := make([]string, 0, len(settings))
+	for v := range settings {
+		vs = append(vs, v)
+	}
+	sort.Strings(vs)
+	return vs
+}
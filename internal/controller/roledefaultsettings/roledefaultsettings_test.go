@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roledefaultsettings
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestRoleDefaultSettingsStatements(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.RoleDefaultSettingsParameters
+		have   map[string]string
+		want   []string
+	}{
+		"AllDatabases": {
+			reason: "Session defaults should be set in all databases when no database is supplied.",
+			p:      v1alpha1.RoleDefaultSettingsParameters{Role: "app", Settings: map[string]string{"search_path": "app, public", "default_transaction_quality_of_service": "background"}},
+			want: []string{
+				`ALTER ROLE "app" SET "default_transaction_quality_of_service" = 'background'`,
+				`ALTER ROLE "app" SET "search_path" = 'app, public'`,
+			},
+		},
+		"UpToDate": {
+			reason: "No statements should be needed when the role has the desired session defaults.",
+			p:      v1alpha1.RoleDefaultSettingsParameters{Role: "app", Database: "shop", Settings: map[string]string{"search_path": "app"}},
+			have:   map[string]string{"search_path": "app"},
+			want:   []string{},
+		},
+		"Changed": {
+			reason: "Changed session defaults should be set, and session defaults that are not listed reset.",
+			p:      v1alpha1.RoleDefaultSettingsParameters{Role: "app", Database: "shop", Settings: map[string]string{"search_path": "app"}},
+			have:   map[string]string{"search_path": "public", "timezone": "UTC"},
+			want: []string{
+				`ALTER ROLE "app" IN DATABASE "shop" SET "search_path" = 'app'`,
+				`ALTER ROLE "app" IN DATABASE "shop" RESET "timezone"`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := roleDefaultSettingsStatements(tc.p, tc.have)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nroleDefaultSettingsStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseRoleSettings(t *testing.T) {
+	want := map[string]string{"search_path": "app, public", "statement_timeout": "10s"}
+	got := parseRoleSettings([]string{"search_path=app, public", "statement_timeout=10s"})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseRoleSettings(...): -want, +got:\n%s\n", diff)
+	}
+}
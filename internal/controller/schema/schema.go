@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotSchema        = "managed resource is not a Schema custom resource"
+	errNoSchemaDatabase = "no database to create the schema in: set database, databaseRef or databaseSelector"
+	errObserveSchema    = "cannot observe schema"
+	errCreateSchema     = "cannot create schema"
+	errUpdateSchema     = "cannot update schema"
+	errDropSchema       = "cannot drop schema"
+)
+
+// Setup adds a controller that reconciles Schema managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.SchemaGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.SchemaGroupVersionKind, &v1alpha1.Schema{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles Schemas over SQL.
+type external struct {
+	db *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Schema)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSchema)
+	}
+	db := cr.Spec.ForProvider.Database
+	if db == "" {
+		return managed.ExternalObservation{}, errors.New(errNoSchemaDatabase)
+	}
+
+	var owner string
+	err := c.db.QueryRow(ctx, fmt.Sprintf("SELECT owner FROM [SHOW SCHEMAS FROM %s] WHERE schema_name = $1", pgx.Identifier{db}.Sanitize()), meta.GetExternalName(cr)).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) || sqlclient.IsNotFound(err) && meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveSchema)
+	}
+
+	cr.Status.AtProvider.Owner = owner
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: cr.Spec.ForProvider.Owner == nil || *cr.Spec.ForProvider.Owner == owner,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Schema)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSchema)
+	}
+	stmt := createSchemaStatement(cr.Spec.ForProvider.Database, meta.GetExternalName(cr), cr.Spec.ForProvider.Owner)
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmt), errCreateSchema)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Schema)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSchema)
+	}
+	if cr.Spec.ForProvider.Owner == nil {
+		return managed.ExternalUpdate{}, nil
+	}
+	stmt := fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pgx.Identifier{cr.Spec.ForProvider.Database, meta.GetExternalName(cr)}.Sanitize(), pgx.Identifier{*cr.Spec.ForProvider.Owner}.Sanitize())
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmt), errUpdateSchema)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Schema)
+	if !ok {
+		return errors.New(errNotSchema)
+	}
+	stmt := dropSchemaStatement(cr.Spec.ForProvider.Database, meta.GetExternalName(cr), cr.Spec.ForProvider.Cascade)
+	return errors.Wrap(c.db.Exec(ctx, stmt), errDropSchema)
+}
+
+// createSchemaStatement returns the SQL statement that creates the named
+// schema in the supplied database, owned by the supplied role if any.
+func createSchemaStatement(db, name string, owner *string) string {
+	stmt := "CREATE SCHEMA IF NOT EXISTS " + pgx.Identifier{db, name}.Sanitize()
+	if owner != nil {
+		stmt += " AUTHORIZATION " + pgx.Identifier{*owner}.Sanitize()
+	}
+	return stmt
+}
+
+// dropSchemaStatement returns the SQL statement that drops the named schema
+// of the supplied database, along with its objects if cascade is true.
+func dropSchemaStatement(db, name string, cascade bool) string {
+	behavior := "RESTRICT"
+	if cascade {
+		behavior = "CASCADE"
+	}
+	return fmt.Sprintf("DROP SCHEMA IF EXISTS %s %s", pgx.Identifier{db, name}.Sanitize(), behavior)
+}
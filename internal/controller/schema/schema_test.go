@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCreateSchemaStatement(t *testing.T) {
+	owner := "app-owner"
+
+	cases := map[string]struct {
+		reason string
+		owner  *string
+		want   string
+	}{
+		"Defaults": {
+			reason: "A schema without an owner should be owned by the user it is created as.",
+			want:   `CREATE SCHEMA IF NOT EXISTS "app"."billing"`,
+		},
+		"Owner": {
+			reason: "A schema should be created with the authorization of its owner.",
+			owner:  &owner,
+			want:   `CREATE SCHEMA IF NOT EXISTS "app"."billing" AUTHORIZATION "app-owner"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := createSchemaStatement("app", "billing", tc.owner)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncreateSchemaStatement(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDropSchemaStatement(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		cascade bool
+		want    string
+	}{
+		"Restrict": {
+			reason: "Only empty schemas should be dropped by default.",
+			want:   `DROP SCHEMA IF EXISTS "app"."billing" RESTRICT`,
+		},
+		"Cascade": {
+			reason:  "Schemas should be dropped along with their objects if requested.",
+			cascade: true,
+			want:    `DROP SCHEMA IF EXISTS "app"."billing" CASCADE`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := dropSchemaStatement("app", "billing", tc.cascade)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndropSchemaStatement(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
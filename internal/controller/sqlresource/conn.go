@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlresource
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errGetSQLCluster           = "cannot get Cluster to connect to"
+	errGetSQLConnectionDetails = "cannot get connection details of Cluster"
+	errFmtNoConnectionSecret   = "Cluster %q publishes no connection details: set its writeConnectionSecretToRef"
+	errGetSelfHostedCA         = "cannot get CA certificate of self-hosted cluster"
+	errGetSelfHostedPassword   = "cannot get password of self-hosted cluster"
+	errGetClientCert           = "cannot get client certificate"
+	errGetClientKey            = "cannot get private key of client certificate"
+)
+
+// clusterSQLConfig returns the Config to connect to the supplied database of
+// the cluster the named Cluster manages, as the user whose connection details
+// the Cluster publishes. The Cluster is namespaced if a namespace is supplied.
+// The database of the published DSN is used if none is supplied.
+func clusterSQLConfig(ctx context.Context, kube client.Client, namespace, cluster, database string) (sqlclient.Config, error) {
+	cl, err := getCluster(ctx, kube, namespace, cluster)
+	if err != nil {
+		return sqlclient.Config{}, errors.Wrap(err, errGetSQLCluster)
+	}
+	ref := cl.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return sqlclient.Config{}, errors.Errorf(errFmtNoConnectionSecret, cluster)
+	}
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return sqlclient.Config{}, errors.Wrap(err, errGetSQLConnectionDetails)
+	}
+	cfg, err := sqlclient.FromConnectionDetails(s.Data, database)
+	if err != nil {
+		return sqlclient.Config{}, errors.Wrap(err, errGetSQLConnectionDetails)
+	}
+	if conn := cl.Spec.ForProvider.Connection; conn != nil && conn.ClientCertificate != nil {
+		cc := conn.ClientCertificate
+		cfg.Cert, cfg.Key, err = clientCertificate(ctx, kube, &cc.CertSecretRef, &cc.KeySecretRef)
+	}
+	return cfg, err
+}
+
+// getCluster returns the named Cluster. A namespaced Cluster is returned as
+// its cluster scoped equivalent, whose Secret references point to the
+// namespace of the namespaced Cluster.
+func getCluster(ctx context.Context, kube client.Client, namespace, name string) (*v1alpha1.Cluster, error) {
+	if namespace == "" {
+		cl := &v1alpha1.Cluster{}
+		return cl, kube.Get(ctx, types.NamespacedName{Name: name}, cl)
+	}
+	ncl := &namespacedv1alpha1.Cluster{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, ncl); err != nil {
+		return nil, err
+	}
+	cl := &v1alpha1.Cluster{Spec: v1alpha1.ClusterSpec{
+		ResourceSpec: *ncl.Spec.ResourceSpec.DeepCopy(),
+		ForProvider:  *ncl.Spec.ForProvider.DeepCopy(),
+	}}
+	if ref := cl.Spec.WriteConnectionSecretToReference; ref != nil {
+		ref.Namespace = namespace
+	}
+	if c := cl.Spec.ForProvider.Connection; c != nil && c.ClientCertificate != nil {
+		c.ClientCertificate.CertSecretRef.Namespace = namespace
+		c.ClientCertificate.KeySecretRef.Namespace = namespace
+	}
+	return cl, nil
+}
+
+// clientCertificate returns the client certificate and private key of the
+// supplied Secret keys.
+func clientCertificate(ctx context.Context, kube client.Client, certRef, keyRef *xpv1.SecretKeySelector) ([]byte, []byte, error) {
+	cert, err := cloud.GetSecretKey(ctx, kube, certRef)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetClientCert)
+	}
+	key, err := cloud.GetSecretKey(ctx, kube, keyRef)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetClientKey)
+	}
+	return cert, key, nil
+}
+
+// selfHostedSQLConfig returns the Config to connect to the supplied database
+// of the supplied self-hosted cluster, as its admin user, authenticated with
+// a password, a client certificate, or both. The database of the
+// self-hosted cluster is used if none is supplied.
+func selfHostedSQLConfig(ctx context.Context, kube client.Client, sh *apisv1alpha1.SelfHostedCluster, database string) (sqlclient.Config, error) {
+	ca, err := cloud.GetSecretKey(ctx, kube, &sh.CASecretRef)
+	if err != nil {
+		return sqlclient.Config{}, errors.Wrap(err, errGetSelfHostedCA)
+	}
+	cfg := sqlclient.Config{CA: ca}
+	creds := sh.Credentials
+	user := url.User(creds.Username)
+	if creds.PasswordSecretRef != nil {
+		pwd, err := cloud.GetSecretKey(ctx, kube, creds.PasswordSecretRef)
+		if err != nil {
+			return sqlclient.Config{}, errors.Wrap(err, errGetSelfHostedPassword)
+		}
+		user = url.UserPassword(creds.Username, string(pwd))
+	}
+	if creds.CertSecretRef != nil && creds.KeySecretRef != nil {
+		if cfg.Cert, cfg.Key, err = clientCertificate(ctx, kube, creds.CertSecretRef, creds.KeySecretRef); err != nil {
+			return sqlclient.Config{}, err
+		}
+	}
+
+	port := cloud.DefaultSQLPort
+	if sh.Port != 0 {
+		port = strconv.Itoa(int(sh.Port))
+	}
+	if database == "" {
+		database = sh.Database
+	}
+	if database == "" {
+		database = cloud.DefaultDatabase
+	}
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     user,
+		Host:     net.JoinHostPort(sh.Host, port),
+		Path:     "/" + database,
+		RawQuery: "sslmode=verify-full",
+	}
+	cfg.DSN = u.String()
+	return cfg, nil
+}
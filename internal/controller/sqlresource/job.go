@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlresource
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errFmtInvalidJobID = "invalid job ID %q"
+)
+
+// Statuses of jobs, such as backups and restores, that run inside a cluster.
+const (
+	jobStatusSucceeded = "succeeded"
+	jobStatusFailed    = "failed"
+	jobStatusCanceled  = "canceled"
+)
+
+// A Job is a job, such as a backup or a restore, that runs inside a
+// cluster.
+type Job struct {
+	Status            string
+	FractionCompleted float64
+	Error             string
+}
+
+// ParseJobID parses the supplied external name of a managed resource that runs
+// a job.
+func ParseJobID(id string) (int64, error) {
+	jid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf(errFmtInvalidJobID, id)
+	}
+	return jid, nil
+}
+
+// GetJob returns the job with the supplied ID. It returns pgx.ErrNoRows if
+// there is no such job.
+func GetJob(ctx context.Context, db *sqlclient.DB, id int64) (*Job, error) {
+	j := &Job{}
+	err := db.QueryRow(ctx, "SELECT status, COALESCE(fraction_completed, 0), COALESCE(error, '') FROM crdb_internal.jobs WHERE job_id = $1", id).Scan(&j.Status, &j.FractionCompleted, &j.Error)
+	return j, err
+}
+
+// Finished returns true if the job will not make any more progress.
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case jobStatusSucceeded, jobStatusFailed, jobStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// Condition returns the Ready condition of a managed resource that runs the
+// job.
+func (j *Job) Condition() xpv1.Condition {
+	switch j.Status {
+	case jobStatusSucceeded:
+		return xpv1.Available()
+	case jobStatusFailed, jobStatusCanceled:
+		return xpv1.Unavailable().WithMessage(j.Error)
+	default:
+		return xpv1.Creating()
+	}
+}
+
+// CompletionPercent returns how much of the job is done.
+func (j *Job) CompletionPercent() int32 {
+	return int32(math.Floor(j.FractionCompleted * 100))
+}
+
+// CancelJobStatement returns the SQL statement that cancels the job with the
+// supplied ID.
+func CancelJobStatement(id int64) string {
+	return "CANCEL JOB " + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlresource
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestSQLJobCondition(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		job    Job
+		want   xpv1.Condition
+	}{
+		"Running": {
+			reason: "A running job should still be creating.",
+			job:    Job{Status: "running", FractionCompleted: 0.5},
+			want:   xpv1.Creating(),
+		},
+		"Succeeded": {
+			reason: "A job that succeeded should be available.",
+			job:    Job{Status: jobStatusSucceeded, FractionCompleted: 1},
+			want:   xpv1.Available(),
+		},
+		"Failed": {
+			reason: "A job that failed should be unavailable, and explain why.",
+			job:    Job{Status: jobStatusFailed, Error: "boom"},
+			want:   xpv1.Unavailable().WithMessage("boom"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.job.Condition()
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nCondition(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
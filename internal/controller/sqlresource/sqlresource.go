@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlresource
+
+import (
+	"context"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/usage"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+)
+
+const (
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+
+	errNoSQLCluster = "no Cluster to connect to: set clusterRef or clusterSelector, or use a ProviderConfig with a self-hosted cluster"
+)
+
+// Setup adds a controller that reconciles the supplied kind of managed
+// resource, which is managed over SQL inside a cluster, with the
+// ExternalClients produced by the supplied function. The external name of
+// these resources defaults to their name, unless the supplied options replace
+// the initializers of the reconciler.
+func Setup(mgr ctrl.Manager, o cloud.Options, name string, gvk schema.GroupVersionKind, obj resource.Managed, newExternal func(client.Client, *sqlclient.DB) managed.ExternalClient, opts ...managed.ReconcilerOption) error {
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	opts = append([]managed.ReconcilerOption{
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			tracker:     usage.NewTracker(mgr.GetClient()),
+			pool:        o.SQLPool,
+			newExternal: newExternal}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder),
+	}, opts...)
+	r := managed.NewReconciler(mgr, resource.ManagedKind(gvk), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(obj).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return obj.DeepCopyObject().(resource.Managed) }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A connector produces an ExternalClient for managed resources
+// that are managed over SQL inside a cluster.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	tracker     *usage.Tracker
+	pool        *sqlclient.Pool
+	newExternal func(client.Client, *sqlclient.DB) managed.ExternalClient
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+	ref := cloud.ClusterRef(mg)
+	if ref == nil {
+		return c.connectSelfHosted(ctx, mg)
+	}
+	if err := c.tracker.Track(ctx, mg, ref.Name); err != nil {
+		return nil, err
+	}
+
+	cfg, err := clusterSQLConfig(ctx, c.kube, mg.GetNamespace(), ref.Name, sqlDatabase(mg))
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.kube, pool: c.pool, config: cfg, newExternal: c.newExternal}, nil
+}
+
+// connectSelfHosted produces an ExternalClient that connects to the
+// self-hosted cluster of the ProviderConfig of the supplied managed resource.
+func (c *connector) connectSelfHosted(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if mg.GetProviderConfigReference() == nil {
+		return nil, errors.New(errNoSQLCluster)
+	}
+	pc, err := cloud.ProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Spec.SelfHosted == nil {
+		return nil, errors.New(errNoSQLCluster)
+	}
+
+	cfg, err := selfHostedSQLConfig(ctx, c.kube, pc.Spec.SelfHosted, sqlDatabase(mg))
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.kube, pool: c.pool, config: cfg, newExternal: c.newExternal}, nil
+}
+
+// sqlDatabase returns the database to connect to in order to manage the
+// supplied managed resource, for statements that apply to the current
+// database. The database of the connection details is used otherwise.
+func sqlDatabase(mg resource.Managed) string {
+	switch cr := mg.(type) {
+	case *v1alpha1.DefaultPrivileges:
+		return cr.Spec.ForProvider.Database
+	case *v1alpha1.SQLScript:
+		return cr.Spec.ForProvider.Database
+	}
+	return ""
+}
+
+// An external acquires a connection to the cluster for each operation on a
+// managed resource, and releases it once the operation completed.
+type external struct {
+	kube        client.Client
+	pool        *sqlclient.Pool
+	config      sqlclient.Config
+	newExternal func(client.Client, *sqlclient.DB) managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	db, err := e.pool.Acquire(ctx, e.config)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	defer e.pool.Release(ctx, db)
+	return e.newExternal(e.kube, db).Observe(ctx, mg)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	mg.SetConditions(xpv1.Creating())
+	db, err := e.pool.Acquire(ctx, e.config)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	defer e.pool.Release(ctx, db)
+	return e.newExternal(e.kube, db).Create(ctx, mg)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	db, err := e.pool.Acquire(ctx, e.config)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	defer e.pool.Release(ctx, db)
+	return e.newExternal(e.kube, db).Update(ctx, mg)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	mg.SetConditions(xpv1.Deleting())
+	db, err := e.pool.Acquire(ctx, e.config)
+	if err != nil {
+		return err
+	}
+	defer e.pool.Release(ctx, db)
+	return e.newExternal(e.kube, db).Delete(ctx, mg)
+}
+
+// Literal returns the supplied string as a SQL string literal.
+func Literal(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
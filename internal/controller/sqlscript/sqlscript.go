@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlscript
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotSQLScript = "managed resource is not a SQLScript custom resource"
+	errGetScript    = "cannot get script"
+	errRunScript    = "cannot run script"
+	errNoScript     = "no script to run: set script, scriptConfigMapRef or scriptSecretRef"
+
+	// maxAppliedChecksums is the number of checksums of scripts that ran a
+	// SQLScript keeps in its status.
+	maxAppliedChecksums = 10
+)
+
+// Setup adds a controller that reconciles SQLScript managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.SQLScriptGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.SQLScriptGroupVersionKind, &v1alpha1.SQLScript{}, func(kube client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{kube: kube, db: db}
+	})
+}
+
+// An external reconciles SQLScripts over SQL.
+type external struct {
+	kube client.Client
+	db   *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.SQLScript)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSQLScript)
+	}
+	if meta.WasDeleted(cr) {
+		// A script cannot be undone. See Delete.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	applied := cr.Status.AtProvider.AppliedChecksums
+	if len(applied) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	script, err := getScript(ctx, c.kube, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: containsChecksum(applied, scriptChecksum(script)),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.SQLScript)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSQLScript)
+	}
+	return managed.ExternalCreation{}, c.run(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.SQLScript)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSQLScript)
+	}
+	return managed.ExternalUpdate{}, c.run(ctx, cr)
+}
+
+// Delete does nothing, as a script cannot be undone.
+func (c *external) Delete(_ context.Context, _ resource.Managed) error {
+	return nil
+}
+
+// run the script of the supplied SQLScript, and record its checksum.
+func (c *external) run(ctx context.Context, cr *v1alpha1.SQLScript) error {
+	script, err := getScript(ctx, c.kube, cr.Spec.ForProvider)
+	if err != nil {
+		return err
+	}
+	if err := c.db.ExecScript(ctx, script); err != nil {
+		return errors.Wrap(err, errRunScript)
+	}
+	now := metav1.Now()
+	cr.Status.AtProvider.AppliedChecksums = appendChecksum(cr.Status.AtProvider.AppliedChecksums, scriptChecksum(script))
+	cr.Status.AtProvider.LastAppliedTime = &now
+	return nil
+}
+
+// getScript returns the script of the supplied parameters.
+func getScript(ctx context.Context, kube client.Client, p v1alpha1.SQLScriptParameters) (string, error) {
+	switch {
+	case p.Script != "":
+		return p.Script, nil
+	case p.ScriptConfigMapRef != nil:
+		ref := p.ScriptConfigMapRef
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return "", errors.Wrap(err, errGetScript)
+		}
+		script, ok := cm.Data[ref.Key]
+		if !ok {
+			return "", errors.Wrap(fmt.Errorf("configmap key \"%s\" not found", ref.Key), errGetScript)
+		}
+		return script, nil
+	case p.ScriptSecretRef != nil:
+		script, err := cloud.GetSecretKey(ctx, kube, p.ScriptSecretRef)
+		return string(script), errors.Wrap(err, errGetScript)
+	}
+	return "", errors.New(errNoScript)
+}
+
+// scriptChecksum returns the SHA-256 checksum of the supplied script.
+func scriptChecksum(script string) string {
+	h := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(h[:])
+}
+
+// containsChecksum returns true if the supplied checksums contain the
+// supplied checksum.
+func containsChecksum(checksums []string, checksum string) bool {
+	for _, c := range checksums {
+		if c == checksum {
+			return true
+		}
+	}
+	return false
+}
+
+// appendChecksum returns the supplied checksums with the supplied one as the
+// most recent, keeping no more than maxAppliedChecksums.
+func appendChecksum(checksums []string, checksum string) []string {
+	cs := []string{}
+	for _, c := range checksums {
+		if c != checksum {
+			cs = append(cs, c)
+		}
+	}
+	cs = append(cs, checksum)
+	if len(cs) > maxAppliedChecksums {
+		cs = cs[len(cs)-maxAppliedChecksums:]
+	}
+	return cs
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlscript
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestGetScript(t *testing.T) {
+	errBoom := errors.New("boom")
+	configMap := func(data map[string]string) client.Client {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+			o.(*corev1.ConfigMap).Data = data
+			return nil
+		})}
+	}
+	ref := &v1alpha1.ConfigMapKeySelector{
+		ConfigMapReference: v1alpha1.ConfigMapReference{Name: "app-bootstrap", Namespace: "crossplane-system"},
+		Key:                "bootstrap.sql",
+	}
+
+	type want struct {
+		script string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		p      v1alpha1.SQLScriptParameters
+		want   want
+	}{
+		"Inline": {
+			reason: "An inline script should be returned as is.",
+			p:      v1alpha1.SQLScriptParameters{Script: "SELECT 1"},
+			want:   want{script: "SELECT 1"},
+		},
+		"ConfigMap": {
+			reason: "A script should be read from the key of a ConfigMap.",
+			kube:   configMap(map[string]string{"bootstrap.sql": "SELECT 1"}),
+			p:      v1alpha1.SQLScriptParameters{ScriptConfigMapRef: ref},
+			want:   want{script: "SELECT 1"},
+		},
+		"ConfigMapKeyNotFound": {
+			reason: "A missing ConfigMap key should be an error.",
+			kube:   configMap(map[string]string{}),
+			p:      v1alpha1.SQLScriptParameters{ScriptConfigMapRef: ref},
+			want:   want{err: errors.Wrap(fmt.Errorf("configmap key \"%s\" not found", "bootstrap.sql"), errGetScript)},
+		},
+		"GetConfigMapError": {
+			reason: "Errors getting the ConfigMap should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			p:      v1alpha1.SQLScriptParameters{ScriptConfigMapRef: ref},
+			want:   want{err: errors.Wrap(errBoom, errGetScript)},
+		},
+		"NoScript": {
+			reason: "Parameters without a script should be an error.",
+			want:   want{err: errors.New(errNoScript)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := getScript(context.Background(), tc.kube, tc.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngetScript(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.script, got); diff != "" {
+				t.Errorf("\n%s\ngetScript(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAppendChecksum(t *testing.T) {
+	many := []string{}
+	for i := 0; i < maxAppliedChecksums; i++ {
+		many = append(many, fmt.Sprintf("c%d", i))
+	}
+
+	cases := map[string]struct {
+		reason    string
+		checksums []string
+		checksum  string
+		want      []string
+	}{
+		"First": {
+			reason:   "The first checksum should be recorded.",
+			checksum: "a",
+			want:     []string{"a"},
+		},
+		"AlreadyApplied": {
+			reason:    "A checksum that was applied before should become the most recent.",
+			checksums: []string{"a", "b"},
+			checksum:  "a",
+			want:      []string{"b", "a"},
+		},
+		"Capped": {
+			reason:    "The oldest checksums should be forgotten.",
+			checksums: many,
+			checksum:  "new",
+			want:      append(append([]string{}, many[1:]...), "new"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := appendChecksum(tc.checksums, tc.checksum)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nappendChecksum(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
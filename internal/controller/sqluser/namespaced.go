@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqluser
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	namespacedv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/namespaced/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/usage"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+)
+
+const (
+	errNotNamespacedSQLUser = "managed resource is not a namespaced SQLUser custom resource"
+)
+
+// SetupNamespaced adds a controller that reconciles namespaced SQLUser
+// managed resources.
+func SetupNamespaced(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(namespacedv1alpha1.SQLUserGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+	for i := range cps {
+		cps[i] = cloud.NewLocalConnectionPublisher(cps[i])
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(namespacedv1alpha1.SQLUserGroupVersionKind),
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&namespacedConnector{connector: &connector{
+			Connector: &cloud.Connector{
+				Kube:         mgr.GetClient(),
+				Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+				APIInfo:      o.APIInfo,
+				NewServiceFn: o.NewService},
+			tracker: usage.NewTracker(mgr.GetClient())}}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		// The SQL user is named after spec.forProvider.name rather than the
+		// external name, which is set once the user was created.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&namespacedv1alpha1.SQLUser{}).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return &namespacedv1alpha1.SQLUser{} }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A namespacedConnector produces an ExternalClient for namespaced SQLUsers.
+type namespacedConnector struct {
+	*connector
+}
+
+func (c *namespacedConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*namespacedv1alpha1.SQLUser)
+	if !ok {
+		return nil, errors.New(errNotNamespacedSQLUser)
+	}
+
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+	if ref := cr.Spec.ForProvider.ClusterRef; ref != nil {
+		if err := c.tracker.Track(ctx, mg, ref.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	svc, err := c.Service(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &namespacedExternal{external: &external{service: svc, kube: c.Kube}}, nil
+}
+
+// A namespacedExternal reconciles namespaced SQLUsers by delegating to the
+// cluster scoped external client.
+type namespacedExternal struct {
+	external *external
+}
+
+func (c *namespacedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*namespacedv1alpha1.SQLUser)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotNamespacedSQLUser)
+	}
+	u := sqlUserFor(cr)
+	o, err := c.external.Observe(ctx, u)
+	setFromSQLUser(cr, u)
+	return o, err
+}
+
+func (c *namespacedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*namespacedv1alpha1.SQLUser)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotNamespacedSQLUser)
+	}
+	u := sqlUserFor(cr)
+	cre, err := c.external.Create(ctx, u)
+	setFromSQLUser(cr, u)
+	return cre, err
+}
+
+func (c *namespacedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*namespacedv1alpha1.SQLUser)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNamespacedSQLUser)
+	}
+	u := sqlUserFor(cr)
+	upd, err := c.external.Update(ctx, u)
+	setFromSQLUser(cr, u)
+	return upd, err
+}
+
+func (c *namespacedExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*namespacedv1alpha1.SQLUser)
+	if !ok {
+		return errors.New(errNotNamespacedSQLUser)
+	}
+	u := sqlUserFor(cr)
+	err := c.external.Delete(ctx, u)
+	setFromSQLUser(cr, u)
+	return err
+}
+
+// sqlUserFor returns the cluster scoped equivalent of a namespaced SQLUser.
+// Secret references are confined to the namespace of the namespaced SQLUser.
+func sqlUserFor(cr *namespacedv1alpha1.SQLUser) *v1alpha1.SQLUser {
+	u := &v1alpha1.SQLUser{
+		ObjectMeta: *cr.ObjectMeta.DeepCopy(),
+		Spec: v1alpha1.SQLUserSpec{
+			ResourceSpec: *cr.Spec.ResourceSpec.DeepCopy(),
+			ForProvider:  *cr.Spec.ForProvider.DeepCopy(),
+		},
+		Status: v1alpha1.SQLUserStatus{
+			ResourceStatus: *cr.Status.ResourceStatus.DeepCopy(),
+			AtProvider:     *cr.Status.AtProvider.DeepCopy(),
+		},
+	}
+	if ref := u.Spec.ForProvider.PasswordSecretRef; ref != nil {
+		ref.Namespace = cr.GetNamespace()
+	}
+	if ref := u.Spec.WriteConnectionSecretToReference; ref != nil {
+		ref.Namespace = cr.GetNamespace()
+	}
+	return u
+}
+
+// setFromSQLUser copies the state written by the external client back to the
+// namespaced SQLUser.
+func setFromSQLUser(cr *namespacedv1alpha1.SQLUser, u *v1alpha1.SQLUser) {
+	cr.SetAnnotations(u.GetAnnotations())
+	cr.Status.ResourceStatus = u.Status.ResourceStatus
+	cr.Status.AtProvider = u.Status.AtProvider
+}
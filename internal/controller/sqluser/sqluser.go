@@ -0,0 +1,281 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqluser
+
+import (
+	"context"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-cockroachdb/apis/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/audit"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/features"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/usage"
+	"github.com/crossplane/provider-cockroachdb/internal/priority"
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+	"github.com/crossplane/provider-cockroachdb/internal/shutdown"
+	"github.com/crossplane/provider-cockroachdb/internal/tracing"
+)
+
+const (
+	errNotSQLUser            = "managed resource is not a SQLUser custom resource"
+	errNoClusterID           = "spec.forProvider.clusterId is required unless a Cluster is referenced"
+	errGetSQLUserCluster     = "cannot get cluster of SQL user"
+	errGetSQLUserSecret      = "cannot get password Secret of SQL user"
+	errGetSQLUserConnCA      = "cannot get CA certificate of cluster of SQL user"
+	errSQLUserClusterGone    = "cluster of SQL user does not exist"
+	errTrackPCUsage          = "cannot track ProviderConfig usage"
+	errCreateSQLUser         = "cannot create SQL user"
+	errGetSQLUserPassword    = "cannot get password of SQL user"
+	errUpdateSQLUserPassword = "cannot update password of SQL user"
+	errDeleteSQLUser         = "cannot delete SQL user"
+)
+
+// Setup adds a controller that reconciles SQLUser managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.SQLUserGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.SQLUserGroupVersionKind),
+		managed.WithExternalConnecter(redact.NewConnecter(cloud.NewTimeoutConnecter(tracing.NewConnecter(name, audit.NewConnecter(&connector{
+			Connector: &cloud.Connector{
+				Kube:         mgr.GetClient(),
+				Usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+				APIInfo:      o.APIInfo,
+				NewServiceFn: o.NewService},
+			tracker: usage.NewTracker(mgr.GetClient())}, audit.NewRecorder(recorder, o.Logger.WithValues("controller", name)))), o.OperationTimeout))),
+		// The SQL user is named after spec.forProvider.name rather than the
+		// external name, which is set once the user was created.
+		managed.WithInitializers(),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.SQLUser{}).
+		Complete(shutdown.NewReconciler(priority.NewReconciler(name, mgr.GetClient(), func() resource.Managed { return &v1alpha1.SQLUser{} }, tracing.NewReconciler(name, r), o.GlobalRateLimiter), o.ShutdownGracePeriod))
+}
+
+// A connector produces an ExternalClient for SQLUsers.
+type connector struct {
+	*cloud.Connector
+	tracker *usage.Tracker
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.SQLUser)
+	if !ok {
+		return nil, errors.New(errNotSQLUser)
+	}
+
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+	if ref := cr.Spec.ForProvider.ClusterRef; ref != nil {
+		if err := c.tracker.Track(ctx, mg, ref.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	svc, err := c.Service(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &external{service: svc, kube: c.Kube}, nil
+}
+
+// An external reconciles SQLUsers.
+type external struct {
+	service *cloud.Service
+	kube    client.Client
+
+	// cluster the SQL user belongs to, as of the last observation.
+	cluster *cockroachdb.Cluster
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.SQLUser)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSQLUser)
+	}
+	clusterID := cr.Spec.ForProvider.ClusterID
+	if clusterID == "" && meta.WasDeleted(cr) {
+		// References are not resolved while deleting, so a SQLUser whose
+		// Cluster never existed would otherwise never go.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if clusterID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoClusterID)
+	}
+
+	cluster, res, err := c.service.CRDBClient.GetCluster(ctx, clusterID)
+	if cloud.IsNotFound(res) {
+		// The SQL users of a cluster are deleted along with it.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSQLUserCluster)
+	}
+	c.cluster = cluster
+
+	users, err := c.service.ListSQLUsers(ctx, clusterID)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if !users[cr.SQLUserName()] {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.AtProvider.ClusterID = clusterID
+	cr.Status.SetConditions(xpv1.Available())
+
+	// A generated password only lives in the connection details, so there is
+	// nothing to compare it with.
+	ref := cr.Spec.ForProvider.PasswordSecretRef
+	if ref == nil {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	_, version, err := cloud.GetSecretKeyVersion(ctx, c.kube, *ref)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSQLUserSecret)
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: version == cr.Status.AtProvider.PasswordSecretVersion,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.SQLUser)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSQLUser)
+	}
+	if c.cluster == nil {
+		return managed.ExternalCreation{}, errors.New(errSQLUserClusterGone)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	pwd, version, err := c.password(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	name := cr.SQLUserName()
+	if _, _, err := c.service.CRDBClient.CreateSQLUser(ctx, c.cluster.Id, &cockroachdb.CreateSQLUserRequest{Name: name, Password: string(pwd)}); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSQLUser)
+	}
+	meta.SetExternalName(cr, name)
+	cr.Status.AtProvider.ClusterID = c.cluster.Id
+	cr.Status.AtProvider.PasswordSecretVersion = version
+
+	cd, err := c.connectionDetails(ctx, name, pwd)
+	return managed.ExternalCreation{ConnectionDetails: cd}, err
+}
+
+// Update rotates the password of the SQL user after the referenced password
+// Secret changed.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.SQLUser)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSQLUser)
+	}
+	if c.cluster == nil {
+		return managed.ExternalUpdate{}, errors.New(errSQLUserClusterGone)
+	}
+	ref := cr.Spec.ForProvider.PasswordSecretRef
+	if ref == nil {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	pwd, version, err := cloud.GetSecretKeyVersion(ctx, c.kube, *ref)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetSQLUserSecret)
+	}
+	name := cr.SQLUserName()
+	req := &cockroachdb.UpdateSQLUserPasswordRequest{Password: string(pwd)}
+	if _, _, err := c.service.CRDBClient.UpdateSQLUserPassword(ctx, c.cluster.Id, name, req); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateSQLUserPassword)
+	}
+	cr.Status.AtProvider.PasswordSecretVersion = version
+
+	cd, err := c.connectionDetails(ctx, name, pwd)
+	return managed.ExternalUpdate{ConnectionDetails: cd}, err
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.SQLUser)
+	if !ok {
+		return errors.New(errNotSQLUser)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	clusterID := cr.Status.AtProvider.ClusterID
+	if clusterID == "" {
+		clusterID = cr.Spec.ForProvider.ClusterID
+	}
+	_, res, err := c.service.CRDBClient.DeleteSQLUser(ctx, clusterID, cr.SQLUserName())
+	if err != nil && !cloud.IsNotFound(res) {
+		return errors.Wrap(err, errDeleteSQLUser)
+	}
+	return nil
+}
+
+// password returns the password to create the supplied SQLUser with and the
+// resource version of the Secret it was read from. A random password with an
+// empty version is returned if the SQLUser references no Secret; it is only
+// generated once, when the SQL user is created.
+func (c *external) password(ctx context.Context, cr *v1alpha1.SQLUser) ([]byte, string, error) {
+	ref := cr.Spec.ForProvider.PasswordSecretRef
+	if ref == nil {
+		pwd, err := cloud.GetPassword(ctx, c.kube, nil)
+		return pwd, "", errors.Wrap(err, errGetSQLUserPassword)
+	}
+	pwd, version, err := cloud.GetSecretKeyVersion(ctx, c.kube, *ref)
+	return pwd, version, errors.Wrap(err, errGetSQLUserSecret)
+}
+
+// connectionDetails returns the connection details of the named SQL user of
+// the observed cluster.
+func (c *external) connectionDetails(ctx context.Context, name string, pwd []byte) (managed.ConnectionDetails, error) {
+	ca, err := c.service.ClusterCACert(ctx, c.cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetSQLUserConnCA)
+	}
+	cd := c.service.ConnectionDetails(ctx, name, c.cluster, ca, pwd)
+	cd[xpv1.ResourceCredentialsSecretUserKey] = []byte(name)
+	cd[xpv1.ResourceCredentialsSecretPasswordKey] = pwd
+	return cd, nil
+}
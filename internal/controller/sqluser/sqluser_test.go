@@ -0,0 +1,262 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqluser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+)
+
+const testClusterID = "8f2d0c3e-7d4b-4a54-9b1e-0d5e5f1a2b3c"
+
+type mockService struct {
+	cockroachdb.Service
+
+	MockGetCluster    func(ctx context.Context, clusterId string) (*cockroachdb.Cluster, *http.Response, error)
+	MockListSQLUsers  func(ctx context.Context, clusterId string, options *cockroachdb.ListSQLUsersOptions) (*cockroachdb.ListSQLUsersResponse, *http.Response, error)
+	MockDeleteSQLUser func(ctx context.Context, clusterId string, name string) (*cockroachdb.SQLUser, *http.Response, error)
+}
+
+func (m *mockService) GetCluster(ctx context.Context, clusterId string) (*cockroachdb.Cluster, *http.Response, error) {
+	return m.MockGetCluster(ctx, clusterId)
+}
+
+func (m *mockService) ListSQLUsers(ctx context.Context, clusterId string, options *cockroachdb.ListSQLUsersOptions) (*cockroachdb.ListSQLUsersResponse, *http.Response, error) {
+	return m.MockListSQLUsers(ctx, clusterId, options)
+}
+
+func (m *mockService) DeleteSQLUser(ctx context.Context, clusterId string, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+	return m.MockDeleteSQLUser(ctx, clusterId, name)
+}
+
+type sqlUserModifier func(*v1alpha1.SQLUser)
+
+func withPasswordSecretRef() sqlUserModifier {
+	return func(cr *v1alpha1.SQLUser) {
+		cr.Spec.ForProvider.PasswordSecretRef = &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "pwd", Namespace: "default"},
+			Key:             "password",
+		}
+	}
+}
+
+func withPasswordSecretVersion(v string) sqlUserModifier {
+	return func(cr *v1alpha1.SQLUser) { cr.Status.AtProvider.PasswordSecretVersion = v }
+}
+
+func sqlUser(m ...sqlUserModifier) *v1alpha1.SQLUser {
+	cr := &v1alpha1.SQLUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: v1alpha1.SQLUserSpec{
+			ForProvider: v1alpha1.SQLUserParameters{ClusterID: testClusterID},
+		},
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func TestSQLUserObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	getCluster := func(_ context.Context, id string) (*cockroachdb.Cluster, *http.Response, error) {
+		return &cockroachdb.Cluster{Id: id}, &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	listUsers := func(names ...string) func(context.Context, string, *cockroachdb.ListSQLUsersOptions) (*cockroachdb.ListSQLUsersResponse, *http.Response, error) {
+		return func(context.Context, string, *cockroachdb.ListSQLUsersOptions) (*cockroachdb.ListSQLUsersResponse, *http.Response, error) {
+			res := &cockroachdb.ListSQLUsersResponse{}
+			for _, n := range names {
+				res.Users = append(res.Users, cockroachdb.SQLUser{Name: n})
+			}
+			return res, &http.Response{StatusCode: http.StatusOK}, nil
+		}
+	}
+	secret := func(version string) client.Client {
+		return &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			s := obj.(*corev1.Secret)
+			s.SetResourceVersion(version)
+			s.Data = map[string][]byte{"password": []byte("s3cr3t")}
+			return nil
+		}}
+	}
+	available := func(cr *v1alpha1.SQLUser) {
+		cr.Status.AtProvider.ClusterID = testClusterID
+		cr.Status.SetConditions(xpv1.Available())
+	}
+
+	type want struct {
+		o   managed.ExternalObservation
+		cr  *v1alpha1.SQLUser
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service cockroachdb.Service
+		kube    client.Client
+		cr      *v1alpha1.SQLUser
+		want    want
+	}{
+		"NoClusterID": {
+			reason: "A SQLUser without a cluster ID should not be observed.",
+			cr:     sqlUser(func(cr *v1alpha1.SQLUser) { cr.Spec.ForProvider.ClusterID = "" }),
+			want: want{
+				cr:  sqlUser(func(cr *v1alpha1.SQLUser) { cr.Spec.ForProvider.ClusterID = "" }),
+				err: errors.New(errNoClusterID),
+			},
+		},
+		"ClusterGone": {
+			reason: "The SQL user should not exist once its cluster is gone.",
+			service: &mockService{MockGetCluster: func(context.Context, string) (*cockroachdb.Cluster, *http.Response, error) {
+				return nil, &http.Response{StatusCode: http.StatusNotFound}, errBoom
+			}},
+			cr:   sqlUser(),
+			want: want{cr: sqlUser()},
+		},
+		"GetClusterError": {
+			reason: "Errors getting the cluster should be returned.",
+			service: &mockService{MockGetCluster: func(context.Context, string) (*cockroachdb.Cluster, *http.Response, error) {
+				return nil, &http.Response{StatusCode: http.StatusInternalServerError}, errBoom
+			}},
+			cr:   sqlUser(),
+			want: want{cr: sqlUser(), err: errors.Wrap(errBoom, errGetSQLUserCluster)},
+		},
+		"DoesNotExist": {
+			reason: "A SQL user missing from the cluster should not exist.",
+			service: &mockService{
+				MockGetCluster:   getCluster,
+				MockListSQLUsers: listUsers("other"),
+			},
+			cr:   sqlUser(),
+			want: want{cr: sqlUser()},
+		},
+		"UpToDate": {
+			reason: "An existing SQL user whose password Secret did not change should be up to date.",
+			service: &mockService{
+				MockGetCluster:   getCluster,
+				MockListSQLUsers: listUsers("app"),
+			},
+			kube: secret("7"),
+			cr:   sqlUser(withPasswordSecretRef(), withPasswordSecretVersion("7")),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cr: sqlUser(withPasswordSecretRef(), withPasswordSecretVersion("7"), available),
+			},
+		},
+		"GeneratedPassword": {
+			reason: "An existing SQL user without a password Secret should be up to date without reading or generating a password.",
+			service: &mockService{
+				MockGetCluster:   getCluster,
+				MockListSQLUsers: listUsers("app"),
+			},
+			cr: sqlUser(),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cr: sqlUser(available),
+			},
+		},
+		"PasswordChanged": {
+			reason: "An existing SQL user whose password Secret changed should be rotated.",
+			service: &mockService{
+				MockGetCluster:   getCluster,
+				MockListSQLUsers: listUsers("app"),
+			},
+			kube: secret("8"),
+			cr:   sqlUser(withPasswordSecretRef(), withPasswordSecretVersion("7")),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				cr: sqlUser(withPasswordSecretRef(), withPasswordSecretVersion("7"), available),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{service: &cloud.Service{CRDBClient: tc.service}, kube: tc.kube}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSQLUserDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		res    *http.Response
+		err    error
+		want   error
+	}{
+		"Deleted": {
+			reason: "Deleting a SQL user should succeed.",
+			res:    &http.Response{StatusCode: http.StatusOK},
+		},
+		"AlreadyGone": {
+			reason: "A SQL user that is already gone should be ignored.",
+			res:    &http.Response{StatusCode: http.StatusNotFound},
+			err:    errBoom,
+		},
+		"DeleteError": {
+			reason: "Errors deleting the SQL user should be returned.",
+			res:    &http.Response{StatusCode: http.StatusInternalServerError},
+			err:    errBoom,
+			want:   errors.Wrap(errBoom, errDeleteSQLUser),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted string
+			svc := &mockService{MockDeleteSQLUser: func(_ context.Context, id, name string) (*cockroachdb.SQLUser, *http.Response, error) {
+				deleted = id + "/" + name
+				return nil, tc.res, tc.err
+			}}
+			e := &external{service: &cloud.Service{CRDBClient: svc}}
+			err := e.Delete(context.Background(), sqlUser())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(testClusterID+"/app", deleted); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want deleted, +got deleted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
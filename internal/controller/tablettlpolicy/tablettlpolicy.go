@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tablettlpolicy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/grant"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/sqlresource"
+	"github.com/crossplane/provider-cockroachdb/internal/sqlclient"
+)
+
+const (
+	errNotTableTTLPolicy        = "managed resource is not a TableTTLPolicy custom resource"
+	errNoTableTTLPolicyDatabase = "no database of the tables: set database, databaseRef or databaseSelector"
+	errObserveTableTTL          = "cannot observe TTL of tables"
+	errApplyTableTTL            = "cannot apply TTL to tables"
+	errResetTableTTL            = "cannot remove TTL from tables"
+)
+
+// Storage parameters of tables that configure row-level TTL.
+const (
+	ttlParamEnabled              = "ttl"
+	ttlParamExpireAfter          = "ttl_expire_after"
+	ttlParamExpirationExpression = "ttl_expiration_expression"
+	ttlParamJobCron              = "ttl_job_cron"
+)
+
+// ttlParams are the storage parameters a TableTTLPolicy manages.
+var ttlParams = []string{ttlParamExpireAfter, ttlParamExpirationExpression, ttlParamJobCron}
+
+// Setup adds a controller that reconciles TableTTLPolicy managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.TableTTLPolicyGroupKind)
+	return sqlresource.Setup(mgr, o, name, v1alpha1.TableTTLPolicyGroupVersionKind, &v1alpha1.TableTTLPolicy{}, func(_ client.Client, db *sqlclient.DB) managed.ExternalClient {
+		return &external{db: db}
+	})
+}
+
+// An external reconciles TableTTLPolicies over SQL.
+type external struct {
+	db *sqlclient.DB
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.TableTTLPolicy)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotTableTTLPolicy)
+	}
+	if cr.Spec.ForProvider.Database == "" {
+		return managed.ExternalObservation{}, errors.New(errNoTableTTLPolicyDatabase)
+	}
+
+	observed, err := c.observe(ctx, cr.Spec.ForProvider)
+	if sqlclient.IsNotFound(err) && meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errObserveTableTTL)
+	}
+
+	exists := false
+	for _, params := range observed {
+		exists = exists || params[ttlParamEnabled] == "on"
+	}
+	if !exists {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(tableTTLStatements(cr.Spec.ForProvider, observed)) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.TableTTLPolicy)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotTableTTLPolicy)
+	}
+	stmts := tableTTLStatements(cr.Spec.ForProvider, map[string]map[string]string{})
+	return managed.ExternalCreation{}, errors.Wrap(c.db.Exec(ctx, stmts...), errApplyTableTTL)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.TableTTLPolicy)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotTableTTLPolicy)
+	}
+	observed, err := c.observe(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errObserveTableTTL)
+	}
+	stmts := tableTTLStatements(cr.Spec.ForProvider, observed)
+	return managed.ExternalUpdate{}, errors.Wrap(c.db.Exec(ctx, stmts...), errApplyTableTTL)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.TableTTLPolicy)
+	if !ok {
+		return errors.New(errNotTableTTLPolicy)
+	}
+	stmts := []string{}
+	for _, t := range ttlTables(cr.Spec.ForProvider) {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE IF EXISTS %s RESET (%s)", t, ttlParamEnabled))
+	}
+	return errors.Wrap(c.db.Exec(ctx, stmts...), errResetTableTTL)
+}
+
+// observe returns the TTL storage parameters of each existing table of the
+// supplied parameters. The interval tables expire rows after is replaced by that of
+// the parameters if both are equal, even if they are spelled differently.
+func (c *external) observe(ctx context.Context, p v1alpha1.TableTTLPolicyParameters) (map[string]map[string]string, error) {
+	schema := p.Schema
+	if schema == "" {
+		schema = grant.DefaultSchema
+	}
+	catalog := pgx.Identifier{p.Database, "pg_catalog"}.Sanitize()
+	query := fmt.Sprintf("SELECT COALESCE(c.reloptions, ARRAY[]::STRING[]) FROM %[1]s.pg_class c JOIN %[1]s.pg_namespace n ON c.relnamespace = n.oid WHERE n.nspname = $1 AND c.relname = $2", catalog)
+
+	observed := map[string]map[string]string{}
+	tables := ttlTables(p)
+	for i, name := range p.Tables {
+		var opts []string
+		err := c.db.QueryRow(ctx, query, schema, name).Scan(&opts)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Applying TTL to a table that does not exist fails, and
+			// explains why.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		params := parseStorageParams(opts)
+		if have, ok := params[ttlParamExpireAfter]; ok && p.ExpireAfter != "" && have != p.ExpireAfter {
+			var same bool
+			if err := c.db.QueryRow(ctx, "SELECT $1::INTERVAL = $2::INTERVAL", have, p.ExpireAfter).Scan(&same); err != nil {
+				return nil, err
+			}
+			if same {
+				params[ttlParamExpireAfter] = p.ExpireAfter
+			}
+		}
+		observed[tables[i]] = params
+	}
+	return observed, nil
+}
+
+// ttlTables returns the qualified names of the tables of the supplied
+// parameters.
+func ttlTables(p v1alpha1.TableTTLPolicyParameters) []string {
+	schema := p.Schema
+	if schema == "" {
+		schema = grant.DefaultSchema
+	}
+	tables := make([]string, len(p.Tables))
+	for i, t := range p.Tables {
+		tables[i] = pgx.Identifier{p.Database, schema, t}.Sanitize()
+	}
+	return tables
+}
+
+// tableTTLStatements returns the SQL statements that change the supplied TTL
+// storage parameters of each table to those of the supplied parameters.
+func tableTTLStatements(p v1alpha1.TableTTLPolicyParameters, observed map[string]map[string]string) []string {
+	want := map[string]string{
+		ttlParamExpireAfter:          p.ExpireAfter,
+		ttlParamExpirationExpression: p.ExpirationExpression,
+		ttlParamJobCron:              p.JobCron,
+	}
+
+	stmts := []string{}
+	for _, t := range ttlTables(p) {
+		have := observed[t]
+		set, reset := []string{}, []string{}
+		for _, param := range ttlParams {
+			v, ok := have[param]
+			switch {
+			case want[param] != "" && v != want[param]:
+				set = append(set, fmt.Sprintf("%s = %s", param, sqlresource.Literal(want[param])))
+			case want[param] == "" && ok:
+				reset = append(reset, param)
+			}
+		}
+		if len(set) > 0 {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SET (%s)", t, strings.Join(set, ", ")))
+		}
+		if len(reset) > 0 {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s RESET (%s)", t, strings.Join(reset, ", ")))
+		}
+	}
+	return stmts
+}
+
+// storageParamCast matches the type cast CockroachDB appends to some storage
+// parameters, e.g. ':::INTERVAL'.
+var storageParamCast = regexp.MustCompile(`:::[A-Z ]+$`)
+
+// parseStorageParams parses the supplied storage parameters of a table, as
+// listed in pg_class.reloptions, e.g. ttl_expire_after='30 days':::INTERVAL.
+func parseStorageParams(opts []string) map[string]string {
+	params := map[string]string{}
+	for _, o := range opts {
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		v := storageParamCast.ReplaceAllString(strings.TrimSpace(kv[1]), "")
+		if len(v) >= 2 && strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'") {
+			v = strings.ReplaceAll(v[1:len(v)-1], "''", "'")
+		}
+		params[strings.TrimSpace(kv[0])] = v
+	}
+	return params
+}
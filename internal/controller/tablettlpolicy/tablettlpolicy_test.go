@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tablettlpolicy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+)
+
+func TestTableTTLStatements(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		p        v1alpha1.TableTTLPolicyParameters
+		observed map[string]map[string]string
+		want     []string
+	}{
+		"NoTTL": {
+			reason: "TTL should be applied to each table, in the public schema by default.",
+			p:      v1alpha1.TableTTLPolicyParameters{Database: "app", Tables: []string{"events", "logs"}, ExpireAfter: "30 days"},
+			want: []string{
+				`ALTER TABLE "app"."public"."events" SET (ttl_expire_after = '30 days')`,
+				`ALTER TABLE "app"."public"."logs" SET (ttl_expire_after = '30 days')`,
+			},
+		},
+		"UpToDate": {
+			reason: "No statements should be needed when the tables have the desired TTL.",
+			p:      v1alpha1.TableTTLPolicyParameters{Database: "app", Schema: "audit", Tables: []string{"events"}, ExpireAfter: "30 days", JobCron: "@daily"},
+			observed: map[string]map[string]string{
+				`"app"."audit"."events"`: {"ttl": "on", "ttl_expire_after": "30 days", "ttl_job_cron": "@daily"},
+			},
+			want: []string{},
+		},
+		"Drift": {
+			reason: "TTL settings changed outside of the policy should be corrected, and those it does not set removed.",
+			p:      v1alpha1.TableTTLPolicyParameters{Database: "app", Tables: []string{"events"}, ExpirationExpression: "created_at + INTERVAL '7 days'"},
+			observed: map[string]map[string]string{
+				`"app"."public"."events"`: {"ttl": "on", "ttl_expire_after": "30 days", "ttl_job_cron": "@daily"},
+			},
+			want: []string{
+				`ALTER TABLE "app"."public"."events" SET (ttl_expiration_expression = 'created_at + INTERVAL ''7 days''')`,
+				`ALTER TABLE "app"."public"."events" RESET (ttl_expire_after, ttl_job_cron)`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tableTTLStatements(tc.p, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntableTTLStatements(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseStorageParams(t *testing.T) {
+	opts := []string{
+		"ttl='on'",
+		"ttl_expire_after='30 days':::INTERVAL",
+		"ttl_expiration_expression='created_at + ''7 days'':::INTERVAL'",
+		"fillfactor=100",
+	}
+	want := map[string]string{
+		"ttl":                       "on",
+		"ttl_expire_after":          "30 days",
+		"ttl_expiration_expression": "created_at + '7 days':::INTERVAL",
+		"fillfactor":                "100",
+	}
+	if diff := cmp.Diff(want, parseStorageParams(opts)); diff != "" {
+		t.Errorf("parseStorageParams(...): -want, +got:\n%s\n", diff)
+	}
+}
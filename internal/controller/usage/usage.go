@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage protects Clusters from being deleted while other managed
+// resources still depend on them.
+package usage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LabelKeyCluster is set on managed resources that depend on a Cluster. Its
+// value is the name of the Cluster.
+const LabelKeyCluster = "database.cockroachdb.crossplane.io/cluster"
+
+const (
+	errTrack          = "cannot label managed resource with the Cluster it depends on"
+	errListDependents = "cannot list resources depending on Cluster"
+	errFmtInUse       = "cannot delete Cluster in use by %s"
+)
+
+// A Tracker records that a managed resource depends on a Cluster.
+type Tracker struct {
+	kube client.Client
+}
+
+// NewTracker returns a Tracker that labels dependents through the supplied
+// client.
+func NewTracker(kube client.Client) *Tracker {
+	return &Tracker{kube: kube}
+}
+
+// Track records that the supplied managed resource depends on the named
+// Cluster.
+func (t *Tracker) Track(ctx context.Context, mg resource.Managed, cluster string) error {
+	if mg.GetLabels()[LabelKeyCluster] == cluster {
+		return nil
+	}
+	meta.AddLabels(mg, map[string]string{LabelKeyCluster: cluster})
+	return errors.Wrap(t.kube.Update(ctx, mg), errTrack)
+}
+
+// A Protector refuses to let a Cluster go while resources of the supplied
+// kinds still depend on it.
+type Protector struct {
+	kube       client.Client
+	dependents []schema.GroupVersionKind
+}
+
+// NewProtector returns a Protector that checks the supplied list kinds for
+// dependents.
+func NewProtector(kube client.Client, dependents ...schema.GroupVersionKind) *Protector {
+	return &Protector{kube: kube, dependents: dependents}
+}
+
+// Protect returns an error if any resource still depends on the supplied
+// Cluster. Dependents of a namespaced Cluster are looked up in its namespace.
+func (p *Protector) Protect(ctx context.Context, cluster resource.Managed) error {
+	users := []string{}
+	for _, gvk := range p.dependents {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		opts := []client.ListOption{client.MatchingLabels{LabelKeyCluster: cluster.GetName()}}
+		if ns := cluster.GetNamespace(); ns != "" {
+			opts = append(opts, client.InNamespace(ns))
+		}
+		if err := p.kube.List(ctx, l, opts...); err != nil {
+			return errors.Wrap(err, errListDependents)
+		}
+		for _, u := range l.Items {
+			users = append(users, strings.TrimSuffix(gvk.Kind, "List")+"/"+u.GetName())
+		}
+	}
+	if len(users) > 0 {
+		return errors.Errorf(errFmtInUse, strings.Join(users, ", "))
+	}
+	return nil
+}
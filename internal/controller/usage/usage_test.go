@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestProtect(t *testing.T) {
+	errBoom := errors.New("boom")
+	userList := schema.GroupVersionKind{Group: "database.cockroachdb.crossplane.io", Version: "v1alpha1", Kind: "SQLUserList"}
+
+	type args struct {
+		kube       client.Client
+		dependents []schema.GroupVersionKind
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoDependents": {
+			reason: "A Cluster without dependents should not be protected.",
+			args: args{
+				kube:       &test.MockClient{MockList: test.NewMockListFn(nil)},
+				dependents: []schema.GroupVersionKind{userList},
+			},
+		},
+		"ListError": {
+			reason: "Errors listing dependents should be returned.",
+			args: args{
+				kube:       &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				dependents: []schema.GroupVersionKind{userList},
+			},
+			want: errors.Wrap(errBoom, errListDependents),
+		},
+		"InUse": {
+			reason: "A Cluster with dependents should be protected.",
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					u := unstructured.Unstructured{}
+					u.SetName("admin")
+					o.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{u}
+					return nil
+				})},
+				dependents: []schema.GroupVersionKind{userList},
+			},
+			want: errors.Errorf(errFmtInUse, "SQLUser/admin"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewProtector(tc.args.kube, tc.args.dependents...)
+			err := p.Protect(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Protect(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
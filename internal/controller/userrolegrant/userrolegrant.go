@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userrolegrant
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/internal/controller/cloud"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+const (
+	errNotUserRoleGrant = "managed resource is not a UserRoleGrant custom resource"
+	errListRoleGrants   = "cannot list roles granted to user"
+	errAddRoleGrant     = "cannot grant role to user"
+	errRemoveRoleGrant  = "cannot revoke role from user"
+)
+
+// Setup adds a controller that reconciles UserRoleGrant managed resources.
+func Setup(mgr ctrl.Manager, o cloud.Options) error {
+	name := managed.ControllerName(v1alpha1.UserRoleGrantGroupKind)
+	return cloud.SetupResource(mgr, o, name, v1alpha1.UserRoleGrantGroupVersionKind, &v1alpha1.UserRoleGrant{}, func(_ client.Client, c *cockroachcloud.Client) managed.ExternalClient {
+		return &external{client: c}
+	})
+}
+
+// An external reconciles UserRoleGrants.
+type external struct {
+	client *cockroachcloud.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.UserRoleGrant)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotUserRoleGrant)
+	}
+
+	grants, err := c.client.ListRoleGrants(ctx, cr.Spec.ForProvider.UserID)
+	if cockroachcloud.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListRoleGrants)
+	}
+	want := roleGrant(cr.Spec.ForProvider)
+	for _, g := range grants {
+		if g == want {
+			cr.Status.SetConditions(xpv1.Available())
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+		}
+	}
+	return managed.ExternalObservation{ResourceExists: false}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.UserRoleGrant)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotUserRoleGrant)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+
+	err := c.client.AddRoleGrant(ctx, cr.Spec.ForProvider.UserID, roleGrant(cr.Spec.ForProvider))
+	return managed.ExternalCreation{}, errors.Wrap(err, errAddRoleGrant)
+}
+
+// Update is a no-op, since grants are immutable.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.UserRoleGrant)
+	if !ok {
+		return errors.New(errNotUserRoleGrant)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+
+	err := c.client.RemoveRoleGrant(ctx, cr.Spec.ForProvider.UserID, roleGrant(cr.Spec.ForProvider))
+	if cockroachcloud.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, errRemoveRoleGrant)
+}
+
+// roleGrant returns the Cloud API grant of the supplied parameters.
+func roleGrant(p v1alpha1.UserRoleGrantParameters) cockroachcloud.RoleGrant {
+	return cockroachcloud.RoleGrant{
+		Name:     p.Role,
+		Resource: cockroachcloud.Resource{Type: cockroachcloud.ResourceType(p.Resource.Type), ID: p.Resource.ID},
+	}
+}
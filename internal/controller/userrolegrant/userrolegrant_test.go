@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userrolegrant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-cockroachdb/apis/database/v1alpha1"
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud/cockroachcloudtest"
+)
+
+func TestRoleGrantObserve(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   managed.ExternalObservation
+	}{
+		"UserNotFound": {
+			reason: "A grant to a user the Cloud API does not know should not exist.",
+		},
+		"NotGranted": {
+			reason: "A role granted on another resource should not satisfy the grant.",
+			body:   `{"roles":[{"name":"CLUSTER_ADMIN","resource":{"type":"CLUSTER","id":"other"}}]}`,
+		},
+		"Granted": {
+			reason: "A granted role should exist and be up to date.",
+			body:   `{"roles":[{"name":"ORG_MEMBER","resource":{"type":"ORGANIZATION","id":"org"}},{"name":"CLUSTER_ADMIN","resource":{"type":"CLUSTER","id":"cluster"}}]}`,
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: cockroachcloudtest.NewClient(t, tc.body)}
+			cr := &v1alpha1.UserRoleGrant{Spec: v1alpha1.UserRoleGrantSpec{ForProvider: v1alpha1.UserRoleGrantParameters{
+				UserID:   "user",
+				Role:     "CLUSTER_ADMIN",
+				Resource: v1alpha1.RoleResource{Type: "CLUSTER", ID: "cluster"},
+			}}}
+			got, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package function implements a Composition Function that renders
+// app-specific connection details from those of a composed Cluster.
+package function
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FunctionIO is the subset of the Composition Function input and output
+// (apiextensions.fn.io/v1alpha1) that the function reads or writes. Parts it
+// does not need are passed through untouched.
+type FunctionIO struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Config   json.RawMessage `json:"config,omitempty"`
+	Observed Observed        `json:"observed"`
+	Desired  Desired         `json:"desired"`
+	Results  []Result        `json:"results,omitempty"`
+}
+
+// Observed state of the composite resource and its composed resources.
+type Observed struct {
+	Composite json.RawMessage    `json:"composite,omitempty"`
+	Resources []ObservedResource `json:"resources,omitempty"`
+}
+
+// An ObservedResource is an observed composed resource.
+type ObservedResource struct {
+	Name              string                     `json:"name"`
+	Resource          json.RawMessage            `json:"resource,omitempty"`
+	ConnectionDetails []ExplicitConnectionDetail `json:"connectionDetails,omitempty"`
+}
+
+// Desired state of the composite resource and its composed resources.
+type Desired struct {
+	Composite DesiredComposite  `json:"composite"`
+	Resources []json.RawMessage `json:"resources,omitempty"`
+}
+
+// DesiredComposite is the desired state of the composite resource.
+type DesiredComposite struct {
+	Resource          json.RawMessage            `json:"resource,omitempty"`
+	ConnectionDetails []ExplicitConnectionDetail `json:"connectionDetails,omitempty"`
+}
+
+// An ExplicitConnectionDetail is a connection detail with a known value.
+type ExplicitConnectionDetail struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Severities of results.
+const (
+	SeverityFatal   = "Fatal"
+	SeverityWarning = "Warning"
+	SeverityNormal  = "Normal"
+)
+
+// A Result of running the function.
+type Result struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
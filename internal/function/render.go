@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package function
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Config of the function, supplied through the config of the function in the
+// Composition.
+type Config struct {
+	// Resource is the name of the composed Cluster whose connection details
+	// are rendered. Defaults to the first composed resource.
+	Resource string `json:"resource,omitempty"`
+	// Keys of the rendered connection details, mapped to Go templates of
+	// their values, e.g. DATABASE_URL: "{{ .dsn }}". Templates can refer to
+	// every connection detail of the Cluster, and to the host, port,
+	// username, password and database parsed from its dsn.
+	Keys map[string]string `json:"keys"`
+}
+
+// Run renders the connection details of the composite resource. Failures are
+// reported as a Fatal result rather than returned, as Crossplane expects.
+func Run(io *FunctionIO) {
+	if err := render(io); err != nil {
+		io.Results = append(io.Results, Result{Severity: SeverityFatal, Message: err.Error()})
+	}
+}
+
+func render(io *FunctionIO) error {
+	cfg := &Config{}
+	if len(io.Config) > 0 {
+		if err := json.Unmarshal(io.Config, cfg); err != nil {
+			return fmt.Errorf("cannot parse function config: %v", err)
+		}
+	}
+
+	res := observedResource(io.Observed.Resources, cfg.Resource)
+	if res == nil {
+		io.Results = append(io.Results, Result{Severity: SeverityNormal, Message: "composed resource is not observed yet"})
+		return nil
+	}
+	data := templateData(res.ConnectionDetails)
+
+	keys := make([]string, 0, len(cfg.Keys))
+	for k := range cfg.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rendered := make(map[string]string, len(keys))
+	for _, k := range keys {
+		t, err := template.New(k).Option("missingkey=error").Parse(cfg.Keys[k])
+		if err != nil {
+			return fmt.Errorf("cannot parse template of connection detail %q: %v", k, err)
+		}
+		b := &bytes.Buffer{}
+		if err := t.Execute(b, data); err != nil {
+			// The Cluster may not have published every detail yet.
+			io.Results = append(io.Results, Result{Severity: SeverityWarning, Message: fmt.Sprintf("cannot render connection detail %q: %v", k, err)})
+			continue
+		}
+		rendered[k] = b.String()
+	}
+
+	details := []ExplicitConnectionDetail{}
+	for _, d := range io.Desired.Composite.ConnectionDetails {
+		if _, ok := rendered[d.Name]; !ok {
+			details = append(details, d)
+		}
+	}
+	for _, k := range keys {
+		if v, ok := rendered[k]; ok {
+			details = append(details, ExplicitConnectionDetail{Name: k, Value: v})
+		}
+	}
+	io.Desired.Composite.ConnectionDetails = details
+	return nil
+}
+
+// observedResource returns the named observed resource, or the first one if
+// no name is supplied.
+func observedResource(resources []ObservedResource, name string) *ObservedResource {
+	for i := range resources {
+		if name == "" || resources[i].Name == name {
+			return &resources[i]
+		}
+	}
+	return nil
+}
+
+// templateData returns the supplied connection details, along with the parts
+// of the dsn connection detail that are not connection details themselves.
+func templateData(details []ExplicitConnectionDetail) map[string]string {
+	data := map[string]string{}
+	for _, d := range details {
+		data[d.Name] = d.Value
+	}
+	u, err := url.Parse(data["dsn"])
+	if err != nil || u.Host == "" {
+		return data
+	}
+	parts := map[string]string{
+		"host":     u.Hostname(),
+		"port":     u.Port(),
+		"username": u.User.Username(),
+		"database": strings.TrimPrefix(u.Path, "/"),
+	}
+	if pwd, ok := u.User.Password(); ok {
+		parts["password"] = pwd
+	}
+	for k, v := range parts {
+		if _, ok := data[k]; !ok {
+			data[k] = v
+		}
+	}
+	return data
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// inventoryTimeout bounds how long a scrape waits for the inventory.
+const inventoryTimeout = 10 * time.Second
+
+var (
+	inventoryResources = prometheus.NewDesc(
+		"cockroachdb_managed_resources",
+		"Number of managed resources by kind, plan, cloud provider and readiness.",
+		[]string{"kind", "plan", "provider", "ready"}, nil)
+
+	inventoryOldestUnready = prometheus.NewDesc(
+		"cockroachdb_managed_resources_oldest_unready_seconds",
+		"Age of the oldest managed resource of each kind that is not ready, or 0 if all are ready.",
+		[]string{"kind"}, nil)
+)
+
+// An InventoryItem is a managed resource counted in the inventory.
+type InventoryItem struct {
+	Kind     string
+	Plan     string
+	Provider string
+	Ready    bool
+	Created  time.Time
+}
+
+// An InventorySource lists the managed resources counted in the inventory.
+type InventorySource func(ctx context.Context) ([]InventoryItem, error)
+
+// An InventoryCollector exports the number of managed resources, and the age
+// of the oldest one that is not ready, each time it is scraped.
+type InventoryCollector struct {
+	source InventorySource
+	now    func() time.Time
+}
+
+// NewInventoryCollector returns an InventoryCollector that counts the
+// managed resources listed by the supplied source.
+func NewInventoryCollector(source InventorySource) *InventoryCollector {
+	return &InventoryCollector{source: source, now: time.Now}
+}
+
+// Describe the metrics exported by the InventoryCollector.
+func (c *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inventoryResources
+	ch <- inventoryOldestUnready
+}
+
+// Collect the inventory. Nothing is exported if it cannot be listed.
+func (c *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryTimeout)
+	defer cancel()
+
+	items, err := c.source(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(inventoryResources, err)
+		return
+	}
+
+	type key struct{ kind, plan, provider, ready string }
+	counts := map[key]float64{}
+	oldest := map[string]time.Time{}
+	for _, i := range items {
+		counts[key{i.Kind, i.Plan, i.Provider, strconv.FormatBool(i.Ready)}]++
+		o, ok := oldest[i.Kind]
+		if !ok {
+			oldest[i.Kind] = time.Time{}
+		}
+		if !i.Ready && (o.IsZero() || i.Created.Before(o)) {
+			oldest[i.Kind] = i.Created
+		}
+	}
+
+	for k, n := range counts {
+		ch <- prometheus.MustNewConstMetric(inventoryResources, prometheus.GaugeValue, n, k.kind, k.plan, k.provider, k.ready)
+	}
+	for kind, created := range oldest {
+		age := 0.0
+		if !created.IsZero() {
+			age = c.now().Sub(created).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(inventoryOldestUnready, prometheus.GaugeValue, age, kind)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInventoryCollector(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewInventoryCollector(func(_ context.Context) ([]InventoryItem, error) {
+		return []InventoryItem{
+			{Kind: "Cluster", Plan: "SERVERLESS", Provider: "AWS", Ready: true, Created: now.Add(-3 * time.Hour)},
+			{Kind: "Cluster", Plan: "SERVERLESS", Provider: "AWS", Ready: false, Created: now.Add(-time.Hour)},
+			{Kind: "Cluster", Plan: "SERVERLESS", Provider: "GCP", Ready: false, Created: now.Add(-2 * time.Hour)},
+			{Kind: "SQLUser", Plan: "", Provider: "", Ready: true, Created: now.Add(-time.Hour)},
+		}, nil
+	})
+	c.now = func() time.Time { return now }
+
+	want := `
+# HELP cockroachdb_managed_resources Number of managed resources by kind, plan, cloud provider and readiness.
+# TYPE cockroachdb_managed_resources gauge
+cockroachdb_managed_resources{kind="Cluster",plan="SERVERLESS",provider="AWS",ready="false"} 1
+cockroachdb_managed_resources{kind="Cluster",plan="SERVERLESS",provider="AWS",ready="true"} 1
+cockroachdb_managed_resources{kind="Cluster",plan="SERVERLESS",provider="GCP",ready="false"} 1
+cockroachdb_managed_resources{kind="SQLUser",plan="",provider="",ready="true"} 1
+# HELP cockroachdb_managed_resources_oldest_unready_seconds Age of the oldest managed resource of each kind that is not ready, or 0 if all are ready.
+# TYPE cockroachdb_managed_resources_oldest_unready_seconds gauge
+cockroachdb_managed_resources_oldest_unready_seconds{kind="Cluster"} 7200
+cockroachdb_managed_resources_oldest_unready_seconds{kind="SQLUser"} 0
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Errorf("Collect(...): %v", err)
+	}
+}
+
+func TestInventoryCollectorSourceError(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(NewInventoryCollector(func(_ context.Context) ([]InventoryItem, error) {
+		return nil, errBoom
+	}))
+
+	// The registry flattens collection errors into its own messages.
+	if _, err := r.Gather(); err == nil || !strings.Contains(err.Error(), errBoom.Error()) {
+		t.Errorf("Gather(...): want error %v, got %v", errBoom, err)
+	}
+}
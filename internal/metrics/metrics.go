@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exports Prometheus metrics about the resources managed by
+// the CockroachDB provider.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	clusterState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cockroachdb_cluster_state",
+		Help: "Current state of a managed CockroachDB cluster. Always 1 for the state the cluster is in.",
+	}, []string{"namespace", "name", "state", "plan", "provider"})
+
+	clusterStateTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cockroachdb_cluster_state_transitions_total",
+		Help: "Number of state transitions observed for managed CockroachDB clusters.",
+	}, []string{"from", "to"})
+
+	apiInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cockroachdb_cloud_api_info",
+		Help: "Version of the CockroachDB Cloud API served to each ProviderConfig. Always 1.",
+	}, []string{"provider_config", "version"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(clusterState, clusterStateTransitions, apiInfo)
+}
+
+// An APIInfoRecorder records the Cloud API version served to ProviderConfigs.
+type APIInfoRecorder struct {
+	info *prometheus.GaugeVec
+
+	mu       sync.Mutex
+	versions map[string]string
+}
+
+// NewAPIInfoRecorder returns an APIInfoRecorder that exports its metrics
+// through the controller-runtime metrics registry.
+func NewAPIInfoRecorder() *APIInfoRecorder {
+	return &APIInfoRecorder{info: apiInfo, versions: map[string]string{}}
+}
+
+// Record the Cloud API version served to the named ProviderConfig.
+func (r *APIInfoRecorder) Record(providerConfig, version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev, ok := r.versions[providerConfig]; ok {
+		r.info.DeleteLabelValues(providerConfig, prev)
+	}
+	r.info.WithLabelValues(providerConfig, version).Set(1)
+	r.versions[providerConfig] = version
+}
+
+// A ClusterStateRecorder records the observed state of managed clusters.
+type ClusterStateRecorder struct {
+	state       *prometheus.GaugeVec
+	transitions *prometheus.CounterVec
+
+	mu     sync.Mutex
+	labels map[types.NamespacedName]prometheus.Labels
+}
+
+// NewClusterStateRecorder returns a ClusterStateRecorder that exports its
+// metrics through the controller-runtime metrics registry.
+func NewClusterStateRecorder() *ClusterStateRecorder {
+	return newClusterStateRecorder(clusterState, clusterStateTransitions)
+}
+
+func newClusterStateRecorder(state *prometheus.GaugeVec, transitions *prometheus.CounterVec) *ClusterStateRecorder {
+	return &ClusterStateRecorder{
+		state:       state,
+		transitions: transitions,
+		labels:      map[types.NamespacedName]prometheus.Labels{},
+	}
+}
+
+// Record the observed state of the named cluster, counting a transition if it
+// was previously observed in a different state.
+func (r *ClusterStateRecorder) Record(nn types.NamespacedName, state, plan, provider string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l := prometheus.Labels{"namespace": nn.Namespace, "name": nn.Name, "state": state, "plan": plan, "provider": provider}
+	if prev, ok := r.labels[nn]; ok {
+		if prev["state"] != state {
+			r.transitions.WithLabelValues(prev["state"], state).Inc()
+		}
+		r.state.Delete(prev)
+	}
+	r.state.With(l).Set(1)
+	r.labels[nn] = l
+}
+
+// Forget the named cluster, e.g. because it no longer exists.
+func (r *ClusterStateRecorder) Forget(nn types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev, ok := r.labels[nn]; ok {
+		r.state.Delete(prev)
+		delete(r.labels, nn)
+	}
+}
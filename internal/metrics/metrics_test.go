@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClusterStateRecorder(t *testing.T) {
+	state := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "state"}, []string{"namespace", "name", "state", "plan", "provider"})
+	transitions := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "transitions"}, []string{"from", "to"})
+	r := newClusterStateRecorder(state, transitions)
+	nn := types.NamespacedName{Name: "cool"}
+
+	r.Record(nn, "CREATING", "SERVERLESS", "AWS")
+	r.Record(nn, "CREATING", "SERVERLESS", "AWS")
+	r.Record(nn, "CREATED", "SERVERLESS", "AWS")
+
+	if got := testutil.CollectAndCount(state); got != 1 {
+		t.Errorf("Record(...): want 1 state series, got %d", got)
+	}
+	if got := testutil.ToFloat64(state.WithLabelValues("", "cool", "CREATED", "SERVERLESS", "AWS")); got != 1 {
+		t.Errorf("Record(...): want CREATED state 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(transitions.WithLabelValues("CREATING", "CREATED")); got != 1 {
+		t.Errorf("Record(...): want 1 transition, got %v", got)
+	}
+
+	r.Forget(nn)
+	if got := testutil.CollectAndCount(state); got != 0 {
+		t.Errorf("Forget(...): want 0 state series, got %d", got)
+	}
+}
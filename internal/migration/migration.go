@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration migrates the stored objects of the CRDs of the provider
+// to their storage version, so that older API versions can be removed.
+package migration
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GroupSuffix is the suffix of the API groups of the provider.
+const GroupSuffix = "cockroachdb.crossplane.io"
+
+const (
+	listPageSize = 100
+
+	errListCRDs        = "cannot list CustomResourceDefinitions"
+	errNoStorage       = "CustomResourceDefinition has no storage version"
+	errListObjects     = "cannot list objects"
+	errRewriteObject   = "cannot rewrite object in storage version"
+	errUpdateStoredVer = "cannot update stored versions of CustomResourceDefinition"
+	errFmtMigrate      = "cannot migrate %s"
+)
+
+var crdListGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinitionList"}
+
+// A Migrator rewrites every object of a CRD in its storage version, then
+// drops any other version from the stored versions of the CRD.
+type Migrator struct {
+	kube client.Client
+	log  logging.Logger
+}
+
+// NewMigrator returns a Migrator that migrates through the supplied client.
+// The client must not be backed by a cache, which does not support paging.
+func NewMigrator(kube client.Client, log logging.Logger) *Migrator {
+	return &Migrator{kube: kube, log: log}
+}
+
+// Start migrates the CRDs of the provider. It satisfies the Runnable
+// interface of the controller-runtime manager, so that migration runs once
+// on the leader. Failures are logged rather than returned, as they must not
+// stop the manager; migration is retried on the next start.
+func (m *Migrator) Start(ctx context.Context) error {
+	if err := m.MigrateAll(ctx); err != nil {
+		m.log.Info("Cannot migrate stored objects to storage version", "error", err)
+	}
+	return nil
+}
+
+// MigrateAll migrates the CRDs of the provider.
+func (m *Migrator) MigrateAll(ctx context.Context) error {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(crdListGVK)
+	if err := m.kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListCRDs)
+	}
+	for i := range l.Items {
+		crd := &l.Items[i]
+		if g, _, _ := unstructured.NestedString(crd.Object, "spec", "group"); !strings.HasSuffix(g, GroupSuffix) {
+			continue
+		}
+		if err := m.Migrate(ctx, crd); err != nil {
+			return errors.Wrapf(err, errFmtMigrate, crd.GetName())
+		}
+	}
+	return nil
+}
+
+// Migrate the objects of the supplied CRD to its storage version.
+func (m *Migrator) Migrate(ctx context.Context, crd *unstructured.Unstructured) error {
+	storage := storageVersion(crd)
+	if storage == "" {
+		return errors.New(errNoStorage)
+	}
+	stored, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if len(stored) == 1 && stored[0] == storage {
+		return nil
+	}
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	listKind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "listKind")
+	gvk := schema.GroupVersionKind{Group: group, Version: storage, Kind: listKind}
+
+	n := 0
+	cont := ""
+	for {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := m.kube.List(ctx, l, client.Limit(listPageSize), client.Continue(cont)); err != nil {
+			return errors.Wrap(err, errListObjects)
+		}
+		for i := range l.Items {
+			// An update without changes makes the API server store the
+			// object in the storage version. Objects that were deleted or
+			// written by others meanwhile are stored in it already.
+			if err := resource.Ignore(kerrors.IsConflict, resource.IgnoreNotFound(m.kube.Update(ctx, &l.Items[i]))); err != nil {
+				return errors.Wrap(err, errRewriteObject)
+			}
+			n++
+		}
+		if cont = l.GetContinue(); cont == "" {
+			break
+		}
+	}
+
+	if err := unstructured.SetNestedStringSlice(crd.Object, []string{storage}, "status", "storedVersions"); err != nil {
+		return errors.Wrap(err, errUpdateStoredVer)
+	}
+	if err := m.kube.Status().Update(ctx, crd); err != nil {
+		return errors.Wrap(err, errUpdateStoredVer)
+	}
+	m.log.Info("Migrated stored objects to storage version", "crd", crd.GetName(), "version", storage, "from", stored, "objects", n)
+	return nil
+}
+
+func storageVersion(crd *unstructured.Unstructured) string {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if s, _ := vm["storage"].(bool); s {
+			name, _ := vm["name"].(string)
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func crd(name, group string, stored ...interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"group": group,
+			"names": map[string]interface{}{"listKind": "ClusterList"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "storage": false},
+				map[string]interface{}{"name": "v1beta1", "storage": true},
+			},
+		},
+		"status": map[string]interface{}{"storedVersions": stored},
+	}}
+}
+
+func TestMigrateAll(t *testing.T) {
+	rewritten := []string{}
+	migrated := map[string][]string{}
+
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			if l.GetKind() == crdListGVK.Kind {
+				l.Items = []unstructured.Unstructured{
+					crd("clusters.database.cockroachdb.crossplane.io", "database.cockroachdb.crossplane.io", "v1alpha1", "v1beta1"),
+					crd("sqlusers.database.cockroachdb.crossplane.io", "database.cockroachdb.crossplane.io", "v1beta1"),
+					crd("buckets.s3.aws.crossplane.io", "s3.aws.crossplane.io", "v1alpha1", "v1beta1"),
+				}
+				return nil
+			}
+			if l.GroupVersionKind().Version != "v1beta1" {
+				t.Errorf("List(...): want objects listed in storage version, got %s", l.GroupVersionKind())
+			}
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			u := unstructured.Unstructured{}
+			if lo.Continue == "" {
+				u.SetName("first")
+				l.SetContinue("next")
+			} else {
+				u.SetName("second")
+			}
+			l.Items = []unstructured.Unstructured{u}
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			rewritten = append(rewritten, obj.GetName())
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			stored, _, _ := unstructured.NestedStringSlice(obj.(*unstructured.Unstructured).Object, "status", "storedVersions")
+			migrated[obj.GetName()] = stored
+			return nil
+		},
+	}
+
+	m := NewMigrator(kube, logging.NewNopLogger())
+	if err := m.MigrateAll(context.Background()); err != nil {
+		t.Fatalf("m.MigrateAll(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"first", "second"}, rewritten); diff != "" {
+		t.Errorf("m.MigrateAll(...): -want rewritten, +got rewritten:\n%s\n", diff)
+	}
+	want := map[string][]string{"clusters.database.cockroachdb.crossplane.io": {"v1beta1"}}
+	if diff := cmp.Diff(want, migrated); diff != "" {
+		t.Errorf("m.MigrateAll(...): -want stored versions, +got stored versions:\n%s\n", diff)
+	}
+}
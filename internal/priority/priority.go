@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package priority reconciles managed resources that are being created,
+// changed or deleted ahead of routine periodic observes.
+package priority
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	crratelimiter "github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultMaxUrgency is how long requests for a managed resource are urgent
+// by default, once it became urgent.
+const DefaultMaxUrgency = 5 * time.Minute
+
+// A Reconciler passes urgent requests straight to the wrapped Reconciler,
+// and subjects all other requests to the supplied rate limiter. Requests for
+// managed resources that are not available yet, whose spec changed since
+// they were last reconciled, or that are being deleted are urgent. When the
+// rate limiter is saturated by the periodic observes of a large fleet, urgent
+// requests thus keep provisioning latency low.
+//
+// A managed resource stays urgent for a limited time only, so that resources
+// that never become available or fail to be deleted cannot bypass the rate
+// limiter forever. It becomes urgent again once its spec changes.
+type Reconciler struct {
+	kube       client.Reader
+	newManaged func() resource.Managed
+	inner      reconcile.Reconciler
+	routine    *crratelimiter.Reconciler
+	maxUrgency time.Duration
+	now        func() time.Time
+
+	mu   sync.Mutex
+	seen map[reconcile.Request]seen
+}
+
+// seen is what a Reconciler remembers of a managed resource.
+type seen struct {
+	generation int64
+	// urgentSince is when the managed resource became urgent, or zero if it
+	// is not urgent.
+	urgentSince time.Time
+}
+
+// An Option configures a Reconciler.
+type Option func(*Reconciler)
+
+// WithMaxUrgency sets how long requests for a managed resource are urgent,
+// once it became urgent.
+func WithMaxUrgency(d time.Duration) Option {
+	return func(r *Reconciler) {
+		r.maxUrgency = d
+	}
+}
+
+// NewReconciler wraps the supplied Reconciler of the managed resources
+// returned by newManaged. Routine requests are rate limited by the supplied
+// RateLimiter, like crossplane-runtime's ratelimiter.Reconciler does.
+func NewReconciler(name string, kube client.Reader, newManaged func() resource.Managed, r reconcile.Reconciler, l ratelimiter.RateLimiter, o ...Option) *Reconciler {
+	pr := &Reconciler{
+		kube:       kube,
+		newManaged: newManaged,
+		inner:      r,
+		routine:    crratelimiter.NewReconciler(name, r, l),
+		maxUrgency: DefaultMaxUrgency,
+		now:        time.Now,
+		seen:       map[reconcile.Request]seen{},
+	}
+	for _, fn := range o {
+		fn(pr)
+	}
+	return pr
+}
+
+// Reconcile the supplied request, rate limiting it unless it is urgent.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	mg := r.newManaged()
+	err := r.kube.Get(ctx, req.NamespacedName, mg)
+	if kerrors.IsNotFound(err) {
+		r.forget(req)
+		return r.inner.Reconcile(ctx, req)
+	}
+	if err != nil || !r.urgent(req, mg) {
+		return r.routine.Reconcile(ctx, req)
+	}
+	return r.inner.Reconcile(ctx, req)
+}
+
+// urgent returns true if the supplied managed resource is not available yet,
+// changed since it was last seen, or is being deleted, and became so less
+// than the maximum urgency ago.
+func (r *Reconciler) urgent(req reconcile.Request, mg resource.Managed) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	last, ok := r.seen[req]
+	changed := ok && last.generation != mg.GetGeneration()
+	urgent := mg.GetDeletionTimestamp() != nil || changed ||
+		mg.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonAvailable
+
+	s := seen{generation: mg.GetGeneration()}
+	switch {
+	case !urgent:
+	case changed || last.urgentSince.IsZero():
+		s.urgentSince = now
+	default:
+		s.urgentSince = last.urgentSince
+	}
+	r.seen[req] = s
+
+	return urgent && now.Sub(s.urgentSince) < r.maxUrgency
+}
+
+func (r *Reconciler) forget(req reconcile.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.seen, req)
+}
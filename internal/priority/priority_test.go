@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// A limiter that always rate limits.
+type limiter struct{}
+
+func (limiter) When(interface{}) time.Duration { return time.Minute }
+func (limiter) Forget(interface{})             {}
+func (limiter) NumRequeues(interface{}) int    { return 0 }
+
+func TestReconcile(t *testing.T) {
+	req := reconcile.Request{}
+	inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{Requeue: true}, nil
+	})
+	getFn := func(generation int64, c xpv1.Condition, deleting bool) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o client.Object) error {
+			mg := o.(*fake.Managed)
+			mg.SetGeneration(generation)
+			mg.SetConditions(c)
+			if deleting {
+				now := metav1.Now()
+				mg.SetDeletionTimestamp(&now)
+			}
+			return nil
+		})
+	}
+
+	now := time.Now()
+
+	cases := map[string]struct {
+		reason string
+		seen   map[reconcile.Request]seen
+		get    test.MockGetFn
+		want   reconcile.Result
+	}{
+		"Routine": {
+			reason: "An available resource that did not change should be rate limited.",
+			seen:   map[reconcile.Request]seen{req: {generation: 1}},
+			get:    getFn(1, xpv1.Available(), false),
+			want:   reconcile.Result{RequeueAfter: time.Minute},
+		},
+		"NotAvailable": {
+			reason: "A resource that is not available yet should not be rate limited.",
+			get:    getFn(1, xpv1.Creating(), false),
+			want:   reconcile.Result{Requeue: true},
+		},
+		"Changed": {
+			reason: "A resource whose spec changed should not be rate limited.",
+			seen:   map[reconcile.Request]seen{req: {generation: 1}},
+			get:    getFn(2, xpv1.Available(), false),
+			want:   reconcile.Result{Requeue: true},
+		},
+		"Deleting": {
+			reason: "A resource that is being deleted should not be rate limited.",
+			seen:   map[reconcile.Request]seen{req: {generation: 1}},
+			get:    getFn(1, xpv1.Available(), true),
+			want:   reconcile.Result{Requeue: true},
+		},
+		"StillNotAvailable": {
+			reason: "A resource that recently became unavailable should not be rate limited.",
+			seen:   map[reconcile.Request]seen{req: {generation: 1, urgentSince: now.Add(-time.Minute)}},
+			get:    getFn(1, xpv1.Unavailable(), false),
+			want:   reconcile.Result{Requeue: true},
+		},
+		"UrgencyExpired": {
+			reason: "A resource that has not been available for long should be rate limited.",
+			seen:   map[reconcile.Request]seen{req: {generation: 1, urgentSince: now.Add(-time.Hour)}},
+			get:    getFn(1, xpv1.Unavailable(), false),
+			want:   reconcile.Result{RequeueAfter: time.Minute},
+		},
+		"ChangedAfterUrgencyExpired": {
+			reason: "A resource whose spec changed should be urgent again, even if it has not been available for long.",
+			seen:   map[reconcile.Request]seen{req: {generation: 1, urgentSince: now.Add(-time.Hour)}},
+			get:    getFn(2, xpv1.Unavailable(), false),
+			want:   reconcile.Result{Requeue: true},
+		},
+		"NotFound": {
+			reason: "A request for a resource that is gone should not be rate limited.",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+			want:   reconcile.Result{Requeue: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler("test", &test.MockClient{MockGet: tc.get}, func() resource.Managed { return &fake.Managed{} }, inner, limiter{})
+			r.now = func() time.Time { return now }
+			for k, v := range tc.seen {
+				r.seen[k] = v
+			}
+			got, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
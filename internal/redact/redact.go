@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redact removes secrets such as passwords, DSNs and API keys from
+// logs, events and condition messages.
+package redact
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Redacted replaces secrets.
+const Redacted = "REDACTED"
+
+var replacements = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Passwords in the user info of URLs, e.g. DSNs.
+	{re: regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s]*):[^@\s]*@`), repl: "${1}:" + Redacted + "@"},
+	// Secret JSON fields, e.g. of Cloud API requests echoed in errors.
+	{re: regexp.MustCompile(`(?i)("(?:password|secret|token|api_?key)"\s*:\s*")(?:[^"\\]|\\.)*"`), repl: "${1}" + Redacted + `"`},
+	// Secret key value pairs, e.g. of libpq connection strings.
+	{re: regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api_?key)(\s*=\s*)[^\s"&,;]+`), repl: "${1}${2}" + Redacted},
+	// Bearer tokens of Authorization headers.
+	{re: regexp.MustCompile(`(?i)(bearer\s+)[^\s"]+`), repl: "${1}" + Redacted},
+	// CockroachDB Cloud API keys.
+	{re: regexp.MustCompile(`CCDB1_[A-Za-z0-9_]+`), repl: Redacted},
+}
+
+// String returns the supplied string with any secret replaced.
+func String(s string) string {
+	for _, r := range replacements {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+// Error returns an error whose message is the message of the supplied error
+// with any secret replaced. The supplied error remains its cause.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string { return String(e.err.Error()) }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// NewLogger returns a Logger that redacts messages and the string and error
+// values of structured data before passing them to the supplied Logger.
+func NewLogger(l logging.Logger) logging.Logger {
+	return &logger{wrapped: l}
+}
+
+type logger struct {
+	wrapped logging.Logger
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	l.wrapped.Info(String(msg), values(keysAndValues)...)
+}
+
+func (l *logger) Debug(msg string, keysAndValues ...interface{}) {
+	l.wrapped.Debug(String(msg), values(keysAndValues)...)
+}
+
+func (l *logger) WithValues(keysAndValues ...interface{}) logging.Logger {
+	return &logger{wrapped: l.wrapped.WithValues(values(keysAndValues)...)}
+}
+
+func values(keysAndValues []interface{}) []interface{} {
+	out := make([]interface{}, len(keysAndValues))
+	for i, v := range keysAndValues {
+		switch t := v.(type) {
+		case string:
+			out[i] = String(t)
+		case error:
+			out[i] = Error(t)
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// A Connecter redacts the errors of the wrapped ExternalConnecter and of the
+// ExternalClients it produces. The managed reconciler surfaces these errors
+// in logs, events and the Synced condition.
+type Connecter struct {
+	wrapped managed.ExternalConnecter
+}
+
+// NewConnecter wraps the supplied ExternalConnecter with redaction.
+func NewConnecter(c managed.ExternalConnecter) *Connecter {
+	return &Connecter{wrapped: c}
+}
+
+// Connect to the external system, redacting any error.
+func (c *Connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.wrapped.Connect(ctx, mg)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &external{wrapped: e}, nil
+}
+
+type external struct {
+	wrapped managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.wrapped.Observe(ctx, mg)
+	return o, Error(err)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.wrapped.Create(ctx, mg)
+	return c, Error(err)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.wrapped.Update(ctx, mg)
+	return u, Error(err)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	return Error(e.wrapped.Delete(ctx, mg))
+}
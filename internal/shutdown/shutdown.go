@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shutdown lets in-flight reconciles complete when the provider is
+// stopped, so that they are not interrupted between creating an external
+// resource and recording its external name.
+package shutdown
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// A Reconciler runs the wrapped Reconciler with a context that outlives the
+// cancellation of its own context by a grace period. Controllers cancel the
+// context of their reconciles when the provider is stopped, e.g. on SIGTERM,
+// which would otherwise abort a Create after the Cloud API created a cluster
+// but before its external name was persisted. Requests that start once the
+// provider is stopping are not reconciled, but left to the next leader.
+type Reconciler struct {
+	wrapped reconcile.Reconciler
+	grace   time.Duration
+}
+
+// NewReconciler wraps the supplied Reconciler, giving its in-flight
+// reconciles the supplied grace period to complete once the provider is
+// stopping.
+func NewReconciler(r reconcile.Reconciler, grace time.Duration) *Reconciler {
+	return &Reconciler{wrapped: r, grace: grace}
+}
+
+// Reconcile the supplied request, unless the provider is stopping.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if ctx.Err() != nil {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	ctx, cancel := Detach(ctx, r.grace)
+	defer cancel()
+	return r.wrapped.Reconcile(ctx, req)
+}
+
+// detached carries the values of a context, but not its cancellation.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
+
+// Detach returns a context that carries the values and deadline of the
+// supplied context, but is only cancelled the supplied grace period after
+// the supplied context is cancelled.
+func Detach(parent context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	var ctx context.Context = detached{parent}
+	cancelDeadline := context.CancelFunc(func() {})
+	if d, ok := parent.Deadline(); ok {
+		ctx, cancelDeadline = context.WithDeadline(ctx, d)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		select {
+		case <-parent.Done():
+		case <-ctx.Done():
+			return
+		}
+		t := time.NewTimer(grace)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		cancel()
+		cancelDeadline()
+	}
+}
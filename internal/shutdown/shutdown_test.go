@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcile(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		stopping bool
+		want     reconcile.Result
+		wantRan  bool
+	}{
+		"Running": {
+			reason:  "Requests should be reconciled while the provider is running.",
+			want:    reconcile.Result{RequeueAfter: time.Minute},
+			wantRan: true,
+		},
+		"Stopping": {
+			reason:   "Requests should be left to the next leader once the provider is stopping.",
+			stopping: true,
+			want:     reconcile.Result{Requeue: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ran := false
+			inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				ran = true
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.stopping {
+				cancel()
+			}
+			defer cancel()
+
+			got, err := NewReconciler(inner, time.Second).Reconcile(ctx, reconcile.Request{})
+			if err != nil {
+				t.Fatalf("\n%s\nReconcile(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if ran != tc.wantRan {
+				t.Errorf("\n%s\nReconcile(...): want wrapped Reconciler run %t, got %t", tc.reason, tc.wantRan, ran)
+			}
+		})
+	}
+}
+
+type key struct{}
+
+func TestDetach(t *testing.T) {
+	parent, stop := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	ctx, cancel := Detach(parent, 50*time.Millisecond)
+	defer cancel()
+
+	if got := ctx.Value(key{}); got != "value" {
+		t.Errorf("Detach(...): want values of the parent context, got %v", got)
+	}
+
+	stop()
+	select {
+	case <-ctx.Done():
+		t.Fatal("Detach(...): must not be cancelled before the grace period ends")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Detach(...): must be cancelled once the grace period ends")
+	}
+}
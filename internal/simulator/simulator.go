@@ -0,0 +1,518 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulator serves a fake CockroachDB Cloud API for tests and
+// benchmarks. Faults can be injected to check that controllers converge
+// despite an unreliable API.
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/uuid"
+)
+
+// Faults injected into the responses of a Server.
+type Faults struct {
+	// ErrorRate is the fraction of requests that fail with 500 Internal
+	// Server Error.
+	ErrorRate float64
+
+	// ThrottleEvery starts a burst of ThrottleBurst requests rejected with
+	// 429 Too Many Requests every ThrottleEvery requests, beginning with the
+	// first request.
+	ThrottleEvery int
+	ThrottleBurst int
+
+	// Latency is added to every response.
+	Latency time.Duration
+
+	// CreationFailureRate is the fraction of created clusters that end up
+	// CREATION_FAILED rather than CREATED.
+	CreationFailureRate float64
+}
+
+// A Server is a fake CockroachDB Cloud API. It serves the endpoints used by
+// the provider for serverless clusters, their SQL users and allowlists.
+type Server struct {
+	mu       sync.Mutex
+	faults   Faults
+	rand     *rand.Rand
+	polls    int
+	clusters map[string]*cluster
+	requests int
+	calls    map[string]int
+}
+
+type cluster struct {
+	cockroachdb.Cluster
+	users     map[string]bool
+	allowlist map[string]cockroachdb.AllowlistEntry
+	polls     int
+	fail      bool
+}
+
+// An Option configures a Server.
+type Option func(*Server)
+
+// WithFaults injects the supplied faults.
+func WithFaults(f Faults) Option {
+	return func(s *Server) {
+		s.faults = f
+	}
+}
+
+// WithSeed seeds the randomness of injected faults, so that runs can be
+// reproduced.
+func WithSeed(seed int64) Option {
+	return func(s *Server) {
+		s.rand = rand.New(rand.NewSource(seed)) //nolint:gosec // Not used for security.
+	}
+}
+
+// WithProvisioningPolls keeps created clusters CREATING for the supplied
+// number of reads.
+func WithProvisioningPolls(n int) Option {
+	return func(s *Server) {
+		s.polls = n
+	}
+}
+
+// New returns a Server without clusters.
+func New(o ...Option) *Server {
+	s := &Server{
+		rand:     rand.New(rand.NewSource(1)), //nolint:gosec // Not used for security.
+		clusters: map[string]*cluster{},
+		calls:    map[string]int{},
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+// SetFaults replaces the faults injected by the Server.
+func (s *Server) SetFaults(f Faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = f
+}
+
+// Calls returns the number of requests served per endpoint, keyed by method
+// and path template, e.g. "GET /api/v1/clusters/{cluster_id}". Requests
+// rejected by an injected fault are included.
+func (s *Server) Calls() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make(map[string]int, len(s.calls))
+	for k, v := range s.calls {
+		calls[k] = v
+	}
+	return calls
+}
+
+// ResetCalls forgets the requests served so far.
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = map[string]int{}
+}
+
+// Clusters returns the clusters that exist, sorted by name.
+func (s *Server) Clusters() []cockroachdb.Cluster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clusters := make([]cockroachdb.Cluster, 0, len(s.clusters))
+	for _, c := range s.clusters {
+		clusters = append(clusters, c.Cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters
+}
+
+// SQLUsers returns the sorted names of the SQL users of the cluster with the
+// supplied ID.
+func (s *Server) SQLUsers(id string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := []string{}
+	if c, ok := s.clusters[id]; ok {
+		for u := range c.users {
+			users = append(users, u)
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+// ServeHTTP serves a request to the Cloud API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	route, params := match(r.Method, r.URL.Path)
+	s.calls[route]++
+	s.requests++
+
+	if s.faults.Latency > 0 {
+		// Latency must not hold up other requests.
+		s.mu.Unlock()
+		time.Sleep(s.faults.Latency)
+		s.mu.Lock()
+	}
+	if f := s.faults; f.ThrottleEvery > 0 && (s.requests-1)%f.ThrottleEvery < f.ThrottleBurst {
+		w.Header().Set("Retry-After", "0")
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+	if s.rand.Float64() < s.faults.ErrorRate {
+		writeError(w, http.StatusInternalServerError, "injected fault")
+		return
+	}
+
+	h, ok := s.handlers()[route]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	h(w, r, params)
+}
+
+type handler func(w http.ResponseWriter, r *http.Request, params []string)
+
+func (s *Server) handlers() map[string]handler {
+	return map[string]handler{
+		"GET /api/v1/clusters":                                                            s.listClusters,
+		"POST /api/v1/clusters":                                                           s.createCluster,
+		"GET /api/v1/clusters/{cluster_id}":                                               s.withCluster(s.getCluster),
+		"PATCH /api/v1/clusters/{cluster_id}":                                             s.withCluster(s.updateCluster),
+		"DELETE /api/v1/clusters/{cluster_id}":                                            s.withCluster(s.deleteCluster),
+		"GET /api/v1/clusters/{cluster_id}/sql-users":                                     s.withCluster(s.listSQLUsers),
+		"POST /api/v1/clusters/{cluster_id}/sql-users":                                    s.withCluster(s.createSQLUser),
+		"DELETE /api/v1/clusters/{cluster_id}/sql-users/{name}":                           s.withCluster(s.deleteSQLUser),
+		"PUT /api/v1/clusters/{cluster_id}/sql-users/{name}/password":                     s.withCluster(s.updateSQLUserPassword),
+		"GET /api/v1/clusters/{cluster_id}/networking/allowlist":                          s.withCluster(s.listAllowlist),
+		"POST /api/v1/clusters/{cluster_id}/networking/allowlist":                         s.withCluster(s.addAllowlistEntry),
+		"PATCH /api/v1/clusters/{cluster_id}/networking/allowlist/{cidr_ip}/{cidr_mask}":  s.withCluster(s.updateAllowlistEntry),
+		"DELETE /api/v1/clusters/{cluster_id}/networking/allowlist/{cidr_ip}/{cidr_mask}": s.withCluster(s.deleteAllowlistEntry),
+	}
+}
+
+// match returns the route of the supplied request, and the values of the
+// parameters of its path template.
+func match(method, path string) (string, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 3 || segments[0] != "api" || segments[1] != "v1" || segments[2] != "clusters" {
+		return method + " " + path, nil
+	}
+	names := []string{"{cluster_id}"}
+	switch {
+	case len(segments) >= 5 && segments[4] == "sql-users":
+		names = append(names, "sql-users", "{name}", "password")
+	case len(segments) >= 5 && segments[4] == "networking":
+		names = append(names, "networking", "allowlist", "{cidr_ip}", "{cidr_mask}")
+	}
+	route := []string{"api", "v1", "clusters"}
+	params := []string{}
+	for i, seg := range segments[3:] {
+		if i >= len(names) {
+			return method + " " + path, nil
+		}
+		route = append(route, names[i])
+		if strings.HasPrefix(names[i], "{") {
+			params = append(params, seg)
+		}
+	}
+	return method + " /" + strings.Join(route, "/"), params
+}
+
+func (s *Server) withCluster(fn func(w http.ResponseWriter, r *http.Request, c *cluster, params []string)) handler {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		c, ok := s.clusters[params[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "cluster not found")
+			return
+		}
+		fn(w, r, c, params[1:])
+	}
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, _ *http.Request, _ []string) {
+	res := cockroachdb.ListClustersResponse{Clusters: []cockroachdb.Cluster{}}
+	for _, c := range s.clusters {
+		res.Clusters = append(res.Clusters, c.Cluster)
+	}
+	sort.Slice(res.Clusters, func(i, j int) bool { return res.Clusters[i].Name < res.Clusters[j].Name })
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request, _ []string) {
+	req := cockroachdb.CreateClusterRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.Spec.Serverless == nil) == (req.Spec.Dedicated == nil) {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	for _, c := range s.clusters {
+		if c.Name == req.Name {
+			writeError(w, http.StatusConflict, fmt.Sprintf("cluster name %q already exists", req.Name))
+			return
+		}
+	}
+
+	id := uuid.New().String()
+	c := &cluster{
+		Cluster: cockroachdb.Cluster{
+			Id:               id,
+			Name:             req.Name,
+			CockroachVersion: "v22.1.0",
+			Plan:             cockroachdb.PLAN_SERVERLESS,
+			CloudProvider:    req.Provider,
+			State:            cockroachdb.CLUSTERSTATETYPE_CREATING,
+			// Every field must hold a valid value, or the SDK silently
+			// decodes an empty cluster.
+			OperationStatus: cockroachdb.CLUSTERSTATUSTYPE_CLUSTER_STATUS_UNSPECIFIED,
+		},
+		users:     map[string]bool{},
+		allowlist: map[string]cockroachdb.AllowlistEntry{},
+		fail:      s.rand.Float64() < s.faults.CreationFailureRate,
+	}
+	if d := req.Spec.Dedicated; d != nil {
+		c.Plan = cockroachdb.PLAN_DEDICATED
+		c.Config.Dedicated = &cockroachdb.DedicatedHardwareConfig{}
+		setHardware(c.Config.Dedicated, &d.Hardware.MachineSpec, &d.Hardware.StorageGib, d.Hardware.DiskIops)
+		c.setRegionNodes(d.RegionNodes)
+	} else {
+		c.Config.Serverless = &cockroachdb.ServerlessClusterConfig{
+			SpendLimit: req.Spec.Serverless.SpendLimit,
+			RoutingId:  req.Name + "-" + id[:4],
+		}
+		for _, region := range req.Spec.Serverless.Regions {
+			c.Regions = append(c.Regions, cockroachdb.Region{Name: region, SqlDns: "free-tier." + region + ".cockroachlabs.cloud"})
+		}
+	}
+	s.clusters[id] = c
+	writeJSON(w, http.StatusOK, c.Cluster)
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, _ *http.Request, c *cluster, _ []string) {
+	if c.State == cockroachdb.CLUSTERSTATETYPE_CREATING {
+		c.polls++
+		if c.polls > s.polls {
+			c.State = cockroachdb.CLUSTERSTATETYPE_CREATED
+			if c.fail {
+				c.State = cockroachdb.CLUSTERSTATETYPE_CREATION_FAILED
+			}
+		}
+	}
+	if c.OperationStatus == cockroachdb.CLUSTERSTATUSTYPE_CRDB_SCALE_RUNNING {
+		c.polls++
+		if c.polls > s.polls {
+			c.OperationStatus = cockroachdb.CLUSTERSTATUSTYPE_CLUSTER_STATUS_UNSPECIFIED
+		}
+	}
+	writeJSON(w, http.StatusOK, c.Cluster)
+}
+
+func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request, c *cluster, _ []string) {
+	req := cockroachdb.UpdateClusterSpecification{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.Serverless == nil) == (req.Dedicated == nil) {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if req.Serverless != nil {
+		if c.Config.Serverless == nil {
+			writeError(w, http.StatusBadRequest, "cluster is not serverless")
+			return
+		}
+		c.Config.Serverless.SpendLimit = req.Serverless.SpendLimit
+	}
+	if d := req.Dedicated; d != nil {
+		if c.Config.Dedicated == nil {
+			writeError(w, http.StatusBadRequest, "cluster is not dedicated")
+			return
+		}
+		if hw := d.Hardware; hw != nil {
+			setHardware(c.Config.Dedicated, hw.MachineSpec, hw.StorageGib, hw.DiskIops)
+		}
+		if d.RegionNodes != nil {
+			c.scale(*d.RegionNodes)
+		}
+	}
+	writeJSON(w, http.StatusOK, c.Cluster)
+}
+
+// scale sets the number of nodes of each region of a dedicated cluster and
+// starts a scale operation that settles after as many polls as a creation.
+func (c *cluster) scale(regionNodes map[string]int32) {
+	c.setRegionNodes(regionNodes)
+	c.OperationStatus = cockroachdb.CLUSTERSTATUSTYPE_CRDB_SCALE_RUNNING
+	c.polls = 0
+}
+
+// setRegionNodes sets the regions of a dedicated cluster, in order, with the
+// number of nodes of each. Every region gets a host name of its own.
+func (c *cluster) setRegionNodes(regionNodes map[string]int32) {
+	regions := make([]string, 0, len(regionNodes))
+	for region := range regionNodes {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	c.Regions = nil
+	for _, region := range regions {
+		c.Regions = append(c.Regions, cockroachdb.Region{Name: region, SqlDns: c.Name + "-" + c.Id[:4] + "." + region + ".cockroachlabs.cloud", NodeCount: regionNodes[region]})
+	}
+}
+
+// setHardware applies the supplied machine, storage and IOPS, if any, to the
+// hardware of a dedicated cluster. Machines requested by number of virtual
+// CPUs get a machine type named after them.
+func setHardware(hw *cockroachdb.DedicatedHardwareConfig, ms *cockroachdb.DedicatedMachineTypeSpecification, storage, iops *int32) {
+	if ms != nil && ms.MachineType != nil {
+		hw.MachineType = *ms.MachineType
+		hw.NumVirtualCpus = 0
+	}
+	if ms != nil && ms.NumVirtualCpus != nil {
+		hw.MachineType = fmt.Sprintf("sim-%dvcpu", *ms.NumVirtualCpus)
+		hw.NumVirtualCpus = *ms.NumVirtualCpus
+	}
+	if storage != nil {
+		hw.StorageGib = *storage
+	}
+	if iops != nil {
+		hw.DiskIops = *iops
+	}
+}
+
+func (s *Server) deleteCluster(w http.ResponseWriter, _ *http.Request, c *cluster, _ []string) {
+	delete(s.clusters, c.Id)
+	c.State = cockroachdb.CLUSTERSTATETYPE_DELETED
+	writeJSON(w, http.StatusOK, c.Cluster)
+}
+
+func (s *Server) listSQLUsers(w http.ResponseWriter, _ *http.Request, c *cluster, _ []string) {
+	res := cockroachdb.ListSQLUsersResponse{Users: []cockroachdb.SQLUser{}}
+	for u := range c.users {
+		res.Users = append(res.Users, cockroachdb.SQLUser{Name: u})
+	}
+	sort.Slice(res.Users, func(i, j int) bool { return res.Users[i].Name < res.Users[j].Name })
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) createSQLUser(w http.ResponseWriter, r *http.Request, c *cluster, _ []string) {
+	req := cockroachdb.CreateSQLUserRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if c.users[req.Name] {
+		writeError(w, http.StatusConflict, fmt.Sprintf("SQL user %q already exists", req.Name))
+		return
+	}
+	c.users[req.Name] = true
+	writeJSON(w, http.StatusOK, cockroachdb.SQLUser{Name: req.Name})
+}
+
+func (s *Server) deleteSQLUser(w http.ResponseWriter, _ *http.Request, c *cluster, params []string) {
+	if len(params) != 1 || !c.users[params[0]] {
+		writeError(w, http.StatusNotFound, "SQL user not found")
+		return
+	}
+	delete(c.users, params[0])
+	writeJSON(w, http.StatusOK, cockroachdb.SQLUser{Name: params[0]})
+}
+
+func (s *Server) updateSQLUserPassword(w http.ResponseWriter, _ *http.Request, c *cluster, params []string) {
+	if len(params) != 1 || !c.users[params[0]] {
+		writeError(w, http.StatusNotFound, "SQL user not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, cockroachdb.SQLUser{Name: params[0]})
+}
+
+func (s *Server) listAllowlist(w http.ResponseWriter, _ *http.Request, c *cluster, _ []string) {
+	res := cockroachdb.ListAllowlistEntriesResponse{Allowlist: []cockroachdb.AllowlistEntry{}}
+	for _, e := range c.allowlist {
+		res.Allowlist = append(res.Allowlist, e)
+	}
+	sort.Slice(res.Allowlist, func(i, j int) bool { return key(res.Allowlist[i]) < key(res.Allowlist[j]) })
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) addAllowlistEntry(w http.ResponseWriter, r *http.Request, c *cluster, _ []string) {
+	e := cockroachdb.AllowlistEntry{}
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if _, ok := c.allowlist[key(e)]; ok {
+		writeError(w, http.StatusConflict, "allowlist entry already exists")
+		return
+	}
+	c.allowlist[key(e)] = e
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) updateAllowlistEntry(w http.ResponseWriter, r *http.Request, c *cluster, params []string) {
+	k := strings.Join(params, "/")
+	if _, ok := c.allowlist[k]; !ok {
+		writeError(w, http.StatusNotFound, "allowlist entry not found")
+		return
+	}
+	e := cockroachdb.AllowlistEntry{}
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	e.CidrIp = params[0]
+	mask, _ := strconv.Atoi(params[1])
+	e.CidrMask = int32(mask)
+	c.allowlist[k] = e
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) deleteAllowlistEntry(w http.ResponseWriter, _ *http.Request, c *cluster, params []string) {
+	k := strings.Join(params, "/")
+	e, ok := c.allowlist[k]
+	if !ok {
+		writeError(w, http.StatusNotFound, "allowlist entry not found")
+		return
+	}
+	delete(c.allowlist, k)
+	writeJSON(w, http.StatusOK, e)
+}
+
+func key(e cockroachdb.AllowlistEntry) string {
+	return fmt.Sprintf("%s/%d", e.CidrIp, e.CidrMask)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]interface{}{"code": code, "message": msg})
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newService(url string) cockroachdb.Service {
+	cfg := cockroachdb.NewConfiguration("key")
+	cfg.ServerURL = url
+	return cockroachdb.NewService(cockroachdb.NewClient(cfg))
+}
+
+func TestServer(t *testing.T) {
+	s := New(WithProvisioningPolls(1))
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	svc := newService(srv.URL)
+	ctx := context.Background()
+
+	c, _, err := svc.CreateCluster(ctx, &cockroachdb.CreateClusterRequest{
+		Name:     "cool",
+		Provider: cockroachdb.APICLOUDPROVIDER_AWS,
+		Spec:     cockroachdb.CreateClusterSpecification{Serverless: &cockroachdb.ServerlessClusterCreateSpecification{Regions: []string{"eu-west-1"}}},
+	})
+	if err != nil {
+		t.Fatalf("svc.CreateCluster(...): %v", err)
+	}
+	if _, res, err := svc.CreateCluster(ctx, &cockroachdb.CreateClusterRequest{Name: "cool", Provider: cockroachdb.APICLOUDPROVIDER_AWS, Spec: cockroachdb.CreateClusterSpecification{Serverless: &cockroachdb.ServerlessClusterCreateSpecification{}}}); err == nil || res.StatusCode != http.StatusConflict {
+		t.Errorf("svc.CreateCluster(...): want %d for a taken name, got %v", http.StatusConflict, err)
+	}
+
+	for _, want := range []cockroachdb.ClusterStateType{cockroachdb.CLUSTERSTATETYPE_CREATING, cockroachdb.CLUSTERSTATETYPE_CREATED} {
+		got, _, err := svc.GetCluster(ctx, c.Id)
+		if err != nil {
+			t.Fatalf("svc.GetCluster(...): %v", err)
+		}
+		if got.State != want {
+			t.Errorf("svc.GetCluster(...): want state %s, got %s", want, got.State)
+		}
+	}
+
+	if _, _, err := svc.CreateSQLUser(ctx, c.Id, &cockroachdb.CreateSQLUserRequest{Name: "app", Password: "secret"}); err != nil {
+		t.Fatalf("svc.CreateSQLUser(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"app"}, s.SQLUsers(c.Id)); diff != "" {
+		t.Errorf("s.SQLUsers(...): -want, +got:\n%s", diff)
+	}
+
+	if _, _, err := svc.DeleteCluster(ctx, c.Id); err != nil {
+		t.Fatalf("svc.DeleteCluster(...): %v", err)
+	}
+	if _, res, _ := svc.GetCluster(ctx, c.Id); res.StatusCode != http.StatusNotFound {
+		t.Errorf("svc.GetCluster(...): want %d for a deleted cluster, got %d", http.StatusNotFound, res.StatusCode)
+	}
+
+	want := map[string]int{
+		"POST /api/v1/clusters":                        2,
+		"GET /api/v1/clusters/{cluster_id}":            3,
+		"POST /api/v1/clusters/{cluster_id}/sql-users": 1,
+		"DELETE /api/v1/clusters/{cluster_id}":         1,
+	}
+	if diff := cmp.Diff(want, s.Calls()); diff != "" {
+		t.Errorf("s.Calls(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestScale(t *testing.T) {
+	s := New(WithProvisioningPolls(1))
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	svc := newService(srv.URL)
+	ctx := context.Background()
+
+	machineType := "m5.large"
+	c, _, err := svc.CreateCluster(ctx, &cockroachdb.CreateClusterRequest{
+		Name:     "cool",
+		Provider: cockroachdb.APICLOUDPROVIDER_AWS,
+		Spec: cockroachdb.CreateClusterSpecification{Dedicated: &cockroachdb.DedicatedClusterCreateSpecification{
+			RegionNodes: map[string]int32{"eu-west-1": 3},
+			Hardware:    cockroachdb.DedicatedHardwareCreateSpecification{MachineSpec: cockroachdb.DedicatedMachineTypeSpecification{MachineType: &machineType}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("svc.CreateCluster(...): %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := svc.GetCluster(ctx, c.Id); err != nil {
+			t.Fatalf("svc.GetCluster(...): %v", err)
+		}
+	}
+
+	nodes := map[string]int32{"eu-west-1": 5}
+	if _, _, err := svc.UpdateCluster(ctx, c.Id, &cockroachdb.UpdateClusterSpecification{Dedicated: &cockroachdb.DedicatedClusterUpdateSpecification{RegionNodes: &nodes}}, &cockroachdb.UpdateClusterOptions{}); err != nil {
+		t.Fatalf("svc.UpdateCluster(...): %v", err)
+	}
+	for _, want := range []cockroachdb.ClusterStatusType{cockroachdb.CLUSTERSTATUSTYPE_CRDB_SCALE_RUNNING, cockroachdb.CLUSTERSTATUSTYPE_CLUSTER_STATUS_UNSPECIFIED} {
+		got, _, err := svc.GetCluster(ctx, c.Id)
+		if err != nil {
+			t.Fatalf("svc.GetCluster(...): %v", err)
+		}
+		if got.OperationStatus != want {
+			t.Errorf("svc.GetCluster(...): want operation status %s, got %s", want, got.OperationStatus)
+		}
+		if len(got.Regions) != 1 || got.Regions[0].NodeCount != 5 {
+			t.Errorf("svc.GetCluster(...): want 5 nodes in eu-west-1, got %v", got.Regions)
+		}
+	}
+}
+
+func TestFaults(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		faults Faults
+		want   []int
+	}{
+		"Throttling": {
+			reason: "Bursts of requests should be rejected as throttled.",
+			faults: Faults{ThrottleEvery: 4, ThrottleBurst: 2},
+			want:   []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		},
+		"Errors": {
+			reason: "Requests should fail at the injected error rate.",
+			faults: Faults{ErrorRate: 1},
+			want:   []int{http.StatusInternalServerError, http.StatusInternalServerError},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(New(WithFaults(tc.faults)))
+			defer srv.Close()
+			got := make([]int, len(tc.want))
+			for i := range got {
+				res, err := http.Get(srv.URL + "/api/v1/clusters")
+				if err != nil {
+					t.Fatalf("http.Get(...): %v", err)
+				}
+				res.Body.Close() //nolint:errcheck
+				got[i] = res.StatusCode
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nhttp.Get(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Defaults of a Pool.
+const (
+	DefaultMaxConns    = 4
+	DefaultIdleTimeout = 5 * time.Minute
+)
+
+const (
+	errAcquire = "cannot acquire connection to cluster"
+)
+
+// A Pool shares connections to clusters, so that operations on the same
+// cluster do not each open a connection of their own. Connections are keyed
+// by the Config they were opened with, so that rotated credentials open new
+// connections.
+type Pool struct {
+	maxConns    int
+	idleTimeout time.Duration
+	tlsConfig   *tls.Config
+
+	connect  func(context.Context, Config) (*DB, error)
+	reusable func(*DB) bool
+	close    func(context.Context, *DB)
+	now      func() time.Time
+
+	mu      sync.Mutex
+	targets map[string]*target
+}
+
+// A target is the connections of a Pool with the same Config.
+type target struct {
+	// slots holds a token for each connection in use, so that no more than
+	// the maximum number of connections are opened.
+	slots chan struct{}
+	idle  []*DB
+	// users is the number of operations waiting for or using a connection.
+	users int
+}
+
+// NewPool returns a Pool that opens at most the supplied number of
+// connections per cluster, and closes connections that were idle for longer
+// than the supplied timeout. Connections use the supplied TLS configuration,
+// unless their Config has one.
+func NewPool(maxConns int, idleTimeout time.Duration, tlsConfig *tls.Config) *Pool {
+	if maxConns < 1 {
+		maxConns = 1
+	}
+	return &Pool{
+		maxConns:    maxConns,
+		idleTimeout: idleTimeout,
+		tlsConfig:   tlsConfig,
+		connect:     Connect,
+		reusable:    (*DB).reusable,
+		close:       func(ctx context.Context, db *DB) { _ = db.Close(ctx) },
+		now:         time.Now,
+		targets:     map[string]*target{},
+	}
+}
+
+// Acquire a connection to the cluster of the supplied Config, reusing an
+// idle one if possible. Acquire waits for a connection to be released if the
+// maximum number of connections to the cluster are in use. The connection
+// must be released once done with.
+func (p *Pool) Acquire(ctx context.Context, c Config) (*DB, error) {
+	key := c.key()
+
+	p.mu.Lock()
+	stale := p.expire()
+	t, ok := p.targets[key]
+	if !ok {
+		t = &target{slots: make(chan struct{}, p.maxConns)}
+		p.targets[key] = t
+	}
+	t.users++
+	p.mu.Unlock()
+	p.closeAll(ctx, stale)
+
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		p.mu.Lock()
+		t.users--
+		p.mu.Unlock()
+		return nil, errors.Wrap(ctx.Err(), errAcquire)
+	}
+
+	p.mu.Lock()
+	var db *DB
+	if n := len(t.idle); n > 0 {
+		db, t.idle = t.idle[n-1], t.idle[:n-1]
+	}
+	p.mu.Unlock()
+	if db != nil {
+		return db, nil
+	}
+
+	if c.TLS == nil {
+		c.TLS = p.tlsConfig
+	}
+	db, err := p.connect(ctx, c)
+	if err != nil {
+		p.mu.Lock()
+		<-t.slots
+		t.users--
+		p.mu.Unlock()
+		return nil, err
+	}
+	db.target = t
+	return db, nil
+}
+
+// Release a connection acquired from the Pool. It is kept for reuse unless
+// it can no longer be used, e.g. because it failed or a transaction is still
+// open on it.
+func (p *Pool) Release(ctx context.Context, db *DB) {
+	t := db.target
+	p.mu.Lock()
+	keep := p.reusable(db)
+	if keep {
+		db.idleSince = p.now()
+		t.idle = append(t.idle, db)
+	}
+	<-t.slots
+	t.users--
+	p.mu.Unlock()
+	if !keep {
+		p.close(ctx, db)
+	}
+}
+
+// expire removes the connections that were idle for longer than the idle
+// timeout, and returns them to be closed. Clusters without connections are
+// forgotten. The Pool must be locked.
+func (p *Pool) expire() []*DB {
+	stale := []*DB{}
+	for key, t := range p.targets {
+		idle := t.idle[:0]
+		for _, db := range t.idle {
+			if p.now().Sub(db.idleSince) > p.idleTimeout {
+				stale = append(stale, db)
+				continue
+			}
+			idle = append(idle, db)
+		}
+		t.idle = idle
+		if len(t.idle) == 0 && t.users == 0 {
+			delete(p.targets, key)
+		}
+	}
+	return stale
+}
+
+func (p *Pool) closeAll(ctx context.Context, dbs []*DB) {
+	for _, db := range dbs {
+		p.close(ctx, db)
+	}
+}
+
+// key identifies the cluster, user and credentials of the Config, without
+// holding its secrets.
+func (c Config) key() string {
+	h := sha256.New()
+	for _, b := range [][]byte{[]byte(c.DSN), c.CA, c.Cert, c.Key} {
+		_, _ = h.Write(b)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+// A fakePool is a Pool whose connections are fake, and which records the
+// connections it opened and closed.
+type fakePool struct {
+	*Pool
+	opened int
+	closed []*DB
+	clock  time.Time
+}
+
+func newFakePool(maxConns int, reusable bool) *fakePool {
+	fp := &fakePool{Pool: NewPool(maxConns, time.Minute, nil), clock: time.Unix(0, 0)}
+	fp.connect = func(_ context.Context, _ Config) (*DB, error) {
+		fp.opened++
+		return &DB{}, nil
+	}
+	fp.reusable = func(_ *DB) bool { return reusable }
+	fp.close = func(_ context.Context, db *DB) { fp.closed = append(fp.closed, db) }
+	fp.now = func() time.Time { return fp.clock }
+	return fp
+}
+
+func TestPoolReuse(t *testing.T) {
+	ctx := context.Background()
+	a := Config{DSN: "postgresql://admin@a:26257/defaultdb"}
+	b := Config{DSN: "postgresql://admin@b:26257/defaultdb"}
+
+	p := newFakePool(2, true)
+	db, err := p.Acquire(ctx, a)
+	if err != nil {
+		t.Fatalf("p.Acquire(...): %v", err)
+	}
+	p.Release(ctx, db)
+	again, err := p.Acquire(ctx, a)
+	if err != nil {
+		t.Fatalf("p.Acquire(...): %v", err)
+	}
+	if again != db {
+		t.Errorf("p.Acquire(...): want the released connection to be reused")
+	}
+	other, err := p.Acquire(ctx, b)
+	if err != nil {
+		t.Fatalf("p.Acquire(...): %v", err)
+	}
+	if other == db {
+		t.Errorf("p.Acquire(...): want a connection of its own for another cluster")
+	}
+	if diff := cmp.Diff(2, p.opened); diff != "" {
+		t.Errorf("p.Acquire(...): -want opened, +got opened:\n%s\n", diff)
+	}
+}
+
+func TestPoolRelease(t *testing.T) {
+	ctx := context.Background()
+	c := Config{DSN: "postgresql://admin@a:26257/defaultdb"}
+
+	p := newFakePool(1, false)
+	db, _ := p.Acquire(ctx, c)
+	p.Release(ctx, db)
+	if len(p.closed) != 1 || p.closed[0] != db {
+		t.Errorf("p.Release(...): want connections that are not reusable to be closed")
+	}
+	if _, err := p.Acquire(ctx, c); err != nil {
+		t.Errorf("p.Acquire(...): a closed connection should free its slot: %v", err)
+	}
+}
+
+func TestPoolIdleTimeout(t *testing.T) {
+	ctx := context.Background()
+	c := Config{DSN: "postgresql://admin@a:26257/defaultdb"}
+
+	p := newFakePool(1, true)
+	db, _ := p.Acquire(ctx, c)
+	p.Release(ctx, db)
+	p.clock = p.clock.Add(2 * time.Minute)
+	again, _ := p.Acquire(ctx, c)
+	if again == db {
+		t.Errorf("p.Acquire(...): want connections idle for longer than the idle timeout not to be reused")
+	}
+	if len(p.closed) != 1 || p.closed[0] != db {
+		t.Errorf("p.Acquire(...): want connections idle for longer than the idle timeout to be closed")
+	}
+}
+
+func TestPoolMaxConns(t *testing.T) {
+	c := Config{DSN: "postgresql://admin@a:26257/defaultdb"}
+
+	p := newFakePool(1, true)
+	if _, err := p.Acquire(context.Background(), c); err != nil {
+		t.Fatalf("p.Acquire(...): %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := p.Acquire(ctx, c)
+	if diff := cmp.Diff(errors.Wrap(context.Canceled, errAcquire), err, test.EquateErrors()); diff != "" {
+		t.Errorf("p.Acquire(...): no more than the maximum connections should be opened: -want error, +got error:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sqlclient opens SQL connections to clusters, so that objects inside
+// them can be managed over SQL rather than the Cloud API.
+package sqlclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+// Keys of the connection details a Cluster publishes.
+const (
+	ConnectionDetailDSN = "dsn"
+	ConnectionDetailCA  = "ca.crt"
+)
+
+const (
+	errNoDSN            = "connection details have no DSN"
+	errNoCA             = "connection details have no CA certificate"
+	errParseDSN         = "cannot parse DSN"
+	errParseCA          = "cannot parse cluster CA certificate"
+	errParseClientCert  = "cannot parse client certificate"
+	errConnect          = "cannot connect to cluster"
+	errFmtExecStatement = "cannot execute %q"
+	errExecScript       = "cannot execute script"
+)
+
+// A Config of a connection to a cluster.
+type Config struct {
+	// DSN of the cluster, including the credentials of the user to connect
+	// as.
+	DSN string
+	// CA certificate the certificate of the cluster is verified with.
+	CA []byte
+	// Cert is the PEM encoded client certificate to authenticate with, if
+	// any.
+	Cert []byte
+	// Key is the PEM encoded private key of the client certificate.
+	Key []byte
+	// TLS is the TLS configuration to connect with, e.g. the minimum
+	// version and cipher suites, if any. Its root CAs, server name and
+	// certificates are set from the Config.
+	TLS *tls.Config
+}
+
+// FromConnectionDetails returns the Config to connect to the supplied
+// database with the supplied connection details of a Cluster. The database
+// of the DSN is kept if none is supplied.
+func FromConnectionDetails(cd map[string][]byte, database string) (Config, error) {
+	dsn, ok := cd[ConnectionDetailDSN]
+	if !ok || len(dsn) == 0 {
+		return Config{}, errors.New(errNoDSN)
+	}
+	ca, ok := cd[ConnectionDetailCA]
+	if !ok || len(ca) == 0 {
+		return Config{}, errors.New(errNoCA)
+	}
+	cfg := Config{DSN: string(dsn), CA: ca}
+	if database == "" {
+		return cfg, nil
+	}
+	u, err := url.Parse(cfg.DSN)
+	if err != nil {
+		// The DSN may embed a password, so the error is not wrapped.
+		return Config{}, errors.New(errParseDSN)
+	}
+	u.Path = "/" + database
+	cfg.DSN = u.String()
+	return cfg, nil
+}
+
+// A DB is a connection to a cluster.
+type DB struct {
+	conn *pgx.Conn
+
+	// target of the Pool the connection belongs to, if any.
+	target *target
+	// idleSince is when the connection was last released to its Pool.
+	idleSince time.Time
+	// dirty is true if a script ran on the connection, which may have left
+	// session state behind.
+	dirty bool
+}
+
+// Connect to the cluster of the supplied Config, verifying its certificate
+// with the CA of the Config, and presenting its client certificate if any.
+func Connect(ctx context.Context, c Config) (*DB, error) {
+	cfg, err := pgx.ParseConfig(c.DSN)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnect)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(c.CA) {
+		return nil, errors.New(errParseCA)
+	}
+	cfg.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLS != nil {
+		cfg.TLSConfig = c.TLS.Clone()
+	}
+	cfg.TLSConfig.RootCAs = pool
+	cfg.TLSConfig.ServerName = cfg.Host
+	if len(c.Cert) > 0 {
+		cert, err := tls.X509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseClientCert)
+		}
+		cfg.TLSConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnect)
+	}
+	return &DB{conn: conn}, nil
+}
+
+// Exec runs the supplied statements in order, stopping at the first that
+// fails.
+func (db *DB) Exec(ctx context.Context, stmts ...string) error {
+	for _, s := range stmts {
+		if _, err := db.conn.Exec(ctx, s); err != nil {
+			return errors.Wrapf(err, errFmtExecStatement, s)
+		}
+	}
+	return nil
+}
+
+// ExecArgs runs the supplied statement with the supplied arguments for its
+// placeholders. Unlike the statement, the arguments are not included in
+// errors, so they may be secret.
+func (db *DB) ExecArgs(ctx context.Context, stmt string, args ...interface{}) error {
+	_, err := db.conn.Exec(ctx, stmt, args...)
+	return errors.Wrapf(err, errFmtExecStatement, stmt)
+}
+
+// ExecScript runs the supplied script, which may consist of several
+// statements. Unlike statements, the script is not included in errors, as it
+// may be long or secret.
+func (db *DB) ExecScript(ctx context.Context, script string) error {
+	db.dirty = true
+	_, err := db.conn.Exec(ctx, script)
+	return errors.Wrap(err, errExecScript)
+}
+
+// Query runs the supplied query and returns its rows, which must be closed.
+func (db *DB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	return db.conn.Query(ctx, query, args...)
+}
+
+// QueryRow runs the supplied query, which is expected to return at most one
+// row.
+func (db *DB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	return db.conn.QueryRow(ctx, query, args...)
+}
+
+// Close the connection.
+func (db *DB) Close(ctx context.Context) error {
+	return db.conn.Close(ctx)
+}
+
+// reusable returns true if the connection is open, idle outside of a
+// transaction, and has no session state left behind by a script.
+func (db *DB) reusable() bool {
+	return !db.dirty && !db.conn.IsClosed() && db.conn.PgConn().TxStatus() == 'I'
+}
+
+// IsNotFound returns true if the supplied error reports that a database,
+// schema, table or role does not exist.
+func IsNotFound(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "3D000", "3F000", "42P01", "42704":
+		return true
+	}
+	return false
+}
+
+// Exec connects to the cluster of the supplied Config, runs the supplied
+// statements and closes the connection.
+func Exec(ctx context.Context, c Config, stmts ...string) error {
+	db, err := Connect(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx) //nolint:errcheck
+	return db.Exec(ctx, stmts...)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tlsconfig hardens the TLS of the outbound HTTPS connections of the
+// provider.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errFmtMinVersion  = "unsupported minimum TLS version %q: must be one of 1.2, 1.3"
+	errFmtCipherSuite = "unsupported TLS cipher suite %q"
+	errFIPSTLS13      = "FIPS mode requires TLS 1.2, as TLS 1.3 cipher suites cannot be restricted"
+	errFIPSCipher     = "FIPS mode does not allow cipher suite %q"
+)
+
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// fipsCipherSuites are the TLS 1.2 cipher suites approved by FIPS 140-2.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// New returns a TLS configuration that requires the supplied minimum
+// version and, if any, only offers the supplied cipher suites. Cipher suites
+// are named as in the IANA registry, e.g.
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, and only apply to TLS 1.2. In FIPS
+// mode only FIPS approved cipher suites and curves are offered, over TLS 1.2.
+func New(minVersion string, cipherSuites []string, fips bool) (*tls.Config, error) {
+	v, ok := versions[minVersion]
+	if !ok {
+		return nil, errors.Errorf(errFmtMinVersion, minVersion)
+	}
+	cfg := &tls.Config{MinVersion: v} //nolint:gosec // The minimum version is validated above.
+
+	for _, name := range cipherSuites {
+		id, ok := cipherSuite(name)
+		if !ok {
+			return nil, errors.Errorf(errFmtCipherSuite, name)
+		}
+		cfg.CipherSuites = append(cfg.CipherSuites, id)
+	}
+
+	if !fips {
+		return cfg, nil
+	}
+	if v > tls.VersionTLS12 {
+		return nil, errors.New(errFIPSTLS13)
+	}
+	cfg.MaxVersion = tls.VersionTLS12
+	cfg.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+	if len(cfg.CipherSuites) == 0 {
+		cfg.CipherSuites = fipsCipherSuites
+	}
+	for i, id := range cfg.CipherSuites {
+		if !contains(fipsCipherSuites, id) {
+			return nil, errors.Errorf(errFIPSCipher, cipherSuites[i])
+		}
+	}
+	return cfg, nil
+}
+
+// NewTransport returns a transport for outbound HTTPS connections with the
+// supplied TLS configuration. It is otherwise configured like
+// http.DefaultTransport, which is left alone.
+func NewTransport(cfg *tls.Config) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = cfg
+	return t
+}
+
+func cipherSuite(name string) (uint16, bool) {
+	for _, s := range tls.CipherSuites() {
+		if s.Name == name {
+			return s.ID, true
+		}
+	}
+	return 0, false
+}
+
+func contains(ids []uint16, id uint16) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		minVersion   string
+		cipherSuites []string
+		fips         bool
+	}
+
+	type want struct {
+		cfg *tls.Config
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MinVersion": {
+			reason: "The minimum TLS version should be required.",
+			args:   args{minVersion: "1.3"},
+			want:   want{cfg: &tls.Config{MinVersion: tls.VersionTLS13}},
+		},
+		"UnsupportedMinVersion": {
+			reason: "Minimum TLS versions older than 1.2 should be rejected.",
+			args:   args{minVersion: "1.0"},
+			want:   want{err: errors.Errorf(errFmtMinVersion, "1.0")},
+		},
+		"CipherSuites": {
+			reason: "Only the supplied cipher suites should be offered.",
+			args:   args{minVersion: "1.2", cipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}},
+			want: want{cfg: &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			}},
+		},
+		"InsecureCipherSuite": {
+			reason: "Insecure or unknown cipher suites should be rejected.",
+			args:   args{minVersion: "1.2", cipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			want:   want{err: errors.Errorf(errFmtCipherSuite, "TLS_RSA_WITH_RC4_128_SHA")},
+		},
+		"FIPS": {
+			reason: "FIPS mode should only offer FIPS approved cipher suites and curves over TLS 1.2.",
+			args:   args{minVersion: "1.2", fips: true},
+			want: want{cfg: &tls.Config{
+				MinVersion:       tls.VersionTLS12,
+				MaxVersion:       tls.VersionTLS12,
+				CurvePreferences: []tls.CurveID{tls.CurveP256, tls.CurveP384},
+				CipherSuites:     fipsCipherSuites,
+			}},
+		},
+		"FIPSTLS13": {
+			reason: "FIPS mode should reject TLS 1.3, whose cipher suites cannot be restricted.",
+			args:   args{minVersion: "1.3", fips: true},
+			want:   want{err: errors.New(errFIPSTLS13)},
+		},
+		"FIPSCipherSuite": {
+			reason: "FIPS mode should reject cipher suites that are not FIPS approved.",
+			args:   args{minVersion: "1.2", cipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}, fips: true},
+			want:   want{err: errors.Errorf(errFIPSCipher, "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := New(tc.args.minVersion, tc.args.cipherSuites, tc.args.fips)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNew(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cfg, got, cmpopts.IgnoreUnexported(tls.Config{})); diff != "" {
+				t.Errorf("\n%s\nNew(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS13}
+	got := NewTransport(cfg)
+	if got.TLSClientConfig != cfg {
+		t.Errorf("NewTransport(...): want the supplied TLS configuration, got %v", got.TLSClientConfig)
+	}
+	if got == http.DefaultTransport || http.DefaultTransport.(*http.Transport).TLSClientConfig == cfg {
+		t.Errorf("NewTransport(...): http.DefaultTransport should be left alone")
+	}
+}
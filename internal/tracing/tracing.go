@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing instruments reconciles and external clients with
+// OpenTelemetry spans.
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-cockroachdb/internal/redact"
+)
+
+const tracerName = "github.com/crossplane/provider-cockroachdb"
+
+// Setup installs a global TracerProvider that exports spans via OTLP over
+// HTTP. The exporter is configured through the standard OTEL_EXPORTER_OTLP_*
+// environment variables, and connects with the supplied TLS configuration.
+// The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, serviceName string, tlsConfig *tls.Config) (func(context.Context) error, error) {
+	exp, err := otlptracehttp.New(ctx, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// NewTransport returns an http.RoundTripper that records a client span for
+// each request, as a child of the span in the request's context.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base)
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// end ends the supplied span, recording the supplied error if any. Errors are
+// redacted before they are recorded, since spans are exported as is.
+func end(span trace.Span, err error) {
+	if err != nil {
+		err = redact.Error(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func attributes(mg resource.Managed) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("crossplane.resource.namespace", mg.GetNamespace()),
+		attribute.String("crossplane.resource.name", mg.GetName()),
+	)
+}
+
+// A Reconciler records a span for every reconcile of the wrapped Reconciler.
+type Reconciler struct {
+	name    string
+	wrapped reconcile.Reconciler
+}
+
+// NewReconciler wraps the supplied Reconciler with tracing.
+func NewReconciler(name string, r reconcile.Reconciler) *Reconciler {
+	return &Reconciler{name: name, wrapped: r}
+}
+
+// Reconcile the supplied request within a span.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := tracer().Start(ctx, r.name+"/Reconcile", trace.WithAttributes(
+		attribute.String("crossplane.resource.namespace", req.Namespace),
+		attribute.String("crossplane.resource.name", req.Name),
+	))
+	res, err := r.wrapped.Reconcile(ctx, req)
+	end(span, err)
+	return res, err
+}
+
+// A Connecter records a span for every connection of the wrapped
+// ExternalConnecter, and wraps the ExternalClients it produces with tracing.
+type Connecter struct {
+	name    string
+	wrapped managed.ExternalConnecter
+}
+
+// NewConnecter wraps the supplied ExternalConnecter with tracing.
+func NewConnecter(name string, c managed.ExternalConnecter) *Connecter {
+	return &Connecter{name: name, wrapped: c}
+}
+
+// Connect to the external system within a span.
+func (c *Connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ctx, span := tracer().Start(ctx, c.name+"/Connect", attributes(mg))
+	e, err := c.wrapped.Connect(ctx, mg)
+	end(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &external{name: c.name, wrapped: e}, nil
+}
+
+type external struct {
+	name    string
+	wrapped managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := tracer().Start(ctx, e.name+"/Observe", attributes(mg))
+	o, err := e.wrapped.Observe(ctx, mg)
+	span.SetAttributes(
+		attribute.Bool("crossplane.resource.exists", o.ResourceExists),
+		attribute.Bool("crossplane.resource.uptodate", o.ResourceUpToDate),
+	)
+	end(span, err)
+	return o, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := tracer().Start(ctx, e.name+"/Create", attributes(mg))
+	c, err := e.wrapped.Create(ctx, mg)
+	end(span, err)
+	return c, err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := tracer().Start(ctx, e.name+"/Update", attributes(mg))
+	u, err := e.wrapped.Update(ctx, mg)
+	end(span, err)
+	return u, err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, span := tracer().Start(ctx, e.name+"/Delete", attributes(mg))
+	err := e.wrapped.Delete(ctx, mg)
+	end(span, err)
+	return err
+}
@@ -66,7 +66,11 @@ func (c *CAClient) ClusterCACert(ctx context.Context, cluster *cockroachdb.Clust
 		return nil, fmt.Errorf("error parsing CA cert URL: %v", err)
 	}
 
-	res, err := http.Get(url.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting CA cert: %v", err)
+	}
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error requesting CA cert: %v", err)
 	}
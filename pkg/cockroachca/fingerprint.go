@@ -0,0 +1,55 @@
+package cockroachca
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// Fingerprint returns the SHA-256 fingerprint of the supplied certificate as
+// lower case hex.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyFingerprints returns an error unless every certificate of the supplied
+// PEM bundle matches one of the supplied SHA-256 fingerprints. Fingerprints
+// are hex, optionally separated by colons. No verification is done when no
+// fingerprints are supplied.
+func VerifyFingerprints(bundle []byte, fingerprints []string) error {
+	if len(fingerprints) == 0 {
+		return nil
+	}
+	pinned := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		pinned[strings.ToLower(strings.ReplaceAll(fp, ":", ""))] = true
+	}
+
+	n := 0
+	for rest := bundle; ; {
+		var b *pem.Block
+		b, rest = pem.Decode(rest)
+		if b == nil {
+			break
+		}
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return fmt.Errorf("error parsing CA cert: %v", err)
+		}
+		if fp := Fingerprint(cert); !pinned[fp] {
+			return fmt.Errorf("CA cert fingerprint %s does not match any expected fingerprint", fp)
+		}
+		n++
+	}
+	if n == 0 {
+		return fmt.Errorf("error parsing CA cert: no certificate found")
+	}
+	return nil
+}
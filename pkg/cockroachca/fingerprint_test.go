@@ -0,0 +1,102 @@
+package cockroachca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCert(t *testing.T) ([]byte, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(...): %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Cockroach CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate(...): %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate(...): %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), cert
+}
+
+func colons(fp string) string {
+	parts := []string{}
+	for i := 0; i < len(fp); i += 2 {
+		parts = append(parts, strings.ToUpper(fp[i:i+2]))
+	}
+	return strings.Join(parts, ":")
+}
+
+func TestVerifyFingerprints(t *testing.T) {
+	ca, cert := testCert(t)
+	other, _ := testCert(t)
+	fp := Fingerprint(cert)
+
+	cases := map[string]struct {
+		reason       string
+		bundle       []byte
+		fingerprints []string
+		wantErr      bool
+	}{
+		"NotPinned": {
+			reason: "Any certificate should be accepted when no fingerprints are pinned.",
+			bundle: other,
+		},
+		"Match": {
+			reason:       "A certificate matching a pinned fingerprint should be accepted.",
+			bundle:       ca,
+			fingerprints: []string{fp},
+		},
+		"MatchWithColons": {
+			reason:       "Fingerprints should be accepted in upper case hex separated by colons.",
+			bundle:       ca,
+			fingerprints: []string{colons(fp)},
+		},
+		"Mismatch": {
+			reason:       "A certificate matching no pinned fingerprint should be rejected.",
+			bundle:       other,
+			fingerprints: []string{fp},
+			wantErr:      true,
+		},
+		"PartialMatch": {
+			reason:       "A bundle should be rejected if any of its certificates is not pinned.",
+			bundle:       append(append([]byte{}, ca...), other...),
+			fingerprints: []string{fp},
+			wantErr:      true,
+		},
+		"NoCertificate": {
+			reason:       "A bundle without certificates should be rejected.",
+			bundle:       []byte("not a certificate"),
+			fingerprints: []string{fp},
+			wantErr:      true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyFingerprints(tc.bundle, tc.fingerprints)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\nVerifyFingerprints(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// A BackupConfiguration is how often CockroachDB Cloud backs up a cluster,
+// and for how long it keeps the backups.
+type BackupConfiguration struct {
+	Enabled          bool  `json:"enabled"`
+	FrequencyMinutes int32 `json:"frequency_minutes"`
+	RetentionDays    int32 `json:"retention_days"`
+}
+
+// A BackupConfigurationUpdate changes the supplied fields of the backup
+// configuration of a cluster.
+type BackupConfigurationUpdate struct {
+	Enabled          *bool  `json:"enabled,omitempty"`
+	FrequencyMinutes *int32 `json:"frequency_minutes,omitempty"`
+	RetentionDays    *int32 `json:"retention_days,omitempty"`
+}
+
+// GetBackupConfiguration returns the managed backup configuration of the
+// supplied cluster.
+func (c *Client) GetBackupConfiguration(ctx context.Context, clusterID string) (*BackupConfiguration, error) {
+	return c.backupConfiguration(ctx, http.MethodGet, clusterID, nil)
+}
+
+// UpdateBackupConfiguration changes the managed backup configuration of the
+// supplied cluster.
+func (c *Client) UpdateBackupConfiguration(ctx context.Context, clusterID string, u BackupConfigurationUpdate) (*BackupConfiguration, error) {
+	return c.backupConfiguration(ctx, http.MethodPut, clusterID, u)
+}
+
+func (c *Client) backupConfiguration(ctx context.Context, method, clusterID string, body interface{}) (*BackupConfiguration, error) {
+	path := fmt.Sprintf("/api/v1/clusters/%s/backups-config", url.PathEscape(clusterID))
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &BackupConfiguration{}
+	if err := c.do(req, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
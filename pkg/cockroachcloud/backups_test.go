@@ -0,0 +1,52 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBackupConfiguration(t *testing.T) {
+	var methods []string
+	var update map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/backups-config" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodPut {
+			_ = json.NewDecoder(r.Body).Decode(&update)
+		}
+		_, _ = w.Write([]byte(`{"enabled":true,"frequency_minutes":60,"retention_days":30}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	retention := int32(30)
+	if _, err := c.UpdateBackupConfiguration(ctx, "cluster", BackupConfigurationUpdate{RetentionDays: &retention}); err != nil {
+		t.Fatalf("UpdateBackupConfiguration(...): %v", err)
+	}
+	got, err := c.GetBackupConfiguration(ctx, "cluster")
+	if err != nil {
+		t.Fatalf("GetBackupConfiguration(...): %v", err)
+	}
+
+	if diff := cmp.Diff(&BackupConfiguration{Enabled: true, FrequencyMinutes: 60, RetentionDays: 30}, got); diff != "" {
+		t.Errorf("GetBackupConfiguration(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"retention_days": float64(30)}, update); diff != "" {
+		t.Errorf("UpdateBackupConfiguration(...): -want body, +got body:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{http.MethodPut, http.MethodGet}, methods); diff != "" {
+		t.Errorf("BackupConfiguration: -want methods, +got methods:\n%s\n", diff)
+	}
+}
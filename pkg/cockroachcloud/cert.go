@@ -0,0 +1,18 @@
+package cockroachcloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ClusterCACert returns the PEM encoded CA certificate of the supplied
+// cluster, retrieved through the authenticated Cloud API.
+func (c *Client) ClusterCACert(ctx context.Context, clusterID string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if _, err := c.Download(ctx, fmt.Sprintf("/api/v1/clusters/%s/cert", url.PathEscape(clusterID)), buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,36 @@
+package cockroachcloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClusterCACert(t *testing.T) {
+	const pem = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/cert" || r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, pem)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	got, err := c.ClusterCACert(context.Background(), "cluster")
+	if err != nil {
+		t.Fatalf("ClusterCACert(...): %v", err)
+	}
+	if diff := cmp.Diff(pem, string(got)); diff != "" {
+		t.Errorf("ClusterCACert(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,195 @@
+// Package cockroachcloud is a client of the CockroachDB Cloud API for the
+// endpoints and behaviours not covered by cockroach-cloud-sdk-go.
+package cockroachcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	defaultBaseURL   = "https://cockroachlabs.cloud/"
+	defaultUserAgent = "provider-cockroachdb"
+)
+
+// An Option configures a Client.
+type Option func(*Client) error
+
+// WithBaseURL sets the URL of the Cloud API.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		url, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("error parsing base URL: %v", err)
+		}
+		c.baseURL = url
+
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		c.httpClient = httpClient
+
+		return nil
+	}
+}
+
+// WithHeaders adds the supplied headers to every request, e.g. to pin the API
+// version or to tag traffic for an API gateway. They take precedence over the
+// default headers, except for Authorization.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) error {
+		for k, v := range headers {
+			c.headers.Set(k, v)
+		}
+
+		return nil
+	}
+}
+
+// A Client of the CockroachDB Cloud API.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	headers    http.Header
+	specPath   string
+}
+
+// NewClient returns a Client that authenticates with the supplied API key.
+func NewClient(apiKey string, opts ...Option) (*Client, error) {
+	url, err := url.Parse(defaultBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base URL: %v", err)
+	}
+
+	client := &Client{
+		baseURL:    url,
+		httpClient: http.DefaultClient,
+		apiKey:     apiKey,
+		headers:    http.Header{},
+		specPath:   defaultAPISpecPath,
+	}
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, fmt.Errorf("error setting option: %v", err)
+		}
+	}
+
+	return client, nil
+}
+
+// newRequest returns an authenticated request for the supplied path, relative
+// to the base URL. The body, if any, is encoded as JSON.
+func (c *Client) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	url, err := c.baseURL.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing request URL: %v", err)
+	}
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding request body: %v", err)
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url.String(), r)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	return req, nil
+}
+
+// send sends the supplied request with the custom headers of the client.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for k, v := range c.headers {
+		if k == "Authorization" {
+			continue
+		}
+		req.Header[k] = v
+	}
+	return c.httpClient.Do(req)
+}
+
+// do sends the supplied request and decodes the response into v, if not nil.
+func (c *Client) do(req *http.Request, v interface{}) error {
+	res, err := c.send(req)
+	if err != nil {
+		return fmt.Errorf("error requesting %s: %v", req.URL.Path, err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	return handleResponse(res, v)
+}
+
+// An Error is returned when the Cloud API responds with an error status.
+type Error struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// Code is the gRPC status code reported by the Cloud API.
+	Code int32 `json:"code"`
+	// Message reported by the Cloud API.
+	Message string `json:"message"`
+	// Body is the raw body of the response. Errors returned by intermediary
+	// proxies are often HTML or plain text rather than JSON.
+	Body []byte `json:"-"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("cloud API error: status code %d: %s", e.StatusCode, msg)
+}
+
+// IsNotFound returns true if the supplied error is a Cloud API error with
+// status 404 Not Found.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// handleResponse decodes the supplied response into v, if not nil. Error
+// statuses are returned as an *Error, even if their body is not JSON.
+func handleResponse(res *http.Response, v interface{}) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %v", err)
+	}
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &Error{StatusCode: res.StatusCode, Body: body}
+		if err := json.Unmarshal(body, apiErr); err != nil {
+			apiErr.Code, apiErr.Message = 0, ""
+		}
+		return apiErr
+	}
+
+	if v == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
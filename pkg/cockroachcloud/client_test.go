@@ -0,0 +1,118 @@
+package cockroachcloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithHeaders(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		headers map[string]string
+		want    http.Header
+	}{
+		"Defaults": {
+			reason: "Requests should carry the default headers.",
+			want: http.Header{
+				"Accept":        {"application/json"},
+				"Authorization": {"Bearer key"},
+				"User-Agent":    {defaultUserAgent},
+			},
+		},
+		"Custom": {
+			reason: "Custom headers should be added to requests, without overriding authorization.",
+			headers: map[string]string{
+				"Cc-Version":    "2022-09-20",
+				"user-agent":    "tagged",
+				"Authorization": "Bearer other",
+			},
+			want: http.Header{
+				"Accept":        {"application/json"},
+				"Authorization": {"Bearer key"},
+				"Cc-Version":    {"2022-09-20"},
+				"User-Agent":    {"tagged"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				got.Del("Accept-Encoding")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient("key", WithBaseURL(srv.URL), WithHeaders(tc.headers))
+			if err != nil {
+				t.Fatalf("NewClient(...): %v", err)
+			}
+			req, err := c.newRequest(context.Background(), http.MethodGet, "/api/v1/clusters", nil)
+			if err != nil {
+				t.Fatalf("c.newRequest(...): %v", err)
+			}
+			if err := c.do(req, nil); err != nil {
+				t.Fatalf("c.do(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.do(...): -want headers, +got headers:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandleResponse(t *testing.T) {
+	type want struct {
+		v   map[string]string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		res    *http.Response
+		want   want
+	}{
+		"Success": {
+			reason: "Successful responses should be decoded.",
+			res:    &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":"a"}`))},
+			want:   want{v: map[string]string{"id": "a"}},
+		},
+		"JSONError": {
+			reason: "JSON error responses should be decoded into an *Error.",
+			res:    &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"code":5,"message":"cluster not found"}`))},
+			want: want{
+				v:   map[string]string{},
+				err: &Error{StatusCode: http.StatusNotFound, Code: 5, Message: "cluster not found", Body: []byte(`{"code":5,"message":"cluster not found"}`)},
+			},
+		},
+		"HTMLError": {
+			reason: "Non-JSON error responses should be returned as an *Error with the raw body.",
+			res:    &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader("<html>Bad Gateway</html>"))},
+			want: want{
+				v:   map[string]string{},
+				err: &Error{StatusCode: http.StatusBadGateway, Body: []byte("<html>Bad Gateway</html>")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := map[string]string{}
+			err := handleResponse(tc.res, &v)
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("\n%s\nhandleResponse(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.v, v); diff != "" {
+				t.Errorf("\n%s\nhandleResponse(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
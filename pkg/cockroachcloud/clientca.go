@@ -0,0 +1,65 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// A ClientCACertStatus is the state of the client CA certificate of a
+// cluster.
+type ClientCACertStatus string
+
+// States of client CA certificates.
+const (
+	ClientCACertStatusNotSet  ClientCACertStatus = "NOT_SET"
+	ClientCACertStatusIsSet   ClientCACertStatus = "IS_SET"
+	ClientCACertStatusPending ClientCACertStatus = "PENDING"
+	ClientCACertStatusFailed  ClientCACertStatus = "FAILED"
+)
+
+// A ClientCACert is the CA certificate SQL clients of a cluster may
+// authenticate with certificates issued by.
+type ClientCACert struct {
+	X509PEMCert string             `json:"x509_pem_cert"`
+	Status      ClientCACertStatus `json:"status"`
+}
+
+type clientCACertRequest struct {
+	X509PEMCert string `json:"x509_pem_cert"`
+}
+
+// GetClientCACert returns the client CA certificate of the supplied cluster.
+func (c *Client) GetClientCACert(ctx context.Context, clusterID string) (*ClientCACert, error) {
+	cert := &ClientCACert{}
+	return cert, c.clientCACert(ctx, http.MethodGet, clusterID, nil, cert)
+}
+
+// SetClientCACert sets the client CA certificate of the supplied cluster.
+func (c *Client) SetClientCACert(ctx context.Context, clusterID, pem string) (*ClientCACert, error) {
+	cert := &ClientCACert{}
+	return cert, c.clientCACert(ctx, http.MethodPost, clusterID, clientCACertRequest{X509PEMCert: pem}, cert)
+}
+
+// UpdateClientCACert replaces the client CA certificate of the supplied
+// cluster.
+func (c *Client) UpdateClientCACert(ctx context.Context, clusterID, pem string) (*ClientCACert, error) {
+	cert := &ClientCACert{}
+	return cert, c.clientCACert(ctx, http.MethodPatch, clusterID, clientCACertRequest{X509PEMCert: pem}, cert)
+}
+
+// DeleteClientCACert removes the client CA certificate of the supplied
+// cluster.
+func (c *Client) DeleteClientCACert(ctx context.Context, clusterID string) error {
+	return c.clientCACert(ctx, http.MethodDelete, clusterID, nil, nil)
+}
+
+func (c *Client) clientCACert(ctx context.Context, method, clusterID string, body, v interface{}) error {
+	path := fmt.Sprintf("/api/v1/clusters/%s/client-ca-cert", url.PathEscape(clusterID))
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return c.do(req, v)
+}
@@ -0,0 +1,67 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientCACert(t *testing.T) {
+	type request struct {
+		Method string
+		Body   clientCACertRequest
+	}
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/client-ca-cert" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		req := request{Method: r.Method}
+		if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+			_ = json.NewDecoder(r.Body).Decode(&req.Body)
+		}
+		requests = append(requests, req)
+		if r.Method == http.MethodDelete {
+			return
+		}
+		_, _ = w.Write([]byte(`{"x509_pem_cert":"PEM","status":"IS_SET"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	if _, err := c.SetClientCACert(ctx, "cluster", "PEM"); err != nil {
+		t.Fatalf("SetClientCACert(...): %v", err)
+	}
+	if _, err := c.UpdateClientCACert(ctx, "cluster", "NEW"); err != nil {
+		t.Fatalf("UpdateClientCACert(...): %v", err)
+	}
+	got, err := c.GetClientCACert(ctx, "cluster")
+	if err != nil {
+		t.Fatalf("GetClientCACert(...): %v", err)
+	}
+	if err := c.DeleteClientCACert(ctx, "cluster"); err != nil {
+		t.Fatalf("DeleteClientCACert(...): %v", err)
+	}
+
+	if diff := cmp.Diff(&ClientCACert{X509PEMCert: "PEM", Status: ClientCACertStatusIsSet}, got); diff != "" {
+		t.Errorf("GetClientCACert(...): -want, +got:\n%s\n", diff)
+	}
+	want := []request{
+		{Method: http.MethodPost, Body: clientCACertRequest{X509PEMCert: "PEM"}},
+		{Method: http.MethodPatch, Body: clientCACertRequest{X509PEMCert: "NEW"}},
+		{Method: http.MethodGet},
+		{Method: http.MethodDelete},
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("ClientCACert: -want requests, +got requests:\n%s\n", diff)
+	}
+}
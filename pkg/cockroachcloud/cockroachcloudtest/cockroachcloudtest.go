@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cockroachcloudtest provides utilities for testing against the
+// CockroachDB Cloud API.
+package cockroachcloudtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/provider-cockroachdb/pkg/cockroachcloud"
+)
+
+// NewClient returns a client of a Cloud API that responds to every request
+// with the supplied body, or with 404 Not Found if the body is empty. The API
+// is shut down once the test completes.
+func NewClient(t *testing.T, body string) *cockroachcloud.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if body == "" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":5,"message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	c, err := cockroachcloud.NewClient("key", cockroachcloud.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	return c
+}
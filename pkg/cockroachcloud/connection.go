@@ -0,0 +1,35 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type connectionStringResponse struct {
+	ConnectionString string `json:"connection_string"`
+}
+
+// ConnectionString returns the connection string the Cloud API composes for
+// the supplied SQL user and database of a cluster. The connection string does
+// not include the password of the user.
+func (c *Client) ConnectionString(ctx context.Context, clusterID, database, user string) (string, error) {
+	q := url.Values{}
+	if database != "" {
+		q.Set("database", database)
+	}
+	if user != "" {
+		q.Set("sql_user", user)
+	}
+	path := fmt.Sprintf("/api/v1/clusters/%s/connection-string?%s", url.PathEscape(clusterID), q.Encode())
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	res := &connectionStringResponse{}
+	if err := c.do(req, res); err != nil {
+		return "", err
+	}
+	return res.ConnectionString, nil
+}
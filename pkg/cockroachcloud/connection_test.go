@@ -0,0 +1,38 @@
+package cockroachcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConnectionString(t *testing.T) {
+	var path, query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"connection_string":"postgresql://app@cool.cockroachlabs.cloud:26257/defaultdb?sslmode=verify-full"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	got, err := c.ConnectionString(context.Background(), "cluster", "defaultdb", "app")
+	if err != nil {
+		t.Fatalf("ConnectionString(...): %v", err)
+	}
+
+	if diff := cmp.Diff("postgresql://app@cool.cockroachlabs.cloud:26257/defaultdb?sslmode=verify-full", got); diff != "" {
+		t.Errorf("ConnectionString(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("/api/v1/clusters/cluster/connection-string", path); diff != "" {
+		t.Errorf("ConnectionString(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("database=defaultdb&sql_user=app", query); diff != "" {
+		t.Errorf("ConnectionString(...): -want query, +got query:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,74 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+// A Database of a cluster.
+type Database struct {
+	Name       string `json:"name"`
+	TableCount int64  `json:"table_count,omitempty"`
+}
+
+type databases struct {
+	Databases  []Database                            `json:"databases"`
+	Pagination *cockroachdb.KeysetPaginationResponse `json:"pagination,omitempty"`
+}
+
+// ListDatabases returns the databases of the supplied cluster, following
+// pagination transparently.
+func (c *Client) ListDatabases(ctx context.Context, clusterID string) ([]Database, error) {
+	dbs := []Database{}
+	err := NewPager(func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		q := url.Values{}
+		if startKey != nil {
+			q.Set("pagination.start_key", *startKey)
+		}
+		req, err := c.newRequest(ctx, http.MethodGet, databasesPath(clusterID, "")+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		res := &databases{}
+		if err := c.do(req, res); err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, res.Databases...)
+		return res.Pagination, nil
+	}).All(ctx)
+	return dbs, err
+}
+
+// CreateDatabase creates the supplied database in the supplied cluster.
+func (c *Client) CreateDatabase(ctx context.Context, clusterID, name string) (*Database, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, databasesPath(clusterID, ""), Database{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	db := &Database{}
+	if err := c.do(req, db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// DeleteDatabase drops the supplied database of the supplied cluster.
+func (c *Client) DeleteDatabase(ctx context.Context, clusterID, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, databasesPath(clusterID, name), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func databasesPath(clusterID, name string) string {
+	path := fmt.Sprintf("/api/v1/clusters/%s/databases", url.PathEscape(clusterID))
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	return path
+}
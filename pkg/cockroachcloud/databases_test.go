@@ -0,0 +1,62 @@
+package cockroachcloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDatabases(t *testing.T) {
+	pages := map[string]string{
+		"":    `{"databases":[{"name":"app","table_count":3}],"pagination":{"next":"app"}}`,
+		"app": `{"databases":[{"name":"defaultdb"}]}`,
+	}
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = io.WriteString(w, pages[r.URL.Query().Get("pagination.start_key")])
+		case http.MethodPost:
+			_, _ = io.Copy(w, r.Body)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	created, err := c.CreateDatabase(ctx, "cluster", "app")
+	if err != nil {
+		t.Fatalf("CreateDatabase(...): %v", err)
+	}
+	got, err := c.ListDatabases(ctx, "cluster")
+	if err != nil {
+		t.Fatalf("ListDatabases(...): %v", err)
+	}
+	if err := c.DeleteDatabase(ctx, "cluster", "app"); err != nil {
+		t.Fatalf("DeleteDatabase(...): %v", err)
+	}
+
+	if diff := cmp.Diff(&Database{Name: "app"}, created); diff != "" {
+		t.Errorf("CreateDatabase(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]Database{{Name: "app", TableCount: 3}, {Name: "defaultdb"}}, got); diff != "" {
+		t.Errorf("ListDatabases(...): -want, +got:\n%s\n", diff)
+	}
+	want := []string{
+		"POST /api/v1/clusters/cluster/databases",
+		"GET /api/v1/clusters/cluster/databases",
+		"GET /api/v1/clusters/cluster/databases",
+		"DELETE /api/v1/clusters/cluster/databases/app",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("Databases: -want requests, +got requests:\n%s\n", diff)
+	}
+}
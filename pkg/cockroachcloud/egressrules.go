@@ -0,0 +1,91 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// An EgressRuleState is the state of an egress rule.
+type EgressRuleState string
+
+// States of egress rules.
+const (
+	EgressRuleStateActive          EgressRuleState = "ACTIVE"
+	EgressRuleStatePendingCreation EgressRuleState = "PENDING_CREATION"
+	EgressRuleStatePendingUpdate   EgressRuleState = "PENDING_UPDATE"
+	EgressRuleStatePendingDeletion EgressRuleState = "PENDING_DELETION"
+	EgressRuleStateFailed          EgressRuleState = "FAILED"
+)
+
+// An EgressRule allows a dedicated cluster with egress traffic restricted to
+// reach a destination, e.g. to export changefeeds or backups.
+type EgressRule struct {
+	ID          string          `json:"id,omitempty"`
+	ClusterID   string          `json:"cluster_id,omitempty"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Destination string          `json:"destination"`
+	Ports       []int32         `json:"ports,omitempty"`
+	Paths       []string        `json:"paths,omitempty"`
+	Description string          `json:"description"`
+	State       EgressRuleState `json:"state,omitempty"`
+}
+
+// An EgressRuleUpdate changes the mutable fields of an egress rule.
+type EgressRuleUpdate struct {
+	Type        string   `json:"type"`
+	Destination string   `json:"destination"`
+	Ports       []int32  `json:"ports"`
+	Paths       []string `json:"paths"`
+	Description string   `json:"description"`
+}
+
+type egressRule struct {
+	Rule EgressRule `json:"rule"`
+}
+
+// GetEgressRule returns the supplied egress rule of the supplied cluster.
+func (c *Client) GetEgressRule(ctx context.Context, clusterID, ruleID string) (*EgressRule, error) {
+	return c.egressRule(ctx, http.MethodGet, egressRulePath(clusterID, ruleID), nil)
+}
+
+// CreateEgressRule adds the supplied egress rule to the supplied cluster.
+func (c *Client) CreateEgressRule(ctx context.Context, clusterID string, r EgressRule) (*EgressRule, error) {
+	return c.egressRule(ctx, http.MethodPost, egressRulePath(clusterID, ""), r)
+}
+
+// UpdateEgressRule changes the supplied egress rule of the supplied cluster.
+func (c *Client) UpdateEgressRule(ctx context.Context, clusterID, ruleID string, u EgressRuleUpdate) (*EgressRule, error) {
+	return c.egressRule(ctx, http.MethodPatch, egressRulePath(clusterID, ruleID), u)
+}
+
+// DeleteEgressRule removes the supplied egress rule of the supplied cluster.
+func (c *Client) DeleteEgressRule(ctx context.Context, clusterID, ruleID string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, egressRulePath(clusterID, ruleID), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func (c *Client) egressRule(ctx context.Context, method, path string, body interface{}) (*EgressRule, error) {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	res := &egressRule{}
+	if err := c.do(req, res); err != nil {
+		return nil, err
+	}
+	return &res.Rule, nil
+}
+
+func egressRulePath(clusterID, ruleID string) string {
+	path := fmt.Sprintf("/api/v1/clusters/%s/networking/egress-rules", url.PathEscape(clusterID))
+	if ruleID != "" {
+		path += "/" + url.PathEscape(ruleID)
+	}
+	return path
+}
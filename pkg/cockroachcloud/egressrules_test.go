@@ -0,0 +1,73 @@
+package cockroachcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEgressRules(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodDelete {
+			return
+		}
+		_, _ = w.Write([]byte(`{"rule":{
+			"id":"rule",
+			"cluster_id":"cluster",
+			"name":"s3",
+			"type":"FQDN",
+			"destination":"s3.amazonaws.com",
+			"ports":[443],
+			"description":"backups",
+			"state":"ACTIVE"
+		}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	if _, err := c.CreateEgressRule(ctx, "cluster", EgressRule{Name: "s3", Type: "FQDN", Destination: "s3.amazonaws.com"}); err != nil {
+		t.Fatalf("CreateEgressRule(...): %v", err)
+	}
+	if _, err := c.UpdateEgressRule(ctx, "cluster", "rule", EgressRuleUpdate{Type: "FQDN", Destination: "s3.amazonaws.com"}); err != nil {
+		t.Fatalf("UpdateEgressRule(...): %v", err)
+	}
+	got, err := c.GetEgressRule(ctx, "cluster", "rule")
+	if err != nil {
+		t.Fatalf("GetEgressRule(...): %v", err)
+	}
+	if err := c.DeleteEgressRule(ctx, "cluster", "rule"); err != nil {
+		t.Fatalf("DeleteEgressRule(...): %v", err)
+	}
+
+	want := &EgressRule{
+		ID:          "rule",
+		ClusterID:   "cluster",
+		Name:        "s3",
+		Type:        "FQDN",
+		Destination: "s3.amazonaws.com",
+		Ports:       []int32{443},
+		Description: "backups",
+		State:       EgressRuleStateActive,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetEgressRule(...): -want, +got:\n%s\n", diff)
+	}
+	wantRequests := []string{
+		"POST /api/v1/clusters/cluster/networking/egress-rules",
+		"PATCH /api/v1/clusters/cluster/networking/egress-rules/rule",
+		"GET /api/v1/clusters/cluster/networking/egress-rules/rule",
+		"DELETE /api/v1/clusters/cluster/networking/egress-rules/rule",
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("EgressRules: -want requests, +got requests:\n%s\n", diff)
+	}
+}
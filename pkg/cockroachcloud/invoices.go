@@ -0,0 +1,67 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// A CurrencyAmount is an amount of money in a currency, e.g. USD.
+type CurrencyAmount struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// An InvoiceCluster identifies the cluster an invoice item is billed for.
+type InvoiceCluster struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// An InvoiceItem is the part of an invoice billed for a single cluster.
+type InvoiceItem struct {
+	Cluster InvoiceCluster   `json:"cluster"`
+	Totals  []CurrencyAmount `json:"totals"`
+}
+
+// An Invoice of the organization for a billing period.
+type Invoice struct {
+	InvoiceID    string           `json:"invoice_id"`
+	PeriodStart  time.Time        `json:"period_start"`
+	PeriodEnd    time.Time        `json:"period_end"`
+	Totals       []CurrencyAmount `json:"totals"`
+	InvoiceItems []InvoiceItem    `json:"invoice_items"`
+}
+
+type listInvoicesResponse struct {
+	Invoices []Invoice `json:"invoices"`
+}
+
+// ListInvoices returns the invoices of the organization.
+func (c *Client) ListInvoices(ctx context.Context) ([]Invoice, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/invoices", nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &listInvoicesResponse{}
+	if err := c.do(req, res); err != nil {
+		return nil, err
+	}
+	return res.Invoices, nil
+}
+
+// GetInvoice returns the supplied invoice of the organization.
+func (c *Client) GetInvoice(ctx context.Context, invoiceID string) (*Invoice, error) {
+	path := fmt.Sprintf("/api/v1/invoices/%s", url.PathEscape(invoiceID))
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	inv := &Invoice{}
+	if err := c.do(req, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
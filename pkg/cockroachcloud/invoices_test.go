@@ -0,0 +1,48 @@
+package cockroachcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestListInvoices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/invoices" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"invoices":[{
+			"invoice_id":"inv-1",
+			"period_start":"2022-01-01T00:00:00Z",
+			"period_end":"2022-02-01T00:00:00Z",
+			"totals":[{"amount":12.5,"currency":"USD"}],
+			"invoice_items":[{"cluster":{"id":"cluster","name":"cool"},"totals":[{"amount":12.5,"currency":"USD"}]}]
+		}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	got, err := c.ListInvoices(context.Background())
+	if err != nil {
+		t.Fatalf("ListInvoices(...): %v", err)
+	}
+
+	want := []Invoice{{
+		InvoiceID:    "inv-1",
+		PeriodStart:  time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		Totals:       []CurrencyAmount{{Amount: 12.5, Currency: "USD"}},
+		InvoiceItems: []InvoiceItem{{Cluster: InvoiceCluster{ID: "cluster", Name: "cool"}, Totals: []CurrencyAmount{{Amount: 12.5, Currency: "USD"}}}},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListInvoices(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,108 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// A MetricExportStatus is the state of a metric export of a cluster.
+type MetricExportStatus string
+
+// States of metric exports.
+const (
+	MetricExportStatusNotDeployed MetricExportStatus = "NOT_DEPLOYED"
+	MetricExportStatusEnabling    MetricExportStatus = "ENABLING"
+	MetricExportStatusEnabled     MetricExportStatus = "ENABLED"
+	MetricExportStatusDisabling   MetricExportStatus = "DISABLING"
+	MetricExportStatusError       MetricExportStatus = "ERROR"
+)
+
+// A DatadogMetricExport exports the metrics of a cluster to Datadog.
+type DatadogMetricExport struct {
+	ClusterID string `json:"cluster_id"`
+	// Site of the Datadog account, e.g. US1 or EU1.
+	Site string `json:"site"`
+	// APIKey is masked by the Cloud API, apart from its last characters.
+	APIKey      string             `json:"api_key,omitempty"`
+	Status      MetricExportStatus `json:"status,omitempty"`
+	UserMessage string             `json:"user_message,omitempty"`
+}
+
+type enableDatadogMetricExportRequest struct {
+	Site   string `json:"site"`
+	APIKey string `json:"api_key"`
+}
+
+// GetDatadogMetricExport returns the Datadog metric export of the supplied
+// cluster.
+func (c *Client) GetDatadogMetricExport(ctx context.Context, clusterID string) (*DatadogMetricExport, error) {
+	e := &DatadogMetricExport{}
+	return e, c.metricExport(ctx, http.MethodGet, clusterID, "datadog", nil, e)
+}
+
+// EnableDatadogMetricExport exports the metrics of the supplied cluster to
+// the Datadog account of the supplied site and API key. An enabled export is
+// reconfigured.
+func (c *Client) EnableDatadogMetricExport(ctx context.Context, clusterID, site, apiKey string) (*DatadogMetricExport, error) {
+	e := &DatadogMetricExport{}
+	return e, c.metricExport(ctx, http.MethodPost, clusterID, "datadog", enableDatadogMetricExportRequest{Site: site, APIKey: apiKey}, e)
+}
+
+// DeleteDatadogMetricExport stops exporting the metrics of the supplied
+// cluster to Datadog.
+func (c *Client) DeleteDatadogMetricExport(ctx context.Context, clusterID string) error {
+	return c.metricExport(ctx, http.MethodDelete, clusterID, "datadog", nil, nil)
+}
+
+// A CloudWatchMetricExport exports the metrics of a cluster to AWS
+// CloudWatch.
+type CloudWatchMetricExport struct {
+	ClusterID string `json:"cluster_id"`
+	// RoleARN of the IAM role the Cloud API assumes to write to CloudWatch.
+	RoleARN string `json:"role_arn"`
+	// TargetRegion metrics are exported to. Defaults to the region of the
+	// cluster.
+	TargetRegion string `json:"target_region,omitempty"`
+	// LogGroupName metrics are exported to.
+	LogGroupName string             `json:"log_group_name,omitempty"`
+	Status       MetricExportStatus `json:"status,omitempty"`
+	UserMessage  string             `json:"user_message,omitempty"`
+}
+
+type enableCloudWatchMetricExportRequest struct {
+	RoleARN      string `json:"role_arn"`
+	TargetRegion string `json:"target_region,omitempty"`
+	LogGroupName string `json:"log_group_name,omitempty"`
+}
+
+// GetCloudWatchMetricExport returns the CloudWatch metric export of the
+// supplied cluster.
+func (c *Client) GetCloudWatchMetricExport(ctx context.Context, clusterID string) (*CloudWatchMetricExport, error) {
+	e := &CloudWatchMetricExport{}
+	return e, c.metricExport(ctx, http.MethodGet, clusterID, "cloudwatch", nil, e)
+}
+
+// EnableCloudWatchMetricExport exports the metrics of the supplied cluster to
+// the supplied CloudWatch log group. An enabled export is reconfigured.
+func (c *Client) EnableCloudWatchMetricExport(ctx context.Context, clusterID, roleARN, targetRegion, logGroupName string) (*CloudWatchMetricExport, error) {
+	e := &CloudWatchMetricExport{}
+	body := enableCloudWatchMetricExportRequest{RoleARN: roleARN, TargetRegion: targetRegion, LogGroupName: logGroupName}
+	return e, c.metricExport(ctx, http.MethodPost, clusterID, "cloudwatch", body, e)
+}
+
+// DeleteCloudWatchMetricExport stops exporting the metrics of the supplied
+// cluster to CloudWatch.
+func (c *Client) DeleteCloudWatchMetricExport(ctx context.Context, clusterID string) error {
+	return c.metricExport(ctx, http.MethodDelete, clusterID, "cloudwatch", nil, nil)
+}
+
+func (c *Client) metricExport(ctx context.Context, method, clusterID, target string, body, v interface{}) error {
+	path := fmt.Sprintf("/api/v1/clusters/%s/metricexport/%s", url.PathEscape(clusterID), target)
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return c.do(req, v)
+}
@@ -0,0 +1,101 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDatadogMetricExport(t *testing.T) {
+	var requests []string
+	var enabled enableDatadogMetricExportRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/metricexport/datadog" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests = append(requests, r.Method)
+		switch r.Method {
+		case http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&enabled)
+		case http.MethodDelete:
+			return
+		}
+		_, _ = w.Write([]byte(`{"cluster_id":"cluster","site":"EU1","api_key":"****abcd","status":"ENABLING","user_message":"enabling"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	if _, err := c.EnableDatadogMetricExport(context.Background(), "cluster", "EU1", "s3cr3tabcd"); err != nil {
+		t.Fatalf("EnableDatadogMetricExport(...): %v", err)
+	}
+	got, err := c.GetDatadogMetricExport(context.Background(), "cluster")
+	if err != nil {
+		t.Fatalf("GetDatadogMetricExport(...): %v", err)
+	}
+	if err := c.DeleteDatadogMetricExport(context.Background(), "cluster"); err != nil {
+		t.Fatalf("DeleteDatadogMetricExport(...): %v", err)
+	}
+
+	want := &DatadogMetricExport{ClusterID: "cluster", Site: "EU1", APIKey: "****abcd", Status: MetricExportStatusEnabling, UserMessage: "enabling"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetDatadogMetricExport(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(enableDatadogMetricExportRequest{Site: "EU1", APIKey: "s3cr3tabcd"}, enabled); diff != "" {
+		t.Errorf("EnableDatadogMetricExport(...): -want request, +got request:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{http.MethodPost, http.MethodGet, http.MethodDelete}, requests); diff != "" {
+		t.Errorf("DatadogMetricExport: -want methods, +got methods:\n%s\n", diff)
+	}
+}
+
+func TestCloudWatchMetricExport(t *testing.T) {
+	var enabled enableCloudWatchMetricExportRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/metricexport/cloudwatch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&enabled)
+		}
+		_, _ = w.Write([]byte(`{"cluster_id":"cluster","role_arn":"arn:aws:iam::1:role/r","target_region":"us-east-1","log_group_name":"crdb","status":"ENABLED"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	if _, err := c.EnableCloudWatchMetricExport(context.Background(), "cluster", "arn:aws:iam::1:role/r", "", "crdb"); err != nil {
+		t.Fatalf("EnableCloudWatchMetricExport(...): %v", err)
+	}
+	got, err := c.GetCloudWatchMetricExport(context.Background(), "cluster")
+	if err != nil {
+		t.Fatalf("GetCloudWatchMetricExport(...): %v", err)
+	}
+
+	want := &CloudWatchMetricExport{ClusterID: "cluster", RoleARN: "arn:aws:iam::1:role/r", TargetRegion: "us-east-1", LogGroupName: "crdb", Status: MetricExportStatusEnabled}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetCloudWatchMetricExport(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(enableCloudWatchMetricExportRequest{RoleARN: "arn:aws:iam::1:role/r", LogGroupName: "crdb"}, enabled); diff != "" {
+		t.Errorf("EnableCloudWatchMetricExport(...): -want request, +got request:\n%s\n", diff)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(&Error{StatusCode: http.StatusNotFound}) {
+		t.Errorf("IsNotFound(...): want true for 404 Not Found")
+	}
+	if IsNotFound(&Error{StatusCode: http.StatusForbidden}) {
+		t.Errorf("IsNotFound(...): want false for 403 Forbidden")
+	}
+}
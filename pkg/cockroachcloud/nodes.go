@@ -0,0 +1,37 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+// ListClusterNodes returns the nodes of the supplied cluster. Only dedicated
+// clusters have nodes.
+func (c *Client) ListClusterNodes(ctx context.Context, clusterID string) ([]cockroachdb.Node, error) {
+	nodes := []cockroachdb.Node{}
+	err := NewPager(func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		q := url.Values{}
+		if startKey != nil {
+			q.Set("pagination.start_key", *startKey)
+		}
+		path := fmt.Sprintf("/api/v1/clusters/%s/nodes?%s", url.PathEscape(clusterID), q.Encode())
+		req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		res := &cockroachdb.ListClusterNodesResponse{}
+		if err := c.do(req, res); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, res.Nodes...)
+		return res.Pagination, nil
+	}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
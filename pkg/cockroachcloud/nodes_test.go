@@ -0,0 +1,44 @@
+package cockroachcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestListClusterNodes(t *testing.T) {
+	pages := map[string]string{
+		"":     `{"nodes":[{"name":"node-1","region_name":"us-east1","status":"LIVE"}],"pagination":{"next":"next"}}`,
+		"next": `{"nodes":[{"name":"node-2","region_name":"us-west2","status":"NOT_READY"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/nodes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("pagination.start_key")]))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	got, err := c.ListClusterNodes(context.Background(), "cluster")
+	if err != nil {
+		t.Fatalf("ListClusterNodes(...): %v", err)
+	}
+
+	want := []cockroachdb.Node{
+		{Name: "node-1", RegionName: "us-east1", Status: cockroachdb.NODESTATUS_LIVE},
+		{Name: "node-2", RegionName: "us-west2", Status: cockroachdb.NODESTATUS_NOT_READY},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(cockroachdb.Node{}, "AdditionalProperties")); diff != "" {
+		t.Errorf("ListClusterNodes(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,36 @@
+package cockroachcloud
+
+import (
+	"context"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+// A PageFunc fetches the page of a list endpoint that starts at the supplied
+// key, nil for the first page, and returns the pagination of the response.
+type PageFunc func(ctx context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error)
+
+// A Pager follows the keyset pagination of a Cloud API list endpoint.
+type Pager struct {
+	fetch PageFunc
+}
+
+// NewPager returns a Pager that fetches pages with the supplied function.
+func NewPager(fetch PageFunc) *Pager {
+	return &Pager{fetch: fetch}
+}
+
+// All fetches pages until the last one, or until fetching a page fails.
+func (p *Pager) All(ctx context.Context) error {
+	var key *string
+	for {
+		pg, err := p.fetch(ctx, key)
+		if err != nil {
+			return err
+		}
+		if pg == nil || pg.Next == nil || *pg.Next == "" {
+			return nil
+		}
+		key = pg.Next
+	}
+}
@@ -0,0 +1,49 @@
+package cockroachcloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPager(t *testing.T) {
+	next := func(k string) *cockroachdb.KeysetPaginationResponse {
+		return &cockroachdb.KeysetPaginationResponse{Next: &k}
+	}
+	pages := map[string]*cockroachdb.KeysetPaginationResponse{
+		"":  next("b"),
+		"b": next("c"),
+		"c": {},
+	}
+
+	got := []string{}
+	err := NewPager(func(_ context.Context, startKey *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		k := ""
+		if startKey != nil {
+			k = *startKey
+		}
+		got = append(got, k)
+		return pages[k], nil
+	}).All(context.Background())
+	if err != nil {
+		t.Fatalf("p.All(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"", "b", "c"}, got); diff != "" {
+		t.Errorf("p.All(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+	err := NewPager(func(_ context.Context, _ *string) (*cockroachdb.KeysetPaginationResponse, error) {
+		calls++
+		return nil, errBoom
+	}).All(context.Background())
+	if !errors.Is(err, errBoom) || calls != 1 {
+		t.Errorf("p.All(...): want error %v after 1 call, got %v after %d", errBoom, err, calls)
+	}
+}
@@ -0,0 +1,157 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+// A PrivateEndpointServiceStatus is the state of a private endpoint service.
+type PrivateEndpointServiceStatus string
+
+// States of private endpoint services.
+const (
+	PrivateEndpointServiceStatusAvailable    PrivateEndpointServiceStatus = "ENDPOINT_SERVICE_STATUS_AVAILABLE"
+	PrivateEndpointServiceStatusCreating     PrivateEndpointServiceStatus = "ENDPOINT_SERVICE_STATUS_CREATING"
+	PrivateEndpointServiceStatusCreateFailed PrivateEndpointServiceStatus = "ENDPOINT_SERVICE_STATUS_CREATE_FAILED"
+	PrivateEndpointServiceStatusDeleting     PrivateEndpointServiceStatus = "ENDPOINT_SERVICE_STATUS_DELETING"
+	PrivateEndpointServiceStatusDeleteFailed PrivateEndpointServiceStatus = "ENDPOINT_SERVICE_STATUS_DELETE_FAILED"
+)
+
+// An AWSPrivateEndpointService is an AWS PrivateLink endpoint service. VPC
+// endpoints connect to it by its service name.
+type AWSPrivateEndpointService struct {
+	ServiceName         string   `json:"service_name"`
+	ServiceID           string   `json:"service_id"`
+	AvailabilityZoneIDs []string `json:"availability_zone_ids"`
+}
+
+// An AzurePrivateEndpointService is an Azure Private Link service. Private
+// endpoints connect to it by its alias.
+type AzurePrivateEndpointService struct {
+	AliasName string `json:"alias_name"`
+	ServiceID string `json:"service_id"`
+}
+
+// A PrivateEndpointService exposes a region of a dedicated cluster to private
+// endpoints in the VPCs of its users.
+type PrivateEndpointService struct {
+	RegionName    string                       `json:"region_name"`
+	CloudProvider cockroachdb.ApiCloudProvider `json:"cloud_provider"`
+	Status        PrivateEndpointServiceStatus `json:"status"`
+	AWS           *AWSPrivateEndpointService   `json:"aws,omitempty"`
+	Azure         *AzurePrivateEndpointService `json:"azure,omitempty"`
+}
+
+type privateEndpointServices struct {
+	Services []PrivateEndpointService `json:"services"`
+}
+
+// ListPrivateEndpointServices returns the private endpoint services of the
+// supplied cluster.
+func (c *Client) ListPrivateEndpointServices(ctx context.Context, clusterID string) ([]PrivateEndpointService, error) {
+	return c.privateEndpointServices(ctx, http.MethodGet, clusterID, nil)
+}
+
+// CreatePrivateEndpointServices creates a private endpoint service in each
+// region of the supplied dedicated cluster.
+func (c *Client) CreatePrivateEndpointServices(ctx context.Context, clusterID string) ([]PrivateEndpointService, error) {
+	return c.privateEndpointServices(ctx, http.MethodPost, clusterID, struct{}{})
+}
+
+func (c *Client) privateEndpointServices(ctx context.Context, method, clusterID string, body interface{}) ([]PrivateEndpointService, error) {
+	path := fmt.Sprintf("/api/v1/clusters/%s/networking/private-endpoint-services", url.PathEscape(clusterID))
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	res := &privateEndpointServices{}
+	if err := c.do(req, res); err != nil {
+		return nil, err
+	}
+	return res.Services, nil
+}
+
+// A PrivateEndpointConnectionStatus is the state of a private endpoint
+// connection.
+type PrivateEndpointConnectionStatus string
+
+// States of private endpoint connections.
+const (
+	PrivateEndpointConnectionStatusPendingAcceptance PrivateEndpointConnectionStatus = "ENDPOINT_PENDING_ACCEPTANCE"
+	PrivateEndpointConnectionStatusPending           PrivateEndpointConnectionStatus = "ENDPOINT_PENDING"
+	PrivateEndpointConnectionStatusAvailable         PrivateEndpointConnectionStatus = "ENDPOINT_AVAILABLE"
+	PrivateEndpointConnectionStatusDeleting          PrivateEndpointConnectionStatus = "ENDPOINT_DELETING"
+	PrivateEndpointConnectionStatusDeleted           PrivateEndpointConnectionStatus = "ENDPOINT_DELETED"
+	PrivateEndpointConnectionStatusRejected          PrivateEndpointConnectionStatus = "ENDPOINT_REJECTED"
+	PrivateEndpointConnectionStatusFailed            PrivateEndpointConnectionStatus = "ENDPOINT_FAILED"
+	PrivateEndpointConnectionStatusExpired           PrivateEndpointConnectionStatus = "ENDPOINT_EXPIRED"
+)
+
+// A PrivateEndpointConnection connects a private endpoint in a VPC or VNet of
+// a user, e.g. an AWS VPC endpoint or an Azure private endpoint, to the
+// private endpoint service of a cluster.
+type PrivateEndpointConnection struct {
+	RegionName    string                          `json:"region_name"`
+	CloudProvider cockroachdb.ApiCloudProvider    `json:"cloud_provider"`
+	Status        PrivateEndpointConnectionStatus `json:"status"`
+	EndpointID    string                          `json:"endpoint_id"`
+	ServiceID     string                          `json:"endpoint_service_id"`
+}
+
+type privateEndpointConnections struct {
+	Connections []PrivateEndpointConnection `json:"connections"`
+}
+
+type privateEndpointConnectionRequest struct {
+	EndpointID string `json:"endpoint_id"`
+}
+
+// ListPrivateEndpointConnections returns the private endpoints connected to
+// the supplied cluster.
+func (c *Client) ListPrivateEndpointConnections(ctx context.Context, clusterID string) ([]PrivateEndpointConnection, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, privateEndpointConnectionsPath(clusterID, ""), nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &privateEndpointConnections{}
+	if err := c.do(req, res); err != nil {
+		return nil, err
+	}
+	return res.Connections, nil
+}
+
+// AddPrivateEndpointConnection accepts the supplied private endpoint's
+// connection to the supplied cluster.
+func (c *Client) AddPrivateEndpointConnection(ctx context.Context, clusterID, endpointID string) (*PrivateEndpointConnection, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, privateEndpointConnectionsPath(clusterID, ""), privateEndpointConnectionRequest{EndpointID: endpointID})
+	if err != nil {
+		return nil, err
+	}
+	conn := &PrivateEndpointConnection{}
+	if err := c.do(req, conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// RemovePrivateEndpointConnection disconnects the supplied private endpoint
+// from the supplied cluster.
+func (c *Client) RemovePrivateEndpointConnection(ctx context.Context, clusterID, endpointID string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, privateEndpointConnectionsPath(clusterID, endpointID), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
+
+func privateEndpointConnectionsPath(clusterID, endpointID string) string {
+	path := fmt.Sprintf("/api/v1/clusters/%s/networking/private-endpoint-connections", url.PathEscape(clusterID))
+	if endpointID != "" {
+		path += "/" + url.PathEscape(endpointID)
+	}
+	return path
+}
@@ -0,0 +1,116 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrivateEndpointServices(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters/cluster/networking/private-endpoint-services" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`{"services":[{
+			"region_name":"us-east-1",
+			"cloud_provider":"AWS",
+			"status":"ENDPOINT_SERVICE_STATUS_AVAILABLE",
+			"aws":{"service_name":"com.amazonaws.vpce.us-east-1.vpce-svc-1","service_id":"vpce-svc-1","availability_zone_ids":["use1-az1"]}
+		}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	if _, err := c.CreatePrivateEndpointServices(context.Background(), "cluster"); err != nil {
+		t.Fatalf("CreatePrivateEndpointServices(...): %v", err)
+	}
+	got, err := c.ListPrivateEndpointServices(context.Background(), "cluster")
+	if err != nil {
+		t.Fatalf("ListPrivateEndpointServices(...): %v", err)
+	}
+
+	want := []PrivateEndpointService{{
+		RegionName:    "us-east-1",
+		CloudProvider: cockroachdb.APICLOUDPROVIDER_AWS,
+		Status:        PrivateEndpointServiceStatusAvailable,
+		AWS: &AWSPrivateEndpointService{
+			ServiceName:         "com.amazonaws.vpce.us-east-1.vpce-svc-1",
+			ServiceID:           "vpce-svc-1",
+			AvailabilityZoneIDs: []string{"use1-az1"},
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListPrivateEndpointServices(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{http.MethodPost, http.MethodGet}, methods); diff != "" {
+		t.Errorf("PrivateEndpointServices: -want methods, +got methods:\n%s\n", diff)
+	}
+}
+
+func TestPrivateEndpointConnections(t *testing.T) {
+	const endpointID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/privateEndpoints/crdb"
+
+	var requests []string
+	var added privateEndpointConnectionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		conn := `{"region_name":"eastus2","cloud_provider":"AZURE","status":"ENDPOINT_PENDING","endpoint_id":"` + endpointID + `","endpoint_service_id":"svc"}`
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"connections":[` + conn + `]}`))
+		case http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&added)
+			_, _ = w.Write([]byte(conn))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	if _, err := c.AddPrivateEndpointConnection(ctx, "cluster", endpointID); err != nil {
+		t.Fatalf("AddPrivateEndpointConnection(...): %v", err)
+	}
+	got, err := c.ListPrivateEndpointConnections(ctx, "cluster")
+	if err != nil {
+		t.Fatalf("ListPrivateEndpointConnections(...): %v", err)
+	}
+	if err := c.RemovePrivateEndpointConnection(ctx, "cluster", endpointID); err != nil {
+		t.Fatalf("RemovePrivateEndpointConnection(...): %v", err)
+	}
+
+	want := []PrivateEndpointConnection{{
+		RegionName:    "eastus2",
+		CloudProvider: CloudProviderAzure,
+		Status:        PrivateEndpointConnectionStatusPending,
+		EndpointID:    endpointID,
+		ServiceID:     "svc",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListPrivateEndpointConnections(...): -want, +got:\n%s\n", diff)
+	}
+	if added.EndpointID != endpointID {
+		t.Errorf("AddPrivateEndpointConnection(...): want endpoint %q, got %q", endpointID, added.EndpointID)
+	}
+	wantRequests := []string{
+		"POST /api/v1/clusters/cluster/networking/private-endpoint-connections",
+		"GET /api/v1/clusters/cluster/networking/private-endpoint-connections",
+		"DELETE /api/v1/clusters/cluster/networking/private-endpoint-connections/%2Fsubscriptions%2Fsub%2FresourceGroups%2Frg%2Fproviders%2FMicrosoft.Network%2FprivateEndpoints%2Fcrdb",
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("PrivateEndpointConnections: -want requests, +got requests:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,15 @@
+package cockroachcloud
+
+import (
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+// CloudProviderAzure is the Microsoft Azure cloud provider. The SDK predates
+// Azure support in the Cloud API, so it does not define it.
+const CloudProviderAzure cockroachdb.ApiCloudProvider = "AZURE"
+
+func init() {
+	// The SDK refuses to decode cloud providers it does not know, so it could
+	// neither read Azure clusters nor the regions offered on Azure.
+	cockroachdb.AllowedApiCloudProviderEnumValues = append(cockroachdb.AllowedApiCloudProviderEnumValues, CloudProviderAzure)
+}
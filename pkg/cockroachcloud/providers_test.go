@@ -0,0 +1,18 @@
+package cockroachcloud
+
+import (
+	"encoding/json"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+func TestCloudProviderAzure(t *testing.T) {
+	cl := &cockroachdb.Cluster{}
+	if err := json.Unmarshal([]byte(`{"id":"cluster","cloud_provider":"AZURE"}`), cl); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+	if cl.CloudProvider != CloudProviderAzure {
+		t.Errorf("json.Unmarshal(...): want cloud provider %s, got %s", CloudProviderAzure, cl.CloudProvider)
+	}
+}
@@ -0,0 +1,93 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// A RestoreStatus is the state of a restore.
+type RestoreStatus string
+
+// States of restores.
+const (
+	RestoreStatusPending    RestoreStatus = "PENDING"
+	RestoreStatusSuccess    RestoreStatus = "SUCCESS"
+	RestoreStatusFailed     RestoreStatus = "FAILED"
+	RestoreStatusCancelling RestoreStatus = "CANCELLING"
+	RestoreStatusCancelled  RestoreStatus = "CANCELLED"
+)
+
+// RestoreTypeCluster restores all data of a cluster, replacing that of the
+// cluster restored into.
+const RestoreTypeCluster = "CLUSTER"
+
+// A RestoreItem is a database or table to restore.
+type RestoreItem struct {
+	Database string `json:"database"`
+	Schema   string `json:"schema,omitempty"`
+	Table    string `json:"table,omitempty"`
+}
+
+// RestoreOptions change how objects are restored.
+type RestoreOptions struct {
+	NewDBName              string `json:"new_db_name,omitempty"`
+	IntoDB                 string `json:"into_db,omitempty"`
+	SkipLocalitiesCheck    bool   `json:"skip_localities_check,omitempty"`
+	SkipMissingForeignKeys bool   `json:"skip_missing_foreign_keys,omitempty"`
+	SkipMissingSequences   bool   `json:"skip_missing_sequences,omitempty"`
+	SchemaOnly             bool   `json:"schema_only,omitempty"`
+}
+
+// A RestoreRequest restores a managed backup into a cluster.
+type RestoreRequest struct {
+	SourceClusterID string          `json:"source_cluster_id,omitempty"`
+	BackupID        string          `json:"backup_id,omitempty"`
+	Type            string          `json:"type"`
+	Objects         []RestoreItem   `json:"objects,omitempty"`
+	RestoreOpts     *RestoreOptions `json:"restore_opts,omitempty"`
+}
+
+// A Restore of a managed backup into a cluster. Its CompletionPercent is the
+// fraction of the restore that is done, from 0 to 1.
+type Restore struct {
+	ID                 string        `json:"id"`
+	BackupEndTime      string        `json:"backup_end_time,omitempty"`
+	Status             RestoreStatus `json:"status"`
+	Type               string        `json:"type,omitempty"`
+	CompletionPercent  float64       `json:"completion_percent"`
+	ClientErrorCode    int32         `json:"client_error_code,omitempty"`
+	ClientErrorMessage string        `json:"client_error_message,omitempty"`
+	CRDBJobID          string        `json:"crdb_job_id,omitempty"`
+}
+
+// CreateRestore starts restoring a managed backup into the supplied cluster.
+func (c *Client) CreateRestore(ctx context.Context, clusterID string, r RestoreRequest) (*Restore, error) {
+	return c.restore(ctx, http.MethodPost, restorePath(clusterID, ""), r)
+}
+
+// GetRestore returns the supplied restore into the supplied cluster.
+func (c *Client) GetRestore(ctx context.Context, clusterID, restoreID string) (*Restore, error) {
+	return c.restore(ctx, http.MethodGet, restorePath(clusterID, restoreID), nil)
+}
+
+func (c *Client) restore(ctx context.Context, method, path string, body interface{}) (*Restore, error) {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	r := &Restore{}
+	if err := c.do(req, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func restorePath(clusterID, restoreID string) string {
+	path := fmt.Sprintf("/api/v1/clusters/%s/restores", url.PathEscape(clusterID))
+	if restoreID != "" {
+		path += "/" + url.PathEscape(restoreID)
+	}
+	return path
+}
@@ -0,0 +1,58 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRestores(t *testing.T) {
+	var requests []string
+	var created RestoreRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&created)
+		}
+		_, _ = w.Write([]byte(`{"id":"restore","status":"PENDING","type":"DATABASE","completion_percent":0.5,"crdb_job_id":"123"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	req := RestoreRequest{
+		SourceClusterID: "source",
+		Type:            "DATABASE",
+		Objects:         []RestoreItem{{Database: "app"}},
+		RestoreOpts:     &RestoreOptions{NewDBName: "app_restored"},
+	}
+	if _, err := c.CreateRestore(ctx, "cluster", req); err != nil {
+		t.Fatalf("CreateRestore(...): %v", err)
+	}
+	got, err := c.GetRestore(ctx, "cluster", "restore")
+	if err != nil {
+		t.Fatalf("GetRestore(...): %v", err)
+	}
+
+	if diff := cmp.Diff(req, created); diff != "" {
+		t.Errorf("CreateRestore(...): -want request, +got request:\n%s\n", diff)
+	}
+	want := &Restore{ID: "restore", Status: RestoreStatusPending, Type: "DATABASE", CompletionPercent: 0.5, CRDBJobID: "123"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetRestore(...): -want, +got:\n%s\n", diff)
+	}
+	wantRequests := []string{
+		"POST /api/v1/clusters/cluster/restores",
+		"GET /api/v1/clusters/cluster/restores/restore",
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("Restores: -want requests, +got requests:\n%s\n", diff)
+	}
+}
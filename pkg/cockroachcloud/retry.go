@@ -0,0 +1,117 @@
+package cockroachcloud
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header that makes non idempotent requests safe
+// to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// A RetryPolicy determines which failed requests are retried, and how often.
+// Requests are retried on transport errors, throttling and unavailability.
+type RetryPolicy struct {
+	// MaxRetries per HTTP method. Requests with a method that is not listed
+	// are never retried, so that retries cannot create duplicate resources.
+	MaxRetries map[string]int
+	// MaxRetriesWithIdempotencyKey applies to requests of any method that
+	// carry an Idempotency-Key header.
+	MaxRetriesWithIdempotencyKey int
+	// Backoff before the first retry. It doubles after every retry.
+	Backoff time.Duration
+}
+
+// DefaultRetryPolicy retries idempotent methods and requests carrying an
+// Idempotency-Key, but never plain POST requests.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: map[string]int{
+			http.MethodGet:    3,
+			http.MethodHead:   3,
+			http.MethodPut:    3,
+			http.MethodDelete: 3,
+		},
+		MaxRetriesWithIdempotencyKey: 3,
+		Backoff:                      500 * time.Millisecond,
+	}
+}
+
+func (p RetryPolicy) maxRetries(req *http.Request) int {
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return p.MaxRetriesWithIdempotencyKey
+	}
+	return p.MaxRetries[req.Method]
+}
+
+// WithRetryPolicy retries failed requests according to the supplied policy.
+// It must be set after WithHTTPClient, if any.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) error {
+		hc := *c.httpClient
+		hc.Transport = NewRetryTransport(hc.Transport, p)
+		c.httpClient = &hc
+
+		return nil
+	}
+}
+
+// NewRetryTransport returns an http.RoundTripper that retries failed requests
+// of the supplied RoundTripper according to the supplied policy.
+func NewRetryTransport(base http.RoundTripper, p RetryPolicy) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, policy: p}
+}
+
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	max := t.policy.maxRetries(req)
+	backoff := t.policy.Backoff
+	for attempt := 0; ; attempt++ {
+		res, err := t.base.RoundTrip(req)
+		if attempt >= max || !retryable(res, err) {
+			return res, err
+		}
+		// The body of a request can only be replayed if it can be
+		// obtained again.
+		if req.Body != nil && req.GetBody == nil {
+			return res, err
+		}
+		if res != nil {
+			res.Body.Close() //nolint:errcheck
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
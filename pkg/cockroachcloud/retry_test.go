@@ -0,0 +1,76 @@
+package cockroachcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRetryTransport(t *testing.T) {
+	type want struct {
+		attempts int
+		status   int
+	}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		header http.Header
+		want   want
+	}{
+		"RetryGet": {
+			reason: "GET requests should be retried.",
+			method: http.MethodGet,
+			want:   want{attempts: 3, status: http.StatusOK},
+		},
+		"NeverRetryPost": {
+			reason: "POST requests should not be retried, given they may create duplicates.",
+			method: http.MethodPost,
+			want:   want{attempts: 1, status: http.StatusServiceUnavailable},
+		},
+		"RetryPostWithIdempotencyKey": {
+			reason: "POST requests with an idempotency key should be retried.",
+			method: http.MethodPost,
+			header: http.Header{IdempotencyKeyHeader: {"key"}},
+			want:   want{attempts: 3, status: http.StatusOK},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				attempts++
+				if attempts < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			p := DefaultRetryPolicy()
+			p.Backoff = 0
+			hc := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, p)}
+
+			req, _ := http.NewRequestWithContext(context.Background(), tc.method, srv.URL, strings.NewReader("{}"))
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+			res, err := hc.Do(req)
+			if err != nil {
+				t.Fatalf("hc.Do(...): %v", err)
+			}
+			res.Body.Close() //nolint:errcheck
+
+			got := want{attempts: attempts, status: res.StatusCode}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nhc.Do(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
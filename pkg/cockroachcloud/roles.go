@@ -0,0 +1,70 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// A ResourceType is the kind of resource a role is granted on.
+type ResourceType string
+
+// Kinds of resources roles are granted on.
+const (
+	ResourceTypeOrganization ResourceType = "ORGANIZATION"
+	ResourceTypeFolder       ResourceType = "FOLDER"
+	ResourceTypeCluster      ResourceType = "CLUSTER"
+)
+
+// A Resource roles are granted on.
+type Resource struct {
+	Type ResourceType `json:"type"`
+	ID   string       `json:"id"`
+}
+
+// A RoleGrant is a built-in role granted to a user or service account on a
+// resource, e.g. CLUSTER_ADMIN on a cluster.
+type RoleGrant struct {
+	Name     string   `json:"name"`
+	Resource Resource `json:"resource"`
+}
+
+type roleGrants struct {
+	Roles []RoleGrant `json:"roles"`
+}
+
+// ListRoleGrants returns the roles granted to the supplied user or service
+// account.
+func (c *Client) ListRoleGrants(ctx context.Context, userID string) ([]RoleGrant, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/roles/"+url.PathEscape(userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &roleGrants{}
+	if err := c.do(req, res); err != nil {
+		return nil, err
+	}
+	return res.Roles, nil
+}
+
+// AddRoleGrant grants the supplied role to the supplied user or service
+// account.
+func (c *Client) AddRoleGrant(ctx context.Context, userID string, g RoleGrant) error {
+	return c.roleGrant(ctx, http.MethodPost, userID, g)
+}
+
+// RemoveRoleGrant revokes the supplied role from the supplied user or
+// service account.
+func (c *Client) RemoveRoleGrant(ctx context.Context, userID string, g RoleGrant) error {
+	return c.roleGrant(ctx, http.MethodDelete, userID, g)
+}
+
+func (c *Client) roleGrant(ctx context.Context, method, userID string, g RoleGrant) error {
+	path := fmt.Sprintf("/api/v1/roles/%s/%s/%s/%s", url.PathEscape(userID), url.PathEscape(string(g.Resource.Type)), url.PathEscape(g.Resource.ID), url.PathEscape(g.Name))
+	req, err := c.newRequest(ctx, method, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
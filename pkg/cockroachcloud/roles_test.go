@@ -0,0 +1,51 @@
+package cockroachcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRoleGrants(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method != http.MethodGet {
+			return
+		}
+		_, _ = w.Write([]byte(`{"roles":[{"name":"CLUSTER_ADMIN","resource":{"type":"CLUSTER","id":"cluster"}}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	ctx := context.Background()
+	g := RoleGrant{Name: "CLUSTER_ADMIN", Resource: Resource{Type: ResourceTypeCluster, ID: "cluster"}}
+	if err := c.AddRoleGrant(ctx, "user", g); err != nil {
+		t.Fatalf("AddRoleGrant(...): %v", err)
+	}
+	got, err := c.ListRoleGrants(ctx, "user")
+	if err != nil {
+		t.Fatalf("ListRoleGrants(...): %v", err)
+	}
+	if err := c.RemoveRoleGrant(ctx, "user", g); err != nil {
+		t.Fatalf("RemoveRoleGrant(...): %v", err)
+	}
+
+	if diff := cmp.Diff([]RoleGrant{g}, got); diff != "" {
+		t.Errorf("ListRoleGrants(...): -want, +got:\n%s\n", diff)
+	}
+	want := []string{
+		"POST /api/v1/roles/user/CLUSTER/cluster/CLUSTER_ADMIN",
+		"GET /api/v1/roles/user",
+		"DELETE /api/v1/roles/user/CLUSTER/cluster/CLUSTER_ADMIN",
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("RoleGrants: -want requests, +got requests:\n%s\n", diff)
+	}
+}
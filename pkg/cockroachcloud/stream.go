@@ -0,0 +1,36 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Download streams the response of a GET request for the supplied path to w,
+// rather than buffering it, so that large exports such as audit logs or
+// invoice CSVs do not need to fit in memory. It returns the number of bytes
+// written.
+func (c *Client) Download(ctx context.Context, path string, w io.Writer) (int64, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "*/*")
+
+	res, err := c.send(req)
+	if err != nil {
+		return 0, fmt.Errorf("error requesting %s: %v", req.URL.Path, err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return 0, handleResponse(res, nil)
+	}
+
+	n, err := io.Copy(w, res.Body)
+	if err != nil {
+		return n, fmt.Errorf("error streaming response: %v", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,64 @@
+package cockroachcloud
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDownload(t *testing.T) {
+	csv := strings.Repeat("cluster,cost\n", 1024)
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    string
+		wantErr error
+	}{
+		"Streamed": {
+			reason: "The response body should be written to the supplied writer.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, csv)
+			},
+			want: csv,
+		},
+		"Error": {
+			reason: "Error statuses should be returned as an *Error.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"code":5,"message":"not found"}`)
+			},
+			wantErr: &Error{StatusCode: http.StatusNotFound, Code: 5, Message: "not found", Body: []byte(`{"code":5,"message":"not found"}`)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			c, err := NewClient("key", WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatalf("NewClient(...): %v", err)
+			}
+			buf := &bytes.Buffer{}
+			n, err := c.Download(context.Background(), "/api/v1/invoices/1", buf)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Download(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("\n%s\nc.Download(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if n != int64(len(tc.want)) {
+				t.Errorf("\n%s\nc.Download(...): want %d bytes written, got %d", tc.reason, len(tc.want), n)
+			}
+		})
+	}
+}
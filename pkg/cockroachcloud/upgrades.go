@@ -0,0 +1,98 @@
+package cockroachcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+)
+
+// An UpgradeStatus is the state of a major version upgrade of a cluster.
+type UpgradeStatus string
+
+// Upgrade statuses a cluster can be moved to.
+const (
+	UpgradeStatusMajorUpgradeRunning UpgradeStatus = "MAJOR_UPGRADE_RUNNING"
+	UpgradeStatusFinalized           UpgradeStatus = "FINALIZED"
+	UpgradeStatusRollbackRunning     UpgradeStatus = "ROLLBACK_RUNNING"
+)
+
+// Upgrade statuses a cluster can only be observed in.
+const (
+	UpgradeStatusUpgradeAvailable    UpgradeStatus = "UPGRADE_AVAILABLE"
+	UpgradeStatusPendingFinalization UpgradeStatus = "PENDING_FINALIZATION"
+)
+
+// An Upgrade is the state of the major version upgrade of a cluster. The SDK
+// does not decode the upgrade status of clusters.
+type Upgrade struct {
+	CockroachVersion string                        `json:"cockroach_version"`
+	UpgradeStatus    UpgradeStatus                 `json:"upgrade_status"`
+	OperationStatus  cockroachdb.ClusterStatusType `json:"operation_status"`
+}
+
+type upgradeRequest struct {
+	CockroachVersion string        `json:"cockroach_version,omitempty"`
+	UpgradeStatus    UpgradeStatus `json:"upgrade_status"`
+}
+
+// An UpgradeClient manages major version upgrades of clusters.
+type UpgradeClient struct {
+	client *Client
+}
+
+// Upgrades returns a client for major version upgrades of clusters.
+func (c *Client) Upgrades() *UpgradeClient {
+	return &UpgradeClient{client: c}
+}
+
+// Get returns the state of the major version upgrade of the supplied
+// cluster.
+func (c *UpgradeClient) Get(ctx context.Context, clusterID string) (*Upgrade, error) {
+	req, err := c.client.newRequest(ctx, http.MethodGet, upgradePath(clusterID), nil)
+	if err != nil {
+		return nil, err
+	}
+	u := &Upgrade{}
+	if err := c.client.do(req, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Initiate starts upgrading the supplied cluster to the supplied major
+// version, e.g. v23.1. The upgrade must be finalized or rolled back once the
+// cluster is running the new version.
+func (c *UpgradeClient) Initiate(ctx context.Context, clusterID, version string) (*cockroachdb.Cluster, error) {
+	return c.update(ctx, clusterID, upgradeRequest{CockroachVersion: version, UpgradeStatus: UpgradeStatusMajorUpgradeRunning})
+}
+
+// Finalize completes the running major version upgrade of the supplied
+// cluster. A finalized upgrade can no longer be rolled back.
+func (c *UpgradeClient) Finalize(ctx context.Context, clusterID string) (*cockroachdb.Cluster, error) {
+	return c.update(ctx, clusterID, upgradeRequest{UpgradeStatus: UpgradeStatusFinalized})
+}
+
+// Rollback reverts the supplied cluster to the major version it ran before
+// its pending upgrade.
+func (c *UpgradeClient) Rollback(ctx context.Context, clusterID string) (*cockroachdb.Cluster, error) {
+	return c.update(ctx, clusterID, upgradeRequest{UpgradeStatus: UpgradeStatusRollbackRunning})
+}
+
+func (c *UpgradeClient) update(ctx context.Context, clusterID string, body upgradeRequest) (*cockroachdb.Cluster, error) {
+	req, err := c.client.newRequest(ctx, http.MethodPatch, upgradePath(clusterID), body)
+	if err != nil {
+		return nil, err
+	}
+	cluster := &cockroachdb.Cluster{}
+	if err := c.client.do(req, cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func upgradePath(clusterID string) string {
+	return fmt.Sprintf("/api/v1/clusters/%s", url.PathEscape(clusterID))
+}
@@ -0,0 +1,116 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cockroachdb "github.com/cockroachdb/cockroach-cloud-sdk-go/pkg/client"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpgrades(t *testing.T) {
+	type request struct {
+		Method string
+		Path   string
+		Body   map[string]string
+	}
+
+	cases := map[string]struct {
+		reason  string
+		upgrade func(ctx context.Context, c *UpgradeClient) (*cockroachdb.Cluster, error)
+		want    request
+	}{
+		"Initiate": {
+			reason: "Initiating an upgrade should request the new version.",
+			upgrade: func(ctx context.Context, c *UpgradeClient) (*cockroachdb.Cluster, error) {
+				return c.Initiate(ctx, "cluster", "v23.1")
+			},
+			want: request{
+				Method: http.MethodPatch,
+				Path:   "/api/v1/clusters/cluster",
+				Body:   map[string]string{"cockroach_version": "v23.1", "upgrade_status": "MAJOR_UPGRADE_RUNNING"},
+			},
+		},
+		"Finalize": {
+			reason: "Finalizing an upgrade should only change its status.",
+			upgrade: func(ctx context.Context, c *UpgradeClient) (*cockroachdb.Cluster, error) {
+				return c.Finalize(ctx, "cluster")
+			},
+			want: request{
+				Method: http.MethodPatch,
+				Path:   "/api/v1/clusters/cluster",
+				Body:   map[string]string{"upgrade_status": "FINALIZED"},
+			},
+		},
+		"Rollback": {
+			reason: "Rolling back an upgrade should only change its status.",
+			upgrade: func(ctx context.Context, c *UpgradeClient) (*cockroachdb.Cluster, error) {
+				return c.Rollback(ctx, "cluster")
+			},
+			want: request{
+				Method: http.MethodPatch,
+				Path:   "/api/v1/clusters/cluster",
+				Body:   map[string]string{"upgrade_status": "ROLLBACK_RUNNING"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = request{Method: r.Method, Path: r.URL.Path}
+				_ = json.NewDecoder(r.Body).Decode(&got.Body)
+				_, _ = w.Write([]byte(`{"id":"cluster"}`))
+			}))
+			defer srv.Close()
+
+			c, err := NewClient("key", WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatalf("NewClient(...): %v", err)
+			}
+			cluster, err := tc.upgrade(context.Background(), c.Upgrades())
+			if err != nil {
+				t.Fatalf("\n%s\nupgrade(...): %v", tc.reason, err)
+			}
+			if cluster.Id != "cluster" {
+				t.Errorf("\n%s\nupgrade(...): want cluster %q, got %q", tc.reason, "cluster", cluster.Id)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nupgrade(...): -want request, +got request:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetUpgrade(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		_, _ = w.Write([]byte(`{"id":"cluster","cockroach_version":"v23.1.11","upgrade_status":"PENDING_FINALIZATION","operation_status":"CLUSTER_STATUS_UNSPECIFIED"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	got, err := c.Upgrades().Get(context.Background(), "cluster")
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	want := &Upgrade{
+		CockroachVersion: "v23.1.11",
+		UpgradeStatus:    UpgradeStatusPendingFinalization,
+		OperationStatus:  cockroachdb.CLUSTERSTATUSTYPE_CLUSTER_STATUS_UNSPECIFIED,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get(...): -want, +got:\n%s\n", diff)
+	}
+	if path != "GET /api/v1/clusters/cluster" {
+		t.Errorf("Get(...): want request %q, got %q", "GET /api/v1/clusters/cluster", path)
+	}
+}
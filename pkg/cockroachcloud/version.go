@@ -0,0 +1,64 @@
+package cockroachcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// defaultAPISpecPath is the path of the OpenAPI document describing the
+// Cloud API.
+const defaultAPISpecPath = "/api/v1/openapi.json"
+
+// WithAPISpecPath sets the path of the OpenAPI document describing the Cloud
+// API, used to discover its version and features.
+func WithAPISpecPath(path string) Option {
+	return func(c *Client) error {
+		c.specPath = path
+
+		return nil
+	}
+}
+
+// APIInfo describes the Cloud API served to a Client.
+type APIInfo struct {
+	// Version of the Cloud API.
+	Version string
+	// Paths served by the Cloud API, sorted.
+	Paths []string
+}
+
+// Supports returns true if the Cloud API serves the supplied path template,
+// e.g. /api/v1/clusters/{cluster_id}/nodes.
+func (i *APIInfo) Supports(path string) bool {
+	n := sort.SearchStrings(i.Paths, path)
+	return n < len(i.Paths) && i.Paths[n] == path
+}
+
+// APIInfo discovers the version and the paths served by the Cloud API from
+// its OpenAPI document, so that incompatibilities with the provider surface
+// at startup rather than on the first failing request.
+func (c *Client) APIInfo(ctx context.Context) (*APIInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.specPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := c.do(req, &spec); err != nil {
+		return nil, err
+	}
+
+	info := &APIInfo{Version: spec.Info.Version, Paths: make([]string, 0, len(spec.Paths))}
+	for p := range spec.Paths {
+		info.Paths = append(info.Paths, p)
+	}
+	sort.Strings(info.Paths)
+	return info, nil
+}
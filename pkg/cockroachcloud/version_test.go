@@ -0,0 +1,42 @@
+package cockroachcloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAPIInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != defaultAPISpecPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, `{"info":{"version":"2022-09-20"},"paths":{"/api/v1/clusters":{},"/api/v1/clusters/{cluster_id}/nodes":{}}}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient(...): %v", err)
+	}
+	got, err := c.APIInfo(context.Background())
+	if err != nil {
+		t.Fatalf("c.APIInfo(...): %v", err)
+	}
+
+	want := &APIInfo{Version: "2022-09-20", Paths: []string{"/api/v1/clusters", "/api/v1/clusters/{cluster_id}/nodes"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.APIInfo(...): -want, +got:\n%s\n", diff)
+	}
+	if !got.Supports("/api/v1/clusters/{cluster_id}/nodes") {
+		t.Errorf("got.Supports(...): want true for a served path")
+	}
+	if got.Supports("/api/v1/clusters/{cluster_id}/upgrade") {
+		t.Errorf("got.Supports(...): want false for a path that is not served")
+	}
+}